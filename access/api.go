@@ -28,6 +28,7 @@ type API interface {
 	SendTransaction(ctx context.Context, tx *flow.TransactionBody) error
 	GetTransaction(ctx context.Context, id flow.Identifier) (*flow.TransactionBody, error)
 	GetTransactionResult(ctx context.Context, id flow.Identifier) (*TransactionResult, error)
+	EstimateTransactionFee(ctx context.Context, tx *flow.TransactionBody) (*FeeEstimate, error)
 
 	GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtLatestBlock(ctx context.Context, address flow.Address) (*flow.Account, error)
@@ -68,6 +69,15 @@ func MessageToTransactionResult(message *access.TransactionResultResponse) *Tran
 	}
 }
 
+// FeeEstimate is the fee a transaction would be charged if it was executed at the latest sealed block,
+// along with the fee parameters it is computed from.
+type FeeEstimate struct {
+	Fee             uint64 // in units of 1e-8 FLOW
+	InclusionEffort uint64
+	ComputationUsed uint64
+	StorageDelta    int64
+}
+
 // NetworkParameters contains the network-wide parameters for the Flow blockchain.
 type NetworkParameters struct {
 	ChainID flow.ChainID
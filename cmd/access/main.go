@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/onflow/cadence/runtime"
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
 
@@ -11,17 +13,23 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 
 	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/consensus/hotstuff/verification"
 	recovery "github.com/onflow/flow-go/consensus/recovery/protocol"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/access/ingestion"
 	pingeng "github.com/onflow/flow-go/engine/access/ping"
+	registerseng "github.com/onflow/flow-go/engine/access/registers"
 	"github.com/onflow/flow-go/engine/access/rpc"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	followereng "github.com/onflow/flow-go/engine/common/follower"
 	"github.com/onflow/flow-go/engine/common/requester"
 	synceng "github.com/onflow/flow-go/engine/common/synchronization"
+	executionState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/encoding"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
@@ -34,6 +42,7 @@ import (
 	"github.com/onflow/flow-go/module/synchronization"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
+	storerr "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/badger"
 	grpcutils "github.com/onflow/flow-go/utils/grpc"
 )
@@ -71,6 +80,11 @@ func main() {
 		logTxTimeToFinalizedExecuted bool
 		retryEnabled                 bool
 		rpcMetricsEnabled            bool
+		registerIndexEnabled         bool
+		registerCheckpoint           string
+		registerIndex                *storage.Registers
+		scriptExecutor               backend.ScriptExecutor // nil if scripts are only executed on the execution nodes
+		registersEng                 *registerseng.Engine
 	)
 
 	cmd.FlowNode(flow.RoleAccess.String()).
@@ -82,6 +96,7 @@ func main() {
 			flags.UintVar(&executionGRPCPort, "execution-ingress-port", 9000, "the grpc ingress port for all execution nodes")
 			flags.StringVarP(&rpcConf.GRPCListenAddr, "rpc-addr", "r", "localhost:9000", "the address the gRPC server listens on")
 			flags.StringVarP(&rpcConf.HTTPListenAddr, "http-addr", "h", "localhost:8000", "the address the http proxy server listens on")
+			flags.StringVarP(&rpcConf.RESTListenAddr, "rest-addr", "", "", "the address the REST API server listens on, e.g. localhost:8070 (the REST API is disabled if empty)")
 			flags.StringVarP(&rpcConf.CollectionAddr, "static-collection-ingress-addr", "", "", "the address (of the collection node) to send transactions to")
 			flags.StringVarP(&rpcConf.ExecutionAddr, "script-addr", "s", "localhost:9000", "the address (of the execution node) forward the script to")
 			flags.StringVarP(&rpcConf.HistoricalAccessAddrs, "historical-access-addr", "", "", "comma separated rpc addresses for historical access nodes")
@@ -92,6 +107,8 @@ func main() {
 			flags.BoolVar(&retryEnabled, "retry-enabled", false, "whether to enable the retry mechanism at the access node level")
 			flags.BoolVar(&rpcMetricsEnabled, "rpc-metrics-enabled", false, "whether to enable the rpc metrics")
 			flags.StringVarP(&nodeInfoFile, "node-info-file", "", "", "full path to a json file which provides more details about nodes when reporting its reachability metrics")
+			flags.BoolVar(&registerIndexEnabled, "register-index-enabled", false, "whether to index the registers pushed by the execution nodes, to execute scripts locally")
+			flags.StringVar(&registerCheckpoint, "register-checkpoint", "", "the root checkpoint to bootstrap the register index from, only read if the index is not bootstrapped yet")
 		}).
 		Module("mutable follower state", func(node *cmd.FlowNodeBuilder) error {
 			// For now, we only support state implementations from package badger.
@@ -205,6 +222,30 @@ func main() {
 			pingMetrics = metrics.NewPingCollector()
 			return nil
 		}).
+		Module("register index", func(node *cmd.FlowNodeBuilder) error {
+			if !registerIndexEnabled {
+				return nil
+			}
+
+			registerIndex = storage.NewRegisters(node.DB)
+
+			// the index starts at the root block, so it needs the root execution state
+			_, err := registerIndex.FirstHeight()
+			if errors.Is(err, storerr.ErrNotFound) {
+				if registerCheckpoint == "" {
+					return fmt.Errorf("the root checkpoint is required to bootstrap the register index")
+				}
+				err = bootstrapRegisters(registerIndex, registerCheckpoint, node.RootBlock.Header.Height, node.RootSeal.FinalState)
+			}
+			if err != nil {
+				return fmt.Errorf("could not bootstrap the register index: %w", err)
+			}
+
+			vm := fvm.New(runtime.NewInterpreterRuntime())
+			vmCtx := fvm.NewContext(node.Logger, node.FvmOptions...)
+			scriptExecutor = registerseng.NewScriptExecutor(vm, vmCtx, node.Storage.Headers, registerIndex)
+			return nil
+		}).
 		Component("RPC engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 			rpcEng = rpc.New(
 				node.Logger,
@@ -218,6 +259,7 @@ func main() {
 				node.Storage.Collections,
 				node.Storage.Transactions,
 				node.Storage.Receipts,
+				scriptExecutor,
 				node.RootChainID,
 				transactionMetrics,
 				collectionGRPCPort,
@@ -246,6 +288,21 @@ func main() {
 			requestEng.WithHandle(ingestEng.OnCollection)
 			return ingestEng, err
 		}).
+		Component("register index engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
+			if !registerIndexEnabled {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+
+			// the register deltas are buffered until their blocks are finalized and their end states are trusted
+			deltas, err := stdmap.NewRegisterDeltas(1 * 300) // assume 1 block per second * 300 seconds
+			if err != nil {
+				return nil, err
+			}
+
+			registersEng, err = registerseng.New(node.Logger, metrics.NewRegisterIndexCollector(), node.Network, node.State, node.Me, node.Storage.Headers,
+				node.Storage.Index, node.Storage.Seals, node.Storage.Receipts, registerIndex, deltas)
+			return registersEng, err
+		}).
 		Component("requester engine", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 			// We initialize the requester engine inside the ingestion engine due to the mutual dependency. However, in
 			// order for it to properly start and shut down, we should still return it as its own engine here, so it can
@@ -282,9 +339,15 @@ func main() {
 				return nil, fmt.Errorf("could not find latest finalized block and pending blocks to recover consensus follower: %w", err)
 			}
 
-			// creates a consensus follower with ingestEngine as the notifier
-			// so that it gets notified upon each new finalized block
-			followerCore, err := consensus.NewFollower(node.Logger, committee, node.Storage.Headers, final, verifier, ingestEng, node.RootBlock.Header, node.RootQC, finalized, pending)
+			// the ingestion engine and the register index engine get notified upon each new finalized block
+			notifier := pubsub.NewFinalizationDistributor()
+			notifier.AddConsumer(ingestEng)
+			if registersEng != nil {
+				notifier.AddConsumer(registersEng)
+			}
+
+			// creates a consensus follower with the distributor as the notifier
+			followerCore, err := consensus.NewFollower(node.Logger, committee, node.Storage.Headers, final, verifier, notifier, node.RootBlock.Header, node.RootQC, finalized, pending)
 			if err != nil {
 				return nil, fmt.Errorf("could not initialize follower core: %w", err)
 			}
@@ -342,3 +405,26 @@ func main() {
 		}).
 		Run()
 }
+
+// bootstrapRegisters bootstraps the register index with the root execution state, read from the root checkpoint
+func bootstrapRegisters(registers *storage.Registers, checkpointFile string, height uint64, commit flow.StateCommitment) error {
+	t, err := common.LoadCheckpointTrie(checkpointFile, commit)
+	if err != nil {
+		return fmt.Errorf("could not load the root checkpoint: %w", err)
+	}
+
+	payloads, err := t.AllPayloads()
+	if err != nil {
+		return fmt.Errorf("could not read the root payloads: %w", err)
+	}
+	entries := make(flow.RegisterEntries, 0, len(payloads))
+	for _, payload := range payloads {
+		id, err := executionState.KeyToRegisterID(payload.Key)
+		if err != nil {
+			return fmt.Errorf("could not get register ID of payload: %w", err)
+		}
+		entries = append(entries, flow.RegisterEntry{Key: id, Value: payload.Value})
+	}
+
+	return registers.Bootstrap(height, entries)
+}
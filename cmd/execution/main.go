@@ -28,8 +28,11 @@ import (
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
 	"github.com/onflow/flow-go/fvm"
+	ledgerpkg "github.com/onflow/flow-go/ledger"
 	ledger "github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/nodestore"
 	wal "github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/ledger/remote"
 	bootstrapFilenames "github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/encoding"
 	"github.com/onflow/flow-go/model/flow"
@@ -50,6 +53,7 @@ func main() {
 	var (
 		followerState         protocol.MutableState
 		ledgerStorage         *ledger.Ledger
+		executionLedger       ledgerpkg.Ledger // either ledgerStorage or a remote ledger
 		events                *storage.Events
 		txResults             *storage.TransactionResults
 		results               *storage.ExecutionResults
@@ -69,9 +73,19 @@ func main() {
 		triedir               string
 		collector             module.ExecutionMetrics
 		mTrieCacheSize        uint32
+		mTrieNodeStore        bool
+		mTrieNodeCacheSize    uint32
 		checkpointDistance    uint
 		checkpointsToKeep     uint
+		checkpointDeltas      uint
+		ledgerServiceAddr     string
+		ledgerServiceTimeout  time.Duration
+		ledgerCompression     string
+		checkpointParts       bool
+		stateRetention        state.RetentionPolicy
+		txParallelism         uint
 		stateDeltasLimit      uint
+		pushStateDeltas       bool
 		requestInterval       time.Duration
 		preferredExeNodeIDStr string
 		syncByBlocks          bool
@@ -88,9 +102,20 @@ func main() {
 			flags.StringVarP(&rpcConf.ListenAddr, "rpc-addr", "i", "localhost:9000", "the address the gRPC server listens on")
 			flags.StringVar(&triedir, "triedir", datadir, "directory to store the execution State")
 			flags.Uint32Var(&mTrieCacheSize, "mtrie-cache-size", 1000, "cache size for MTrie")
+			flags.BoolVar(&mTrieNodeStore, "mtrie-node-store", false, "page MTrie nodes to an on-disk node store instead of keeping all tries in memory")
+			flags.Uint32Var(&mTrieNodeCacheSize, "mtrie-node-cache-size", nodestore.DefaultCacheSize, "number of hot MTrie nodes kept in memory when using the on-disk node store")
 			flags.UintVar(&checkpointDistance, "checkpoint-distance", 10, "number of WAL segments between checkpoints")
 			flags.UintVar(&checkpointsToKeep, "checkpoints-to-keep", 5, "number of recent checkpoints to keep (0 to keep all)")
+			flags.StringVar(&ledgerServiceAddr, "ledger-service-addr", "", "address of a remote ledger service to use instead of an in-process ledger (empty for in-process ledger)")
+			flags.DurationVar(&ledgerServiceTimeout, "ledger-service-timeout", remote.DefaultTimeout, "timeout of the calls to the remote ledger service")
+			flags.UintVar(&checkpointDeltas, "checkpoint-deltas", 0, "number of incremental checkpoints between full checkpoints (0 to only create full checkpoints)")
+			flags.StringVar(&ledgerCompression, "ledger-compression", "none", "compression codec for WAL records and checkpoints (none or snappy)")
+			flags.BoolVar(&checkpointParts, "checkpoint-parts", false, "split checkpoints into parts loaded in parallel (not readable by binaries without support for them)")
+			flags.Uint64Var(&stateRetention.RecentHeights, "state-retention-recent-heights", 0, "number of recent sealed heights whose states are restored from checkpoints and WAL for scripts after being evicted from memory")
+			flags.Uint64Var(&stateRetention.Interval, "state-retention-interval", 0, "additionally restore the states of every n-th height for scripts after being evicted from memory (0 to disable)")
+			flags.UintVar(&txParallelism, "tx-parallelism", 1, "number of workers optimistically executing the transactions of a collection concurrently (1 to execute them one after the other)")
 			flags.UintVar(&stateDeltasLimit, "state-deltas-limit", 1000, "maximum number of state deltas in the memory pool")
+			flags.BoolVar(&pushStateDeltas, "push-state-deltas", false, "push the register delta of each executed block to the access nodes, for their local register index")
 			flags.DurationVar(&requestInterval, "request-interval", 60*time.Second, "the interval between requests for the requester engine")
 			flags.StringVar(&preferredExeNodeIDStr, "preferred-exe-node-id", "", "node ID for preferred execution node used for state sync")
 			flags.BoolVar(&syncByBlocks, "sync-by-blocks", true, "deprecated, sync by blocks instead of execution state deltas")
@@ -128,13 +153,22 @@ func main() {
 				node.State,
 				vm,
 				vmCtx,
+				txParallelism,
 			)
 			computationManager = manager
 
 			return err
 		}).
 		Module("execution metrics", func(node *cmd.FlowNodeBuilder) error {
-			collector = metrics.NewExecutionCollector(node.Tracer, node.MetricsRegisterer)
+			slowestTransactions := metrics.NewSlowestTransactions(metrics.DefaultSlowestTransactionsSize, metrics.DefaultSlowestTransactionsWindow)
+			node.MetricsServer.Handle("/slowest-transactions", slowestTransactions)
+
+			var coreContracts []string
+			for _, location := range fvm.CoreContractLocations(node.RootChainID.Chain()) {
+				coreContracts = append(coreContracts, string(location))
+			}
+
+			collector = metrics.NewExecutionCollector(node.Tracer, node.MetricsRegisterer, slowestTransactions, coreContracts)
 			return nil
 		}).
 		Module("sync core", func(node *cmd.FlowNodeBuilder) error {
@@ -168,9 +202,12 @@ func main() {
 			if !bootstrapped {
 				// when bootstrapping, the bootstrap folder must have a checkpoint file
 				// we need to cover this file to the trie folder to restore the trie to restore the execution state.
-				err = copyBootstrapState(node.BaseConfig.BootstrapDir, triedir)
-				if err != nil {
-					return nil, fmt.Errorf("could not load bootstrap state from checkpoint file: %w", err)
+				// A remote ledger service has to be bootstrapped with the checkpoint file on its own.
+				if ledgerServiceAddr == "" {
+					err = copyBootstrapState(node.BaseConfig.BootstrapDir, triedir)
+					if err != nil {
+						return nil, fmt.Errorf("could not load bootstrap state from checkpoint file: %w", err)
+					}
 				}
 
 				// TODO: check that the checkpoint file contains the root block's statecommit hash
@@ -189,16 +226,43 @@ func main() {
 				}
 			}
 
-			ledgerStorage, err = ledger.NewLedger(triedir, int(mTrieCacheSize), collector, node.Logger.With().Str("subcomponent", "ledger").Logger(), node.MetricsRegisterer, ledger.DefaultPathFinderVersion)
-			return ledgerStorage, err
+			if ledgerServiceAddr != "" {
+				node.Logger.Info().Str("address", ledgerServiceAddr).Msg("connecting to remote ledger service")
+				executionLedger, err = remote.NewClient(ledgerServiceAddr, 0, ledgerServiceTimeout)
+				return executionLedger, err
+			}
+
+			codec, err := wal.ParseCodec(ledgerCompression)
+			if err != nil {
+				return nil, err
+			}
+
+			ledgerLogger := node.Logger.With().Str("subcomponent", "ledger").Logger()
+			if mTrieNodeStore {
+				ledgerStorage, err = ledger.NewLedgerWithNodeStore(triedir, int(mTrieCacheSize), int(mTrieNodeCacheSize), collector, ledgerLogger, node.MetricsRegisterer, ledger.DefaultPathFinderVersion)
+			} else {
+				ledgerStorage, err = ledger.NewLedger(triedir, int(mTrieCacheSize), collector, ledgerLogger, node.MetricsRegisterer, ledger.DefaultPathFinderVersion)
+			}
+			if err != nil {
+				return nil, err
+			}
+			ledgerStorage.SetCodec(codec)
+			ledgerStorage.SetCheckpointParts(checkpointParts)
+			executionLedger = ledgerStorage
+			return ledgerStorage, nil
 		}).
 		Component("execution state ledger WAL compactor", func(node *cmd.FlowNodeBuilder) (module.ReadyDoneAware, error) {
 
+			// the remote ledger service compacts its own WAL
+			if ledgerServiceAddr != "" {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+
 			checkpointer, err := ledgerStorage.Checkpointer()
 			if err != nil {
 				return nil, fmt.Errorf("cannot create checkpointer: %w", err)
 			}
-			compactor := wal.NewCompactor(checkpointer, 10*time.Second, checkpointDistance, checkpointsToKeep)
+			compactor := wal.NewCompactor(checkpointer, 10*time.Second, checkpointDistance, checkpointsToKeep, checkpointDeltas)
 
 			return compactor, nil
 		}).
@@ -207,7 +271,7 @@ func main() {
 			stateCommitments := storage.NewCommits(node.Metrics.Cache, node.DB)
 
 			executionState = state.NewExecutionState(
-				ledgerStorage,
+				executionLedger,
 				stateCommitments,
 				node.Storage.Blocks,
 				node.Storage.Collections,
@@ -226,6 +290,7 @@ func main() {
 				node.Me,
 				executionState,
 				collector,
+				pushStateDeltas,
 			)
 
 			return providerEngine, err
@@ -273,6 +338,7 @@ func main() {
 				deltas,
 				syncThreshold,
 				syncFast,
+				stateRetention,
 			)
 
 			// TODO: we should solve these mutual dependencies better
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/pflag"
+
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/nodestore"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/ledger/remote"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+// ledger runs a ledger as a standalone service, which execution nodes
+// can use through the --ledger-service-addr flag.
+func main() {
+
+	homedir, _ := os.UserHomeDir()
+	datadir := filepath.Join(homedir, ".flow", "execution")
+
+	var (
+		listenAddr         string
+		triedir            string
+		mTrieCacheSize     uint32
+		mTrieNodeStore     bool
+		mTrieNodeCacheSize uint32
+		checkpointDistance uint
+		checkpointsToKeep  uint
+		checkpointDeltas   uint
+		compression        string
+		checkpointParts    bool
+		logLevel           string
+	)
+
+	flags := pflag.NewFlagSet("ledger", pflag.ExitOnError)
+	flags.StringVar(&listenAddr, "rpc-addr", "localhost:9001", "the address the ledger gRPC server listens on")
+	flags.StringVar(&triedir, "triedir", datadir, "directory to store the execution State")
+	flags.Uint32Var(&mTrieCacheSize, "mtrie-cache-size", 1000, "cache size for MTrie")
+	flags.BoolVar(&mTrieNodeStore, "mtrie-node-store", false, "page MTrie nodes to an on-disk node store instead of keeping all tries in memory")
+	flags.Uint32Var(&mTrieNodeCacheSize, "mtrie-node-cache-size", nodestore.DefaultCacheSize, "number of hot MTrie nodes kept in memory when using the on-disk node store")
+	flags.UintVar(&checkpointDistance, "checkpoint-distance", 10, "number of WAL segments between checkpoints")
+	flags.UintVar(&checkpointsToKeep, "checkpoints-to-keep", 5, "number of recent checkpoints to keep (0 to keep all)")
+	flags.UintVar(&checkpointDeltas, "checkpoint-deltas", 0, "number of incremental checkpoints between full checkpoints (0 to only create full checkpoints)")
+	flags.StringVar(&compression, "compression", "none", "compression codec for WAL records and checkpoints (none or snappy)")
+	flags.BoolVar(&checkpointParts, "checkpoint-parts", false, "split checkpoints into parts loaded in parallel (not readable by binaries without support for them)")
+	flags.StringVar(&logLevel, "loglevel", "info", "level for logging output")
+	_ = flags.Parse(os.Args[1:])
+
+	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
+	level, err := zerolog.ParseLevel(logLevel)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid log level")
+	}
+	log = log.Level(level)
+
+	codec, err := wal.ParseCodec(compression)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid compression codec")
+	}
+
+	collector := &metrics.NoopCollector{}
+	ledgerLogger := log.With().Str("subcomponent", "ledger").Logger()
+
+	var ledger *complete.Ledger
+	if mTrieNodeStore {
+		ledger, err = complete.NewLedgerWithNodeStore(triedir, int(mTrieCacheSize), int(mTrieNodeCacheSize), collector, ledgerLogger, nil, complete.DefaultPathFinderVersion)
+	} else {
+		ledger, err = complete.NewLedger(triedir, int(mTrieCacheSize), collector, ledgerLogger, nil, complete.DefaultPathFinderVersion)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create ledger")
+	}
+	ledger.SetCodec(codec)
+	ledger.SetCheckpointParts(checkpointParts)
+
+	checkpointer, err := ledger.Checkpointer()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create checkpointer")
+	}
+	compactor := wal.NewCompactor(checkpointer, 10*time.Second, checkpointDistance, checkpointsToKeep, checkpointDeltas)
+
+	server := remote.NewServer(log, remote.Config{ListenAddr: listenAddr}, ledger)
+
+	<-ledger.Ready()
+	<-compactor.Ready()
+	<-server.Ready()
+
+	log.Info().Str("address", listenAddr).Msg("ledger service started")
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+
+	log.Info().Msg("ledger service shutting down")
+
+	<-server.Done()
+	<-compactor.Done()
+	<-ledger.Done()
+}
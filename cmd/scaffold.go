@@ -115,6 +115,7 @@ type FlowNodeBuilder struct {
 	Me                *local.Local
 	Tracer            *trace.OpenTracer
 	MetricsRegisterer prometheus.Registerer
+	MetricsServer     *metrics.Server
 	Metrics           Metrics
 	DB                *badger.DB
 	Storage           Storage
@@ -229,8 +230,7 @@ func (fnb *FlowNodeBuilder) enqueueNetworkInit() {
 
 func (fnb *FlowNodeBuilder) enqueueMetricsServerInit() {
 	fnb.Component("metrics server", func(builder *FlowNodeBuilder) (module.ReadyDoneAware, error) {
-		server := metrics.NewServer(fnb.Logger, fnb.BaseConfig.metricsPort, fnb.BaseConfig.profilerEnabled)
-		return server, nil
+		return fnb.MetricsServer, nil
 	})
 }
 
@@ -304,6 +304,7 @@ func (fnb *FlowNodeBuilder) initMetrics() {
 	fnb.MustNot(err).Msg("could not initialize tracer")
 	fnb.Logger.Info().Msg("Tracer Started")
 	fnb.MetricsRegisterer = prometheus.DefaultRegisterer
+	fnb.MetricsServer = metrics.NewServer(fnb.Logger, fnb.BaseConfig.metricsPort, fnb.BaseConfig.profilerEnabled)
 	fnb.Tracer = tracer
 
 	mempools := metrics.NewMempoolCollector(5 * time.Second)
@@ -557,18 +558,22 @@ func (fnb *FlowNodeBuilder) initState() {
 }
 
 func (fnb *FlowNodeBuilder) initFvmOptions() {
-	blockFinder := fvm.NewBlockFinder(fnb.Storage.Headers)
+	fnb.FvmOptions = FvmOptions(fnb.RootChainID, fnb.Storage.Headers)
+}
+
+// FvmOptions returns the options of the virtual machine contexts of the nodes of the given chain.
+func FvmOptions(chainID flow.ChainID, headers storage.Headers) []fvm.Option {
 	vmOpts := []fvm.Option{
-		fvm.WithChain(fnb.RootChainID.Chain()),
-		fvm.WithBlocks(blockFinder),
+		fvm.WithChain(chainID.Chain()),
+		fvm.WithBlocks(fvm.NewBlockFinder(headers)),
 	}
-	if fnb.RootChainID == flow.Testnet {
+	if chainID == flow.Testnet {
 		vmOpts = append(vmOpts,
 			fvm.WithRestrictedAccountCreation(false),
 			fvm.WithRestrictedDeployment(false),
 		)
 	}
-	fnb.FvmOptions = vmOpts
+	return vmOpts
 }
 
 func (fnb *FlowNodeBuilder) handleModule(v namedModuleFunc) {
@@ -28,6 +28,19 @@ func init() {
 
 func run(*cobra.Command, []string) {
 
+	header, err := wal.ReadCheckpointHeader(flagCheckpoint)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while reading checkpoint header")
+	}
+
+	log.Info().
+		Uint16("version", header.Version).
+		Str("codec", header.Codec.String()).
+		Int("parent", header.Parent).
+		Uint64("nodes", header.NodeCount).
+		Uint16("tries", header.TrieCount).
+		Msg("checkpoint header")
+
 	flattenedForest, err := wal.LoadCheckpoint(flagCheckpoint)
 	if err != nil {
 		log.Fatal().Err(err).Msg("error while loading checkpoint")
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// LoadCheckpointTrie loads the trie of the given state from a checkpoint file
+func LoadCheckpointTrie(checkpointFile string, stateCommitment flow.StateCommitment) (*trie.MTrie, error) {
+	forestSequencing, err := wal.LoadCheckpoint(checkpointFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load checkpoint: %w", err)
+	}
+
+	tries, err := flattener.RebuildTries(forestSequencing)
+	if err != nil {
+		return nil, fmt.Errorf("cannot rebuild tries: %w", err)
+	}
+
+	for _, t := range tries {
+		if bytes.Equal(t.RootHash(), stateCommitment) {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("checkpoint does not contain the state %x", stateCommitment)
+}
+
+// TrieGetRegister returns a function reading registers from the given trie
+func TrieGetRegister(t *trie.MTrie) delta.GetRegisterFunc {
+	return func(owner, controller, key string) (flow.RegisterValue, error) {
+		k := state.RegisterIDToKey(flow.NewRegisterID(owner, controller, key))
+
+		path, err := pathfinder.KeyToPath(k, complete.DefaultPathFinderVersion)
+		if err != nil {
+			return nil, fmt.Errorf("cannot compute path of register: %w", err)
+		}
+
+		payloads, err := t.UnsafeRead([]ledger.Path{path})
+		if err != nil {
+			return nil, fmt.Errorf("cannot read register: %w", err)
+		}
+
+		return payloads[0].Value, nil
+	}
+}
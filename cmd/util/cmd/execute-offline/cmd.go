@@ -0,0 +1,225 @@
+package execute_offline
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagCheckpoint        string
+	flagExecutionStateDir string
+	flagStateCommitment   string
+	flagDatadir           string
+	flagBlockID           string
+	flagChain             string
+	flagGasLimit          uint64
+	flagScript            string
+	flagArguments         []string
+	flagTransaction       string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "execute-offline",
+	Short: "Executes a script or a transaction against a state loaded from a checkpoint, without writing anything",
+	Long: `Executes a script or a transaction against a state of the execution state, loaded either from a single
+checkpoint file, or from the checkpoints and the WAL of an execution state dir, and prints the result as JSON:
+the returned value or the error, the events, the logs, the computation used and the register delta.
+The computation used is the one reported in transaction results, as charged by the metering table.
+Nothing is written, neither to the checkpoints and the WAL, nor to the protocol state.
+
+The state is either given by its state commitment, or by a block, in which case the state after the block is used
+and the block header is made available to the execution.
+Transactions are read from a file containing a JSON encoded transaction body. Unsigned transactions are executed
+without verifying signatures and sequence numbers, and without deducting fees.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagCheckpoint, "checkpoint", "",
+		"checkpoint file containing the state")
+
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written), used if no checkpoint file is given")
+
+	Cmd.Flags().StringVar(&flagStateCommitment, "state-commitment", "",
+		"state commitment (hex-encoded, 64 characters)")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state, required with block-id")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"execute against the state after this block (hex-encoded, 64 characters)")
+
+	Cmd.Flags().StringVar(&flagChain, "chain", "",
+		"chain ID (defaults to the chain of the block, or flow-mainnet)")
+
+	Cmd.Flags().Uint64Var(&flagGasLimit, "gas-limit", fvm.DefaultGasLimit,
+		"gas limit")
+
+	Cmd.Flags().StringVar(&flagScript, "script", "",
+		"file containing the Cadence script to execute")
+
+	Cmd.Flags().StringSliceVar(&flagArguments, "arguments", nil,
+		"JSON-Cadence encoded arguments of the script")
+
+	Cmd.Flags().StringVar(&flagTransaction, "transaction", "",
+		"file containing the JSON encoded transaction body to execute")
+}
+
+func run(*cobra.Command, []string) {
+
+	if (len(flagScript) > 0) == (len(flagTransaction) > 0) {
+		log.Fatal().Msg("exactly one of script and transaction has to be provided")
+	}
+
+	if (len(flagBlockID) > 0) == (len(flagStateCommitment) > 0) {
+		log.Fatal().Msg("exactly one of block-id and state-commitment has to be provided")
+	}
+
+	var (
+		stateCommitment flow.StateCommitment
+		vmOpts          []fvm.Option
+		chainID         = flow.Mainnet
+		err             error
+	)
+
+	if len(flagBlockID) > 0 {
+		blockID, err := flow.HexStringToIdentifier(flagBlockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed block ID")
+		}
+
+		db := common.InitStorage(flagDatadir)
+		defer db.Close()
+
+		storages := common.InitStorages(db)
+
+		header, err := storages.Headers.ByBlockID(blockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot get block header")
+		}
+
+		stateCommitment, err = storages.Commits.ByBlockID(blockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot get state commitment of block")
+		}
+
+		chainID = header.ChainID
+		vmOpts = append(vmOpts,
+			fvm.WithBlockHeader(header),
+			fvm.WithBlocks(fvm.NewBlockFinder(storages.Headers)),
+		)
+	} else {
+		stateCommitment, err = hex.DecodeString(flagStateCommitment)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot decode the state commitment")
+		}
+	}
+
+	if len(flagChain) > 0 {
+		chainID = flow.ChainID(flagChain)
+	}
+
+	vmOpts = append(vmOpts,
+		fvm.WithChain(chainID.Chain()),
+		fvm.WithGasLimit(flagGasLimit),
+		fvm.WithCadenceLogging(true),
+	)
+	if chainID == flow.Testnet {
+		vmOpts = append(vmOpts,
+			fvm.WithRestrictedAccountCreation(false),
+			fvm.WithRestrictedDeployment(false),
+		)
+	}
+
+	t, err := loadTrie(stateCommitment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot load state")
+	}
+
+	view := delta.NewView(common.TrieGetRegister(t))
+
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+	ctx := fvm.NewContext(log.Logger, vmOpts...)
+
+	var r *result
+	if len(flagScript) > 0 {
+		code, err := ioutil.ReadFile(flagScript)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot read script")
+		}
+
+		arguments := make([][]byte, len(flagArguments))
+		for i, argument := range flagArguments {
+			arguments[i] = []byte(argument)
+		}
+
+		r, err = executeScript(vm, ctx, code, arguments, view)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot execute script")
+		}
+	} else {
+		encodedTx, err := ioutil.ReadFile(flagTransaction)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot read transaction")
+		}
+
+		var txBody flow.TransactionBody
+		err = json.Unmarshal(encodedTx, &txBody)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot decode transaction")
+		}
+
+		r, err = executeTransaction(vm, ctx, &txBody, view)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot execute transaction")
+		}
+	}
+
+	common.PrettyPrint(r)
+}
+
+// loadTrie loads the trie of the given state, either from the checkpoint file,
+// or from the checkpoints and the WAL of the execution state dir, which is opened read-only
+func loadTrie(stateCommitment flow.StateCommitment) (*trie.MTrie, error) {
+	if len(flagCheckpoint) > 0 {
+		log.Info().Hex("state_commitment", stateCommitment).Str("checkpoint", flagCheckpoint).Msg("loading state from checkpoint")
+		return common.LoadCheckpointTrie(flagCheckpoint, stateCommitment)
+	}
+
+	if len(flagExecutionStateDir) == 0 {
+		log.Fatal().Msg("either checkpoint or execution-state-dir has to be provided")
+	}
+
+	log.Info().Hex("state_commitment", stateCommitment).Str("dir", flagExecutionStateDir).Msg("restoring state from checkpoints and WAL")
+
+	w, err := wal.OpenReadOnly(log.Logger, flagExecutionStateDir, complete.DefaultCacheSize, pathfinder.PathByteSize)
+	if err != nil {
+		return nil, err
+	}
+
+	t, found, err := w.RestoreTrie(ledger.RootHash(stateCommitment))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ledger.NewErrStatePruned(ledger.State(stateCommitment))
+	}
+
+	return t, nil
+}
@@ -0,0 +1,116 @@
+package execute_offline
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+type event struct {
+	TxIndex    uint32          `json:"tx_index"`
+	EventIndex uint32          `json:"event_index"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+type register struct {
+	OwnerHex      string `json:"owner_hex"`
+	ControllerHex string `json:"controller_hex"`
+	KeyHex        string `json:"key_hex"`
+	ValueHex      string `json:"value_hex"`
+}
+
+type result struct {
+	Value           json.RawMessage `json:"value,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	Events          []event         `json:"events"`
+	Logs            []string        `json:"logs"`
+	ComputationUsed uint64          `json:"computation_used"`
+	Delta           []register      `json:"delta"`
+}
+
+// executeScript executes the script on the view and returns its result
+func executeScript(vm *fvm.VirtualMachine, ctx fvm.Context, code []byte, arguments [][]byte, view *delta.View) (*result, error) {
+	script := fvm.Script(code).WithArguments(arguments...)
+
+	err := vm.Run(ctx, script, view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute script: %w", err)
+	}
+
+	r := newResult(script.Events, script.Logs, script.GasUsed, script.Err, view)
+
+	if script.Err == nil {
+		value, err := jsoncdc.Encode(script.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode script result: %w", err)
+		}
+		r.Value = value
+	}
+
+	return r, nil
+}
+
+// executeTransaction executes the transaction on the view and returns its result.
+// Unsigned transactions are executed without verifying signatures and sequence numbers, and without deducting fees.
+func executeTransaction(vm *fvm.VirtualMachine, ctx fvm.Context, txBody *flow.TransactionBody, view *delta.View) (*result, error) {
+	if len(txBody.PayloadSignatures) == 0 && len(txBody.EnvelopeSignatures) == 0 {
+		ctx = fvm.NewContextFromParent(ctx, fvm.WithTransactionProcessors(
+			fvm.NewTransactionInvocator(ctx.Logger),
+			fvm.NewTransactionStorageLimiter(),
+		))
+	}
+
+	tx := fvm.Transaction(txBody, 0)
+
+	err := vm.Run(ctx, tx, view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+
+	return newResult(tx.Events, tx.Logs, tx.GasUsed, tx.Err, view), nil
+}
+
+func newResult(events []flow.Event, logs []string, computationUsed uint64, procErr fvm.Error, view *delta.View) *result {
+	r := &result{
+		Events:          make([]event, 0, len(events)),
+		Logs:            logs,
+		ComputationUsed: computationUsed,
+	}
+
+	if r.Logs == nil {
+		r.Logs = []string{}
+	}
+
+	if procErr != nil {
+		r.Error = procErr.Error()
+	}
+
+	for _, e := range events {
+		r.Events = append(r.Events, event{
+			TxIndex:    e.TransactionIndex,
+			EventIndex: e.EventIndex,
+			EventType:  string(e.Type),
+			Payload:    e.Payload,
+		})
+	}
+
+	ids, values := view.Delta().RegisterUpdates()
+	r.Delta = make([]register, 0, len(ids))
+	for i, id := range ids {
+		r.Delta = append(r.Delta, register{
+			OwnerHex:      hex.EncodeToString([]byte(id.Owner)),
+			ControllerHex: hex.EncodeToString([]byte(id.Controller)),
+			KeyHex:        hex.EncodeToString([]byte(id.Key)),
+			ValueHex:      hex.EncodeToString(values[i]),
+		})
+	}
+
+	return r
+}
@@ -0,0 +1,115 @@
+package execute_offline
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func emptyView() *delta.View {
+	return delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		return nil, nil
+	})
+}
+
+func TestExecuteScript(t *testing.T) {
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithCadenceLogging(true))
+
+	t.Run("value", func(t *testing.T) {
+		code := []byte(`
+			pub fun main(x: Int): Int {
+				log(x)
+				return x * 2
+			}
+		`)
+
+		argument, err := jsoncdc.Encode(cadence.NewInt(21))
+		require.NoError(t, err)
+
+		r, err := executeScript(vm, ctx, code, [][]byte{argument}, emptyView())
+		require.NoError(t, err)
+
+		require.Empty(t, r.Error)
+		require.Equal(t, []string{"21"}, r.Logs)
+		require.Empty(t, r.Delta)
+
+		value, err := jsoncdc.Decode(r.Value)
+		require.NoError(t, err)
+		require.Equal(t, cadence.NewInt(42), value)
+
+		_, err = json.Marshal(r)
+		require.NoError(t, err)
+	})
+
+	t.Run("computation used", func(t *testing.T) {
+		code := []byte(`
+			pub fun main(): UInt64 {
+				return getAccount(0x01).storageUsed
+			}
+		`)
+
+		// every register holds a value, so the account exists, and reading its storage used reads some bytes
+		view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+			return flow.RegisterValue{0, 0, 0, 0, 0, 0, 0, 1}, nil
+		})
+
+		r, err := executeScript(vm, ctx, code, nil, view)
+		require.NoError(t, err)
+		require.Empty(t, r.Error)
+		require.Equal(t, uint64(0), r.ComputationUsed)
+
+		meteredCtx := fvm.NewContextFromParent(ctx, fvm.WithMeteringTable(fvm.MeteringTable{
+			fvm.MeteringBytesRead: 1,
+		}))
+
+		r, err = executeScript(vm, meteredCtx, code, nil, view)
+		require.NoError(t, err)
+		require.Empty(t, r.Error)
+		require.Greater(t, r.ComputationUsed, uint64(0))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		code := []byte(`
+			pub fun main(): Int {
+				panic("boom")
+			}
+		`)
+
+		r, err := executeScript(vm, ctx, code, nil, emptyView())
+		require.NoError(t, err)
+
+		require.Contains(t, r.Error, "boom")
+		require.Nil(t, r.Value)
+	})
+}
+
+func TestExecuteTransaction(t *testing.T) {
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithCadenceLogging(true))
+
+	txBody := flow.NewTransactionBody().
+		SetScript([]byte(`
+			transaction {
+				execute {
+					log("executed")
+				}
+			}
+		`))
+
+	r, err := executeTransaction(vm, ctx, txBody, emptyView())
+	require.NoError(t, err)
+
+	require.Empty(t, r.Error)
+	require.Equal(t, []string{`"executed"`}, r.Logs)
+	require.Empty(t, r.Events)
+}
@@ -0,0 +1,84 @@
+package export_payloads
+
+import (
+	"bufio"
+	"encoding/hex"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+var (
+	flagExecutionStateDir string
+	flagStateCommitment   string
+	flagOutputFile        string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "export-payloads",
+	Short: "Exports all payloads of a state of the execution state as a portable payload dump",
+	Long: `Exports all payloads of the given state as a portable payload dump, sorted by their paths.
+The dump can be imported with import-payloads to create the root checkpoint of a new execution node.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().StringVar(&flagStateCommitment, "state-commitment", "",
+		"state commitment (hex-encoded, 64 characters)")
+	_ = Cmd.MarkFlagRequired("state-commitment")
+
+	Cmd.Flags().StringVar(&flagOutputFile, "output-file", "",
+		"file to write the payload dump to")
+	_ = Cmd.MarkFlagRequired("output-file")
+}
+
+func run(*cobra.Command, []string) {
+
+	stateCommitment, err := hex.DecodeString(flagStateCommitment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot decode the state commitment")
+	}
+
+	led, err := complete.NewLedger(
+		flagExecutionStateDir,
+		complete.DefaultCacheSize,
+		&metrics.NoopCollector{},
+		log.Logger,
+		nil,
+		complete.DefaultPathFinderVersion)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create ledger from write-a-head logs and checkpoints")
+	}
+
+	file, err := os.Create(flagOutputFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create output file")
+	}
+
+	writer := bufio.NewWriter(file)
+	err = led.ExportPayloads(ledger.State(stateCommitment), writer)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot export payloads")
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot flush output file")
+	}
+
+	err = file.Close()
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot close output file")
+	}
+
+	log.Info().Hex("state_commitment", stateCommitment).Str("file", flagOutputFile).Msg("payloads exported")
+}
@@ -0,0 +1,51 @@
+package import_payloads
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/ledger/complete/dump"
+)
+
+var (
+	flagInputFile string
+	flagOutputDir string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "import-payloads",
+	Short: "Creates a root checkpoint from a portable payload dump",
+	Long: `Builds the trie of all payloads of a payload dump (as written by export-payloads) and stores it as the
+root checkpoint in the output directory. The root hash of the trie is verified against the state commitment
+recorded in the dump; no checkpoint is written if they don't match.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagInputFile, "input-file", "",
+		"payload dump to import")
+	_ = Cmd.MarkFlagRequired("input-file")
+
+	Cmd.Flags().StringVar(&flagOutputDir, "output-dir", "",
+		"directory to write the root checkpoint to")
+	_ = Cmd.MarkFlagRequired("output-dir")
+}
+
+func run(*cobra.Command, []string) {
+
+	file, err := os.Open(flagInputFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot open payload dump")
+	}
+	defer file.Close()
+
+	stateCommitment, err := dump.ImportRootCheckpoint(bufio.NewReader(file), flagOutputDir, log.Logger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot import payload dump")
+	}
+
+	log.Info().Hex("state_commitment", stateCommitment).Str("dir", flagOutputDir).Msg("root checkpoint created")
+}
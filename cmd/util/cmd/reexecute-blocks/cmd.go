@@ -0,0 +1,123 @@
+package reexecute_blocks
+
+import (
+	"context"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/execution/computation"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+)
+
+var (
+	flagDatadir           string
+	flagExecutionStateDir string
+	flagFromHeight        uint64
+	flagToHeight          uint64
+	flagParallelism       uint
+)
+
+var Cmd = &cobra.Command{
+	Use:   "reexecute-blocks",
+	Short: "Re-executes a range of finalized blocks and compares the results with the persisted execution receipts",
+	Long: `Re-executes a range of finalized blocks offline with the computation manager, each on top of its persisted
+start state restored from the checkpoints and the WAL of an execution state dir, which is opened read-only.
+The end state, the event collection and the events of each recomputed chunk, and the ID of each recomputed
+execution result are compared with the persisted execution receipt of the block.
+
+The first divergence is printed as JSON, together with the register-level diff between the persisted and the
+recomputed end states of the first diverging chunk. Nothing is written, neither to the checkpoints and the WAL,
+nor to the protocol state.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written)")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().Uint64Var(&flagFromHeight, "from-height", 0,
+		"height of the first block to re-execute")
+	_ = Cmd.MarkFlagRequired("from-height")
+
+	Cmd.Flags().Uint64Var(&flagToHeight, "to-height", 0,
+		"height of the last block to re-execute (defaults to from-height)")
+
+	Cmd.Flags().UintVar(&flagParallelism, "parallelism", 1,
+		"number of transactions of a collection executed concurrently")
+}
+
+func run(*cobra.Command, []string) {
+
+	toHeight := flagToHeight
+	if toHeight == 0 {
+		toHeight = flagFromHeight
+	}
+	if toHeight < flagFromHeight {
+		log.Fatal().Uint64("from", flagFromHeight).Uint64("to", toHeight).Msg("invalid height range")
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+
+	first, err := storages.Headers.ByHeight(flagFromHeight)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot get first block")
+	}
+
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+	vmCtx := fvm.NewContext(log.Logger, cmd.FvmOptions(first.ChainID, storages.Headers)...)
+
+	manager, err := computation.New(
+		log.Logger,
+		metrics.NewNoopCollector(),
+		trace.NewNoopTracer(),
+		nil,
+		nil,
+		vm,
+		vmCtx,
+		flagParallelism,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create computation manager")
+	}
+
+	w, err := wal.OpenReadOnly(log.Logger, flagExecutionStateDir, complete.DefaultCacheSize, pathfinder.PathByteSize)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot open WAL")
+	}
+
+	r, err := newReexecutor(log.Logger, manager, storages, w)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create re-executor")
+	}
+
+	log.Info().Uint64("from", flagFromHeight).Uint64("to", toHeight).Msg("re-executing blocks")
+
+	d, err := r.reexecuteRange(context.Background(), flagFromHeight, toHeight)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot re-execute blocks")
+	}
+
+	if d != nil {
+		common.PrettyPrint(d)
+		log.Fatal().Uint64("height", d.Height).Hex("block_id", d.BlockID[:]).Msg("recomputed execution result diverges")
+	}
+
+	log.Info().Msg("all recomputed execution results match")
+}
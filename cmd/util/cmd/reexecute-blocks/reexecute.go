@@ -0,0 +1,433 @@
+package reexecute_blocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/execution/computation"
+	"github.com/onflow/flow-go/engine/execution/ingestion"
+	executionState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// forestCapacity is the number of tries held by the forest the recomputed chunks are applied to
+const forestCapacity = 100
+
+// TrieRestorer restores tries of the execution state, for example from checkpoints and the WAL
+type TrieRestorer interface {
+	RestoreTrie(rootHash ledger.RootHash) (*trie.MTrie, bool, error)
+}
+
+// mismatch is a difference between a recomputed and a persisted field of an execution result
+type mismatch struct {
+	// Chunk is the index of the chunk, or nil if the field is a field of the execution result
+	Chunk    *uint64 `json:"chunk,omitempty"`
+	Field    string  `json:"field"`
+	Expected string  `json:"expected"`
+	Actual   string  `json:"actual"`
+}
+
+// divergence is the first block whose recomputed execution result differs from the persisted one
+type divergence struct {
+	BlockID          flow.Identifier `json:"block_id"`
+	Height           uint64          `json:"height"`
+	ExpectedResultID flow.Identifier `json:"expected_result_id"`
+	ActualResultID   flow.Identifier `json:"actual_result_id"`
+	Mismatches       []mismatch      `json:"mismatches"`
+	// RegisterDiff are the differences between the persisted and the recomputed end states
+	// of the first chunk whose end state differs
+	RegisterDiff []registerDiff `json:"register_diff,omitempty"`
+}
+
+type registerDiff struct {
+	Type       string `json:"type"`
+	Owner      string `json:"owner"`
+	Controller string `json:"controller"`
+	Key        string `json:"key"`
+	Expected   string `json:"expected"`
+	Actual     string `json:"actual"`
+}
+
+// reexecutor re-executes finalized blocks and compares their execution results with the persisted ones
+type reexecutor struct {
+	log      zerolog.Logger
+	manager  computation.ComputationManager
+	storages *storage.All
+	restorer TrieRestorer
+	forest   *mtrie.Forest
+}
+
+func newReexecutor(
+	log zerolog.Logger,
+	manager computation.ComputationManager,
+	storages *storage.All,
+	restorer TrieRestorer,
+) (*reexecutor, error) {
+	forest, err := mtrie.NewForest(pathfinder.PathByteSize, "", forestCapacity, &metrics.NoopCollector{}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create forest: %w", err)
+	}
+
+	return &reexecutor{
+		log:      log,
+		manager:  manager,
+		storages: storages,
+		restorer: restorer,
+		forest:   forest,
+	}, nil
+}
+
+// reexecuteRange re-executes the finalized blocks from the given height to the given height, inclusive,
+// and returns the first divergence, or nil if all recomputed execution results match the persisted ones
+func (r *reexecutor) reexecuteRange(ctx context.Context, from, to uint64) (*divergence, error) {
+	for height := from; height <= to; height++ {
+		block, err := r.storages.Blocks.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get block at height %d: %w", height, err)
+		}
+
+		d, err := r.reexecuteBlock(ctx, block)
+		if err != nil {
+			return nil, fmt.Errorf("cannot re-execute block %x at height %d: %w", block.ID(), height, err)
+		}
+		if d != nil {
+			return d, nil
+		}
+
+		r.log.Info().
+			Uint64("height", height).
+			Hex("block_id", logging.Entity(block)).
+			Msg("recomputed execution result matches")
+	}
+
+	return nil, nil
+}
+
+// reexecuteBlock re-executes the block on top of its persisted start state, and returns the divergence
+// of the recomputed execution result from the persisted one, if any
+func (r *reexecutor) reexecuteBlock(ctx context.Context, block *flow.Block) (*divergence, error) {
+	blockID := block.ID()
+
+	startState, err := r.storages.Commits.ByBlockID(block.Header.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get start state: %w", err)
+	}
+
+	startTrie, err := r.trie(startState)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get start state trie: %w", err)
+	}
+
+	executableBlock := &entity.ExecutableBlock{
+		Block:               block,
+		CompleteCollections: make(map[flow.Identifier]*entity.CompleteCollection, len(block.Payload.Guarantees)),
+		StartState:          startState,
+	}
+
+	// the number of transactions of each collection, to attribute events to chunks
+	collectionSizes := make([]int, len(block.Payload.Guarantees))
+
+	for i, guarantee := range block.Payload.Guarantees {
+		collection, err := r.storages.Collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get collection %x: %w", guarantee.CollectionID, err)
+		}
+
+		executableBlock.CompleteCollections[guarantee.ID()] = &entity.CompleteCollection{
+			Guarantee:    guarantee,
+			Transactions: collection.Transactions,
+		}
+		collectionSizes[i] = len(collection.Transactions)
+	}
+
+	receipt, err := r.storages.Receipts.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get persisted execution receipt: %w", err)
+	}
+	expected := &receipt.ExecutionResult
+
+	previousResult, err := r.storages.Results.ByBlockID(block.Header.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get execution result of parent block: %w", err)
+	}
+
+	persistedEvents, err := r.storages.Events.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get persisted events: %w", err)
+	}
+
+	view := delta.NewView(common.TrieGetRegister(startTrie))
+
+	computationResult, err := r.manager.ComputeBlock(ctx, executableBlock, view)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute block: %w", err)
+	}
+
+	d := &divergence{
+		BlockID:          blockID,
+		Height:           block.Header.Height,
+		ExpectedResultID: expected.ID(),
+	}
+
+	chunks := make([]*flow.Chunk, len(computationResult.StateSnapshots))
+
+	// the chunks are committed one after the other, just like the ingestion engine does
+	chunkStartState := startState
+	var firstTransaction uint32
+
+	for i, snapshot := range computationResult.StateSnapshots {
+		endState, err := r.commitDelta(chunkStartState, snapshot.Delta)
+		if err != nil {
+			return nil, fmt.Errorf("cannot apply delta of chunk %d: %w", i, err)
+		}
+
+		// account for system chunk being last
+		collectionID := flow.ZeroID
+		transactions := len(computationResult.TransactionResult) - int(firstTransaction)
+		if i < len(computationResult.StateSnapshots)-1 {
+			guarantee := block.Payload.Guarantees[i]
+			collectionID = executableBlock.CompleteCollections[guarantee.ID()].Collection().ID()
+			transactions = collectionSizes[i]
+		}
+
+		chunks[i] = ingestion.GenerateChunk(i, chunkStartState, endState, collectionID, blockID)
+
+		lastTransaction := firstTransaction + uint32(transactions)
+
+		if i < len(expected.Chunks) {
+			mismatches := compareChunk(
+				expected.Chunks[i],
+				chunks[i],
+				eventsHash(persistedEvents, firstTransaction, lastTransaction),
+				eventsHash(computationResult.Events, firstTransaction, lastTransaction),
+			)
+
+			if len(mismatches) > 0 {
+				d.Mismatches = mismatches
+
+				if !bytes.Equal(expected.Chunks[i].EndState, endState) {
+					d.RegisterDiff, err = r.registerDiff(expected.Chunks[i].EndState, endState)
+					if err != nil {
+						return nil, fmt.Errorf("cannot diff end states of chunk %d: %w", i, err)
+					}
+				}
+
+				return d, nil
+			}
+		}
+
+		chunkStartState = endState
+		firstTransaction = lastTransaction
+	}
+
+	recomputed := &flow.ExecutionResult{
+		PreviousResultID: previousResult.ID(),
+		BlockID:          blockID,
+		Chunks:           chunks,
+		ServiceEvents:    computationResult.ServiceEvents,
+	}
+	d.ActualResultID = recomputed.ID()
+
+	if d.ActualResultID == d.ExpectedResultID {
+		return nil, nil
+	}
+
+	d.Mismatches = compareResult(expected, recomputed)
+	return d, nil
+}
+
+// trie returns the trie of the given state, restoring it if it is not held by the forest
+func (r *reexecutor) trie(state flow.StateCommitment) (*trie.MTrie, error) {
+	rootHash := ledger.RootHash(state)
+
+	if r.forest.HasTrie(rootHash) {
+		return r.forest.GetTrie(rootHash)
+	}
+
+	r.log.Info().Hex("state", state).Msg("restoring state")
+
+	t, found, err := r.restorer.RestoreTrie(rootHash)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ledger.NewErrStatePruned(ledger.State(state))
+	}
+
+	err = r.forest.AddTrie(t)
+	if err != nil {
+		return nil, fmt.Errorf("cannot add trie to forest: %w", err)
+	}
+
+	return t, nil
+}
+
+// commitDelta applies the delta to the given state, the same way the execution state does, and returns the new state
+func (r *reexecutor) commitDelta(state flow.StateCommitment, d delta.Delta) (flow.StateCommitment, error) {
+	ids, values := d.RegisterUpdates()
+
+	update, err := ledger.NewUpdate(
+		ledger.State(state),
+		executionState.RegisterIDSToKeys(ids),
+		executionState.RegisterValuesToValues(values),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create ledger update: %w", err)
+	}
+
+	trieUpdate, err := pathfinder.UpdateToTrieUpdate(update, complete.DefaultPathFinderVersion)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create trie update: %w", err)
+	}
+
+	rootHash, err := r.forest.Update(trieUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("cannot update trie: %w", err)
+	}
+
+	return flow.StateCommitment(rootHash), nil
+}
+
+// registerDiff returns the registers which differ between the expected and the actual state
+func (r *reexecutor) registerDiff(expectedState, actualState flow.StateCommitment) ([]registerDiff, error) {
+	expectedTrie, err := r.trie(expectedState)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get expected state: %w", err)
+	}
+
+	actualTrie, err := r.forest.GetTrie(ledger.RootHash(actualState))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get actual state: %w", err)
+	}
+
+	var diffs []registerDiff
+
+	err = trie.Diff(expectedTrie, actualTrie, func(diff *ledger.PayloadDiff) error {
+		payload := diff.After
+		if payload == nil {
+			payload = diff.Before
+		}
+
+		id, err := executionState.KeyToRegisterID(payload.Key)
+		if err != nil {
+			return err
+		}
+
+		rd := registerDiff{
+			Type:       diff.Type.String(),
+			Owner:      hex.EncodeToString([]byte(id.Owner)),
+			Controller: hex.EncodeToString([]byte(id.Controller)),
+			Key:        id.Key,
+		}
+		if diff.Before != nil {
+			rd.Expected = hex.EncodeToString(diff.Before.Value)
+		}
+		if diff.After != nil {
+			rd.Actual = hex.EncodeToString(diff.After.Value)
+		}
+
+		diffs = append(diffs, rd)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return diffs, nil
+}
+
+// compareChunk returns the differences between the persisted and the recomputed chunk
+func compareChunk(expected, actual *flow.Chunk, expectedEventsHash, actualEventsHash flow.Identifier) []mismatch {
+	index := actual.Index
+
+	var mismatches []mismatch
+	add := func(field, expected, actual string) {
+		mismatches = append(mismatches, mismatch{
+			Chunk:    &index,
+			Field:    field,
+			Expected: expected,
+			Actual:   actual,
+		})
+	}
+
+	if !bytes.Equal(expected.StartState, actual.StartState) {
+		add("start_state", hex.EncodeToString(expected.StartState), hex.EncodeToString(actual.StartState))
+	}
+	if !bytes.Equal(expected.EndState, actual.EndState) {
+		add("end_state", hex.EncodeToString(expected.EndState), hex.EncodeToString(actual.EndState))
+	}
+	if expected.EventCollection != actual.EventCollection {
+		add("event_collection", expected.EventCollection.String(), actual.EventCollection.String())
+	}
+	if expectedEventsHash != actualEventsHash {
+		add("events", expectedEventsHash.String(), actualEventsHash.String())
+	}
+	if len(mismatches) == 0 && expected.ID() != actual.ID() {
+		add("id", expected.ID().String(), actual.ID().String())
+	}
+
+	return mismatches
+}
+
+// compareResult returns the differences between the persisted and the recomputed execution result
+func compareResult(expected, actual *flow.ExecutionResult) []mismatch {
+	var mismatches []mismatch
+	add := func(field, expected, actual string) {
+		mismatches = append(mismatches, mismatch{
+			Field:    field,
+			Expected: expected,
+			Actual:   actual,
+		})
+	}
+
+	if expected.PreviousResultID != actual.PreviousResultID {
+		add("previous_result_id", expected.PreviousResultID.String(), actual.PreviousResultID.String())
+	}
+	if len(expected.Chunks) != len(actual.Chunks) {
+		add("chunks", fmt.Sprint(len(expected.Chunks)), fmt.Sprint(len(actual.Chunks)))
+	}
+	if flow.MakeID(expected.ServiceEvents) != flow.MakeID(actual.ServiceEvents) {
+		add("service_events", flow.MakeID(expected.ServiceEvents).String(), flow.MakeID(actual.ServiceEvents).String())
+	}
+	if len(mismatches) == 0 {
+		add("id", expected.ID().String(), actual.ID().String())
+	}
+
+	return mismatches
+}
+
+// eventsHash returns the hash of the events emitted by the transactions with an index
+// from first (inclusive) to last (exclusive), in order of emission
+func eventsHash(events []flow.Event, first, last uint32) flow.Identifier {
+	var chunkEvents []flow.Event
+	for _, event := range events {
+		if event.TransactionIndex >= first && event.TransactionIndex < last {
+			chunkEvents = append(chunkEvents, event)
+		}
+	}
+
+	sort.SliceStable(chunkEvents, func(i, j int) bool {
+		if chunkEvents[i].TransactionIndex != chunkEvents[j].TransactionIndex {
+			return chunkEvents[i].TransactionIndex < chunkEvents[j].TransactionIndex
+		}
+		return chunkEvents[i].EventIndex < chunkEvents[j].EventIndex
+	})
+
+	return flow.MakeID(chunkEvents)
+}
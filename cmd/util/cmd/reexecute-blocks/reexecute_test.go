@@ -0,0 +1,196 @@
+package reexecute_blocks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution"
+	computationmock "github.com/onflow/flow-go/engine/execution/computation/mock"
+	"github.com/onflow/flow-go/engine/execution/ingestion"
+	executionState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// tries is a trie restorer holding the tries in memory
+type tries map[string]*trie.MTrie
+
+func (t tries) RestoreTrie(rootHash ledger.RootHash) (*trie.MTrie, bool, error) {
+	restored, ok := t[string(rootHash)]
+	return restored, ok, nil
+}
+
+func TestReexecuteBlock(t *testing.T) {
+	owner := string(flow.HexToAddress("01").Bytes())
+
+	emptyTrie, err := trie.NewEmptyMTrie(pathfinder.PathByteSize)
+	require.NoError(t, err)
+	startState := flow.StateCommitment(emptyTrie.RootHash())
+
+	// applyDelta applies the delta to the empty trie, independently of the re-executor
+	applyDelta := func(t *testing.T, d delta.Delta) *trie.MTrie {
+		forest, err := mtrie.NewForest(pathfinder.PathByteSize, "", forestCapacity, &metrics.NoopCollector{}, nil)
+		require.NoError(t, err)
+
+		ids, values := d.RegisterUpdates()
+		update, err := ledger.NewUpdate(
+			ledger.State(startState),
+			executionState.RegisterIDSToKeys(ids),
+			executionState.RegisterValuesToValues(values),
+		)
+		require.NoError(t, err)
+
+		trieUpdate, err := pathfinder.UpdateToTrieUpdate(update, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		rootHash, err := forest.Update(trieUpdate)
+		require.NoError(t, err)
+
+		updated, err := forest.GetTrie(rootHash)
+		require.NoError(t, err)
+		return updated
+	}
+
+	recomputedDelta := delta.NewDelta()
+	recomputedDelta.Set(owner, "", "a", flow.RegisterValue("recomputed"))
+
+	block := unittest.BlockFixture()
+	block.Payload.Guarantees = nil
+	blockID := block.ID()
+
+	previousResult := unittest.ExecutionResultFixture()
+
+	events := []flow.Event{
+		unittest.EventFixture(flow.EventAccountCreated, 0, 0, unittest.IdentifierFixture()),
+	}
+
+	// setup returns a re-executor recomputing the delta and the events, and the persisted states, receipt and events
+	setup := func(t *testing.T, persistedDelta delta.Delta, persistedEvents []flow.Event) *reexecutor {
+		persistedTrie := applyDelta(t, persistedDelta)
+		persistedEndState := flow.StateCommitment(persistedTrie.RootHash())
+
+		receipt := &flow.ExecutionReceipt{
+			ExecutionResult: flow.ExecutionResult{
+				PreviousResultID: previousResult.ID(),
+				BlockID:          blockID,
+				Chunks: flow.ChunkList{
+					ingestion.GenerateChunk(0, startState, persistedEndState, flow.ZeroID, blockID),
+				},
+			},
+		}
+
+		commits := new(storagemock.Commits)
+		commits.On("ByBlockID", block.Header.ParentID).Return(startState, nil)
+
+		receipts := new(storagemock.ExecutionReceipts)
+		receipts.On("ByBlockID", blockID).Return(receipt, nil)
+
+		results := new(storagemock.ExecutionResults)
+		results.On("ByBlockID", block.Header.ParentID).Return(previousResult, nil)
+
+		persisted := new(storagemock.Events)
+		persisted.On("ByBlockID", blockID).Return(persistedEvents, nil)
+
+		manager := new(computationmock.ComputationManager)
+		manager.On("ComputeBlock", mock.Anything, mock.Anything, mock.Anything).
+			Return(&execution.ComputationResult{
+				StateSnapshots: []*delta.SpockSnapshot{
+					{Snapshot: delta.Snapshot{Delta: recomputedDelta}},
+				},
+				Events:            events,
+				TransactionResult: []flow.TransactionResult{{}},
+			}, nil)
+
+		storages := &storage.All{
+			Commits:  commits,
+			Receipts: receipts,
+			Results:  results,
+			Events:   persisted,
+		}
+
+		restorer := tries{
+			string(emptyTrie.RootHash()):     emptyTrie,
+			string(persistedTrie.RootHash()): persistedTrie,
+		}
+
+		r, err := newReexecutor(zerolog.Nop(), manager, storages, restorer)
+		require.NoError(t, err)
+		return r
+	}
+
+	t.Run("matching result", func(t *testing.T) {
+		r := setup(t, recomputedDelta, events)
+
+		d, err := r.reexecuteBlock(context.Background(), &block)
+		require.NoError(t, err)
+		assert.Nil(t, d)
+	})
+
+	t.Run("diverging end state", func(t *testing.T) {
+		persistedDelta := delta.NewDelta()
+		persistedDelta.Set(owner, "", "a", flow.RegisterValue("persisted"))
+		persistedDelta.Set(owner, "", "b", flow.RegisterValue("removed"))
+
+		r := setup(t, persistedDelta, events)
+
+		d, err := r.reexecuteBlock(context.Background(), &block)
+		require.NoError(t, err)
+		require.NotNil(t, d)
+
+		assert.Equal(t, blockID, d.BlockID)
+		require.Len(t, d.Mismatches, 1)
+		assert.Equal(t, "end_state", d.Mismatches[0].Field)
+		assert.Equal(t, uint64(0), *d.Mismatches[0].Chunk)
+
+		require.Len(t, d.RegisterDiff, 2)
+		diffs := make(map[string]registerDiff)
+		for _, diff := range d.RegisterDiff {
+			diffs[diff.Key] = diff
+		}
+		assert.Equal(t, ledger.PayloadModified.String(), diffs["a"].Type)
+		assert.Equal(t, ledger.PayloadRemoved.String(), diffs["b"].Type)
+	})
+
+	t.Run("diverging events", func(t *testing.T) {
+		r := setup(t, recomputedDelta, nil)
+
+		d, err := r.reexecuteBlock(context.Background(), &block)
+		require.NoError(t, err)
+		require.NotNil(t, d)
+
+		require.Len(t, d.Mismatches, 1)
+		assert.Equal(t, "events", d.Mismatches[0].Field)
+		assert.Empty(t, d.RegisterDiff)
+	})
+}
+
+func TestEventsHash(t *testing.T) {
+	txID := unittest.IdentifierFixture()
+	first := unittest.EventFixture(flow.EventAccountCreated, 1, 0, txID)
+	second := unittest.EventFixture(flow.EventAccountCreated, 1, 1, txID)
+	other := unittest.EventFixture(flow.EventAccountCreated, 2, 0, txID)
+
+	// events are ordered, and only events of the transactions of the chunk are included
+	assert.Equal(t,
+		eventsHash([]flow.Event{first, second}, 1, 2),
+		eventsHash([]flow.Event{other, second, first}, 1, 2),
+	)
+	assert.NotEqual(t,
+		eventsHash([]flow.Event{first, second}, 1, 2),
+		eventsHash([]flow.Event{first}, 1, 2),
+	)
+}
@@ -0,0 +1,124 @@
+package replay_transaction
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagDatadir       string
+	flagCheckpoint    string
+	flagBlockID       string
+	flagTransactionID string
+	flagJSON          bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "replay-transaction",
+	Short: "Replays a transaction of a historical block against a checkpoint and prints its register access trace",
+	Long: `Replays a transaction of a historical block offline, on top of the start state of the block loaded from a checkpoint.
+The transactions of the block preceding the transaction are executed first, in the same context and on the same
+views of the collections of the block as on the execution node, then the transaction is executed with tracing
+enabled, and all its register accesses and emitted events are printed in order.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagCheckpoint, "checkpoint", "",
+		"checkpoint file containing the start state of the block")
+	_ = Cmd.MarkFlagRequired("checkpoint")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"block ID (hex-encoded, 64 characters)")
+	_ = Cmd.MarkFlagRequired("block-id")
+
+	Cmd.Flags().StringVar(&flagTransactionID, "transaction-id", "",
+		"ID of the transaction to replay (hex-encoded, 64 characters)")
+	_ = Cmd.MarkFlagRequired("transaction-id")
+
+	Cmd.Flags().BoolVar(&flagJSON, "json", false,
+		"print the trace as JSON")
+}
+
+func run(*cobra.Command, []string) {
+
+	blockID, err := flow.HexStringToIdentifier(flagBlockID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("malformed block ID")
+	}
+
+	txID, err := flow.HexStringToIdentifier(flagTransactionID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("malformed transaction ID")
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+
+	block, err := storages.Blocks.ByID(blockID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot get block")
+	}
+
+	collections := make([]*flow.Collection, 0, len(block.Payload.Guarantees))
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := storages.Collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			log.Fatal().Err(err).Hex("collection_id", guarantee.CollectionID[:]).Msg("cannot get collection")
+		}
+		collections = append(collections, collection)
+	}
+
+	startState, err := storages.Commits.ByBlockID(block.Header.ParentID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot get start state of block")
+	}
+
+	log.Info().Hex("start_state", startState).Str("checkpoint", flagCheckpoint).Msg("loading start state of block")
+
+	t, err := common.LoadCheckpointTrie(flagCheckpoint, startState)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot load start state of block")
+	}
+
+	// the context the execution node executes the transactions of the block in, see computer.NewBlockComputer
+	vmOpts := append(cmd.FvmOptions(block.Header.ChainID, storages.Headers),
+		fvm.WithBlockHeader(block.Header),
+	)
+
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+	ctx := fvm.NewContext(log.Logger, vmOpts...)
+
+	tx, err := replayTransaction(vm, ctx, collections, txID, common.TrieGetRegister(t))
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot replay transaction")
+	}
+
+	if flagJSON {
+		common.PrettyPrint(tx.Trace)
+	} else {
+		for i, entry := range tx.Trace.Entries {
+			fmt.Printf("%d: %s\n", i, entry)
+		}
+	}
+
+	if tx.Err != nil {
+		log.Info().Uint32("tx_index", tx.TxIndex).Str("error", tx.Err.Error()).Msg("transaction failed")
+	} else {
+		log.Info().Uint32("tx_index", tx.TxIndex).Int("events", len(tx.Events)).Msg("transaction executed successfully")
+	}
+}
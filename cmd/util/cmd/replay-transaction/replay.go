@@ -0,0 +1,56 @@
+package replay_transaction
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// replayTransaction executes a transaction of a block on top of the start state of the block, like the execution node does:
+// each collection of the block is executed on its own view, and each transaction on a view of its collection.
+// The transactions preceding the transaction in the block, including those of its collection, are executed first,
+// and then the transaction itself with tracing enabled.
+func replayTransaction(
+	vm computer.VirtualMachine,
+	blockCtx fvm.Context,
+	collections []*flow.Collection,
+	txID flow.Identifier,
+	getRegister delta.GetRegisterFunc,
+) (*fvm.TransactionProcedure, error) {
+
+	blockView := delta.NewView(getRegister)
+
+	var txIndex uint32
+	for _, collection := range collections {
+		collectionView := blockView.NewChild()
+
+		for _, txBody := range collection.Transactions {
+			txView := collectionView.NewChild()
+			tx := fvm.Transaction(txBody, txIndex)
+
+			if tx.ID == txID {
+				err := vm.Run(fvm.NewContextFromParent(blockCtx, fvm.WithTransactionTrace(true)), tx, txView)
+				if err != nil {
+					return nil, fmt.Errorf("failed to execute transaction %x: %w", txID, err)
+				}
+				return tx, nil
+			}
+
+			err := vm.Run(blockCtx, tx, txView)
+			if err != nil {
+				return nil, fmt.Errorf("failed to execute preceding transaction %x: %w", tx.ID, err)
+			}
+			// failed transactions are merged too, as their fees and sequence number increments are kept
+			collectionView.MergeView(txView)
+
+			txIndex++
+		}
+
+		blockView.MergeView(collectionView)
+	}
+
+	return nil, fmt.Errorf("block does not contain transaction %x", txID)
+}
@@ -0,0 +1,96 @@
+package replay_transaction
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// counterVM increments a counter register for every transaction, and fails the transactions
+// with a gas limit of one after incrementing it, like a failed transaction still paying its fee.
+type counterVM struct {
+	// traced are the IDs of the transactions executed with tracing enabled
+	traced []flow.Identifier
+	// counters are the values of the counter read by each transaction
+	counters map[flow.Identifier]uint64
+}
+
+func (vm *counterVM) Run(ctx fvm.Context, proc fvm.Procedure, ledger state.Ledger) error {
+	tx := proc.(*fvm.TransactionProcedure)
+	if ctx.TransactionTraceEnabled {
+		vm.traced = append(vm.traced, tx.ID)
+	}
+
+	value, err := ledger.Get("owner", "", "counter")
+	if err != nil {
+		return err
+	}
+
+	var counter uint64
+	if len(value) > 0 {
+		counter = binary.BigEndian.Uint64(value)
+	}
+	vm.counters[tx.ID] = counter
+
+	value = make([]byte, 8)
+	binary.BigEndian.PutUint64(value, counter+1)
+	err = ledger.Set("owner", "", "counter", value)
+	if err != nil {
+		return err
+	}
+
+	if tx.Transaction.GasLimit == 1 {
+		tx.Err = &fvm.MissingPayerError{}
+	}
+
+	return nil
+}
+
+func TestReplayTransaction(t *testing.T) {
+	collections := []*flow.Collection{
+		{Transactions: []*flow.TransactionBody{
+			{Script: []byte("transaction {}"), GasLimit: 10},
+			{Script: []byte("transaction {}"), GasLimit: 1},
+		}},
+		{Transactions: []*flow.TransactionBody{
+			{Script: []byte("transaction {}"), GasLimit: 11},
+			{Script: []byte("transaction {}"), GasLimit: 12},
+			{Script: []byte("transaction {}"), GasLimit: 13},
+		}},
+	}
+	target := collections[1].Transactions[1]
+
+	vm := &counterVM{counters: make(map[flow.Identifier]uint64)}
+	getRegister := func(owner, controller, key string) (flow.RegisterValue, error) {
+		return nil, nil
+	}
+
+	tx, err := replayTransaction(vm, fvm.NewContext(unittest.Logger()), collections, target.ID(), getRegister)
+	require.NoError(t, err)
+
+	assert.Equal(t, target.ID(), tx.ID)
+	assert.Equal(t, uint32(3), tx.TxIndex)
+
+	// only the transaction itself is traced, and the transactions following it are not executed
+	assert.Equal(t, []flow.Identifier{target.ID()}, vm.traced)
+	assert.Len(t, vm.counters, 4)
+
+	// the transaction reads the state written by the transactions preceding it, including the failed ones,
+	// in its own collection and in the collections before
+	assert.Equal(t, uint64(0), vm.counters[collections[0].Transactions[0].ID()])
+	assert.Equal(t, uint64(1), vm.counters[collections[0].Transactions[1].ID()])
+	assert.Equal(t, uint64(2), vm.counters[collections[1].Transactions[0].ID()])
+	assert.Equal(t, uint64(3), vm.counters[target.ID()])
+
+	t.Run("missing transaction", func(t *testing.T) {
+		_, err := replayTransaction(vm, fvm.NewContext(unittest.Logger()), collections, unittest.IdentifierFixture(), getRegister)
+		require.Error(t, err)
+	})
+}
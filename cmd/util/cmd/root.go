@@ -11,8 +11,15 @@ import (
 
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
 	export "github.com/onflow/flow-go/cmd/util/cmd/exec-data-json-export"
+	execute_offline "github.com/onflow/flow-go/cmd/util/cmd/execute-offline"
 	extract "github.com/onflow/flow-go/cmd/util/cmd/execution-state-extract"
+	export_payloads "github.com/onflow/flow-go/cmd/util/cmd/export-payloads"
+	import_payloads "github.com/onflow/flow-go/cmd/util/cmd/import-payloads"
+	reexecute_blocks "github.com/onflow/flow-go/cmd/util/cmd/reexecute-blocks"
+	replay_transaction "github.com/onflow/flow-go/cmd/util/cmd/replay-transaction"
+	storage_report "github.com/onflow/flow-go/cmd/util/cmd/storage-report"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
+	verify_wal "github.com/onflow/flow-go/cmd/util/cmd/verify-wal"
 )
 
 var (
@@ -50,6 +57,13 @@ func addCommands() {
 	rootCmd.AddCommand(export.Cmd)
 	rootCmd.AddCommand(checkpoint_list_tries.Cmd)
 	rootCmd.AddCommand(truncate_database.Cmd)
+	rootCmd.AddCommand(verify_wal.Cmd)
+	rootCmd.AddCommand(export_payloads.Cmd)
+	rootCmd.AddCommand(import_payloads.Cmd)
+	rootCmd.AddCommand(replay_transaction.Cmd)
+	rootCmd.AddCommand(execute_offline.Cmd)
+	rootCmd.AddCommand(storage_report.Cmd)
+	rootCmd.AddCommand(reexecute_blocks.Cmd)
 }
 
 func initConfig() {
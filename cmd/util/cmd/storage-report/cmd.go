@@ -0,0 +1,136 @@
+package storage_report
+
+import (
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+var (
+	flagCheckpoint       string
+	flagStateCommitment  string
+	flagChain            string
+	flagOutputDir        string
+	flagFormat           string
+	flagLargestRegisters int
+)
+
+var Cmd = &cobra.Command{
+	Use:   "storage-report",
+	Short: "Reports the storage used by each account of a state loaded from a checkpoint, against its storage capacity",
+	Long: `Reports the storage used by each account of a state loaded from a checkpoint, against its storage capacity,
+as computed by the storage fees contract of the service account. The report of each account also includes
+its number of registers and their total size, its largest registers, and the breakdown of its registers
+by contract and by storage path.
+
+As CSV, the report is written to three files in the output dir: storage_report_accounts.csv,
+storage_report_registers.csv and storage_report_breakdown.csv. As JSON, it is written to storage_report.json.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagCheckpoint, "checkpoint", "",
+		"checkpoint file containing the state")
+	_ = Cmd.MarkFlagRequired("checkpoint")
+
+	Cmd.Flags().StringVar(&flagStateCommitment, "state-commitment", "",
+		"state commitment (hex-encoded, 64 characters)")
+	_ = Cmd.MarkFlagRequired("state-commitment")
+
+	Cmd.Flags().StringVar(&flagChain, "chain", string(flow.Mainnet),
+		"chain ID")
+
+	Cmd.Flags().StringVar(&flagOutputDir, "output-dir", "",
+		"directory to write the report to")
+	_ = Cmd.MarkFlagRequired("output-dir")
+
+	Cmd.Flags().StringVar(&flagFormat, "format", formatCSV,
+		"format of the report (csv or json)")
+
+	Cmd.Flags().IntVar(&flagLargestRegisters, "largest-registers", 10,
+		"number of largest registers reported per account")
+}
+
+func run(*cobra.Command, []string) {
+
+	if flagFormat != formatCSV && flagFormat != formatJSON {
+		log.Fatal().Str("format", flagFormat).Msg("unsupported format, choose one of \"csv\" or \"json\"")
+	}
+
+	stateCommitment, err := hex.DecodeString(flagStateCommitment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot decode the state commitment")
+	}
+
+	log.Info().Hex("state_commitment", stateCommitment).Str("checkpoint", flagCheckpoint).Msg("loading state from checkpoint")
+
+	t, err := common.LoadCheckpointTrie(flagCheckpoint, stateCommitment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot load state")
+	}
+
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+	ctx := fvm.NewContext(log.Logger, fvm.WithChain(flow.ChainID(flagChain).Chain()))
+
+	payloads, err := t.AllPayloads()
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot read payloads")
+	}
+
+	reports, err := newReport(payloads, flagLargestRegisters, trieGetStorage(vm, ctx, t))
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create storage report")
+	}
+
+	log.Info().Int("accounts", len(reports)).Str("output_dir", flagOutputDir).Msg("writing storage report")
+
+	if flagFormat == formatJSON {
+		writeFile("storage_report.json", reports, writeJSON)
+	} else {
+		writeFile("storage_report_accounts.csv", reports, writeAccountsCSV)
+		writeFile("storage_report_registers.csv", reports, writeRegistersCSV)
+		writeFile("storage_report_breakdown.csv", reports, writeBreakdownCSV)
+	}
+
+	log.Info().Msg("storage report written")
+}
+
+// trieGetStorage returns a function getting the storage of an account from the trie
+func trieGetStorage(vm *fvm.VirtualMachine, ctx fvm.Context, t *trie.MTrie) getStorageFunc {
+	return func(address flow.Address) (*fvm.AccountStorage, error) {
+		// a new view for each account, so the registers read are not retained
+		view := delta.NewView(common.TrieGetRegister(t))
+		return vm.GetAccountStorage(ctx, address, view)
+	}
+}
+
+func writeFile(name string, reports []*accountReport, write func(io.Writer, []*accountReport) error) {
+	path := filepath.Join(flagOutputDir, name)
+
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("cannot create report file")
+	}
+	defer f.Close()
+
+	err = write(f, reports)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("cannot write report file")
+	}
+}
@@ -0,0 +1,328 @@
+package storage_report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	executionState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// registerUsage is the size of a single register of an account
+type registerUsage struct {
+	Key  string `json:"key"`
+	Size uint64 `json:"size"`
+}
+
+// usage is the number and the total size of the registers of an account attributed to a contract or a path
+type usage struct {
+	Name          string `json:"name"`
+	RegisterCount int    `json:"register_count"`
+	Size          uint64 `json:"size"`
+}
+
+type accountReport struct {
+	Address string `json:"address"`
+	// StorageUsed is the storage used by the account, as accounted in its storage used register
+	StorageUsed uint64 `json:"storage_used"`
+	// StorageCapacity is the storage capacity of the account, as computed by the storage fees contract
+	StorageCapacity  uint64          `json:"storage_capacity"`
+	RegisterCount    int             `json:"register_count"`
+	RegistersSize    uint64          `json:"registers_size"`
+	LargestRegisters []registerUsage `json:"largest_registers"`
+	Contracts        []usage         `json:"contracts"`
+	Paths            []usage         `json:"paths"`
+	// Other are the registers attributed to neither a contract nor a path, e.g. account keys
+	Other usage `json:"other"`
+}
+
+// getStorageFunc returns the storage used and the storage capacity of an account
+type getStorageFunc func(address flow.Address) (*fvm.AccountStorage, error)
+
+// newReport builds the storage report of all accounts owning registers in the payloads,
+// sorted by storage used, largest first
+func newReport(payloads []ledger.Payload, largestRegisters int, getStorage getStorageFunc) ([]*accountReport, error) {
+	builders := make(map[flow.Address]*accountReportBuilder)
+
+	for _, payload := range payloads {
+		if len(payload.Value) == 0 {
+			continue
+		}
+
+		id, err := executionState.KeyToRegisterID(payload.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(id.Owner) != flow.AddressLength {
+			// not owned by an account
+			continue
+		}
+
+		address := flow.BytesToAddress([]byte(id.Owner))
+
+		builder, ok := builders[address]
+		if !ok {
+			builder = newAccountReportBuilder(address)
+			builders[address] = builder
+		}
+
+		builder.add(id.Key, uint64(payload.Key.Size()+len(payload.Value)))
+	}
+
+	reports := make([]*accountReport, 0, len(builders))
+
+	for address, builder := range builders {
+		storage, err := getStorage(address)
+		if err != nil && !errors.Is(err, fvm.ErrAccountNotFound) {
+			return nil, fmt.Errorf("cannot get storage of account %s: %w", address, err)
+		}
+
+		report := builder.build(largestRegisters)
+		if storage != nil {
+			report.StorageUsed = storage.Used
+			report.StorageCapacity = storage.Capacity
+		}
+
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].StorageUsed != reports[j].StorageUsed {
+			return reports[i].StorageUsed > reports[j].StorageUsed
+		}
+		return reports[i].Address < reports[j].Address
+	})
+
+	return reports, nil
+}
+
+type accountReportBuilder struct {
+	report    *accountReport
+	registers []registerUsage
+	contracts map[string]*usage
+	paths     map[string]*usage
+}
+
+func newAccountReportBuilder(address flow.Address) *accountReportBuilder {
+	return &accountReportBuilder{
+		report: &accountReport{
+			Address: address.Hex(),
+		},
+		contracts: make(map[string]*usage),
+		paths:     make(map[string]*usage),
+	}
+}
+
+func (b *accountReportBuilder) add(key string, size uint64) {
+	b.report.RegisterCount++
+	b.report.RegistersSize += size
+
+	b.registers = append(b.registers, registerUsage{
+		Key:  registerName(key),
+		Size: size,
+	})
+
+	if contract, ok := contractName(key); ok {
+		addUsage(b.contracts, contract, size)
+	} else if path, ok := pathName(key); ok {
+		addUsage(b.paths, path, size)
+	} else {
+		b.report.Other.RegisterCount++
+		b.report.Other.Size += size
+	}
+}
+
+func (b *accountReportBuilder) build(largestRegisters int) *accountReport {
+	sort.SliceStable(b.registers, func(i, j int) bool {
+		if b.registers[i].Size != b.registers[j].Size {
+			return b.registers[i].Size > b.registers[j].Size
+		}
+		return b.registers[i].Key < b.registers[j].Key
+	})
+	if len(b.registers) > largestRegisters {
+		b.registers = b.registers[:largestRegisters]
+	}
+
+	b.report.LargestRegisters = b.registers
+	b.report.Contracts = sortedUsages(b.contracts)
+	b.report.Paths = sortedUsages(b.paths)
+
+	return b.report
+}
+
+func addUsage(usages map[string]*usage, name string, size uint64) {
+	u, ok := usages[name]
+	if !ok {
+		u = &usage{Name: name}
+		usages[name] = u
+	}
+	u.RegisterCount++
+	u.Size += size
+}
+
+// sortedUsages returns the usages sorted by size, largest first
+func sortedUsages(usages map[string]*usage) []usage {
+	sorted := make([]usage, 0, len(usages))
+	for _, u := range usages {
+		sorted = append(sorted, *u)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Size != sorted[j].Size {
+			return sorted[i].Size > sorted[j].Size
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted
+}
+
+// Cadence separates the parts of the keys of stored values with \x1F (Information Separator One)
+const keySeparator = "\x1F"
+
+// contractName returns the name of the contract a register belongs to:
+// either the register holding the code of the contract, or the one holding its stored value
+func contractName(key string) (string, bool) {
+	if strings.HasPrefix(key, state.KeyCode+".") {
+		return strings.TrimPrefix(key, state.KeyCode+"."), true
+	}
+	if strings.HasPrefix(key, "contract"+keySeparator) {
+		return strings.TrimPrefix(key, "contract"+keySeparator), true
+	}
+	return "", false
+}
+
+// pathName returns the path of the value stored in a register, e.g. /storage/flowTokenVault
+func pathName(key string) (string, bool) {
+	parts := strings.SplitN(key, keySeparator, 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+
+	switch parts[0] {
+	case "storage", "public", "private":
+		return fmt.Sprintf("/%s/%s", parts[0], parts[1]), true
+	}
+	return "", false
+}
+
+// registerName returns a printable name of a register key
+func registerName(key string) string {
+	if path, ok := pathName(key); ok {
+		return path
+	}
+	return strings.ReplaceAll(key, keySeparator, ".")
+}
+
+// writeJSON writes the report as a JSON array of accounts
+func writeJSON(w io.Writer, reports []*accountReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reports)
+}
+
+// writeAccountsCSV writes one row per account, with its storage used and capacity and register totals
+func writeAccountsCSV(w io.Writer, reports []*accountReport) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"address", "storage_used", "storage_capacity", "register_count", "registers_size"})
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		err = writer.Write([]string{
+			report.Address,
+			strconv.FormatUint(report.StorageUsed, 10),
+			strconv.FormatUint(report.StorageCapacity, 10),
+			strconv.Itoa(report.RegisterCount),
+			strconv.FormatUint(report.RegistersSize, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeRegistersCSV writes one row per largest register of each account
+func writeRegistersCSV(w io.Writer, reports []*accountReport) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"address", "key", "size"})
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		for _, register := range report.LargestRegisters {
+			err = writer.Write([]string{
+				report.Address,
+				register.Key,
+				strconv.FormatUint(register.Size, 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeBreakdownCSV writes one row per contract, path and other registers of each account
+func writeBreakdownCSV(w io.Writer, reports []*accountReport) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"address", "kind", "name", "register_count", "size"})
+	if err != nil {
+		return err
+	}
+
+	write := func(address string, kind string, u usage) error {
+		return writer.Write([]string{
+			address,
+			kind,
+			u.Name,
+			strconv.Itoa(u.RegisterCount),
+			strconv.FormatUint(u.Size, 10),
+		})
+	}
+
+	for _, report := range reports {
+		for _, u := range report.Contracts {
+			err = write(report.Address, "contract", u)
+			if err != nil {
+				return err
+			}
+		}
+		for _, u := range report.Paths {
+			err = write(report.Address, "path", u)
+			if err != nil {
+				return err
+			}
+		}
+		if report.Other.RegisterCount > 0 {
+			err = write(report.Address, "other", report.Other)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,128 @@
+package storage_report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	executionState "github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestReport(t *testing.T) {
+	address := flow.HexToAddress("01")
+	otherAddress := flow.HexToAddress("02")
+	unknownAddress := flow.HexToAddress("03")
+
+	payload := func(address flow.Address, key string, size int) ledger.Payload {
+		id := flow.NewRegisterID(string(address.Bytes()), "", key)
+		return *ledger.NewPayload(executionState.RegisterIDToKey(id), make([]byte, size))
+	}
+
+	payloads := []ledger.Payload{
+		payload(address, "exists", 1),
+		payload(address, "code.Answer", 100),
+		payload(address, "contract\x1FAnswer", 20),
+		payload(address, "storage\x1FflowTokenVault", 50),
+		payload(address, "public\x1FflowTokenBalance", 10),
+		payload(address, "deleted", 0),
+		payload(otherAddress, "exists", 1),
+		payload(unknownAddress, "storage\x1Fleftover", 5),
+		// not owned by an account
+		*ledger.NewPayload(executionState.RegisterIDToKey(flow.NewRegisterID("", "", "uuid")), []byte{1}),
+	}
+
+	size := func(p ledger.Payload) uint64 {
+		return uint64(p.Key.Size() + len(p.Value))
+	}
+
+	storage := map[flow.Address]*fvm.AccountStorage{
+		address:      {Used: 300, Capacity: 100000},
+		otherAddress: {Used: 30, Capacity: 100000},
+	}
+
+	reports, err := newReport(payloads, 2, func(address flow.Address) (*fvm.AccountStorage, error) {
+		s, ok := storage[address]
+		if !ok {
+			return nil, fvm.ErrAccountNotFound
+		}
+		return s, nil
+	})
+	require.NoError(t, err)
+
+	// sorted by storage used
+	require.Len(t, reports, 3)
+	assert.Equal(t, address.Hex(), reports[0].Address)
+	assert.Equal(t, otherAddress.Hex(), reports[1].Address)
+	assert.Equal(t, unknownAddress.Hex(), reports[2].Address)
+
+	report := reports[0]
+	assert.Equal(t, uint64(300), report.StorageUsed)
+	assert.Equal(t, uint64(100000), report.StorageCapacity)
+	assert.Equal(t, 5, report.RegisterCount)
+	assert.Equal(t,
+		size(payloads[0])+size(payloads[1])+size(payloads[2])+size(payloads[3])+size(payloads[4]),
+		report.RegistersSize,
+	)
+
+	assert.Equal(t,
+		[]registerUsage{
+			{Key: "code.Answer", Size: size(payloads[1])},
+			{Key: "/storage/flowTokenVault", Size: size(payloads[3])},
+		},
+		report.LargestRegisters,
+	)
+
+	assert.Equal(t,
+		[]usage{{Name: "Answer", RegisterCount: 2, Size: size(payloads[1]) + size(payloads[2])}},
+		report.Contracts,
+	)
+	assert.Equal(t,
+		[]usage{
+			{Name: "/storage/flowTokenVault", RegisterCount: 1, Size: size(payloads[3])},
+			{Name: "/public/flowTokenBalance", RegisterCount: 1, Size: size(payloads[4])},
+		},
+		report.Paths,
+	)
+	assert.Equal(t, usage{RegisterCount: 1, Size: size(payloads[0])}, report.Other)
+
+	// registers of accounts which do not exist are still reported
+	assert.Equal(t, uint64(0), reports[2].StorageUsed)
+	assert.Equal(t, 1, reports[2].RegisterCount)
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := writeAccountsCSV(&buf, reports)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 4)
+		assert.True(t, strings.HasPrefix(lines[1], address.Hex()+",300,100000,5,"))
+
+		buf.Reset()
+		err = writeRegistersCSV(&buf, reports)
+		require.NoError(t, err)
+		lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+		// header, two largest registers of the first account, one register of the others
+		require.Len(t, lines, 5)
+
+		buf.Reset()
+		err = writeBreakdownCSV(&buf, reports)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), address.Hex()+",contract,Answer,2,")
+		assert.Contains(t, buf.String(), address.Hex()+",path,/storage/flowTokenVault,1,")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := writeJSON(&buf, reports)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `"storage_capacity": 100000`)
+	})
+}
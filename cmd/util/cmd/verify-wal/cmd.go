@@ -0,0 +1,120 @@
+package verify_wal
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+)
+
+var (
+	flagExecutionStateDir string
+	flagMTrieCacheSize    uint32
+	flagRepair            bool
+)
+
+var Cmd = &cobra.Command{
+	Use:   "verify-wal",
+	Short: "Verifies the checkpoints and write-ahead log (WAL) of the execution state, and optionally repairs the WAL",
+	Long: `Verifies the checksums of all checkpoints, decodes all WAL records and recomputes the root hashes
+of the tries created by the records not covered by a checkpoint. The first bad record is reported.
+
+With --repair the WAL is truncated back to the last consistent record and a new checkpoint is created
+from the last consistent state. Stop the execution node before running this command.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().Uint32Var(&flagMTrieCacheSize, "mtrie-cache-size", complete.DefaultCacheSize,
+		"cache size for MTrie, same as used by the execution node")
+
+	Cmd.Flags().BoolVar(&flagRepair, "repair", false,
+		"truncate the WAL back to the last consistent record and create a new checkpoint")
+}
+
+func run(*cobra.Command, []string) {
+
+	w, err := wal.NewWAL(
+		log.Logger,
+		nil,
+		flagExecutionStateDir,
+		int(flagMTrieCacheSize),
+		pathfinder.PathByteSize,
+		wal.SegmentSize,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while creating WAL")
+	}
+	defer func() {
+		err := w.Close()
+		if err != nil {
+			log.Error().Err(err).Msg("error while closing WAL")
+		}
+	}()
+
+	checkpointer, err := w.NewCheckpointer()
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while creating checkpointer")
+	}
+
+	checkpoints, err := checkpointer.VerifyCheckpoints()
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while verifying checkpoints")
+	}
+
+	badCheckpoints := 0
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Err != nil {
+			badCheckpoints++
+			log.Error().Str("file", checkpoint.File).Err(checkpoint.Err).Msg("checkpoint is corrupted")
+			continue
+		}
+		log.Info().Str("file", checkpoint.File).Msg("checkpoint is valid")
+	}
+
+	verification, err := w.Verify()
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while verifying WAL")
+	}
+
+	if verification.Err == nil {
+		log.Info().
+			Int("records", verification.Records).
+			Int("checkpoint", verification.Checkpoint).
+			Int("recomputed_from_segment", verification.RecomputedFrom).
+			Hex("root_hash", verification.RootHash).
+			Int("corrupted_checkpoints", badCheckpoints).
+			Msg("WAL is valid")
+		return
+	}
+
+	log.Error().
+		Int("records", verification.Records).
+		Int("checkpoint", verification.Checkpoint).
+		Int("recomputed_from_segment", verification.RecomputedFrom).
+		Hex("last_root_hash", verification.RootHash).
+		Int("segment", verification.Segment).
+		Int64("offset", verification.Offset).
+		Err(verification.Err).
+		Msg("WAL contains a bad record")
+
+	if !flagRepair {
+		log.Fatal().Msg("WAL verification failed, run with --repair to truncate the WAL back to the last consistent record")
+	}
+
+	checkpoint, err := w.Repair(verification)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while repairing WAL")
+	}
+
+	log.Info().
+		Int("checkpoint", checkpoint).
+		Hex("root_hash", verification.RootHash).
+		Msg("WAL truncated back to the last consistent record and checkpoint created")
+}
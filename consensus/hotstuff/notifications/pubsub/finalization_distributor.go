@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+)
+
+// FinalizationDistributor distributes the finalization notifications to a list of subscribers.
+//
+// It allows thread-safe subscription of multiple consumers to events.
+type FinalizationDistributor struct {
+	subscribers []hotstuff.FinalizationConsumer
+	lock        sync.RWMutex
+}
+
+func NewFinalizationDistributor() *FinalizationDistributor {
+	return &FinalizationDistributor{}
+}
+
+// AddConsumer adds a finalization consumer to the FinalizationDistributor
+func (p *FinalizationDistributor) AddConsumer(consumer hotstuff.FinalizationConsumer) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.subscribers = append(p.subscribers, consumer)
+}
+
+func (p *FinalizationDistributor) OnBlockIncorporated(block *model.Block) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnBlockIncorporated(block)
+	}
+}
+
+func (p *FinalizationDistributor) OnFinalizedBlock(block *model.Block) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnFinalizedBlock(block)
+	}
+}
+
+func (p *FinalizationDistributor) OnDoubleProposeDetected(block1, block2 *model.Block) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnDoubleProposeDetected(block1, block2)
+	}
+}
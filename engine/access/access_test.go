@@ -110,6 +110,7 @@ func (suite *Suite) RunTest(
 			suite.chainID,
 			suite.metrics,
 			nil,
+			nil,
 			false,
 			suite.log,
 		)
@@ -283,6 +284,7 @@ func (suite *Suite) TestSendTransactionToRandomCollectionNode() {
 			suite.chainID,
 			metrics,
 			connFactory, // passing in the connection factory
+			nil,
 			false,
 			suite.log,
 		)
@@ -443,17 +445,18 @@ func (suite *Suite) TestGetSealedTransaction() {
 		require.NoError(suite.T(), err)
 
 		rpcEng := rpc.New(suite.log, suite.state, rpc.Config{}, nil, nil, nil, blocks, headers, collections, transactions,
-			nil, suite.chainID, metrics, 0, 0, false, false)
+			nil, nil, suite.chainID, metrics, 0, 0, false, false)
 
 		// create the ingest engine
 		ingestEng, err := ingestion.New(suite.log, suite.net, suite.state, suite.me, suite.request, blocks, headers, collections,
 			transactions, nil, metrics, collectionsToMarkFinalized, collectionsToMarkExecuted, blocksToMarkExecuted, rpcEng)
 		require.NoError(suite.T(), err)
 
-		// 1. Assume that follower engine updated the block storage and the protocol state. The block is reported as sealed
+		// 1. Assume that follower engine updated the block storage and the protocol state. The block is reported as sealed,
+		// to the ingest engine notifying the status of the transactions and to the handler
 		err = blocks.Store(&block)
 		require.NoError(suite.T(), err)
-		suite.snapshot.On("Head").Return(block.Header, nil).Once()
+		suite.snapshot.On("Head").Return(block.Header, nil)
 
 		// 2. Ingest engine was notified by the follower engine about a new block.
 		// Follower engine --> Ingest engine
@@ -513,6 +516,7 @@ func (suite *Suite) TestExecuteScript() {
 			suite.chainID,
 			suite.metrics,
 			connFactory,
+			nil,
 			false,
 			suite.log,
 		)
@@ -531,9 +535,13 @@ func (suite *Suite) TestExecuteScript() {
 		conduit := new(mocknetwork.Conduit)
 		suite.net.On("Register", engine.ReceiveReceipts, mock.Anything).Return(conduit, nil).
 			Once()
+
+		rpcEng := rpc.New(suite.log, suite.state, rpc.Config{}, nil, nil, nil, blocks, headers, collections, transactions,
+			receipts, nil, suite.chainID, metrics, 0, 0, false, false)
+
 		// create the ingest engine
 		ingestEng, err := ingestion.New(suite.log, suite.net, suite.state, suite.me, suite.request, blocks, headers, collections,
-			transactions, receipts, metrics, collectionsToMarkFinalized, collectionsToMarkExecuted, blocksToMarkExecuted, nil)
+			transactions, receipts, metrics, collectionsToMarkFinalized, collectionsToMarkExecuted, blocksToMarkExecuted, rpcEng)
 		require.NoError(suite.T(), err)
 
 		// create a block and a seal pointing to that block
@@ -13,6 +13,7 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/access/rpc"
+	"github.com/onflow/flow-go/engine/access/rpc/streaming"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
@@ -198,9 +199,6 @@ func (e *Engine) processFinalizedBlock(blockID flow.Identifier) error {
 		return fmt.Errorf("failed to lookup block: %w", err)
 	}
 
-	// Notify rpc handler of new finalized block height
-	e.rpcEngine.SubmitLocal(block)
-
 	// FIX: we can't index guarantees here, as we might have more than one block
 	// with the same collection as long as it is not finalized
 
@@ -212,6 +210,35 @@ func (e *Engine) processFinalizedBlock(blockID flow.Identifier) error {
 		return fmt.Errorf("could not index block for collections: %w", err)
 	}
 
+	// Notify rpc handler of new finalized block height, once the transactions of
+	// the block can be looked up by their collections
+	e.rpcEngine.SubmitLocal(block)
+
+	// notify the transactions of the collections of the block already received
+	var collections []*flow.LightCollection
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := e.collections.LightByID(guarantee.CollectionID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not lookup collection %v: %w", guarantee.CollectionID, err)
+		}
+		collections = append(collections, collection)
+	}
+	err = e.notifyTransactions(block, collections)
+	if err != nil {
+		return fmt.Errorf("could not notify transactions of block: %w", err)
+	}
+
+	// notify the transactions of the blocks sealed by the block
+	for _, seal := range block.Payload.Seals {
+		err = e.notifyBlockTransactions(seal.BlockID)
+		if err != nil {
+			return fmt.Errorf("could not notify transactions of sealed block %v: %w", seal.BlockID, err)
+		}
+	}
+
 	// queue requesting each of the collections from the collection node
 	e.requestCollections(block.Payload.Guarantees)
 
@@ -266,6 +293,14 @@ func (e *Engine) handleExecutionReceipt(originID flow.Identifier, r *flow.Execut
 		return fmt.Errorf("failed to index execution receipt: %w", err)
 	}
 
+	// notify rpc handler of the new execution receipt
+	e.rpcEngine.SubmitLocal(r)
+
+	err = e.notifyBlockTransactions(r.ExecutionResult.BlockID)
+	if err != nil {
+		return fmt.Errorf("could not notify transactions of executed block: %w", err)
+	}
+
 	e.trackExecutedMetricForReceipt(r)
 	return nil
 }
@@ -357,6 +392,102 @@ func (e *Engine) handleCollection(originID flow.Identifier, entity flow.Entity)
 		}
 	}
 
+	// notify rpc handler of the new collection
+	e.rpcEngine.SubmitLocal(&light)
+
+	// notify the transactions of the collection if the block including it is finalized
+	block, err := e.blocks.ByCollectionID(light.ID())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not lookup block of collection: %w", err)
+	}
+
+	err = e.notifyTransactions(block, []*flow.LightCollection{&light})
+	if err != nil {
+		return fmt.Errorf("could not notify transactions of collection: %w", err)
+	}
+
+	return nil
+}
+
+// notifyBlockTransactions notifies the rpc handler of the status of the transactions of the collections
+// already received of the given block, if it is finalized.
+func (e *Engine) notifyBlockTransactions(blockID flow.Identifier) error {
+	block, err := e.blocks.ByID(blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not lookup block: %w", err)
+	}
+
+	// only the transactions of finalized blocks are notified
+	finalized, err := e.headers.ByHeight(block.Header.Height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not lookup finalized block at height %d: %w", block.Header.Height, err)
+	}
+	if finalized.ID() != blockID {
+		return nil
+	}
+
+	var collections []*flow.LightCollection
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := e.collections.LightByID(guarantee.CollectionID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not lookup collection %v: %w", guarantee.CollectionID, err)
+		}
+		collections = append(collections, collection)
+	}
+
+	return e.notifyTransactions(block, collections)
+}
+
+// notifyTransactions notifies the rpc handler of the status of the transactions of the given collections
+// of the given finalized block: finalized, executed once an execution receipt for the block was received,
+// or sealed.
+func (e *Engine) notifyTransactions(block *flow.Block, collections []*flow.LightCollection) error {
+	var txIDs []flow.Identifier
+	for _, collection := range collections {
+		txIDs = append(txIDs, collection.Transactions...)
+	}
+	if len(txIDs) == 0 {
+		return nil
+	}
+
+	txStatus := flow.TransactionStatusFinalized
+
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not lookup sealed block: %w", err)
+	}
+
+	if block.Header.Height <= sealed.Height {
+		txStatus = flow.TransactionStatusSealed
+	} else {
+		receipts, err := e.executionReceipts.ByBlockIDAllExecutionReceipts(block.ID())
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("could not lookup execution receipts: %w", err)
+		}
+		if len(receipts) > 0 {
+			txStatus = flow.TransactionStatusExecuted
+		}
+	}
+
+	e.rpcEngine.SubmitLocal(&streaming.TransactionTransition{
+		TransactionIDs: txIDs,
+		Status:         txStatus,
+		BlockID:        block.ID(),
+		BlockHeight:    block.Header.Height,
+	})
+
 	return nil
 }
 
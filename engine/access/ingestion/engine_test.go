@@ -65,6 +65,7 @@ func (suite *Suite) SetupTest() {
 	suite.proto.params = new(protocol.Params)
 	suite.proto.state.On("Identity").Return(obsIdentity, nil)
 	suite.proto.state.On("Final").Return(suite.proto.snapshot, nil)
+	suite.proto.state.On("Sealed").Return(suite.proto.snapshot, nil)
 	suite.proto.state.On("Params").Return(suite.proto.params)
 
 	suite.me = new(module.Local)
@@ -82,6 +83,7 @@ func (suite *Suite) SetupTest() {
 	suite.headers = new(storage.Headers)
 	suite.collections = new(storage.Collections)
 	suite.transactions = new(storage.Transactions)
+	suite.receipts = new(storage.ExecutionReceipts)
 	collectionsToMarkFinalized, err := stdmap.NewTimes(100)
 	require.NoError(suite.T(), err)
 	collectionsToMarkExecuted, err := stdmap.NewTimes(100)
@@ -90,7 +92,7 @@ func (suite *Suite) SetupTest() {
 	require.NoError(suite.T(), err)
 
 	rpcEng := rpc.New(log, suite.proto.state, rpc.Config{}, nil, nil, nil, suite.blocks, suite.headers, suite.collections,
-		suite.transactions, suite.receipts, flow.Testnet, metrics.NewNoopCollector(), 0, 0, false, false)
+		suite.transactions, suite.receipts, nil, flow.Testnet, metrics.NewNoopCollector(), 0, 0, false, false)
 
 	eng, err := New(log, net, suite.proto.state, suite.me, suite.request, suite.blocks, suite.headers, suite.collections,
 		suite.transactions, suite.receipts, metrics.NewNoopCollector(), collectionsToMarkFinalized, collectionsToMarkExecuted,
@@ -117,9 +119,18 @@ func (suite *Suite) TestOnFinalizedBlock() {
 		suite.collections.On("LightByID", g.CollectionID).Return(&light, nil).Twice()
 	}
 
+	// the blocks sealed by the block are not known
+	suite.blocks.On("ByID", mock.Anything).Return(nil, storerr.ErrNotFound)
+
 	// expect that the block storage is indexed with each of the collection guarantee
 	suite.blocks.On("IndexBlockForCollections", block.ID(), flow.GetIDs(block.Payload.Guarantees)).Return(nil).Once()
 
+	// the transactions of the block are notified as finalized
+	sealed := unittest.BlockHeaderFixture()
+	sealed.Height = block.Header.Height - 1
+	suite.proto.snapshot.On("Head").Return(&sealed, nil)
+	suite.receipts.On("ByBlockIDAllExecutionReceipts", block.ID()).Return(nil, storerr.ErrNotFound).Once()
+
 	// for each of the guarantees, we should request the corresponding collection once
 	needed := make(map[flow.Identifier]struct{})
 	for _, guarantee := range block.Payload.Guarantees {
@@ -163,6 +174,9 @@ func (suite *Suite) TestOnCollection() {
 	// we should store the light collection and index its transactions
 	suite.collections.On("StoreLightAndIndexByTransaction", &light).Return(nil).Once()
 
+	// the block including the collection is not finalized yet
+	suite.blocks.On("ByCollectionID", light.ID()).Return(nil, storerr.ErrNotFound).Once()
+
 	// for each transaction in the collection, we should store it
 	needed := make(map[flow.Identifier]struct{})
 	for _, txID := range light.Transactions {
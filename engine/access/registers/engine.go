@@ -0,0 +1,476 @@
+package registers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// requiredAgreements is the number of execution nodes which must report the same end state for a block, for its
+// register delta to be indexed before the block is sealed, and the number of execution nodes which must send the same
+// register delta for it to be indexed, unless fewer execution nodes are staked.
+const requiredAgreements = 2
+
+// deltaRequestInterval is the minimum interval between two requests for the register delta of a block.
+const deltaRequestInterval = 10 * time.Second
+
+// Engine indexes the registers of the execution state at each finalized height, from the register deltas pushed by
+// the execution nodes. A height is indexed once its block is finalized, its end state is trusted and the register
+// delta resulting in this end state was received, so the index doesn't go past a finalized block whose register
+// delta is missing, and scripts at later heights are executed on the execution nodes.
+//
+// The end state of a block is trusted once the block is sealed, or once enough execution nodes reported it in
+// their receipts. As the access node can't check the registers of a register delta against the end state, a register
+// delta resulting in the trusted end state is only indexed once enough execution nodes sent the same register delta,
+// so a single execution node can't corrupt the index. If no such register delta was received, because it was lost or
+// ejected from the memory pool, it is requested from the execution nodes.
+type Engine struct {
+	unit      *engine.Unit   // used to manage concurrency & shutdown
+	log       zerolog.Logger // used to log relevant actions with context
+	metrics   module.RegisterIndexMetrics
+	state     protocol.State // used to check the origin of the register deltas
+	me        module.Local   // used to access local node information
+	con       network.Conduit
+	headers   storage.Headers
+	index     storage.Index
+	seals     storage.Seals
+	receipts  storage.ExecutionReceipts
+	registers storage.Registers
+	deltas    mempool.RegisterDeltas // register deltas received for heights not indexed yet
+
+	indexing  sync.Mutex                                       // makes sure heights are indexed one at a time, in order
+	scanned   uint64                                           // finalized height up to which the seals were collected
+	sealed    map[flow.Identifier]flow.StateCommitment         // sealed end states of the blocks not indexed yet
+	requested map[flow.Identifier]time.Time                    // when the register deltas of blocks were last requested
+	senders   map[flow.Identifier]map[flow.Identifier]struct{} // execution nodes which sent each register delta, by ID
+}
+
+// New creates a new register index engine.
+func New(
+	log zerolog.Logger,
+	metrics module.RegisterIndexMetrics,
+	net module.Network,
+	state protocol.State,
+	me module.Local,
+	headers storage.Headers,
+	index storage.Index,
+	seals storage.Seals,
+	receipts storage.ExecutionReceipts,
+	registers storage.Registers,
+	deltas mempool.RegisterDeltas,
+) (*Engine, error) {
+
+	eng := &Engine{
+		unit:      engine.NewUnit(),
+		log:       log.With().Str("engine", "registers").Logger(),
+		metrics:   metrics,
+		state:     state,
+		me:        me,
+		headers:   headers,
+		index:     index,
+		seals:     seals,
+		receipts:  receipts,
+		registers: registers,
+		deltas:    deltas,
+		sealed:    make(map[flow.Identifier]flow.StateCommitment),
+		requested: make(map[flow.Identifier]time.Time),
+		senders:   make(map[flow.Identifier]map[flow.Identifier]struct{}),
+	}
+
+	// the register deltas ejected before being indexed have to be requested again
+	deltas.RegisterEjectionCallbacks(func(flow.Entity) {
+		metrics.RegisterDeltaEjected()
+	})
+
+	// register engine with the register delta provider
+	con, err := net.Register(engine.ReceiveStateDeltas, eng)
+	if err != nil {
+		return nil, fmt.Errorf("could not register for register deltas: %w", err)
+	}
+	eng.con = con
+
+	return eng, nil
+}
+
+// Ready returns a ready channel that is closed once the engine has fully started.
+func (e *Engine) Ready() <-chan struct{} {
+	return e.unit.Ready()
+}
+
+// Done returns a done channel that is closed once the engine has fully stopped.
+func (e *Engine) Done() <-chan struct{} {
+	return e.unit.Done()
+}
+
+// SubmitLocal submits an event originating on the local node.
+func (e *Engine) SubmitLocal(event interface{}) {
+	e.Submit(e.me.NodeID(), event)
+}
+
+// Submit submits the given event from the node with the given origin ID
+// for processing in a non-blocking manner. It returns instantly and logs
+// a potential processing error internally when done.
+func (e *Engine) Submit(originID flow.Identifier, event interface{}) {
+	e.unit.Launch(func() {
+		err := e.process(originID, event)
+		if err != nil {
+			engine.LogError(e.log, err)
+		}
+	})
+}
+
+// ProcessLocal processes an event originating on the local node.
+func (e *Engine) ProcessLocal(event interface{}) error {
+	return e.Process(e.me.NodeID(), event)
+}
+
+// Process processes the given event from the node with the given origin ID in
+// a blocking manner. It returns the potential processing error when done.
+func (e *Engine) Process(originID flow.Identifier, event interface{}) error {
+	return e.unit.Do(func() error {
+		return e.process(originID, event)
+	})
+}
+
+func (e *Engine) process(originID flow.Identifier, event interface{}) error {
+	switch entity := event.(type) {
+	case *messages.ExecutionRegisterDelta:
+		return e.handleRegisterDelta(originID, entity)
+	default:
+		return fmt.Errorf("invalid event type (%T)", event)
+	}
+}
+
+// OnFinalizedBlock indexes the heights up to the finalized block, whose end states became trusted or for which the
+// register deltas were received before their blocks were finalized.
+func (e *Engine) OnFinalizedBlock(*model.Block) {
+	e.unit.Launch(func() {
+		err := e.indexRegisters()
+		if err != nil {
+			e.log.Error().Err(err).Msg("could not index registers")
+		}
+	})
+}
+
+// OnBlockIncorporated is a noop for this engine since only finalized blocks are indexed
+func (e *Engine) OnBlockIncorporated(*model.Block) {
+}
+
+// OnDoubleProposeDetected is a noop for this engine since only finalized blocks are indexed
+func (e *Engine) OnDoubleProposeDetected(*model.Block, *model.Block) {
+}
+
+func (e *Engine) handleRegisterDelta(originID flow.Identifier, registerDelta *messages.ExecutionRegisterDelta) error {
+	identity, err := e.state.Final().Identity(originID)
+	if err != nil {
+		return engine.NewInvalidInputErrorf("could not get origin identity %x: %w", originID, err)
+	}
+	if identity.Role != flow.RoleExecution {
+		return engine.NewInvalidInputErrorf("register delta from non-execution node %x (role: %s)", originID, identity.Role)
+	}
+
+	// the height of a register delta whose block isn't known yet is checked once its block is finalized
+	header, err := e.headers.ByBlockID(registerDelta.BlockID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not get block %x: %w", registerDelta.BlockID, err)
+	}
+	if err == nil && header.Height != registerDelta.Height {
+		return engine.NewInvalidInputErrorf("register delta of block %x at height %d has wrong height %d",
+			registerDelta.BlockID, header.Height, registerDelta.Height)
+	}
+
+	latest, err := e.registers.LatestHeight()
+	if err != nil {
+		return fmt.Errorf("could not get latest indexed height: %w", err)
+	}
+	if registerDelta.Height <= latest {
+		return nil
+	}
+
+	// the register deltas are kept per content, with the execution nodes which sent them, so the register deltas of
+	// execution nodes agreeing on the execution of the block are the same, whatever the order of their registers
+	sort.Sort(registerDelta.Registers)
+	deltaID := registerDelta.ID()
+
+	e.indexing.Lock()
+	e.deltas.Add(registerDelta)
+	if e.senders[deltaID] == nil {
+		e.senders[deltaID] = make(map[flow.Identifier]struct{})
+	}
+	e.senders[deltaID][originID] = struct{}{}
+	e.indexing.Unlock()
+
+	return e.indexRegisters()
+}
+
+// indexRegisters indexes the registers of the finalized heights after the latest indexed height, until a height
+// whose block isn't finalized yet, whose end state isn't trusted yet or whose register delta wasn't received yet
+// from enough execution nodes.
+func (e *Engine) indexRegisters() error {
+	e.indexing.Lock()
+	defer e.indexing.Unlock()
+
+	latest, err := e.registers.LatestHeight()
+	if err != nil {
+		return fmt.Errorf("could not get latest indexed height: %w", err)
+	}
+
+	err = e.collectSeals(latest)
+	if err != nil {
+		return fmt.Errorf("could not collect seals: %w", err)
+	}
+
+	for height := latest + 1; ; height++ {
+		header, err := e.headers.ByHeight(height)
+		if errors.Is(err, storage.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+
+		blockID := header.ID()
+		endState, executorIDs, err := e.trustedEndState(blockID)
+		if err != nil {
+			return fmt.Errorf("could not get trusted end state of block %x: %w", blockID, err)
+		}
+		if endState == nil {
+			break
+		}
+
+		required, err := e.requiredSenders()
+		if err != nil {
+			return fmt.Errorf("could not get number of execution nodes required to send the register delta: %w", err)
+		}
+
+		registerDelta, ok := e.agreedRegisterDelta(header, endState, required)
+		if !ok {
+			err = e.requestRegisterDelta(blockID, executorIDs, required)
+			if err != nil {
+				return fmt.Errorf("could not request register delta of block %x: %w", blockID, err)
+			}
+			break
+		}
+
+		err = e.registers.Store(height, registerDelta.Registers)
+		if err != nil {
+			return fmt.Errorf("could not store registers of block %x: %w", blockID, err)
+		}
+		e.deltas.Rem(registerDelta.ID())
+		delete(e.sealed, blockID)
+		delete(e.requested, blockID)
+		latest = height
+
+		e.metrics.RegistersIndexed(height)
+
+		e.log.Debug().
+			Hex("block_id", blockID[:]).
+			Uint64("height", height).
+			Msg("registers indexed")
+	}
+
+	// drop the register deltas at the indexed heights, of the blocks that weren't finalized, of the end states that
+	// weren't trusted or which too few execution nodes sent, and the senders of the dropped or ejected register deltas
+	for _, registerDelta := range e.deltas.All() {
+		if registerDelta.Height <= latest {
+			e.deltas.Rem(registerDelta.ID())
+		}
+	}
+	for deltaID := range e.senders {
+		_, ok := e.deltas.ByID(deltaID)
+		if !ok {
+			delete(e.senders, deltaID)
+		}
+	}
+
+	return nil
+}
+
+// requiredSenders returns the number of execution nodes which must send the same register delta for it to be
+// indexed, which is the number of required agreements, unless fewer execution nodes are staked.
+func (e *Engine) requiredSenders() (int, error) {
+	identities, err := e.state.Final().Identities(filter.HasRole(flow.RoleExecution))
+	if err != nil {
+		return 0, fmt.Errorf("could not get execution identities: %w", err)
+	}
+	if len(identities) < requiredAgreements {
+		return len(identities), nil
+	}
+	return requiredAgreements, nil
+}
+
+// agreedRegisterDelta returns the register delta of a finalized block resulting in its trusted end state, which
+// enough execution nodes sent. Conflicting register deltas sent by enough execution nodes aren't indexed.
+func (e *Engine) agreedRegisterDelta(header *flow.Header, endState flow.StateCommitment, required int) (*messages.ExecutionRegisterDelta, bool) {
+	blockID := header.ID()
+
+	var agreed []*messages.ExecutionRegisterDelta
+	for _, registerDelta := range e.deltas.All() {
+		if registerDelta.BlockID != blockID || registerDelta.Height != header.Height {
+			continue
+		}
+		if !bytes.Equal(registerDelta.EndState, endState) {
+			continue
+		}
+		if len(e.senders[registerDelta.ID()]) < required {
+			continue
+		}
+		agreed = append(agreed, registerDelta)
+	}
+
+	if len(agreed) > 1 {
+		e.log.Error().
+			Hex("block_id", logging.ID(blockID)).
+			Int("register_deltas", len(agreed)).
+			Msg("conflicting register deltas resulting in the trusted end state")
+		return nil, false
+	}
+	if len(agreed) == 0 {
+		return nil, false
+	}
+
+	return agreed[0], true
+}
+
+// collectSeals collects the end states sealed by the blocks finalized since the last collection, for the blocks
+// above the latest indexed height. As blocks are sealed by later blocks, the seals of the blocks not indexed yet are
+// all collected from the blocks finalized above the latest indexed height.
+func (e *Engine) collectSeals(latest uint64) error {
+	if e.scanned < latest {
+		e.scanned = latest
+	}
+
+	for height := e.scanned + 1; ; height++ {
+		header, err := e.headers.ByHeight(height)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+
+		index, err := e.index.ByBlockID(header.ID())
+		if err != nil {
+			return fmt.Errorf("could not get payload index of block %x: %w", header.ID(), err)
+		}
+
+		for _, sealID := range index.SealIDs {
+			seal, err := e.seals.ByID(sealID)
+			if err != nil {
+				return fmt.Errorf("could not get seal %x: %w", sealID, err)
+			}
+
+			sealed, err := e.headers.ByBlockID(seal.BlockID)
+			if err != nil {
+				return fmt.Errorf("could not get sealed block %x: %w", seal.BlockID, err)
+			}
+			if sealed.Height <= latest {
+				continue
+			}
+
+			e.sealed[seal.BlockID] = seal.FinalState
+		}
+
+		e.scanned = height
+	}
+}
+
+// trustedEndState returns the end state of a block if it is trusted, along with the execution nodes which
+// reported it. The end state is trusted if the block is sealed, or if enough execution nodes reported it and no
+// other end state. It returns a nil end state if it isn't trusted yet.
+func (e *Engine) trustedEndState(blockID flow.Identifier) (flow.StateCommitment, flow.IdentifierList, error) {
+	receipts, err := e.receipts.ByBlockIDAllExecutionReceipts(blockID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("could not get execution receipts: %w", err)
+	}
+
+	// the execution nodes which reported each end state
+	executors := make(map[string]map[flow.Identifier]struct{})
+	for _, receipt := range receipts {
+		endState, ok := receipt.ExecutionResult.FinalStateCommitment()
+		if !ok {
+			continue
+		}
+		if executors[string(endState)] == nil {
+			executors[string(endState)] = make(map[flow.Identifier]struct{})
+		}
+		executors[string(endState)][receipt.ExecutorID] = struct{}{}
+	}
+
+	endState, sealed := e.sealed[blockID]
+	if !sealed {
+		// execution nodes disagreeing on the end state are resolved once the block is sealed
+		if len(executors) != 1 {
+			return nil, nil, nil
+		}
+		for agreed, executorIDs := range executors {
+			if len(executorIDs) < requiredAgreements {
+				return nil, nil, nil
+			}
+			endState = flow.StateCommitment(agreed)
+		}
+	}
+
+	executorIDs := make(flow.IdentifierList, 0, len(executors[string(endState)]))
+	for executorID := range executors[string(endState)] {
+		executorIDs = append(executorIDs, executorID)
+	}
+
+	return endState, executorIDs, nil
+}
+
+// requestRegisterDelta requests the register delta of a block whose end state is trusted but whose register delta
+// wasn't received from enough execution nodes, from the execution nodes which reported its end state, or from all the
+// execution nodes if too few did. The register delta of a block is only requested again once the request interval
+// passed.
+func (e *Engine) requestRegisterDelta(blockID flow.Identifier, executorIDs flow.IdentifierList, required int) error {
+	requested, ok := e.requested[blockID]
+	if ok && time.Since(requested) < deltaRequestInterval {
+		return nil
+	}
+
+	if len(executorIDs) < required {
+		identities, err := e.state.Final().Identities(filter.HasRole(flow.RoleExecution))
+		if err != nil {
+			return fmt.Errorf("could not get execution identities: %w", err)
+		}
+		executorIDs = identities.NodeIDs()
+	}
+
+	req := &messages.ExecutionRegisterDeltaRequest{
+		BlockID: blockID,
+		Nonce:   rand.Uint64(),
+	}
+	err := e.con.Publish(req, executorIDs...)
+	if err != nil {
+		return fmt.Errorf("could not send register delta request: %w", err)
+	}
+	e.requested[blockID] = time.Now()
+
+	e.metrics.RegisterDeltaRequested()
+
+	e.log.Info().
+		Hex("block_id", logging.ID(blockID)).
+		Int("execution_nodes", len(executorIDs)).
+		Msg("missing register delta requested")
+
+	return nil
+}
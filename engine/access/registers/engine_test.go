@@ -0,0 +1,322 @@
+package registers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	module "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+var register = flow.NewRegisterID("owner", "", "key")
+
+// registerDelta returns the register delta of a block resulting in the end state, which sets the register to the value
+func registerDelta(header *flow.Header, endState flow.StateCommitment, value string) *messages.ExecutionRegisterDelta {
+	return &messages.ExecutionRegisterDelta{
+		BlockID:   header.ID(),
+		Height:    header.Height,
+		EndState:  endState,
+		Registers: flow.RegisterEntries{{Key: register, Value: []byte(value)}},
+	}
+}
+
+// receipt returns the execution receipt of an execution node for a block resulting in the end state
+func receipt(executorID flow.Identifier, header *flow.Header, endState flow.StateCommitment) flow.ExecutionReceipt {
+	receipt := unittest.ExecutionReceiptFixture()
+	receipt.ExecutorID = executorID
+	receipt.ExecutionResult.BlockID = header.ID()
+	receipt.ExecutionResult.Chunks = flow.ChunkList{{EndState: endState}}
+	return *receipt
+}
+
+type chain struct {
+	finalized map[uint64]*flow.Header
+	blocks    map[flow.Identifier]*flow.Header
+	seals     map[flow.Identifier][]*flow.Seal            // seals in the payloads of blocks
+	receipts  map[flow.Identifier][]flow.ExecutionReceipt // receipts of the executed blocks
+}
+
+// finalize finalizes the block, whose payload contains the seals
+func (c *chain) finalize(header *flow.Header, seals ...*flow.Seal) {
+	c.finalized[header.Height] = header
+	c.blocks[header.ID()] = header
+	c.seals[header.ID()] = seals
+}
+
+func (c *chain) mock(headers *storagemock.Headers, index *storagemock.Index, seals *storagemock.Seals, receipts *storagemock.ExecutionReceipts) {
+	headers.On("ByHeight", mock.Anything).Return(
+		func(height uint64) *flow.Header {
+			return c.finalized[height]
+		},
+		func(height uint64) error {
+			if c.finalized[height] == nil {
+				return storage.ErrNotFound
+			}
+			return nil
+		},
+	)
+	headers.On("ByBlockID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Header {
+			return c.blocks[blockID]
+		},
+		func(blockID flow.Identifier) error {
+			if c.blocks[blockID] == nil {
+				return storage.ErrNotFound
+			}
+			return nil
+		},
+	)
+	index.On("ByBlockID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Index {
+			var sealIDs []flow.Identifier
+			for _, seal := range c.seals[blockID] {
+				sealIDs = append(sealIDs, seal.ID())
+			}
+			return &flow.Index{SealIDs: sealIDs}
+		},
+		nil,
+	)
+	seals.On("ByID", mock.Anything).Return(
+		func(sealID flow.Identifier) *flow.Seal {
+			for _, blockSeals := range c.seals {
+				for _, seal := range blockSeals {
+					if seal.ID() == sealID {
+						return seal
+					}
+				}
+			}
+			return nil
+		},
+		nil,
+	)
+	receipts.On("ByBlockIDAllExecutionReceipts", mock.Anything).Return(
+		func(blockID flow.Identifier) []flow.ExecutionReceipt {
+			return c.receipts[blockID]
+		},
+		nil,
+	)
+}
+
+func TestIndexRegisterDeltas(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		registers := bstorage.NewRegisters(db)
+		root := unittest.BlockHeaderFixture()
+		err := registers.Bootstrap(root.Height, flow.RegisterEntries{{Key: register, Value: []byte("root")}})
+		require.NoError(t, err)
+
+		executor1 := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+		executor2 := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+		executor3 := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+		collector := unittest.IdentityFixture(unittest.WithRole(flow.RoleCollection))
+		snapshot := new(protocol.Snapshot)
+		snapshot.On("Identity", executor1.NodeID).Return(executor1, nil)
+		snapshot.On("Identity", executor2.NodeID).Return(executor2, nil)
+		snapshot.On("Identity", executor3.NodeID).Return(executor3, nil)
+		snapshot.On("Identity", collector.NodeID).Return(collector, nil)
+		snapshot.On("Identities", mock.Anything).Return(flow.IdentityList{executor1, executor2, executor3}, nil)
+		state := new(protocol.State)
+		state.On("Final").Return(snapshot)
+
+		con := new(mocknetwork.Conduit)
+		net := new(module.Network)
+		net.On("Register", engine.ReceiveStateDeltas, mock.Anything).Return(con, nil)
+
+		metrics := new(module.RegisterIndexMetrics)
+		metrics.On("RegistersIndexed", mock.Anything)
+		metrics.On("RegisterDeltaRequested")
+		metrics.On("RegisterDeltaEjected")
+
+		deltas, err := stdmap.NewRegisterDeltas(10)
+		require.NoError(t, err)
+
+		c := &chain{
+			finalized: make(map[uint64]*flow.Header),
+			blocks:    map[flow.Identifier]*flow.Header{root.ID(): &root},
+			seals:     make(map[flow.Identifier][]*flow.Seal),
+			receipts:  make(map[flow.Identifier][]flow.ExecutionReceipt),
+		}
+		headers := new(storagemock.Headers)
+		index := new(storagemock.Index)
+		seals := new(storagemock.Seals)
+		receipts := new(storagemock.ExecutionReceipts)
+		c.mock(headers, index, seals, receipts)
+
+		eng, err := New(zerolog.Nop(), metrics, net, state, new(module.Local), headers, index, seals, receipts, registers, deltas)
+		require.NoError(t, err)
+
+		first := unittest.BlockHeaderWithParentFixture(&root)
+		second := unittest.BlockHeaderWithParentFixture(&first)
+		fork := unittest.BlockHeaderWithParentFixture(&first)
+		third := unittest.BlockHeaderWithParentFixture(&second)
+		fourth := unittest.BlockHeaderWithParentFixture(&third)
+
+		firstState := unittest.StateCommitmentFixture()
+		secondState := unittest.StateCommitmentFixture()
+		thirdState := unittest.StateCommitmentFixture()
+
+		latest := func() uint64 {
+			latest, err := registers.LatestHeight()
+			require.NoError(t, err)
+			return latest
+		}
+
+		t.Run("non-execution node", func(t *testing.T) {
+			err := eng.process(collector.NodeID, registerDelta(&first, firstState, "first"))
+			assert.True(t, engine.IsInvalidInputError(err))
+			assert.Equal(t, uint(0), deltas.Size())
+		})
+
+		t.Run("wrong height", func(t *testing.T) {
+			wrong := registerDelta(&root, unittest.StateCommitmentFixture(), "wrong")
+			wrong.Height++
+
+			err := eng.process(executor1.NodeID, wrong)
+			assert.True(t, engine.IsInvalidInputError(err))
+			assert.Equal(t, uint(0), deltas.Size())
+		})
+
+		t.Run("unfinalized blocks", func(t *testing.T) {
+			for _, registerDelta := range []*messages.ExecutionRegisterDelta{
+				registerDelta(&second, secondState, "second"),
+				registerDelta(&second, unittest.StateCommitmentFixture(), "second-wrong"),
+				registerDelta(&fork, unittest.StateCommitmentFixture(), "fork"),
+				registerDelta(&first, firstState, "first"),
+			} {
+				err := eng.process(executor1.NodeID, registerDelta)
+				require.NoError(t, err)
+			}
+
+			// the register deltas sent by several execution nodes are kept once
+			for _, registerDelta := range []*messages.ExecutionRegisterDelta{
+				registerDelta(&second, secondState, "second"),
+				registerDelta(&first, firstState, "first"),
+			} {
+				err := eng.process(executor2.NodeID, registerDelta)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, root.Height, latest())
+			assert.Equal(t, uint(4), deltas.Size())
+		})
+
+		t.Run("untrusted end state", func(t *testing.T) {
+			c.finalize(&first)
+			c.receipts[first.ID()] = []flow.ExecutionReceipt{receipt(executor1.NodeID, &first, firstState)}
+
+			err := eng.indexRegisters()
+			require.NoError(t, err)
+
+			// a single execution node reported the end state
+			assert.Equal(t, root.Height, latest())
+		})
+
+		t.Run("agreed end state", func(t *testing.T) {
+			c.receipts[first.ID()] = append(c.receipts[first.ID()], receipt(executor2.NodeID, &first, firstState))
+
+			err := eng.indexRegisters()
+			require.NoError(t, err)
+
+			assert.Equal(t, first.Height, latest())
+
+			// the register deltas of the next height are kept
+			assert.Equal(t, uint(3), deltas.Size())
+		})
+
+		t.Run("sealed end state", func(t *testing.T) {
+			// execution nodes disagreeing on the end state is only resolved by the seal
+			c.finalize(&second)
+			c.receipts[second.ID()] = []flow.ExecutionReceipt{
+				receipt(executor1.NodeID, &second, secondState),
+				receipt(executor2.NodeID, &second, unittest.StateCommitmentFixture()),
+			}
+
+			err := eng.indexRegisters()
+			require.NoError(t, err)
+			assert.Equal(t, first.Height, latest())
+
+			c.finalize(&third, &flow.Seal{BlockID: first.ID(), FinalState: firstState}, &flow.Seal{BlockID: second.ID(), FinalState: secondState})
+
+			// the register delta of the third block is requested from the execution node which reported its end state
+			c.receipts[third.ID()] = []flow.ExecutionReceipt{
+				receipt(executor1.NodeID, &third, thirdState),
+				receipt(executor2.NodeID, &third, thirdState),
+			}
+			con.On("Publish", mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					req, ok := args[0].(*messages.ExecutionRegisterDeltaRequest)
+					require.True(t, ok)
+					assert.Equal(t, third.ID(), req.BlockID)
+					assert.ElementsMatch(t, []flow.Identifier{executor1.NodeID, executor2.NodeID}, args[1:])
+				}).
+				Return(nil).
+				Once()
+
+			err = eng.indexRegisters()
+			require.NoError(t, err)
+
+			// the register deltas of the fork and of the end state which wasn't sealed are dropped
+			assert.Equal(t, second.Height, latest())
+			assert.Equal(t, uint(0), deltas.Size())
+		})
+
+		t.Run("poisoned register delta", func(t *testing.T) {
+			// a register delta resulting in the trusted end state isn't indexed until another execution node sends it
+			err := eng.process(executor1.NodeID, registerDelta(&third, thirdState, "poisoned"))
+			require.NoError(t, err)
+			assert.Equal(t, second.Height, latest())
+		})
+
+		t.Run("missing register delta", func(t *testing.T) {
+			// the register delta is only requested again once the request interval passed
+			err := eng.indexRegisters()
+			require.NoError(t, err)
+			con.AssertNumberOfCalls(t, "Publish", 1)
+			metrics.AssertNumberOfCalls(t, "RegisterDeltaRequested", 1)
+
+			err = eng.process(executor2.NodeID, registerDelta(&third, thirdState, "third"))
+			require.NoError(t, err)
+			err = eng.process(executor3.NodeID, registerDelta(&third, thirdState, "third"))
+			require.NoError(t, err)
+			assert.Equal(t, third.Height, latest())
+		})
+
+		t.Run("ejected register delta", func(t *testing.T) {
+			for i := 0; i < 11; i++ {
+				err := eng.process(executor1.NodeID, registerDelta(&fourth, unittest.StateCommitmentFixture(), "fourth"))
+				require.NoError(t, err)
+			}
+			metrics.AssertNumberOfCalls(t, "RegisterDeltaEjected", 1)
+		})
+
+		expected := map[uint64]string{
+			root.Height:   "root",
+			first.Height:  "first",
+			second.Height: "second",
+			third.Height:  "third",
+		}
+		for height, value := range expected {
+			actual, err := registers.Get(register, height)
+			require.NoError(t, err)
+			assert.Equal(t, value, string(actual))
+		}
+		metrics.AssertNumberOfCalls(t, "RegistersIndexed", 3)
+
+		_, err = registers.Get(register, fourth.Height)
+		assert.True(t, errors.Is(err, storage.ErrHeightNotIndexed))
+	})
+}
@@ -0,0 +1,131 @@
+package registers
+
+import (
+	"errors"
+	"fmt"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ScriptExecutor executes scripts and reads accounts with its own virtual machine, reading the registers of the
+// execution state from the register index. It returns an error wrapping storage.ErrHeightNotIndexed for the heights
+// outside of the index.
+type ScriptExecutor struct {
+	vm        *fvm.VirtualMachine
+	vmCtx     fvm.Context
+	headers   storage.Headers
+	registers storage.Registers
+}
+
+// NewScriptExecutor creates a new script executor reading from the given register index.
+func NewScriptExecutor(vm *fvm.VirtualMachine, vmCtx fvm.Context, headers storage.Headers, registers storage.Registers) *ScriptExecutor {
+	return &ScriptExecutor{
+		vm:        vm,
+		vmCtx:     vmCtx,
+		headers:   headers,
+		registers: registers,
+	}
+}
+
+// ExecuteScriptAtBlockHeight executes a script at the given finalized height and returns its JSON-Cadence encoded
+// value. The errors of failed scripts wrap the fvm.Error they failed with.
+func (e *ScriptExecutor) ExecuteScriptAtBlockHeight(height uint64, script []byte, arguments [][]byte) ([]byte, error) {
+	header, view, err := e.stateAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockCtx := fvm.NewContextFromParent(e.vmCtx, fvm.WithBlockHeader(header))
+
+	procedure := fvm.Script(script).WithArguments(arguments...)
+
+	err = e.vm.Run(blockCtx, procedure, view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute script (internal error): %w", err)
+	}
+
+	if procedure.Err != nil {
+		return nil, fmt.Errorf("failed to execute script at height %d: %w", height, procedure.Err)
+	}
+
+	encodedValue, err := jsoncdc.Encode(procedure.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode runtime value: %w", err)
+	}
+
+	return encodedValue, nil
+}
+
+// GetAccountAtBlockHeight returns the account with the given address at the given finalized height.
+func (e *ScriptExecutor) GetAccountAtBlockHeight(address flow.Address, height uint64) (*flow.Account, error) {
+	header, view, err := e.stateAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockCtx := fvm.NewContextFromParent(e.vmCtx, fvm.WithBlockHeader(header))
+
+	account, err := e.vm.GetAccount(blockCtx, address, view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account at height %d: %w", height, err)
+	}
+
+	return account, nil
+}
+
+// EstimateTransactionFee estimates the fee of the transaction at the given finalized height.
+func (e *ScriptExecutor) EstimateTransactionFee(height uint64, tx *flow.TransactionBody) (*fvm.FeeEstimate, error) {
+	header, view, err := e.stateAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockCtx := fvm.NewContextFromParent(e.vmCtx, fvm.WithBlockHeader(header))
+
+	estimate, err := e.vm.EstimateTransactionFee(blockCtx, tx, view)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate transaction fee at height %d: %w", height, err)
+	}
+
+	return estimate, nil
+}
+
+// stateAtHeight returns the finalized header at the given height, along with a view reading the registers at the
+// height. The height is checked upfront, as the errors of the register reads don't make it through the virtual
+// machine.
+func (e *ScriptExecutor) stateAtHeight(height uint64) (*flow.Header, *delta.View, error) {
+	first, err := e.registers.FirstHeight()
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("register index is not bootstrapped: %w", storage.ErrHeightNotIndexed)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get first indexed height: %w", err)
+	}
+	latest, err := e.registers.LatestHeight()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get latest indexed height: %w", err)
+	}
+	if height < first || height > latest {
+		return nil, nil, fmt.Errorf("height %d is outside of the indexed heights [%d, %d]: %w", height, first, latest, storage.ErrHeightNotIndexed)
+	}
+
+	header, err := e.headers.ByHeight(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+	}
+
+	view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		value, err := e.registers.Get(flow.NewRegisterID(owner, controller, key), height)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		return value, err
+	})
+
+	return header, view, nil
+}
@@ -0,0 +1,108 @@
+package registers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestScriptExecutor(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		chain := flow.Testnet.Chain()
+		vm := fvm.New(runtime.NewInterpreterRuntime())
+		vmCtx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+
+		// bootstrap the register index with the bootstrapped execution state
+		ledger := state.NewMapLedger()
+		view := delta.NewView(ledger.Get)
+		err := vm.Run(vmCtx, fvm.Bootstrap(unittest.ServiceAccountPublicKey, fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply)), view)
+		require.NoError(t, err)
+
+		entries := make(flow.RegisterEntries, 0, len(view.Delta().Data))
+		for _, entry := range view.Delta().Data {
+			entries = append(entries, entry)
+		}
+
+		header := unittest.BlockHeaderFixture()
+		registers := bstorage.NewRegisters(db)
+		err = registers.Bootstrap(header.Height, entries)
+		require.NoError(t, err)
+
+		headers := new(storagemock.Headers)
+		headers.On("ByHeight", header.Height).Return(&header, nil)
+
+		executor := NewScriptExecutor(vm, vmCtx, headers, registers)
+
+		t.Run("execute script", func(t *testing.T) {
+			// the contract and the vault of the service account are read from the registers
+			script := fmt.Sprintf(`
+				import FlowServiceAccount from 0x%s
+
+				pub fun main(): UFix64 {
+					return FlowServiceAccount.defaultTokenBalance(getAccount(0x%s))
+				}`,
+				chain.ServiceAddress().Hex(),
+				chain.ServiceAddress().Hex(),
+			)
+			value, err := executor.ExecuteScriptAtBlockHeight(header.Height, []byte(script), nil)
+			require.NoError(t, err)
+			assert.Contains(t, string(value), `"type":"UFix64"`)
+		})
+
+		t.Run("failed script", func(t *testing.T) {
+			_, err := executor.ExecuteScriptAtBlockHeight(header.Height, []byte("pub fun main() { panic(\"failed\") }"), nil)
+			assert.Error(t, err)
+			assert.False(t, errors.Is(err, storage.ErrHeightNotIndexed))
+		})
+
+		t.Run("get account", func(t *testing.T) {
+			account, err := executor.GetAccountAtBlockHeight(chain.ServiceAddress(), header.Height)
+			require.NoError(t, err)
+			assert.Equal(t, chain.ServiceAddress(), account.Address)
+			assert.NotEmpty(t, account.Keys)
+		})
+
+		t.Run("estimate transaction fee", func(t *testing.T) {
+			// the fixed fee of the service account is read from the registers
+			tx := flow.NewTransactionBody().
+				SetScript([]byte("transaction { prepare(signer: AuthAccount) {} }")).
+				AddAuthorizer(chain.ServiceAddress())
+
+			estimate, err := executor.EstimateTransactionFee(header.Height, tx)
+			require.NoError(t, err)
+			require.NoError(t, estimate.Err)
+			assert.Equal(t, uint64(len(tx.Script)), estimate.Parameters.InclusionEffort)
+		})
+
+		t.Run("missing account", func(t *testing.T) {
+			address, err := chain.AddressAtIndex(1000)
+			require.NoError(t, err)
+
+			_, err = executor.GetAccountAtBlockHeight(address, header.Height)
+			assert.True(t, errors.Is(err, fvm.ErrAccountNotFound))
+		})
+
+		t.Run("height not indexed", func(t *testing.T) {
+			_, err := executor.ExecuteScriptAtBlockHeight(header.Height+1, []byte("pub fun main() { }"), nil)
+			assert.True(t, errors.Is(err, storage.ErrHeightNotIndexed))
+
+			_, err = executor.GetAccountAtBlockHeight(chain.ServiceAddress(), header.Height-1)
+			assert.True(t, errors.Is(err, storage.ErrHeightNotIndexed))
+		})
+	})
+}
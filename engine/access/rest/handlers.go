@@ -0,0 +1,215 @@
+package rest
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// transactionID is the response to a sent transaction.
+type transactionID struct {
+	ID flow.Identifier
+}
+
+// scriptRequest is the request body of a script execution, with the script as UTF-8 encoded Cadence
+// source code and its arguments as JSON-Cadence encoded values.
+type scriptRequest struct {
+	Script    []byte
+	Arguments [][]byte
+}
+
+// scriptResponse is the response to a script execution, with the JSON-Cadence encoded value the script returned.
+type scriptResponse struct {
+	Value []byte
+}
+
+func getLatestBlock(r *request, api access.API) (interface{}, error) {
+	sealed, err := r.optionalBool("sealed")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetLatestBlock(r.Context(), sealed)
+}
+
+func getBlockByHeight(r *request, api access.API) (interface{}, error) {
+	height, err := r.height("height")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetBlockByHeight(r.Context(), height)
+}
+
+func getBlockByID(r *request, api access.API) (interface{}, error) {
+	id, err := r.id("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetBlockByID(r.Context(), id)
+}
+
+func getLatestBlockHeader(r *request, api access.API) (interface{}, error) {
+	sealed, err := r.optionalBool("sealed")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetLatestBlockHeader(r.Context(), sealed)
+}
+
+func getBlockHeaderByHeight(r *request, api access.API) (interface{}, error) {
+	height, err := r.height("height")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetBlockHeaderByHeight(r.Context(), height)
+}
+
+func getBlockHeaderByID(r *request, api access.API) (interface{}, error) {
+	id, err := r.id("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetBlockHeaderByID(r.Context(), id)
+}
+
+func getCollectionByID(r *request, api access.API) (interface{}, error) {
+	id, err := r.id("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetCollectionByID(r.Context(), id)
+}
+
+func sendTransaction(r *request, api access.API) (interface{}, error) {
+	var tx flow.TransactionBody
+	err := r.decodeBody(&tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.SendTransaction(r.Context(), &tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionID{ID: tx.ID()}, nil
+}
+
+func getTransaction(r *request, api access.API) (interface{}, error) {
+	id, err := r.id("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetTransaction(r.Context(), id)
+}
+
+func getTransactionResult(r *request, api access.API) (interface{}, error) {
+	id, err := r.id("id")
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetTransactionResult(r.Context(), id)
+}
+
+func estimateTransactionFee(r *request, api access.API) (interface{}, error) {
+	var tx flow.TransactionBody
+	err := r.decodeBody(&tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.EstimateTransactionFee(r.Context(), &tx)
+}
+
+func getAccount(r *request, api access.API) (interface{}, error) {
+	address, err := r.address("address")
+	if err != nil {
+		return nil, err
+	}
+
+	height, ok, err := r.optionalHeight("block_height")
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		return api.GetAccountAtBlockHeight(r.Context(), address, height)
+	}
+	return api.GetAccountAtLatestBlock(r.Context(), address)
+}
+
+func getEvents(r *request, api access.API) (interface{}, error) {
+	eventType, err := convert.EventType(r.queryParam("type"))
+	if err != nil {
+		return nil, err
+	}
+
+	if r.queryParam("block_ids") != "" {
+		blockIDs, err := r.ids("block_ids")
+		if err != nil {
+			return nil, err
+		}
+
+		return api.GetEventsForBlockIDs(r.Context(), eventType, blockIDs)
+	}
+
+	startHeight, okStart, err := r.optionalHeight("start_height")
+	if err != nil {
+		return nil, err
+	}
+	endHeight, okEnd, err := r.optionalHeight("end_height")
+	if err != nil {
+		return nil, err
+	}
+	if !okStart || !okEnd {
+		return nil, status.Error(codes.InvalidArgument, "either block_ids or start_height and end_height are required")
+	}
+
+	return api.GetEventsForHeightRange(r.Context(), eventType, startHeight, endHeight)
+}
+
+func executeScript(r *request, api access.API) (interface{}, error) {
+	blockID, okID, err := r.optionalID("block_id")
+	if err != nil {
+		return nil, err
+	}
+	height, okHeight, err := r.optionalHeight("block_height")
+	if err != nil {
+		return nil, err
+	}
+	if okID && okHeight {
+		return nil, status.Error(codes.InvalidArgument, "only one of block_id and block_height can be given")
+	}
+
+	var script scriptRequest
+	err = r.decodeBody(&script)
+	if err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	switch {
+	case okID:
+		value, err = api.ExecuteScriptAtBlockID(r.Context(), blockID, script.Script, script.Arguments)
+	case okHeight:
+		value, err = api.ExecuteScriptAtBlockHeight(r.Context(), height, script.Script, script.Arguments)
+	default:
+		value, err = api.ExecuteScriptAtLatestBlock(r.Context(), script.Script, script.Arguments)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return scriptResponse{Value: value}, nil
+}
@@ -0,0 +1,267 @@
+package rest
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// spec is an OpenAPI 3 specification, restricted to the parts the REST API uses.
+type spec struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       specInfo                         `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components components                       `json:"components"`
+}
+
+type specInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type components struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []parameter          `json:"parameters,omitempty"`
+	RequestBody *requestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+// schema is an OpenAPI schema object, the empty schema allows any value.
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+}
+
+var textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// typeSchemas are the schemas of the types with a custom JSON encoding that can't be derived from their Go type.
+var typeSchemas = map[reflect.Type]*schema{
+	reflect.TypeOf(flow.Identifier{}): idSchema,
+	reflect.TypeOf(flow.Address{}):    {Type: "string", Pattern: "^[0-9a-fA-F]{16}$", Description: "hex-encoded address"},
+	reflect.TypeOf(time.Time{}):       {Type: "string", Format: "date-time"},
+	reflect.TypeOf(flow.AccountPublicKey{}): {
+		Type: "object",
+		Properties: map[string]*schema{
+			"PublicKey": {Type: "string", Format: "byte"},
+			"SignAlgo":  {Type: "integer", Format: "int32"},
+			"HashAlgo":  {Type: "integer", Format: "int32"},
+			"SeqNumber": {Type: "integer", Format: "uint64"},
+			"Weight":    {Type: "integer", Format: "int64"},
+		},
+	},
+}
+
+// newSpec generates the OpenAPI specification of the given routes, deriving the schemas of their request and
+// response bodies from the JSON encoding of their Go types.
+func newSpec(routes []route) *spec {
+	g := &schemaGenerator{schemas: make(map[string]*schema)}
+
+	errorSchema := g.schema(reflect.TypeOf(errorResponse{}))
+
+	paths := make(map[string]map[string]*operation)
+	for _, route := range routes {
+		op := &operation{
+			OperationID: route.name,
+			Summary:     route.summary,
+			Responses:   make(map[string]*response),
+		}
+
+		for _, p := range route.params {
+			op.Parameters = append(op.Parameters, parameter{
+				Name:        p.name,
+				In:          p.in,
+				Description: p.description,
+				Required:    p.required,
+				Schema:      p.schema,
+			})
+		}
+
+		if route.body != nil {
+			op.RequestBody = &requestBody{
+				Required: true,
+				Content:  jsonContent(g.schema(reflect.TypeOf(route.body))),
+			}
+		}
+
+		code := route.status
+		if code == 0 {
+			code = http.StatusOK
+		}
+		op.Responses[strconv.Itoa(code)] = &response{
+			Description: http.StatusText(code),
+			Content:     jsonContent(g.schema(reflect.TypeOf(route.response))),
+		}
+		op.Responses["default"] = &response{
+			Description: "Error",
+			Content:     jsonContent(errorSchema),
+		}
+
+		if paths[route.pattern] == nil {
+			paths[route.pattern] = make(map[string]*operation)
+		}
+		paths[route.pattern][strings.ToLower(route.method)] = op
+	}
+
+	return &spec{
+		OpenAPI: "3.0.3",
+		Info: specInfo{
+			Title:   "Flow Access API",
+			Version: "1.0.0",
+		},
+		Paths: paths,
+		Components: components{
+			Schemas: g.schemas,
+		},
+	}
+}
+
+func jsonContent(s *schema) map[string]*mediaType {
+	return map[string]*mediaType{
+		"application/json": {Schema: s},
+	}
+}
+
+// schemaGenerator derives schemas from Go types the way encoding/json encodes them. Named struct types are
+// added to the components of the specification and referenced from the schemas using them.
+type schemaGenerator struct {
+	schemas map[string]*schema
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *schema {
+	if s, ok := typeSchemas[t]; ok {
+		return s
+	}
+
+	if t.Kind() == reflect.Ptr {
+		return g.schema(t.Elem())
+	}
+
+	if t.Implements(textMarshaler) {
+		return &schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint64:
+		return &schema{Type: "integer", Format: "uint64"}
+	case reflect.Float32:
+		return &schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice:
+		// byte slices are encoded as base64 strings
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Array:
+		return &schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		// interfaces can hold any value
+		return &schema{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *schema {
+	name := t.Name()
+	if name == "" {
+		return g.objectSchema(t)
+	}
+
+	ref := &schema{Ref: "#/components/schemas/" + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref
+	}
+
+	// register the name before generating the properties, so recursive types reference themselves
+	g.schemas[name] = &schema{}
+	g.schemas[name] = g.objectSchema(t)
+
+	return ref
+}
+
+func (g *schemaGenerator) objectSchema(t reflect.Type) *schema {
+	s := &schema{
+		Type:       "object",
+		Properties: make(map[string]*schema),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+
+		// the fields of embedded structs are encoded as fields of the embedding struct
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			embedded := g.objectSchema(field.Type)
+			for name, property := range embedded.Properties {
+				s.Properties[name] = property
+			}
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tag != "" {
+			name = tag
+		}
+		s.Properties[name] = g.schema(field.Type)
+	}
+
+	return s
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec(t *testing.T) {
+	router := newTestRouter(&api{})
+
+	code, body := serve(t, router, http.MethodGet, SpecPath, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var s spec
+	err := json.Unmarshal(body, &s)
+	require.NoError(t, err)
+
+	pathParams := regexp.MustCompile(`{([^}]+)}`)
+
+	for _, route := range routes {
+		op, ok := s.Paths[route.pattern][strings.ToLower(route.method)]
+		require.True(t, ok, "missing operation for route %s", route.name)
+		assert.Equal(t, route.name, op.OperationID)
+
+		// every path parameter of the route is documented
+		for _, match := range pathParams.FindAllStringSubmatch(route.pattern, -1) {
+			documented := false
+			for _, p := range op.Parameters {
+				documented = documented || (p.In == "path" && p.Name == match[1])
+			}
+			assert.True(t, documented, "undocumented path parameter %s of route %s", match[1], route.name)
+		}
+	}
+
+	// every referenced schema is defined
+	refs := regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`)
+	for _, match := range refs.FindAllStringSubmatch(string(body), -1) {
+		assert.Contains(t, s.Components.Schemas, match[1])
+	}
+
+	block := s.Components.Schemas["Block"]
+	require.NotNil(t, block)
+	assert.Equal(t, "#/components/schemas/Header", block.Properties["Header"].Ref)
+	assert.Equal(t, idSchema, s.Components.Schemas["Header"].Properties["ParentID"])
+}
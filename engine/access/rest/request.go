@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// request is a request to the REST API, it parses the parameters of the request into flow types and
+// returns an InvalidArgument gRPC status error for invalid parameters, like the Access API does.
+type request struct {
+	*http.Request
+	chain flow.Chain
+}
+
+// pathParam returns the path parameter with the given name, which is always set for a matched route.
+func (r *request) pathParam(name string) string {
+	return mux.Vars(r.Request)[name]
+}
+
+// queryParam returns the query parameter with the given name, or an empty string if it is not set.
+func (r *request) queryParam(name string) string {
+	return r.URL.Query().Get(name)
+}
+
+func (r *request) id(name string) (flow.Identifier, error) {
+	return parseID(name, r.pathParam(name))
+}
+
+func (r *request) height(name string) (uint64, error) {
+	return parseHeight(name, r.pathParam(name))
+}
+
+func (r *request) address(name string) (flow.Address, error) {
+	value := strings.TrimPrefix(r.pathParam(name), "0x")
+
+	b, err := hex.DecodeString(value)
+	if err != nil || len(b) > flow.AddressLength {
+		return flow.EmptyAddress, status.Errorf(codes.InvalidArgument, "invalid %s %q", name, value)
+	}
+
+	return convert.Address(b, r.chain)
+}
+
+// optionalHeight returns the query parameter with the given name parsed as a block height, and whether it is set.
+func (r *request) optionalHeight(name string) (uint64, bool, error) {
+	value := r.queryParam(name)
+	if value == "" {
+		return 0, false, nil
+	}
+
+	height, err := parseHeight(name, value)
+	if err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// optionalID returns the query parameter with the given name parsed as an identifier, and whether it is set.
+func (r *request) optionalID(name string) (flow.Identifier, bool, error) {
+	value := r.queryParam(name)
+	if value == "" {
+		return flow.ZeroID, false, nil
+	}
+
+	id, err := parseID(name, value)
+	if err != nil {
+		return flow.ZeroID, false, err
+	}
+	return id, true, nil
+}
+
+// optionalBool returns the query parameter with the given name parsed as a boolean, or false if it is not set.
+func (r *request) optionalBool(name string) (bool, error) {
+	value := r.queryParam(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "invalid %s %q", name, value)
+	}
+	return b, nil
+}
+
+// ids returns the query parameter with the given name parsed as a comma separated list of identifiers.
+func (r *request) ids(name string) ([]flow.Identifier, error) {
+	var ids []flow.Identifier
+	for _, value := range strings.Split(r.queryParam(name), ",") {
+		id, err := parseID(name, strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// decodeBody decodes the JSON request body into v, rejecting unknown fields.
+func (r *request) decodeBody(v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+func parseID(name string, value string) (flow.Identifier, error) {
+	// check the length first, decoding a longer hex string would overflow the identifier
+	if len(value) != 2*len(flow.ZeroID) {
+		return flow.ZeroID, status.Errorf(codes.InvalidArgument, "invalid %s %q", name, value)
+	}
+
+	id, err := flow.HexStringToIdentifier(value)
+	if err != nil {
+		return flow.ZeroID, status.Errorf(codes.InvalidArgument, "invalid %s %q", name, value)
+	}
+	return id, nil
+}
+
+func parseHeight(name string, value string) (uint64, error) {
+	height, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s %q", name, value)
+	}
+	return height, nil
+}
@@ -0,0 +1,196 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// handlerFunc handles a request to the REST API with the Access API, it returns the value to encode
+// as the JSON response body, or a gRPC status error.
+type handlerFunc func(r *request, api access.API) (interface{}, error)
+
+// route is an endpoint of the REST API. The routes are used both to route requests to their handler
+// and to generate the OpenAPI specification, so that the specification always matches the handlers.
+type route struct {
+	name     string // unique name of the route, used as the OpenAPI operation ID
+	method   string
+	pattern  string // path of the route, with its path parameters in braces
+	summary  string
+	params   []param
+	body     interface{} // value of the type of the request body, nil if the route takes no body
+	response interface{} // value of the type of the response body
+	status   int         // status code of a successful response
+	handler  handlerFunc
+}
+
+// param is a path or query parameter of a route.
+type param struct {
+	name        string
+	in          string // "path" or "query"
+	description string
+	required    bool
+	schema      *schema
+}
+
+var (
+	idSchema      = &schema{Type: "string", Pattern: "^[0-9a-fA-F]{64}$", Description: "hex-encoded identifier"}
+	addressSchema = &schema{Type: "string", Pattern: "^(0x)?[0-9a-fA-F]{1,16}$", Description: "hex-encoded address"}
+	heightSchema  = &schema{Type: "integer", Format: "uint64"}
+	boolSchema    = &schema{Type: "boolean"}
+	stringSchema  = &schema{Type: "string"}
+	idListSchema  = &schema{Type: "string", Description: "comma separated list of hex-encoded identifiers"}
+)
+
+func pathParam(name string, description string, s *schema) param {
+	return param{name: name, in: "path", description: description, required: true, schema: s}
+}
+
+func queryParam(name string, description string, required bool, s *schema) param {
+	return param{name: name, in: "query", description: description, required: required, schema: s}
+}
+
+// routes are the endpoints of the REST API. Routes with a fixed path segment are listed before the
+// routes with a path parameter at the same position, as the first matching route handles a request.
+var routes = []route{
+	{
+		name:     "getLatestBlock",
+		method:   http.MethodGet,
+		pattern:  "/v1/blocks/latest",
+		summary:  "Get the latest finalized or sealed block",
+		params:   []param{queryParam("sealed", "return the latest sealed block instead of the latest finalized block", false, boolSchema)},
+		response: flow.Block{},
+		handler:  getLatestBlock,
+	},
+	{
+		name:     "getBlockByHeight",
+		method:   http.MethodGet,
+		pattern:  "/v1/blocks/height/{height}",
+		summary:  "Get a finalized block by height",
+		params:   []param{pathParam("height", "height of the block", heightSchema)},
+		response: flow.Block{},
+		handler:  getBlockByHeight,
+	},
+	{
+		name:     "getBlockByID",
+		method:   http.MethodGet,
+		pattern:  "/v1/blocks/{id}",
+		summary:  "Get a block by ID",
+		params:   []param{pathParam("id", "ID of the block", idSchema)},
+		response: flow.Block{},
+		handler:  getBlockByID,
+	},
+	{
+		name:     "getLatestBlockHeader",
+		method:   http.MethodGet,
+		pattern:  "/v1/headers/latest",
+		summary:  "Get the header of the latest finalized or sealed block",
+		params:   []param{queryParam("sealed", "return the header of the latest sealed block instead of the latest finalized block", false, boolSchema)},
+		response: flow.Header{},
+		handler:  getLatestBlockHeader,
+	},
+	{
+		name:     "getBlockHeaderByHeight",
+		method:   http.MethodGet,
+		pattern:  "/v1/headers/height/{height}",
+		summary:  "Get the header of a finalized block by height",
+		params:   []param{pathParam("height", "height of the block", heightSchema)},
+		response: flow.Header{},
+		handler:  getBlockHeaderByHeight,
+	},
+	{
+		name:     "getBlockHeaderByID",
+		method:   http.MethodGet,
+		pattern:  "/v1/headers/{id}",
+		summary:  "Get the header of a block by ID",
+		params:   []param{pathParam("id", "ID of the block", idSchema)},
+		response: flow.Header{},
+		handler:  getBlockHeaderByID,
+	},
+	{
+		name:     "getCollectionByID",
+		method:   http.MethodGet,
+		pattern:  "/v1/collections/{id}",
+		summary:  "Get a collection by ID",
+		params:   []param{pathParam("id", "ID of the collection", idSchema)},
+		response: flow.LightCollection{},
+		handler:  getCollectionByID,
+	},
+	{
+		name:     "sendTransaction",
+		method:   http.MethodPost,
+		pattern:  "/v1/transactions",
+		summary:  "Send a transaction to the collection nodes",
+		body:     flow.TransactionBody{},
+		response: transactionID{},
+		status:   http.StatusCreated,
+		handler:  sendTransaction,
+	},
+	{
+		name:     "getTransaction",
+		method:   http.MethodGet,
+		pattern:  "/v1/transactions/{id}",
+		summary:  "Get a transaction by ID",
+		params:   []param{pathParam("id", "ID of the transaction", idSchema)},
+		response: flow.TransactionBody{},
+		handler:  getTransaction,
+	},
+	{
+		name:     "getTransactionResult",
+		method:   http.MethodGet,
+		pattern:  "/v1/transaction_results/{id}",
+		summary:  "Get the result of a transaction by ID",
+		params:   []param{pathParam("id", "ID of the transaction", idSchema)},
+		response: access.TransactionResult{},
+		handler:  getTransactionResult,
+	},
+	{
+		name:     "estimateTransactionFee",
+		method:   http.MethodPost,
+		pattern:  "/v1/transaction_fees",
+		summary:  "Estimate the fee of a transaction at the latest sealed block, before it is signed and sent",
+		body:     flow.TransactionBody{},
+		response: access.FeeEstimate{},
+		handler:  estimateTransactionFee,
+	},
+	{
+		name:    "getAccount",
+		method:  http.MethodGet,
+		pattern: "/v1/accounts/{address}",
+		summary: "Get an account at the latest sealed block or at a given block height",
+		params: []param{
+			pathParam("address", "address of the account", addressSchema),
+			queryParam("block_height", "height of the block to get the account at, the latest sealed block if omitted", false, heightSchema),
+		},
+		response: flow.Account{},
+		handler:  getAccount,
+	},
+	{
+		name:    "getEvents",
+		method:  http.MethodGet,
+		pattern: "/v1/events",
+		summary: "Get the events of a type emitted in a range of blocks or in given blocks",
+		params: []param{
+			queryParam("type", "type of the events", true, stringSchema),
+			queryParam("start_height", "height of the first block of the range, required unless block_ids is given", false, heightSchema),
+			queryParam("end_height", "height of the last block of the range, required unless block_ids is given", false, heightSchema),
+			queryParam("block_ids", "IDs of the blocks, instead of a range of heights", false, idListSchema),
+		},
+		response: []flow.BlockEvents{},
+		handler:  getEvents,
+	},
+	{
+		name:    "executeScript",
+		method:  http.MethodPost,
+		pattern: "/v1/scripts",
+		summary: "Execute a script at the latest sealed block or at a given block",
+		params: []param{
+			queryParam("block_id", "ID of the block to execute the script at", false, idSchema),
+			queryParam("block_height", "height of the block to execute the script at", false, heightSchema),
+		},
+		body:     scriptRequest{},
+		response: scriptResponse{},
+		handler:  executeScript,
+	},
+}
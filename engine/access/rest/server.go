@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	grpcutils "github.com/onflow/flow-go/utils/grpc"
+)
+
+// SpecPath is the path the OpenAPI specification of the REST API is served at.
+const SpecPath = "/v1/openapi.json"
+
+// errorResponse is the response body of a failed request.
+type errorResponse struct {
+	Code    int
+	Message string
+}
+
+// NewServer returns an HTTP server serving the REST API with the given Access API, along with its
+// OpenAPI specification.
+func NewServer(api access.API, chain flow.Chain, address string, log zerolog.Logger) *http.Server {
+	return &http.Server{
+		Addr:    address,
+		Handler: newRouter(api, chain, log),
+	}
+}
+
+func newRouter(api access.API, chain flow.Chain, log zerolog.Logger) *mux.Router {
+	log = log.With().Str("component", "rest").Logger()
+
+	router := mux.NewRouter()
+
+	spec := newSpec(routes)
+	router.Methods(http.MethodGet).Path(SpecPath).HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, spec, log)
+	})
+
+	for _, route := range routes {
+		router.
+			Methods(route.method).
+			Path(route.pattern).
+			Name(route.name).
+			Handler(newHandler(route, api, chain, log))
+	}
+
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "resource not found", log)
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed", log)
+	})
+
+	return router
+}
+
+// newHandler returns the HTTP handler of the route, which encodes the value returned by the route handler as
+// JSON, or the error it returned with the HTTP status code matching its gRPC status code.
+func newHandler(route route, api access.API, chain flow.Chain, log zerolog.Logger) http.Handler {
+	log = log.With().Str("route", route.name).Logger()
+
+	code := route.status
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, grpcutils.DefaultMaxMsgSize)
+
+		response, err := route.handler(&request{Request: r, chain: chain}, api)
+		if err != nil {
+			writeError(w, httpStatusCode(status.Code(err)), status.Convert(err).Message(), log)
+			return
+		}
+
+		writeJSON(w, code, response, log)
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, message string, log zerolog.Logger) {
+	writeJSON(w, code, errorResponse{Code: code, Message: message}, log)
+}
+
+func writeJSON(w http.ResponseWriter, code int, response interface{}, log zerolog.Logger) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to encode response")
+		code = http.StatusInternalServerError
+		body, _ = json.Marshal(errorResponse{Code: code, Message: "failed to encode response"})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to write response")
+	}
+}
+
+// httpStatusCode returns the HTTP status code matching a gRPC status code returned by the Access API.
+func httpStatusCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		// nginx's non standard status code for requests closed by the client
+		return 499
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
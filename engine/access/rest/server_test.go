@@ -0,0 +1,283 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// api mocks the part of the Access API used by the tests, calling any other method panics
+type api struct {
+	access.API
+	mock.Mock
+}
+
+func (a *api) GetBlockByID(_ context.Context, id flow.Identifier) (*flow.Block, error) {
+	args := a.Called(id)
+	block, _ := args.Get(0).(*flow.Block)
+	return block, args.Error(1)
+}
+
+func (a *api) GetBlockHeaderByHeight(_ context.Context, height uint64) (*flow.Header, error) {
+	args := a.Called(height)
+	header, _ := args.Get(0).(*flow.Header)
+	return header, args.Error(1)
+}
+
+func (a *api) SendTransaction(_ context.Context, tx *flow.TransactionBody) error {
+	return a.Called(tx).Error(0)
+}
+
+func (a *api) EstimateTransactionFee(_ context.Context, tx *flow.TransactionBody) (*access.FeeEstimate, error) {
+	args := a.Called(tx)
+	estimate, _ := args.Get(0).(*access.FeeEstimate)
+	return estimate, args.Error(1)
+}
+
+func (a *api) GetAccountAtLatestBlock(_ context.Context, address flow.Address) (*flow.Account, error) {
+	args := a.Called(address)
+	account, _ := args.Get(0).(*flow.Account)
+	return account, args.Error(1)
+}
+
+func (a *api) GetAccountAtBlockHeight(_ context.Context, address flow.Address, height uint64) (*flow.Account, error) {
+	args := a.Called(address, height)
+	account, _ := args.Get(0).(*flow.Account)
+	return account, args.Error(1)
+}
+
+func (a *api) GetEventsForHeightRange(_ context.Context, eventType string, startHeight, endHeight uint64) ([]flow.BlockEvents, error) {
+	args := a.Called(eventType, startHeight, endHeight)
+	events, _ := args.Get(0).([]flow.BlockEvents)
+	return events, args.Error(1)
+}
+
+func (a *api) GetEventsForBlockIDs(_ context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error) {
+	args := a.Called(eventType, blockIDs)
+	events, _ := args.Get(0).([]flow.BlockEvents)
+	return events, args.Error(1)
+}
+
+func (a *api) ExecuteScriptAtBlockHeight(_ context.Context, height uint64, script []byte, arguments [][]byte) ([]byte, error) {
+	args := a.Called(height, script, arguments)
+	value, _ := args.Get(0).([]byte)
+	return value, args.Error(1)
+}
+
+func newTestRouter(api *api) http.Handler {
+	return newRouter(api, flow.Testnet.Chain(), zerolog.Nop())
+}
+
+// serve serves the request with the router and returns the status code and body of the response
+func serve(t *testing.T, router http.Handler, method string, target string, body interface{}) (int, []byte) {
+	var reader bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&reader).Encode(body)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(method, target, &reader)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	return rec.Code, rec.Body.Bytes()
+}
+
+func assertJSON(t *testing.T, expected interface{}, actual []byte) {
+	b, err := json.Marshal(expected)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(actual))
+}
+
+func TestBlocks(t *testing.T) {
+	api := &api{}
+	router := newTestRouter(api)
+
+	block := unittest.BlockFixture()
+	missingID := unittest.IdentifierFixture()
+
+	api.On("GetBlockByID", block.ID()).Return(&block, nil)
+	api.On("GetBlockByID", missingID).Return(nil, status.Errorf(codes.NotFound, "block %v not found", missingID))
+	api.On("GetBlockHeaderByHeight", block.Header.Height).Return(block.Header, nil)
+
+	t.Run("by ID", func(t *testing.T) {
+		code, body := serve(t, router, http.MethodGet, "/v1/blocks/"+block.ID().String(), nil)
+		assert.Equal(t, http.StatusOK, code)
+		assertJSON(t, block, body)
+	})
+
+	t.Run("header by height", func(t *testing.T) {
+		code, body := serve(t, router, http.MethodGet, "/v1/headers/height/"+strconv.FormatUint(block.Header.Height, 10), nil)
+		assert.Equal(t, http.StatusOK, code)
+		assertJSON(t, block.Header, body)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		code, body := serve(t, router, http.MethodGet, "/v1/blocks/"+missingID.String(), nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		assertJSON(t, errorResponse{Code: http.StatusNotFound, Message: "block " + missingID.String() + " not found"}, body)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		code, _ := serve(t, router, http.MethodGet, "/v1/blocks/"+block.ID().String()+"00", nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("invalid height", func(t *testing.T) {
+		code, _ := serve(t, router, http.MethodGet, "/v1/headers/height/-1", nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		code, _ := serve(t, router, http.MethodGet, "/v1/blocks", nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		code, _ := serve(t, router, http.MethodDelete, "/v1/blocks/"+block.ID().String(), nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, code)
+	})
+}
+
+func TestSendTransaction(t *testing.T) {
+	api := &api{}
+	router := newTestRouter(api)
+
+	tx := unittest.TransactionBodyFixture()
+	api.On("SendTransaction", &tx).Return(nil)
+
+	code, body := serve(t, router, http.MethodPost, "/v1/transactions", tx)
+	assert.Equal(t, http.StatusCreated, code)
+	assertJSON(t, transactionID{ID: tx.ID()}, body)
+
+	t.Run("unknown field", func(t *testing.T) {
+		code, _ := serve(t, router, http.MethodPost, "/v1/transactions", map[string]string{"Scrpt": ""})
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestEstimateTransactionFee(t *testing.T) {
+	api := &api{}
+	router := newTestRouter(api)
+
+	tx := unittest.TransactionBodyFixture()
+	estimate := &access.FeeEstimate{Fee: 123, InclusionEffort: 3, ComputationUsed: 2, StorageDelta: 1}
+	api.On("EstimateTransactionFee", &tx).Return(estimate, nil)
+
+	code, body := serve(t, router, http.MethodPost, "/v1/transaction_fees", tx)
+	assert.Equal(t, http.StatusOK, code)
+	assertJSON(t, estimate, body)
+}
+
+func TestGetAccount(t *testing.T) {
+	api := &api{}
+	router := newTestRouter(api)
+
+	account := &flow.Account{
+		Address:   flow.Testnet.Chain().ServiceAddress(),
+		Balance:   100,
+		Contracts: map[string][]byte{"Contract": []byte("contract")},
+	}
+	api.On("GetAccountAtLatestBlock", account.Address).Return(account, nil)
+	api.On("GetAccountAtBlockHeight", account.Address, uint64(42)).Return(account, nil)
+
+	t.Run("latest", func(t *testing.T) {
+		code, body := serve(t, router, http.MethodGet, "/v1/accounts/"+account.Address.HexWithPrefix(), nil)
+		assert.Equal(t, http.StatusOK, code)
+		assertJSON(t, account, body)
+		api.AssertCalled(t, "GetAccountAtLatestBlock", account.Address)
+	})
+
+	t.Run("at height", func(t *testing.T) {
+		code, body := serve(t, router, http.MethodGet, "/v1/accounts/"+account.Address.Hex()+"?block_height=42", nil)
+		assert.Equal(t, http.StatusOK, code)
+		assertJSON(t, account, body)
+		api.AssertCalled(t, "GetAccountAtBlockHeight", account.Address, uint64(42))
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		// valid on mainnet, but not on testnet
+		code, _ := serve(t, router, http.MethodGet, "/v1/accounts/"+flow.Mainnet.Chain().ServiceAddress().Hex(), nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = serve(t, router, http.MethodGet, "/v1/accounts/xyz", nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestGetEvents(t *testing.T) {
+	api := &api{}
+	router := newTestRouter(api)
+
+	blockIDs := []flow.Identifier{unittest.IdentifierFixture(), unittest.IdentifierFixture()}
+	events := []flow.BlockEvents{{
+		BlockID:     blockIDs[0],
+		BlockHeight: 1,
+		Events:      []flow.Event{unittest.EventFixture(flow.EventAccountCreated, 0, 0, unittest.IdentifierFixture())},
+	}}
+
+	api.On("GetEventsForHeightRange", string(flow.EventAccountCreated), uint64(1), uint64(2)).Return(events, nil)
+	api.On("GetEventsForBlockIDs", string(flow.EventAccountCreated), blockIDs).Return(events, nil)
+
+	t.Run("height range", func(t *testing.T) {
+		code, body := serve(t, router, http.MethodGet, "/v1/events?type="+string(flow.EventAccountCreated)+"&start_height=1&end_height=2", nil)
+		assert.Equal(t, http.StatusOK, code)
+		assertJSON(t, events, body)
+	})
+
+	t.Run("block IDs", func(t *testing.T) {
+		target := "/v1/events?type=" + string(flow.EventAccountCreated) + "&block_ids=" + blockIDs[0].String() + "," + blockIDs[1].String()
+		code, body := serve(t, router, http.MethodGet, target, nil)
+		assert.Equal(t, http.StatusOK, code)
+		assertJSON(t, events, body)
+	})
+
+	t.Run("missing range", func(t *testing.T) {
+		code, _ := serve(t, router, http.MethodGet, "/v1/events?type="+string(flow.EventAccountCreated)+"&start_height=1", nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("missing type", func(t *testing.T) {
+		code, _ := serve(t, router, http.MethodGet, "/v1/events?start_height=1&end_height=2", nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
+
+func TestExecuteScript(t *testing.T) {
+	api := &api{}
+	router := newTestRouter(api)
+
+	script := scriptRequest{
+		Script:    []byte("pub fun main(a: Int): Int { return a }"),
+		Arguments: [][]byte{[]byte(`{"type":"Int","value":"1"}`)},
+	}
+	value := []byte(`{"type":"Int","value":"1"}`)
+
+	api.On("ExecuteScriptAtBlockHeight", uint64(42), script.Script, script.Arguments).Return(value, nil)
+
+	code, body := serve(t, router, http.MethodPost, "/v1/scripts?block_height=42", script)
+	assert.Equal(t, http.StatusOK, code)
+	assertJSON(t, scriptResponse{Value: value}, body)
+
+	t.Run("block ID and height", func(t *testing.T) {
+		target := "/v1/scripts?block_height=42&block_id=" + unittest.IdentifierFixture().String()
+		code, _ := serve(t, router, http.MethodPost, target, script)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -63,6 +63,7 @@ func New(
 	chainID flow.ChainID,
 	transactionMetrics module.TransactionMetrics,
 	connFactory ConnectionFactory,
+	scriptExecutor ScriptExecutor,
 	retryEnabled bool,
 	log zerolog.Logger,
 ) *Backend {
@@ -70,6 +71,7 @@ func New(
 	if retryEnabled {
 		retry.Activate()
 	}
+	expiry := newExpiry()
 
 	b := &Backend{
 		executionRPC: executionRPC,
@@ -80,6 +82,7 @@ func New(
 			executionReceipts:  executionReceipts,
 			staticExecutionRPC: executionRPC,
 			connFactory:        connFactory,
+			scriptExecutor:     scriptExecutor,
 			state:              state,
 			log:                log,
 		},
@@ -95,6 +98,7 @@ func New(
 			transactionValidator: configureTransactionValidator(state, chainID),
 			transactionMetrics:   transactionMetrics,
 			retry:                retry,
+			expiry:               expiry,
 			connFactory:          connFactory,
 			previousAccessNodes:  historicalAccessNodes,
 			log:                  log,
@@ -121,6 +125,7 @@ func New(
 			headers:            headers,
 			executionReceipts:  executionReceipts,
 			connFactory:        connFactory,
+			scriptExecutor:     scriptExecutor,
 			log:                log,
 		},
 		collections:       collections,
@@ -130,6 +135,7 @@ func New(
 	}
 
 	retry.SetBackend(b)
+	expiry.SetBackend(b)
 
 	return b
 }
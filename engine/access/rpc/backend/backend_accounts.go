@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -11,6 +12,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
@@ -22,6 +24,7 @@ type backendAccounts struct {
 	headers            storage.Headers
 	executionReceipts  storage.ExecutionReceipts
 	connFactory        ConnectionFactory
+	scriptExecutor     ScriptExecutor // nil if accounts are only read from the execution nodes
 	log                zerolog.Logger
 }
 
@@ -37,15 +40,7 @@ func (b *backendAccounts) GetAccountAtLatestBlock(ctx context.Context, address f
 		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
 	}
 
-	// get the block id of the latest sealed header
-	latestBlockID := latestHeader.ID()
-
-	account, err := b.getAccountAtBlockID(ctx, address, latestBlockID)
-	if err != nil {
-		return nil, err
-	}
-
-	return account, nil
+	return b.getAccountAtFinalizedBlock(ctx, address, latestHeader)
 }
 
 func (b *backendAccounts) GetAccountAtBlockHeight(
@@ -60,15 +55,31 @@ func (b *backendAccounts) GetAccountAtBlockHeight(
 		return nil, err
 	}
 
-	// get block ID of the header at the given height
-	blockID := header.ID()
+	return b.getAccountAtFinalizedBlock(ctx, address, header)
+}
 
-	account, err := b.getAccountAtBlockID(ctx, address, blockID)
-	if err != nil {
-		return nil, err
+// getAccountAtFinalizedBlock reads the account locally if the height of the finalized block is indexed, and from
+// the execution nodes otherwise
+func (b *backendAccounts) getAccountAtFinalizedBlock(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) (*flow.Account, error) {
+
+	if b.scriptExecutor != nil {
+		account, err := b.scriptExecutor.GetAccountAtBlockHeight(address, header.Height)
+		if err == nil {
+			return account, nil
+		}
+		if errors.Is(err, fvm.ErrAccountNotFound) {
+			return nil, status.Errorf(codes.NotFound, "account with address %s does not exist", address)
+		}
+		if !errors.Is(err, storage.ErrHeightNotIndexed) {
+			return nil, status.Errorf(codes.Internal, "failed to get account: %v", err)
+		}
 	}
 
-	return account, nil
+	return b.getAccountAtBlockID(ctx, address, header.ID())
 }
 
 func (b *backendAccounts) getAccountAtBlockID(
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 
 	"github.com/hashicorp/go-multierror"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
@@ -9,17 +10,29 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+// ScriptExecutor executes scripts and reads accounts locally at finalized heights. It returns an error wrapping
+// storage.ErrHeightNotIndexed for the heights it doesn't have the execution state of, for which the requests are
+// forwarded to the execution nodes.
+type ScriptExecutor interface {
+	ExecuteScriptAtBlockHeight(height uint64, script []byte, arguments [][]byte) ([]byte, error)
+	GetAccountAtBlockHeight(address flow.Address, height uint64) (*flow.Account, error)
+	EstimateTransactionFee(height uint64, tx *flow.TransactionBody) (*fvm.FeeEstimate, error)
+}
+
 type backendScripts struct {
 	headers            storage.Headers
 	executionReceipts  storage.ExecutionReceipts
 	state              protocol.State
 	staticExecutionRPC execproto.ExecutionAPIClient
 	connFactory        ConnectionFactory
+	scriptExecutor     ScriptExecutor // nil if scripts are only executed on the execution nodes
 	log                zerolog.Logger
 }
 
@@ -35,11 +48,7 @@ func (b *backendScripts) ExecuteScriptAtLatestBlock(
 		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
 	}
 
-	// get the block id of the latest sealed header
-	latestBlockID := latestHeader.ID()
-
-	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, latestBlockID, script, arguments)
+	return b.executeScriptAtFinalizedBlock(ctx, latestHeader, script, arguments)
 }
 
 func (b *backendScripts) ExecuteScriptAtBlockID(
@@ -65,10 +74,69 @@ func (b *backendScripts) ExecuteScriptAtBlockHeight(
 		return nil, err
 	}
 
-	blockID := header.ID()
+	return b.executeScriptAtFinalizedBlock(ctx, header, script, arguments)
+}
+
+// EstimateTransactionFee estimates the fee of the transaction at the latest sealed block. The execution nodes
+// don't estimate fees, so the fee is only estimated locally, if the height of the block is indexed.
+func (b *backendScripts) EstimateTransactionFee(
+	_ context.Context,
+	tx *flow.TransactionBody,
+) (*access.FeeEstimate, error) {
+	if b.scriptExecutor == nil {
+		return nil, status.Errorf(codes.Unimplemented, "fee estimation requires the register index")
+	}
+
+	latestHeader, err := b.state.Sealed().Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	estimate, err := b.scriptExecutor.EstimateTransactionFee(latestHeader.Height, tx)
+	if errors.Is(err, storage.ErrHeightNotIndexed) {
+		return nil, status.Errorf(codes.Unavailable, "failed to estimate the transaction fee: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to estimate the transaction fee: %v", err)
+	}
+	if estimate.Err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to estimate the transaction fee, the transaction fails: %v", estimate.Err)
+	}
+
+	return &access.FeeEstimate{
+		Fee:             uint64(estimate.Fee),
+		InclusionEffort: estimate.Parameters.InclusionEffort,
+		ComputationUsed: estimate.Parameters.ComputationUsed,
+		StorageDelta:    estimate.Parameters.StorageDelta,
+	}, nil
+}
+
+// executeScriptAtFinalizedBlock executes the script locally if the height of the finalized block is indexed, and
+// forwards it to the execution nodes otherwise
+func (b *backendScripts) executeScriptAtFinalizedBlock(
+	ctx context.Context,
+	header *flow.Header,
+	script []byte,
+	arguments [][]byte,
+) ([]byte, error) {
+
+	if b.scriptExecutor != nil {
+		value, err := b.scriptExecutor.ExecuteScriptAtBlockHeight(header.Height, script, arguments)
+		if err == nil {
+			return value, nil
+		}
+		var scriptErr fvm.Error
+		if errors.As(err, &scriptErr) {
+			// the script itself failed, which the execution nodes report as an invalid argument too
+			return nil, status.Errorf(codes.InvalidArgument, "failed to execute the script: %v", err)
+		}
+		if !errors.Is(err, storage.ErrHeightNotIndexed) {
+			return nil, status.Errorf(codes.Internal, "failed to execute the script: %v", err)
+		}
+	}
 
 	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments)
+	return b.executeScriptOnExecutionNode(ctx, header.ID(), script, arguments)
 }
 
 // executeScriptOnExecutionNode forwards the request to the execution node using the execution node
@@ -99,6 +167,9 @@ func (b *backendScripts) executeScriptOnExecutionNode(
 		}
 		// if an executionRPC is provided, send the script to that execution node
 		execResp, err := b.staticExecutionRPC.ExecuteScriptAtBlockID(ctx, &execReq)
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, err
+		}
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to execute the script on the execution node: %v", err)
 		}
@@ -119,6 +190,10 @@ func (b *backendScripts) executeScriptOnExecutionNode(
 				Msg("Successfully executed script")
 			return result, nil
 		}
+		// the script failed, it would fail the same way on the other execution nodes
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, err
+		}
 		errors = multierror.Append(errors, err)
 	}
 	return nil, errors.ErrorOrNil()
@@ -131,6 +206,9 @@ func (b *backendScripts) tryExecuteScript(ctx context.Context, execNode *flow.Id
 	}
 	defer closer.Close()
 	execResp, err := execRPCClient.ExecuteScriptAtBlockID(ctx, &req)
+	if status.Code(err) == codes.InvalidArgument {
+		return nil, err
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to execute the script on the execution node %s: %v", execNode.String(), err)
 	}
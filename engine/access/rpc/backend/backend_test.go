@@ -2,10 +2,12 @@ package backend
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/onflow/cadence/runtime"
 	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
 	entitiesproto "github.com/onflow/flow/protobuf/go/flow/entities"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
@@ -16,9 +18,11 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	flowaccess "github.com/onflow/flow-go/access"
 	access "github.com/onflow/flow-go/engine/access/mock"
 	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -86,6 +90,7 @@ func (suite *Suite) TestPing() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -108,6 +113,7 @@ func (suite *Suite) TestGetLatestFinalizedBlockHeader() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -136,6 +142,7 @@ func (suite *Suite) TestGetLatestSealedBlockHeader() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -169,6 +176,7 @@ func (suite *Suite) TestGetTransaction() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -198,6 +206,7 @@ func (suite *Suite) TestGetCollection() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -280,6 +289,7 @@ func (suite *Suite) TestTransactionStatusTransition() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory,
+		nil,
 		false,
 		suite.log,
 	)
@@ -383,6 +393,7 @@ func (suite *Suite) TestTransactionExpiredStatusTransition() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -428,6 +439,7 @@ func (suite *Suite) TestGetLatestFinalizedBlock() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -538,6 +550,7 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
+			nil,
 			false,
 			suite.log,
 		)
@@ -562,6 +575,7 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
+			nil,
 			false,
 			suite.log,
 		)
@@ -586,6 +600,7 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
+			nil,
 			false,
 			suite.log,
 		)
@@ -684,6 +699,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
+			nil,
 			false,
 			suite.log,
 		)
@@ -715,6 +731,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
+			nil,
 			false,
 			suite.log,
 		)
@@ -745,6 +762,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
+			nil,
 			false,
 			suite.log,
 		)
@@ -820,6 +838,7 @@ func (suite *Suite) TestGetAccount() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory,
+		nil,
 		false,
 		suite.log,
 	)
@@ -884,6 +903,7 @@ func (suite *Suite) TestGetAccountAtBlockHeight() {
 		flow.Testnet,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -898,6 +918,268 @@ func (suite *Suite) TestGetAccountAtBlockHeight() {
 	})
 }
 
+func (suite *Suite) TestExecuteScriptAtBlockHeightLocally() {
+	ctx := context.Background()
+	script := []byte("pub fun main() { }")
+	value := []byte(`{"type":"Void"}`)
+
+	scriptExecutor := new(backendmock.ScriptExecutor)
+
+	// create the handler with the local script executor
+	backend := New(
+		suite.state,
+		suite.execClient,
+		nil, nil, nil,
+		suite.headers,
+		nil, nil,
+		suite.receipts,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		nil,
+		scriptExecutor,
+		false,
+		suite.log,
+	)
+
+	suite.Run("indexed height", func() {
+		h := unittest.BlockHeaderFixture()
+		suite.headers.On("ByHeight", h.Height).Return(&h, nil).Once()
+		scriptExecutor.On("ExecuteScriptAtBlockHeight", h.Height, script, [][]byte(nil)).Return(value, nil).Once()
+
+		actual, err := backend.ExecuteScriptAtBlockHeight(ctx, h.Height, script, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(value, actual)
+
+		// the execution nodes aren't contacted
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("height not indexed", func() {
+		h := unittest.BlockHeaderFixture()
+		blockID := h.ID()
+		suite.headers.On("ByHeight", h.Height).Return(&h, nil).Once()
+		scriptExecutor.
+			On("ExecuteScriptAtBlockHeight", h.Height, script, [][]byte(nil)).
+			Return(nil, fmt.Errorf("height not available: %w", storage.ErrHeightNotIndexed)).
+			Once()
+
+		// the script falls back to the static execution node
+		suite.receipts.On("ByBlockIDAllExecutionReceipts", blockID).Return([]flow.ExecutionReceipt{}, nil).Once()
+		exeReq := &execproto.ExecuteScriptAtBlockIDRequest{BlockId: blockID[:], Script: script}
+		suite.execClient.
+			On("ExecuteScriptAtBlockID", ctx, exeReq).
+			Return(&execproto.ExecuteScriptAtBlockIDResponse{Value: value}, nil).
+			Once()
+
+		actual, err := backend.ExecuteScriptAtBlockHeight(ctx, h.Height, script, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(value, actual)
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("failed script", func() {
+		h := unittest.BlockHeaderFixture()
+		suite.headers.On("ByHeight", h.Height).Return(&h, nil).Once()
+		scriptErr := &fvm.ExecutionError{Err: runtime.Error{Err: fmt.Errorf("panic")}}
+		scriptExecutor.
+			On("ExecuteScriptAtBlockHeight", h.Height, script, [][]byte(nil)).
+			Return(nil, fmt.Errorf("failed to execute script: %w", scriptErr)).
+			Once()
+
+		// the failed script is reported as the execution nodes report it
+		_, err := backend.ExecuteScriptAtBlockHeight(ctx, h.Height, script, nil)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("internal error", func() {
+		h := unittest.BlockHeaderFixture()
+		suite.headers.On("ByHeight", h.Height).Return(&h, nil).Once()
+		scriptExecutor.
+			On("ExecuteScriptAtBlockHeight", h.Height, script, [][]byte(nil)).
+			Return(nil, fmt.Errorf("failed to execute script (internal error)")).
+			Once()
+
+		_, err := backend.ExecuteScriptAtBlockHeight(ctx, h.Height, script, nil)
+		suite.Require().Equal(codes.Internal, status.Code(err))
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("failed script on the execution node", func() {
+		h := unittest.BlockHeaderFixture()
+		blockID := h.ID()
+		suite.headers.On("ByHeight", h.Height).Return(&h, nil).Once()
+		scriptExecutor.
+			On("ExecuteScriptAtBlockHeight", h.Height, script, [][]byte(nil)).
+			Return(nil, fmt.Errorf("height not available: %w", storage.ErrHeightNotIndexed)).
+			Once()
+
+		suite.receipts.On("ByBlockIDAllExecutionReceipts", blockID).Return([]flow.ExecutionReceipt{}, nil).Once()
+		exeReq := &execproto.ExecuteScriptAtBlockIDRequest{BlockId: blockID[:], Script: script}
+		suite.execClient.
+			On("ExecuteScriptAtBlockID", ctx, exeReq).
+			Return(nil, status.Error(codes.InvalidArgument, "failed to execute script")).
+			Once()
+
+		_, err := backend.ExecuteScriptAtBlockHeight(ctx, h.Height, script, nil)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestGetAccountAtBlockHeightLocally() {
+	ctx := context.Background()
+	address := unittest.AddressFixture()
+
+	scriptExecutor := new(backendmock.ScriptExecutor)
+
+	// create the handler with the local script executor
+	backend := New(
+		suite.state,
+		suite.execClient,
+		nil, nil, nil,
+		suite.headers,
+		nil, nil,
+		suite.receipts,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		nil,
+		scriptExecutor,
+		false,
+		suite.log,
+	)
+
+	suite.Run("indexed height", func() {
+		h := unittest.BlockHeaderFixture()
+		expected := &flow.Account{Address: address}
+		suite.headers.On("ByHeight", h.Height).Return(&h, nil).Once()
+		scriptExecutor.On("GetAccountAtBlockHeight", address, h.Height).Return(expected, nil).Once()
+
+		account, err := backend.GetAccountAtBlockHeight(ctx, address, h.Height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, account)
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("account not found", func() {
+		h := unittest.BlockHeaderFixture()
+		suite.headers.On("ByHeight", h.Height).Return(&h, nil).Once()
+		scriptExecutor.
+			On("GetAccountAtBlockHeight", address, h.Height).
+			Return(nil, fmt.Errorf("failed to get account: %w", fvm.ErrAccountNotFound)).
+			Once()
+
+		_, err := backend.GetAccountAtBlockHeight(ctx, address, h.Height)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestEstimateTransactionFee() {
+	ctx := context.Background()
+	tx := unittest.TransactionBodyFixture()
+
+	scriptExecutor := new(backendmock.ScriptExecutor)
+
+	// create the handler with the local script executor
+	backend := New(
+		suite.state,
+		suite.execClient,
+		nil, nil, nil,
+		suite.headers,
+		nil, nil,
+		suite.receipts,
+		flow.Testnet,
+		metrics.NewNoopCollector(),
+		nil,
+		scriptExecutor,
+		false,
+		suite.log,
+	)
+
+	suite.Run("indexed height", func() {
+		h := unittest.BlockHeaderFixture()
+		suite.snapshot.On("Head").Return(&h, nil).Once()
+		scriptExecutor.
+			On("EstimateTransactionFee", h.Height, &tx).
+			Return(&fvm.FeeEstimate{
+				Fee:        123,
+				Parameters: fvm.FeeParameters{InclusionEffort: 3, ComputationUsed: 2, StorageDelta: 1},
+			}, nil).
+			Once()
+
+		estimate, err := backend.EstimateTransactionFee(ctx, &tx)
+		suite.Require().NoError(err)
+		suite.Require().Equal(&flowaccess.FeeEstimate{Fee: 123, InclusionEffort: 3, ComputationUsed: 2, StorageDelta: 1}, estimate)
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("failing transaction", func() {
+		h := unittest.BlockHeaderFixture()
+		suite.snapshot.On("Head").Return(&h, nil).Once()
+		scriptExecutor.
+			On("EstimateTransactionFee", h.Height, &tx).
+			Return(&fvm.FeeEstimate{Err: &fvm.ExecutionError{Err: runtime.Error{Err: fmt.Errorf("panic")}}}, nil).
+			Once()
+
+		_, err := backend.EstimateTransactionFee(ctx, &tx)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("height not indexed", func() {
+		h := unittest.BlockHeaderFixture()
+		suite.snapshot.On("Head").Return(&h, nil).Once()
+		scriptExecutor.
+			On("EstimateTransactionFee", h.Height, &tx).
+			Return(nil, fmt.Errorf("height not available: %w", storage.ErrHeightNotIndexed)).
+			Once()
+
+		_, err := backend.EstimateTransactionFee(ctx, &tx)
+		suite.Require().Equal(codes.Unavailable, status.Code(err))
+
+		suite.assertAllExpectations()
+		scriptExecutor.AssertExpectations(suite.T())
+	})
+
+	suite.Run("no register index", func() {
+		backend := New(
+			suite.state,
+			suite.execClient,
+			nil, nil, nil,
+			suite.headers,
+			nil, nil,
+			suite.receipts,
+			flow.Testnet,
+			metrics.NewNoopCollector(),
+			nil,
+			nil,
+			false,
+			suite.log,
+		)
+
+		_, err := backend.EstimateTransactionFee(ctx, &tx)
+		suite.Require().Equal(codes.Unimplemented, status.Code(err))
+	})
+}
+
 func (suite *Suite) TestGetNetworkParameters() {
 	expectedChainID := flow.Mainnet
 
@@ -907,6 +1189,7 @@ func (suite *Suite) TestGetNetworkParameters() {
 		flow.Mainnet,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
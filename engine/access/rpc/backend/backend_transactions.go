@@ -36,6 +36,7 @@ type backendTransactions struct {
 	transactionMetrics   module.TransactionMetrics
 	transactionValidator *access.TransactionValidator
 	retry                *Retry
+	expiry               *expiry
 	connFactory          ConnectionFactory
 
 	previousAccessNodes []accessproto.AccessAPIClient
@@ -73,6 +74,8 @@ func (b *backendTransactions) SendTransaction(
 		go b.registerTransactionForRetry(tx)
 	}
 
+	go b.registerTransactionForExpiry(tx)
+
 	return nil
 }
 
@@ -387,6 +390,15 @@ func (b *backendTransactions) registerTransactionForRetry(tx *flow.TransactionBo
 	b.retry.RegisterTransaction(referenceBlock.Height, tx)
 }
 
+func (b *backendTransactions) registerTransactionForExpiry(tx *flow.TransactionBody) {
+	referenceBlock, err := b.state.AtBlockID(tx.ReferenceBlockID).Head()
+	if err != nil {
+		return
+	}
+
+	b.expiry.RegisterTransaction(referenceBlock.Height, tx)
+}
+
 func (b *backendTransactions) getTransactionResultFromExecutionNode(
 	ctx context.Context,
 	blockID flow.Identifier,
@@ -434,8 +446,11 @@ func (b *backendTransactions) getTransactionResultFromExecutionNode(
 	return events, resp.GetStatusCode(), resp.GetErrorMessage(), nil
 }
 
-func (b *backendTransactions) NotifyFinalizedBlockHeight(height uint64) {
+// NotifyFinalizedBlockHeight retries the pending transactions due for a retry at the given finalized height, and
+// returns the IDs of the transactions which expired at the given finalized height.
+func (b *backendTransactions) NotifyFinalizedBlockHeight(height uint64) []flow.Identifier {
 	b.retry.Retry(height)
+	return b.expiry.Expire(height)
 }
 
 func (b *backendTransactions) getTransactionResultFromAnyExeNode(ctx context.Context, execNodes flow.IdentityList, req execproto.GetTransactionResultRequest) (*execproto.GetTransactionResultResponse, error) {
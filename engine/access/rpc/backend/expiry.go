@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// expiry tracks the transactions sent through the access node, to report the ones which expired.
+type expiry struct {
+	mu sync.Mutex
+	// sent transactions, by the height of their reference block
+	transactionsByReferenceBlockHeight map[uint64]map[flow.Identifier]*flow.TransactionBody
+	backend                            *Backend
+}
+
+func newExpiry() *expiry {
+	return &expiry{
+		transactionsByReferenceBlockHeight: map[uint64]map[flow.Identifier]*flow.TransactionBody{},
+	}
+}
+
+func (e *expiry) SetBackend(b *Backend) *expiry {
+	e.backend = b
+	return e
+}
+
+// RegisterTransaction adds a transaction whose expiry is reported
+func (e *expiry) RegisterTransaction(height uint64, tx *flow.TransactionBody) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.transactionsByReferenceBlockHeight[height] == nil {
+		e.transactionsByReferenceBlockHeight[height] = make(map[flow.Identifier]*flow.TransactionBody)
+	}
+	e.transactionsByReferenceBlockHeight[height][tx.ID()] = tx
+}
+
+// Expire returns the IDs of the registered transactions which expired at the given finalized height,
+// i.e. whose reference height is DefaultTransactionExpiry + 1 below it, and weren't included in a block.
+//
+// The transactions which could have expired at the given finalized height or before are removed, so the expiry
+// of a transaction is reported once. Their statuses are derived without holding the lock.
+func (e *expiry) Expire(height uint64) []flow.Identifier {
+	if height <= flow.DefaultTransactionExpiry {
+		return nil
+	}
+	referenceHeight := height - flow.DefaultTransactionExpiry - 1
+
+	e.mu.Lock()
+	candidates := e.transactionsByReferenceBlockHeight[referenceHeight]
+	for h := range e.transactionsByReferenceBlockHeight {
+		if h <= referenceHeight {
+			delete(e.transactionsByReferenceBlockHeight, h)
+		}
+	}
+	e.mu.Unlock()
+
+	var expired []flow.Identifier
+	for txID, tx := range candidates {
+		status, err := e.backend.DeriveTransactionStatus(tx, false)
+		if err != nil {
+			continue
+		}
+		if status == flow.TransactionStatusExpired {
+			expired = append(expired, txID)
+		}
+	}
+	return expired
+}
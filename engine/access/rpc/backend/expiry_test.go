@@ -0,0 +1,49 @@
+package backend
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	realstorage "github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestTransactionExpiry tests that the transactions which expired are reported, even if retrying is not active
+func (suite *Suite) TestTransactionExpiry() {
+
+	collection := unittest.CollectionFixture(1)
+	transactionBody := collection.Transactions[0]
+	block := unittest.BlockFixture()
+	block.Header.Height = flow.DefaultTransactionExpiry + 1
+	transactionBody.SetReferenceBlockID(block.ID())
+
+	// the latest finalized block is the first one the transaction expired at
+	headBlock := unittest.BlockFixture()
+	headBlock.Header.Height = block.Header.Height + flow.DefaultTransactionExpiry + 1
+	suite.snapshot.On("Head").Return(headBlock.Header, nil)
+	snapshotAtBlock := new(protocol.Snapshot)
+	snapshotAtBlock.On("Head").Return(block.Header, nil)
+	suite.state.On("AtBlockID", block.ID()).Return(snapshotAtBlock, nil)
+
+	// collection storage returns a not found error
+	suite.collections.On("LightByTransactionID", transactionBody.ID()).Return(nil, realstorage.ErrNotFound)
+
+	backend := New(suite.state, suite.execClient, suite.colClient, nil, suite.blocks, suite.headers,
+		suite.collections, suite.transactions, suite.receipts, suite.chainID, metrics.NewNoopCollector(), nil, nil,
+		false, suite.log)
+	backend.expiry.RegisterTransaction(block.Header.Height, transactionBody)
+
+	// the transaction has not expired at the previous height
+	suite.Require().Empty(backend.NotifyFinalizedBlockHeight(headBlock.Header.Height - 1))
+
+	suite.Require().Equal([]flow.Identifier{transactionBody.ID()}, backend.NotifyFinalizedBlockHeight(headBlock.Header.Height))
+
+	// the expiry is reported once
+	suite.Require().Empty(backend.NotifyFinalizedBlockHeight(headBlock.Header.Height))
+
+	// the transaction was neither registered for a retry nor retried
+	suite.Require().Empty(backend.retry.transactionByReferencBlockHeight)
+	suite.colClient.AssertNotCalled(suite.T(), "SendTransaction", mock.Anything, mock.Anything)
+}
@@ -50,6 +50,7 @@ func (suite *Suite) TestHistoricalTransactionResult() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
@@ -104,6 +105,7 @@ func (suite *Suite) TestHistoricalTransaction() {
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
+		nil,
 		false,
 		suite.log,
 	)
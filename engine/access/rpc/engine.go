@@ -18,7 +18,10 @@ import (
 	"github.com/onflow/flow-go/access"
 	legacyaccess "github.com/onflow/flow-go/access/legacy"
 	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/engine/access/rpc/streaming"
+	streamingproto "github.com/onflow/flow-go/engine/access/rpc/streaming/protobuf"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/state/protocol"
@@ -30,6 +33,7 @@ import (
 type Config struct {
 	GRPCListenAddr        string
 	HTTPListenAddr        string
+	RESTListenAddr        string // the REST API is disabled if empty
 	ExecutionAddr         string
 	CollectionAddr        string
 	HistoricalAccessAddrs string
@@ -40,9 +44,11 @@ type Config struct {
 type Engine struct {
 	unit       *engine.Unit
 	log        zerolog.Logger
-	backend    *backend.Backend // the gRPC service implementation
-	grpcServer *grpc.Server     // the gRPC server
+	backend    *backend.Backend   // the gRPC service implementation
+	streaming  *streaming.Backend // the gRPC streaming service implementation
+	grpcServer *grpc.Server       // the gRPC server
 	httpServer *http.Server
+	restServer *http.Server // the REST API server, nil if disabled
 	config     Config
 }
 
@@ -58,6 +64,7 @@ func New(log zerolog.Logger,
 	collections storage.Collections,
 	transactions storage.Transactions,
 	executionReceipts storage.ExecutionReceipts,
+	scriptExecutor backend.ScriptExecutor,
 	chainID flow.ChainID,
 	transactionMetrics module.TransactionMetrics,
 	collectionGRPCPort uint,
@@ -108,24 +115,44 @@ func New(log zerolog.Logger,
 		chainID,
 		transactionMetrics,
 		connectionFactory,
+		scriptExecutor,
 		retryEnabled,
 		log,
 	)
 
+	streamingBackend := streaming.New(
+		log,
+		state,
+		headers,
+		blocks,
+		executionReceipts,
+		backend,
+	)
+
 	eng := &Engine{
 		log:        log,
 		unit:       engine.NewUnit(),
 		backend:    backend,
+		streaming:  streamingBackend,
 		grpcServer: grpcServer,
 		httpServer: httpServer,
 		config:     config,
 	}
 
+	if config.RESTListenAddr != "" {
+		eng.restServer = rest.NewServer(backend, chainID.Chain(), config.RESTListenAddr, log)
+	}
+
 	accessproto.RegisterAccessAPIServer(
 		eng.grpcServer,
 		access.NewHandler(backend, chainID.Chain()),
 	)
 
+	streamingproto.RegisterStreamingAPIServer(
+		eng.grpcServer,
+		streaming.NewHandler(streamingBackend, chainID.Chain()),
+	)
+
 	if rpcMetricsEnabled {
 		// Not interested in legacy metrics, so initialize here
 		grpc_prometheus.EnableHandlingTimeHistogram()
@@ -147,6 +174,9 @@ func New(log zerolog.Logger,
 func (e *Engine) Ready() <-chan struct{} {
 	e.unit.Launch(e.serveGRPC)
 	e.unit.Launch(e.serveGRPCWebProxy)
+	if e.restServer != nil {
+		e.unit.Launch(e.serveREST)
+	}
 	return e.unit.Ready()
 }
 
@@ -160,6 +190,15 @@ func (e *Engine) Done() <-chan struct{} {
 			if err != nil {
 				e.log.Error().Err(err).Msg("error stopping http server")
 			}
+		},
+		func() {
+			if e.restServer == nil {
+				return
+			}
+			err := e.restServer.Shutdown(context.Background())
+			if err != nil {
+				e.log.Error().Err(err).Msg("error stopping rest server")
+			}
 		})
 }
 
@@ -179,7 +218,23 @@ func (e *Engine) SubmitLocal(event interface{}) {
 func (e *Engine) process(event interface{}) error {
 	switch entity := event.(type) {
 	case *flow.Block:
-		e.backend.NotifyFinalizedBlockHeight(entity.Header.Height)
+		expired := e.backend.NotifyFinalizedBlockHeight(entity.Header.Height)
+		if len(expired) > 0 {
+			e.streaming.NotifyTransactions(streaming.TransactionTransition{
+				TransactionIDs: expired,
+				Status:         flow.TransactionStatusExpired,
+			})
+		}
+		e.streaming.Notify()
+		return nil
+	case *streaming.TransactionTransition:
+		e.streaming.NotifyTransactions(*entity)
+		return nil
+	case *flow.LightCollection:
+		e.streaming.Notify()
+		return nil
+	case *flow.ExecutionReceipt:
+		e.streaming.Notify()
 		return nil
 	default:
 		return fmt.Errorf("invalid event type (%T)", event)
@@ -219,3 +274,18 @@ func (e *Engine) serveGRPCWebProxy() {
 		e.log.Err(err).Msg("failed to start the http proxy server")
 	}
 }
+
+// serveREST starts the REST API server
+func (e *Engine) serveREST() {
+	log := e.log.With().Str("rest_address", e.config.RESTListenAddr).Logger()
+
+	log.Info().Msg("starting rest server on address")
+
+	err := e.restServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return
+	}
+	if err != nil {
+		e.log.Err(err).Msg("failed to start the rest server")
+	}
+}
@@ -0,0 +1,379 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// BlockStatus is the status a block must reach before it is streamed.
+type BlockStatus int
+
+const (
+	BlockStatusSealed BlockStatus = iota
+	BlockStatusFinalized
+)
+
+func (s BlockStatus) String() string {
+	switch s {
+	case BlockStatusSealed:
+		return "sealed"
+	case BlockStatusFinalized:
+		return "finalized"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// API is the part of the Access API the streaming API is built upon, it is implemented by the access backend.
+type API interface {
+	// GetEventsForBlockIDs retrieves the events of the given type of blocks from the execution nodes,
+	// or all their events if the type is empty.
+	GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error)
+	// SendTransaction sends a transaction to the collection nodes.
+	SendTransaction(ctx context.Context, tx *flow.TransactionBody) error
+	// GetTransactionResult retrieves the result of a transaction from the execution nodes.
+	GetTransactionResult(ctx context.Context, txID flow.Identifier) (*access.TransactionResult, error)
+}
+
+// Backend implements the streaming API.
+//
+// Subscriptions wait on the notifier for the blocks they stream to reach the requested status, each one at
+// its own pace, reading the blocks from storage: a subscription starting in the past backfills up to the latest
+// block, and a slow client only delays its own subscription.
+type Backend struct {
+	log               zerolog.Logger
+	state             protocol.State
+	headers           storage.Headers
+	blocks            storage.Blocks
+	executionReceipts storage.ExecutionReceipts
+	api               API
+	notifier          *Notifier
+	events            *eventsCache
+	transactions      *transactionSubscriptions
+}
+
+// New returns a new streaming backend.
+func New(
+	log zerolog.Logger,
+	state protocol.State,
+	headers storage.Headers,
+	blocks storage.Blocks,
+	executionReceipts storage.ExecutionReceipts,
+	api API,
+) *Backend {
+	return &Backend{
+		log:               log.With().Str("component", "streaming").Logger(),
+		state:             state,
+		headers:           headers,
+		blocks:            blocks,
+		executionReceipts: executionReceipts,
+		api:               api,
+		notifier:          NewNotifier(),
+		events:            newEventsCache(eventsCacheSize),
+		transactions:      newTransactionSubscriptions(),
+	}
+}
+
+// Notify wakes up the subscriptions, it is called whenever the access node learns about a newly finalized
+// block, which may also seal blocks, about a new collection, or about a new execution receipt.
+func (b *Backend) Notify() {
+	b.notifier.Notify()
+}
+
+// SubscribeEvents streams the events matching the filter of every block, starting at the given block,
+// once the block reached the given status, until the context is cancelled or sending fails.
+//
+// A block is sent even if none of its events match, so the height of the last sent block can be used as
+// a cursor to resume from. Finalized blocks are only sent once executed, i.e. once an execution receipt
+// for them has been received.
+//
+// The filter must select at least one event type, contract address or transaction.
+func (b *Backend) SubscribeEvents(
+	ctx context.Context,
+	startBlockID flow.Identifier,
+	startHeight uint64,
+	blockStatus BlockStatus,
+	filter EventFilter,
+	send func(flow.BlockEvents) error,
+) error {
+
+	if len(filter.EventTypes) == 0 && len(filter.Addresses) == 0 && len(filter.TransactionIDs) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one event type, address or transaction ID is required")
+	}
+
+	return b.subscribe(ctx, startBlockID, startHeight, blockStatus, func(header *flow.Header) error {
+		if blockStatus == BlockStatusFinalized {
+			err := b.waitForExecution(ctx, header.ID())
+			if err != nil {
+				return err
+			}
+		}
+
+		events, err := b.blockEvents(ctx, header.ID(), filter)
+		if err != nil {
+			return err
+		}
+
+		return send(flow.BlockEvents{
+			BlockID:        header.ID(),
+			BlockHeight:    header.Height,
+			BlockTimestamp: header.Timestamp,
+			Events:         events,
+		})
+	})
+}
+
+// SubscribeBlockHeaders streams the header of every block, starting at the given block, once the block
+// reached the given status, until the context is cancelled or sending fails.
+func (b *Backend) SubscribeBlockHeaders(
+	ctx context.Context,
+	startBlockID flow.Identifier,
+	startHeight uint64,
+	blockStatus BlockStatus,
+	send func(*flow.Header) error,
+) error {
+	return b.subscribe(ctx, startBlockID, startHeight, blockStatus, send)
+}
+
+// SubscribeBlocks streams every block, starting at the given block, once the block reached the given status,
+// until the context is cancelled or sending fails.
+func (b *Backend) SubscribeBlocks(
+	ctx context.Context,
+	startBlockID flow.Identifier,
+	startHeight uint64,
+	blockStatus BlockStatus,
+	send func(*flow.Block) error,
+) error {
+	return b.subscribe(ctx, startBlockID, startHeight, blockStatus, func(header *flow.Header) error {
+		block, err := b.blocks.ByID(header.ID())
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to get block %v: %v", header.ID(), err)
+		}
+
+		return send(block)
+	})
+}
+
+// subscribe calls next with the header of every block, starting at the given block, once the block reached
+// the given status, until the context is cancelled or next fails.
+//
+// The blocks are read from storage, so a subscription starting in the past first backfills the blocks up to the
+// latest one. The next block is only read once next returned, and sending to a stream blocks while its flow
+// control window is full: a slow client applies backpressure to its own subscription, no blocks are buffered for it.
+func (b *Backend) subscribe(
+	ctx context.Context,
+	startBlockID flow.Identifier,
+	startHeight uint64,
+	blockStatus BlockStatus,
+	next func(*flow.Header) error,
+) error {
+
+	height, err := b.startHeight(startBlockID, startHeight, blockStatus)
+	if err != nil {
+		return err
+	}
+
+	for ; ; height++ {
+		header, err := b.waitForBlock(ctx, height, blockStatus)
+		if err != nil {
+			return err
+		}
+
+		err = next(header)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// startHeight returns the height of the first block of a subscription: the height of the start block if given,
+// the start height otherwise, or the height of the latest block with the given status if neither is given.
+func (b *Backend) startHeight(startBlockID flow.Identifier, startHeight uint64, blockStatus BlockStatus) (uint64, error) {
+	if startBlockID != flow.ZeroID {
+		header, err := b.headers.ByBlockID(startBlockID)
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, status.Errorf(codes.NotFound, "start block %v not found", startBlockID)
+		}
+		if err != nil {
+			return 0, status.Errorf(codes.Internal, "failed to get start block %v: %v", startBlockID, err)
+		}
+
+		// only finalized blocks are indexed by height
+		finalized, err := b.headers.ByHeight(header.Height)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return 0, status.Errorf(codes.Internal, "failed to get finalized block at height %d: %v", header.Height, err)
+		}
+		if err != nil || finalized.ID() != startBlockID {
+			return 0, status.Errorf(codes.InvalidArgument, "start block %v is not finalized", startBlockID)
+		}
+
+		return header.Height, nil
+	}
+
+	if startHeight > 0 {
+		return startHeight, nil
+	}
+
+	latest, err := b.latestBlock(blockStatus)
+	if err != nil {
+		return 0, err
+	}
+	return latest.Height, nil
+}
+
+// latestBlock returns the header of the latest block with the given status.
+func (b *Backend) latestBlock(blockStatus BlockStatus) (*flow.Header, error) {
+	var snapshot protocol.Snapshot
+	switch blockStatus {
+	case BlockStatusSealed:
+		snapshot = b.state.Sealed()
+	case BlockStatusFinalized:
+		snapshot = b.state.Final()
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid block status %v", blockStatus)
+	}
+
+	header, err := snapshot.Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest %v block: %v", blockStatus, err)
+	}
+	return header, nil
+}
+
+// waitForBlock waits until the block at the given height reached the given status, and returns its header.
+func (b *Backend) waitForBlock(ctx context.Context, height uint64, blockStatus BlockStatus) (*flow.Header, error) {
+	for {
+		changed := b.notifier.Changed()
+
+		latest, err := b.latestBlock(blockStatus)
+		if err != nil {
+			return nil, err
+		}
+
+		if height <= latest.Height {
+			header, err := b.headers.ByHeight(height)
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, status.Errorf(codes.NotFound, "block at height %d not found", height)
+			}
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to get block at height %d: %v", height, err)
+			}
+			return header, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, contextError(ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+// waitForExecution waits until an execution receipt for the given block has been received.
+func (b *Backend) waitForExecution(ctx context.Context, blockID flow.Identifier) error {
+	for {
+		changed := b.notifier.Changed()
+
+		executed, err := b.executed(blockID)
+		if err != nil {
+			return err
+		}
+
+		if executed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return contextError(ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+// executed returns true if an execution receipt for the given block has been received.
+func (b *Backend) executed(blockID flow.Identifier) (bool, error) {
+	receipts, err := b.executionReceipts.ByBlockIDAllExecutionReceipts(blockID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return false, status.Errorf(codes.Internal, "failed to get execution receipts for block %v: %v", blockID, err)
+	}
+	return len(receipts) > 0, nil
+}
+
+// blockEvents returns the events of the given block matching the filter, in the order they were emitted.
+//
+// The events are fetched from the execution nodes by event type if the filter selects event types, and all
+// events of the block are fetched at once otherwise, to be filtered by address and transaction here.
+// Fetched events are shared between the subscriptions streaming the same block.
+func (b *Backend) blockEvents(ctx context.Context, blockID flow.Identifier, filter EventFilter) ([]flow.Event, error) {
+	var candidates []flow.Event
+
+	if len(filter.EventTypes) > 0 {
+		for _, eventType := range filter.EventTypes {
+			events, err := b.eventsByType(ctx, blockID, eventType)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, events...)
+		}
+	} else {
+		events, err := b.eventsByType(ctx, blockID, "")
+		if err != nil {
+			return nil, err
+		}
+		candidates = events
+	}
+
+	var events []flow.Event
+	for _, event := range candidates {
+		if filter.Match(event) {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].TransactionIndex != events[j].TransactionIndex {
+			return events[i].TransactionIndex < events[j].TransactionIndex
+		}
+		return events[i].EventIndex < events[j].EventIndex
+	})
+
+	return events, nil
+}
+
+// eventsByType returns the events of the given type of the given block, or all its events if the type is empty.
+func (b *Backend) eventsByType(ctx context.Context, blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error) {
+	key := eventsCacheKey{blockID: blockID, eventType: eventType}
+
+	return b.events.get(ctx, key, func() ([]flow.Event, error) {
+		results, err := b.api.GetEventsForBlockIDs(ctx, string(eventType), []flow.Identifier{blockID})
+		if err != nil {
+			return nil, err
+		}
+
+		var events []flow.Event
+		for _, result := range results {
+			events = append(events, result.Events...)
+		}
+		return events, nil
+	})
+}
+
+// contextError converts the error of a done context to a gRPC status error
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Canceled, err.Error())
+}
@@ -0,0 +1,657 @@
+package streaming
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// api is an Access API serving the events of the blocks and the results of the transactions of the suite
+type api struct {
+	suite *Suite
+}
+
+func (a api) GetEventsForBlockIDs(_ context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error) {
+	a.suite.mu.Lock()
+	defer a.suite.mu.Unlock()
+
+	a.suite.eventsCalls++
+
+	results := make([]flow.BlockEvents, 0, len(blockIDs))
+	for _, blockID := range blockIDs {
+		if eventType == "" {
+			a.suite.allEventsCalls[blockID]++
+		}
+
+		result := flow.BlockEvents{BlockID: blockID}
+		for _, event := range a.suite.events[blockID] {
+			if eventType == "" || event.Type == flow.EventType(eventType) {
+				result.Events = append(result.Events, event)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (a api) SendTransaction(_ context.Context, tx *flow.TransactionBody) error {
+	a.suite.mu.Lock()
+	defer a.suite.mu.Unlock()
+
+	a.suite.sent = append(a.suite.sent, tx)
+	return nil
+}
+
+func (a api) GetTransactionResult(_ context.Context, txID flow.Identifier) (*access.TransactionResult, error) {
+	a.suite.mu.Lock()
+	defer a.suite.mu.Unlock()
+
+	a.suite.resultCalls[txID]++
+
+	result, ok := a.suite.results[txID]
+	if !ok {
+		return &access.TransactionResult{Status: flow.TransactionStatusFinalized}, nil
+	}
+	return result, nil
+}
+
+type Suite struct {
+	suite.Suite
+
+	state    *protocol.State
+	sealed   *protocol.Snapshot
+	final    *protocol.Snapshot
+	headers  *storagemock.Headers
+	blocks   *storagemock.Blocks
+	receipts *storagemock.ExecutionReceipts
+
+	mu              sync.Mutex
+	chain           []*flow.Block
+	sealedHeight    uint64
+	finalizedHeight uint64
+	executed        map[flow.Identifier]bool
+	events          map[flow.Identifier][]flow.Event
+	eventsCalls     int
+	allEventsCalls  map[flow.Identifier]int
+	sent            []*flow.TransactionBody
+	txBlock         *flow.Block
+	txExpired       bool
+	results         map[flow.Identifier]*access.TransactionResult
+	resultCalls     map[flow.Identifier]int
+
+	backend *Backend
+}
+
+func TestBackend(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (suite *Suite) SetupTest() {
+	suite.chain = nil
+	for height := uint64(0); height < 5; height++ {
+		block := unittest.BlockFixture()
+		block.Header.Height = height
+		suite.chain = append(suite.chain, &block)
+	}
+	suite.sealedHeight = 2
+	suite.finalizedHeight = 3
+	suite.executed = make(map[flow.Identifier]bool)
+	suite.events = make(map[flow.Identifier][]flow.Event)
+	suite.eventsCalls = 0
+	suite.allEventsCalls = make(map[flow.Identifier]int)
+	suite.sent = nil
+	suite.results = make(map[flow.Identifier]*access.TransactionResult)
+	suite.resultCalls = make(map[flow.Identifier]int)
+
+	suite.sealed = new(protocol.Snapshot)
+	suite.sealed.On("Head").Return(
+		func() *flow.Header {
+			suite.mu.Lock()
+			defer suite.mu.Unlock()
+			return suite.chain[suite.sealedHeight].Header
+		},
+		nil,
+	)
+
+	suite.final = new(protocol.Snapshot)
+	suite.final.On("Head").Return(
+		func() *flow.Header {
+			suite.mu.Lock()
+			defer suite.mu.Unlock()
+			return suite.chain[suite.finalizedHeight].Header
+		},
+		nil,
+	)
+
+	suite.state = new(protocol.State)
+	suite.state.On("Sealed").Return(suite.sealed)
+	suite.state.On("Final").Return(suite.final)
+
+	suite.headers = new(storagemock.Headers)
+	suite.headers.On("ByHeight", mock.Anything).Return(
+		func(height uint64) *flow.Header {
+			if height >= uint64(len(suite.chain)) {
+				return nil
+			}
+			return suite.chain[height].Header
+		},
+		func(height uint64) error {
+			if height >= uint64(len(suite.chain)) {
+				return storage.ErrNotFound
+			}
+			return nil
+		},
+	)
+
+	suite.blocks = new(storagemock.Blocks)
+	for _, block := range suite.chain {
+		suite.blocks.On("ByID", block.ID()).Return(block, nil)
+	}
+
+	suite.receipts = new(storagemock.ExecutionReceipts)
+	suite.receipts.On("ByBlockIDAllExecutionReceipts", mock.Anything).Return(
+		func(blockID flow.Identifier) []flow.ExecutionReceipt {
+			suite.mu.Lock()
+			defer suite.mu.Unlock()
+			if !suite.executed[blockID] {
+				return nil
+			}
+			return []flow.ExecutionReceipt{*unittest.ExecutionReceiptFixture()}
+		},
+		nil,
+	)
+
+	suite.backend = New(zerolog.Nop(), suite.state, suite.headers, suite.blocks, suite.receipts, api{suite: suite})
+}
+
+// subscribe starts an event subscription, the blocks it sends are returned on the channel, and the error
+// it returns once it is over on the error channel
+func (suite *Suite) subscribe(
+	ctx context.Context,
+	startBlockID flow.Identifier,
+	startHeight uint64,
+	blockStatus BlockStatus,
+	filter EventFilter,
+) (<-chan flow.BlockEvents, <-chan error) {
+	sent := make(chan flow.BlockEvents)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- suite.backend.SubscribeEvents(ctx, startBlockID, startHeight, blockStatus, filter, func(blockEvents flow.BlockEvents) error {
+			select {
+			case sent <- blockEvents:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return sent, errs
+}
+
+func (suite *Suite) next(sent <-chan flow.BlockEvents) flow.BlockEvents {
+	select {
+	case blockEvents := <-sent:
+		return blockEvents
+	case <-time.After(time.Second):
+		suite.FailNow("timed out waiting for block")
+		return flow.BlockEvents{}
+	}
+}
+
+func (suite *Suite) noNext(sent <-chan flow.BlockEvents) {
+	select {
+	case blockEvents := <-sent:
+		suite.FailNow("unexpected block", "height %d", blockEvents.BlockHeight)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (suite *Suite) TestSubscribeEventsSealed() {
+	eventType := flow.EventType("A.0000000000000001.Contract.Event")
+	txID := unittest.IdentifierFixture()
+
+	first := unittest.EventFixture(eventType, 0, 1, txID)
+	second := unittest.EventFixture(eventType, 0, 0, txID)
+	other := unittest.EventFixture(flow.EventAccountCreated, 0, 2, txID)
+	suite.events[suite.chain[1].ID()] = []flow.Event{first, other, second}
+	suite.events[suite.chain[3].ID()] = []flow.Event{first}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sent, errs := suite.subscribe(ctx, flow.ZeroID, 1, BlockStatusSealed, EventFilter{EventTypes: []flow.EventType{eventType}})
+
+	// the matching events of the sealed blocks are backfilled, in the order they were emitted
+	blockEvents := suite.next(sent)
+	suite.Require().Equal(suite.chain[1].ID(), blockEvents.BlockID)
+	suite.Require().Equal(uint64(1), blockEvents.BlockHeight)
+	suite.Require().Equal([]flow.Event{second, first}, blockEvents.Events)
+
+	// blocks without matching events are sent as heartbeats
+	blockEvents = suite.next(sent)
+	suite.Require().Equal(uint64(2), blockEvents.BlockHeight)
+	suite.Require().Empty(blockEvents.Events)
+
+	// the finalized block is not sent until it is sealed
+	suite.noNext(sent)
+
+	suite.mu.Lock()
+	suite.sealedHeight = 3
+	suite.mu.Unlock()
+	suite.backend.Notify()
+
+	blockEvents = suite.next(sent)
+	suite.Require().Equal(uint64(3), blockEvents.BlockHeight)
+	suite.Require().Equal([]flow.Event{first}, blockEvents.Events)
+
+	cancel()
+	err := <-errs
+	suite.Require().Equal(codes.Canceled, status.Code(err))
+}
+
+func (suite *Suite) TestSubscribeEventsWithoutEventTypes() {
+	address := flow.HexToAddress("0000000000000001")
+	eventType := flow.EventType("A.0000000000000001.Contract.Event")
+	otherEventType := flow.EventType("A.0000000000000002.Contract.Event")
+
+	txID := unittest.IdentifierFixture()
+	otherTxID := unittest.IdentifierFixture()
+
+	event := unittest.EventFixture(eventType, 0, 0, txID)
+	otherEvent := unittest.EventFixture(otherEventType, 1, 0, otherTxID)
+	suite.events[suite.chain[1].ID()] = []flow.Event{event, otherEvent}
+
+	suite.Run("address", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sent, errs := suite.subscribe(ctx, flow.ZeroID, 1, BlockStatusSealed, EventFilter{Addresses: []flow.Address{address}})
+		suite.Require().Equal([]flow.Event{event}, suite.next(sent).Events)
+
+		cancel()
+		<-errs
+	})
+
+	suite.Run("transaction", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sent, errs := suite.subscribe(ctx, flow.ZeroID, 1, BlockStatusSealed, EventFilter{TransactionIDs: []flow.Identifier{otherTxID}})
+		suite.Require().Equal([]flow.Event{otherEvent}, suite.next(sent).Events)
+
+		cancel()
+		<-errs
+	})
+
+	// the events of the block are fetched once for both filters, and no transaction results are fetched
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+	suite.Require().Equal(1, suite.allEventsCalls[suite.chain[1].ID()])
+	suite.Require().Empty(suite.resultCalls)
+}
+
+func (suite *Suite) TestSubscribeEventsSharedFetches() {
+	eventType := flow.EventType("A.0000000000000001.Contract.Event")
+	event := unittest.EventFixture(eventType, 0, 0, unittest.IdentifierFixture())
+	suite.events[suite.chain[1].ID()] = []flow.Event{event}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filter := EventFilter{EventTypes: []flow.EventType{eventType}}
+	first, firstErrs := suite.subscribe(ctx, flow.ZeroID, 1, BlockStatusSealed, filter)
+	second, secondErrs := suite.subscribe(ctx, flow.ZeroID, 1, BlockStatusSealed, filter)
+
+	for _, sent := range []<-chan flow.BlockEvents{first, second} {
+		suite.Require().Equal([]flow.Event{event}, suite.next(sent).Events)
+		suite.Require().Empty(suite.next(sent).Events)
+	}
+
+	// the events of each of the two sealed blocks are fetched once for both subscriptions
+	suite.mu.Lock()
+	suite.Require().Equal(2, suite.eventsCalls)
+	suite.mu.Unlock()
+
+	cancel()
+	<-firstErrs
+	<-secondErrs
+}
+
+func (suite *Suite) TestSubscribeEventsFinalized() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the stream starts at the latest finalized block
+	sent, errs := suite.subscribe(ctx, flow.ZeroID, 0, BlockStatusFinalized, EventFilter{EventTypes: []flow.EventType{flow.EventAccountCreated}})
+
+	// the finalized block is not sent until it is executed
+	suite.noNext(sent)
+
+	suite.mu.Lock()
+	suite.executed[suite.chain[3].ID()] = true
+	suite.mu.Unlock()
+	suite.backend.Notify()
+
+	blockEvents := suite.next(sent)
+	suite.Require().Equal(uint64(3), blockEvents.BlockHeight)
+
+	cancel()
+	<-errs
+}
+
+func (suite *Suite) TestSubscribeEventsStartBlockID() {
+	ctx := context.Background()
+	filter := EventFilter{EventTypes: []flow.EventType{flow.EventAccountCreated}}
+
+	suite.Run("finalized block", func() {
+		startBlock := suite.chain[2].Header
+		suite.headers.On("ByBlockID", startBlock.ID()).Return(startBlock, nil)
+
+		ctx, cancel := context.WithCancel(ctx)
+		sent, errs := suite.subscribe(ctx, startBlock.ID(), 0, BlockStatusSealed, filter)
+
+		blockEvents := suite.next(sent)
+		suite.Require().Equal(startBlock.ID(), blockEvents.BlockID)
+
+		cancel()
+		<-errs
+	})
+
+	suite.Run("block not finalized", func() {
+		startBlock := unittest.BlockHeaderFixture()
+		startBlock.Height = 3
+		suite.headers.On("ByBlockID", startBlock.ID()).Return(&startBlock, nil)
+
+		_, errs := suite.subscribe(ctx, startBlock.ID(), 0, BlockStatusSealed, filter)
+
+		err := <-errs
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("unknown block", func() {
+		startBlockID := unittest.IdentifierFixture()
+		suite.headers.On("ByBlockID", startBlockID).Return(nil, storage.ErrNotFound)
+
+		_, errs := suite.subscribe(ctx, startBlockID, 0, BlockStatusSealed, filter)
+
+		err := <-errs
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+func (suite *Suite) TestSubscribeBlockHeaders() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sent := make(chan *flow.Header)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- suite.backend.SubscribeBlockHeaders(ctx, flow.ZeroID, 1, BlockStatusFinalized, func(header *flow.Header) error {
+			select {
+			case sent <- header:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	next := func() *flow.Header {
+		select {
+		case header := <-sent:
+			return header
+		case <-time.After(time.Second):
+			suite.FailNow("timed out waiting for block header")
+			return nil
+		}
+	}
+
+	// the next block is not read before the client received the previous one
+	suite.Require().Equal(suite.chain[1].Header, next())
+	time.Sleep(50 * time.Millisecond)
+	suite.headers.AssertNotCalled(suite.T(), "ByHeight", uint64(3))
+
+	// the finalized blocks are backfilled, without waiting for their execution
+	suite.Require().Equal(suite.chain[2].Header, next())
+	suite.Require().Equal(suite.chain[3].Header, next())
+
+	suite.mu.Lock()
+	suite.finalizedHeight = 4
+	suite.mu.Unlock()
+	suite.backend.Notify()
+
+	suite.Require().Equal(suite.chain[4].Header, next())
+
+	cancel()
+	<-errs
+}
+
+func (suite *Suite) TestSubscribeBlocks() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sent := make(chan *flow.Block, 1)
+	err := suite.backend.SubscribeBlocks(ctx, flow.ZeroID, 0, BlockStatusSealed, func(block *flow.Block) error {
+		sent <- block
+		// stop the subscription once the first block was sent
+		cancel()
+		return nil
+	})
+	suite.Require().Equal(codes.Canceled, status.Code(err))
+
+	// the stream starts at the latest sealed block
+	suite.Require().Equal(suite.chain[2], <-sent)
+}
+
+// sendAndSubscribe sends a transaction and subscribes to its statuses, the updates are returned on the channel,
+// and the error the subscription returns once it is over on the error channel
+func (suite *Suite) sendAndSubscribe(ctx context.Context, tx *flow.TransactionBody) (<-chan TransactionStatusUpdate, <-chan error) {
+	sent := make(chan TransactionStatusUpdate)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- suite.backend.SendAndSubscribeTransactionStatuses(ctx, tx, func(update TransactionStatusUpdate) error {
+			select {
+			case sent <- update:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return sent, errs
+}
+
+func (suite *Suite) nextUpdate(sent <-chan TransactionStatusUpdate) TransactionStatusUpdate {
+	select {
+	case update := <-sent:
+		return update
+	case <-time.After(time.Second):
+		suite.FailNow("timed out waiting for transaction status")
+		return TransactionStatusUpdate{}
+	}
+}
+
+func (suite *Suite) noNextUpdate(sent <-chan TransactionStatusUpdate) {
+	select {
+	case update := <-sent:
+		suite.FailNow("unexpected transaction status", "status %v", update.Status)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (suite *Suite) TestSendAndSubscribeTransactionStatuses() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx := unittest.TransactionBodyFixture()
+	txID := tx.ID()
+
+	sent, errs := suite.sendAndSubscribe(ctx, &tx)
+
+	update := suite.nextUpdate(sent)
+	suite.Require().Equal(txID, update.TransactionID)
+	suite.Require().Equal(flow.TransactionStatusPending, update.Status)
+
+	suite.mu.Lock()
+	suite.Require().Equal([]*flow.TransactionBody{&tx}, suite.sent)
+	suite.mu.Unlock()
+
+	// transitions of other transactions are not sent
+	block := suite.chain[3]
+	suite.backend.NotifyTransactions(TransactionTransition{
+		TransactionIDs: []flow.Identifier{unittest.IdentifierFixture()},
+		Status:         flow.TransactionStatusFinalized,
+		BlockID:        block.ID(),
+		BlockHeight:    block.Header.Height,
+	})
+	suite.noNextUpdate(sent)
+
+	// the transaction is included in a finalized block
+	suite.backend.NotifyTransactions(TransactionTransition{
+		TransactionIDs: []flow.Identifier{txID},
+		Status:         flow.TransactionStatusFinalized,
+		BlockID:        block.ID(),
+		BlockHeight:    block.Header.Height,
+	})
+
+	update = suite.nextUpdate(sent)
+	suite.Require().Equal(flow.TransactionStatusFinalized, update.Status)
+	suite.Require().Equal(block.ID(), update.BlockID)
+	suite.Require().Equal(block.Header.Height, update.BlockHeight)
+	suite.Require().Nil(update.Result)
+
+	// a receipt is received before the execution nodes serve the result, the transaction is not executed yet
+	suite.backend.NotifyTransactions(TransactionTransition{
+		TransactionIDs: []flow.Identifier{txID},
+		Status:         flow.TransactionStatusExecuted,
+		BlockID:        block.ID(),
+		BlockHeight:    block.Header.Height,
+	})
+	suite.noNextUpdate(sent)
+
+	// the result is fetched again once the execution nodes serve it, on the next notification
+	result := &access.TransactionResult{
+		Status: flow.TransactionStatusExecuted,
+		Events: []flow.Event{unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID)},
+	}
+	suite.mu.Lock()
+	suite.results[txID] = result
+	suite.mu.Unlock()
+	suite.backend.Notify()
+
+	update = suite.nextUpdate(sent)
+	suite.Require().Equal(flow.TransactionStatusExecuted, update.Status)
+	suite.Require().Equal(result, update.Result)
+
+	suite.backend.NotifyTransactions(TransactionTransition{
+		TransactionIDs: []flow.Identifier{txID},
+		Status:         flow.TransactionStatusSealed,
+		BlockID:        block.ID(),
+		BlockHeight:    block.Header.Height,
+	})
+
+	update = suite.nextUpdate(sent)
+	suite.Require().Equal(flow.TransactionStatusSealed, update.Status)
+	suite.Require().Equal(result, update.Result)
+
+	// the stream ends once the transaction is sealed
+	suite.Require().NoError(<-errs)
+
+	// the result is fetched once the execution nodes serve it, and not again once sealed
+	suite.mu.Lock()
+	suite.Require().Equal(2, suite.resultCalls[txID])
+	suite.mu.Unlock()
+}
+
+func (suite *Suite) TestSendAndSubscribeTransactionStatusesSkippedTransitions() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx := unittest.TransactionBodyFixture()
+	txID := tx.ID()
+	result := &access.TransactionResult{Status: flow.TransactionStatusSealed}
+	suite.results[txID] = result
+
+	sent, errs := suite.sendAndSubscribe(ctx, &tx)
+	suite.Require().Equal(flow.TransactionStatusPending, suite.nextUpdate(sent).Status)
+
+	// the block including the transaction is sealed by the time its collection is received,
+	// every transition is sent in order
+	block := suite.chain[2]
+	suite.backend.NotifyTransactions(TransactionTransition{
+		TransactionIDs: []flow.Identifier{txID},
+		Status:         flow.TransactionStatusSealed,
+		BlockID:        block.ID(),
+		BlockHeight:    block.Header.Height,
+	})
+
+	update := suite.nextUpdate(sent)
+	suite.Require().Equal(flow.TransactionStatusFinalized, update.Status)
+	suite.Require().Equal(block.ID(), update.BlockID)
+	suite.Require().Nil(update.Result)
+
+	update = suite.nextUpdate(sent)
+	suite.Require().Equal(flow.TransactionStatusExecuted, update.Status)
+	suite.Require().Equal(result, update.Result)
+
+	suite.Require().Equal(flow.TransactionStatusSealed, suite.nextUpdate(sent).Status)
+	suite.Require().NoError(<-errs)
+}
+
+func (suite *Suite) TestSendAndSubscribeTransactionStatusesExpired() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx := unittest.TransactionBodyFixture()
+
+	sent, errs := suite.sendAndSubscribe(ctx, &tx)
+	suite.Require().Equal(flow.TransactionStatusPending, suite.nextUpdate(sent).Status)
+
+	suite.backend.NotifyTransactions(TransactionTransition{
+		TransactionIDs: []flow.Identifier{tx.ID()},
+		Status:         flow.TransactionStatusExpired,
+	})
+
+	suite.Require().Equal(flow.TransactionStatusExpired, suite.nextUpdate(sent).Status)
+	suite.Require().NoError(<-errs)
+}
+
+func TestTransactionTransitions(t *testing.T) {
+	require.Equal(t,
+		[]flow.TransactionStatus{flow.TransactionStatusFinalized, flow.TransactionStatusExecuted, flow.TransactionStatusSealed},
+		transactionTransitions(flow.TransactionStatusPending, flow.TransactionStatusSealed),
+	)
+	require.Equal(t,
+		[]flow.TransactionStatus{flow.TransactionStatusExpired},
+		transactionTransitions(flow.TransactionStatusPending, flow.TransactionStatusExpired),
+	)
+	require.Empty(t, transactionTransitions(flow.TransactionStatusExecuted, flow.TransactionStatusExecuted))
+}
+
+func TestSubscribeEventsWithoutFilter(t *testing.T) {
+	backend := New(zerolog.Nop(), nil, nil, nil, nil, nil)
+
+	err := backend.SubscribeEvents(context.Background(), flow.ZeroID, 0, BlockStatusSealed, EventFilter{}, func(flow.BlockEvents) error {
+		return nil
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
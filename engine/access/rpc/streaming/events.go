@@ -0,0 +1,88 @@
+package streaming
+
+import (
+	"context"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// eventsCacheSize is the number of event lookups of blocks cached for the subscriptions
+const eventsCacheSize = 1000
+
+// eventsCacheKey identifies an event lookup of a block by event type, all events of the block are looked up
+// with the empty event type.
+type eventsCacheKey struct {
+	blockID   flow.Identifier
+	eventType flow.EventType
+}
+
+type eventsCacheEntry struct {
+	done   chan struct{}
+	events []flow.Event
+	err    error
+}
+
+// eventsCache shares the events fetched from the execution nodes between the event subscriptions, so that the
+// events of a block are fetched once per event type, however many subscriptions stream the block.
+//
+// Subscriptions only stream blocks which are sealed, or finalized and executed, so their events do not change.
+type eventsCache struct {
+	mu      sync.Mutex
+	entries *lru.Cache
+}
+
+func newEventsCache(size int) *eventsCache {
+	entries, _ := lru.New(size)
+	return &eventsCache{
+		entries: entries,
+	}
+}
+
+// get returns the events of the lookup, fetching them if they are not cached. Concurrent lookups wait
+// for the events fetched by the first one. Failed fetches are not cached, the lookups waiting for them fetch again.
+func (c *eventsCache) get(ctx context.Context, key eventsCacheKey, fetch func() ([]flow.Event, error)) ([]flow.Event, error) {
+	for {
+		c.mu.Lock()
+		value, ok := c.entries.Get(key)
+		if !ok {
+			entry := &eventsCacheEntry{done: make(chan struct{})}
+			c.entries.Add(key, entry)
+			c.mu.Unlock()
+
+			return c.fetch(key, entry, fetch)
+		}
+		c.mu.Unlock()
+
+		entry := value.(*eventsCacheEntry)
+		select {
+		case <-ctx.Done():
+			return nil, contextError(ctx.Err())
+		case <-entry.done:
+		}
+
+		if entry.err == nil {
+			return entry.events, nil
+		}
+	}
+}
+
+// fetch fetches the events of the entry, and removes the entry if fetching failed.
+func (c *eventsCache) fetch(key eventsCacheKey, entry *eventsCacheEntry, fetch func() ([]flow.Event, error)) ([]flow.Event, error) {
+	entry.events, entry.err = fetch()
+
+	if entry.err != nil {
+		c.mu.Lock()
+		value, ok := c.entries.Peek(key)
+		if ok && value == entry {
+			c.entries.Remove(key)
+		}
+		c.mu.Unlock()
+	}
+
+	close(entry.done)
+
+	return entry.events, entry.err
+}
@@ -0,0 +1,76 @@
+package streaming
+
+import (
+	"strings"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EventFilter selects the events streamed by an event subscription.
+//
+// An event matches the filter if, for each of the event types, addresses and transactions which are given,
+// its type is one of the event types, it is emitted by a contract deployed at one of the addresses,
+// and it is emitted by one of the transactions. An empty filter matches every event.
+type EventFilter struct {
+	EventTypes     []flow.EventType
+	Addresses      []flow.Address
+	TransactionIDs []flow.Identifier
+}
+
+// Match returns true if the event matches the filter.
+func (f EventFilter) Match(event flow.Event) bool {
+	if len(f.EventTypes) > 0 && !f.matchType(event.Type) {
+		return false
+	}
+
+	if len(f.Addresses) > 0 {
+		address, ok := contractAddress(event.Type)
+		if !ok || !f.matchAddress(address) {
+			return false
+		}
+	}
+
+	if len(f.TransactionIDs) > 0 && !f.matchTransaction(event.TransactionID) {
+		return false
+	}
+
+	return true
+}
+
+func (f EventFilter) matchType(eventType flow.EventType) bool {
+	for _, t := range f.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (f EventFilter) matchAddress(address flow.Address) bool {
+	for _, a := range f.Addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (f EventFilter) matchTransaction(txID flow.Identifier) bool {
+	for _, id := range f.TransactionIDs {
+		if id == txID {
+			return true
+		}
+	}
+	return false
+}
+
+// contractAddress returns the address of the contract which declares the event type,
+// e.g. 0x1654653399040a61 for A.1654653399040a61.FlowToken.TokensDeposited.
+// Events emitted by the protocol itself, e.g. flow.AccountCreated, are not declared by a contract.
+func contractAddress(eventType flow.EventType) (flow.Address, bool) {
+	parts := strings.Split(string(eventType), ".")
+	if len(parts) != 4 || parts[0] != "A" {
+		return flow.EmptyAddress, false
+	}
+	return flow.HexToAddress(parts[1]), true
+}
@@ -0,0 +1,50 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestEventFilter(t *testing.T) {
+	address := flow.HexToAddress("01")
+	otherAddress := flow.HexToAddress("02")
+
+	eventType := flow.EventType("A." + address.Hex() + ".Contract.Event")
+	otherEventType := flow.EventType("A." + otherAddress.Hex() + ".Contract.Event")
+
+	txID := unittest.IdentifierFixture()
+	otherTxID := unittest.IdentifierFixture()
+
+	event := unittest.EventFixture(eventType, 0, 0, txID)
+
+	t.Run("event types", func(t *testing.T) {
+		assert.True(t, EventFilter{EventTypes: []flow.EventType{otherEventType, eventType}}.Match(event))
+		assert.False(t, EventFilter{EventTypes: []flow.EventType{otherEventType}}.Match(event))
+		// an empty filter matches every event
+		assert.True(t, EventFilter{}.Match(event))
+	})
+
+	t.Run("addresses", func(t *testing.T) {
+		types := []flow.EventType{eventType, flow.EventAccountCreated}
+
+		assert.True(t, EventFilter{EventTypes: types, Addresses: []flow.Address{address}}.Match(event))
+		assert.True(t, EventFilter{Addresses: []flow.Address{address}}.Match(event))
+		assert.False(t, EventFilter{EventTypes: types, Addresses: []flow.Address{otherAddress}}.Match(event))
+
+		// events emitted by the protocol are not emitted by a contract
+		accountCreated := unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID)
+		assert.False(t, EventFilter{EventTypes: types, Addresses: []flow.Address{address}}.Match(accountCreated))
+	})
+
+	t.Run("transaction IDs", func(t *testing.T) {
+		types := []flow.EventType{eventType}
+
+		assert.True(t, EventFilter{EventTypes: types, TransactionIDs: []flow.Identifier{otherTxID, txID}}.Match(event))
+		assert.True(t, EventFilter{TransactionIDs: []flow.Identifier{txID}}.Match(event))
+		assert.False(t, EventFilter{EventTypes: types, TransactionIDs: []flow.Identifier{otherTxID}}.Match(event))
+	})
+}
@@ -0,0 +1,213 @@
+package streaming
+
+import (
+	"github.com/golang/protobuf/ptypes"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	streamingproto "github.com/onflow/flow-go/engine/access/rpc/streaming/protobuf"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Handler implements the gRPC streaming API on top of the streaming backend.
+type Handler struct {
+	backend *Backend
+	chain   flow.Chain
+}
+
+// NewHandler returns a new gRPC streaming API handler.
+func NewHandler(backend *Backend, chain flow.Chain) *Handler {
+	return &Handler{
+		backend: backend,
+		chain:   chain,
+	}
+}
+
+// SubscribeEvents streams the events matching the filter of every block, starting at the given block.
+func (h *Handler) SubscribeEvents(req *streamingproto.SubscribeEventsRequest, stream streamingproto.StreamingAPI_SubscribeEventsServer) error {
+	blockStatus, err := messageToBlockStatus(req.GetBlockStatus())
+	if err != nil {
+		return err
+	}
+
+	filter, err := messageToEventFilter(req.GetFilter(), h.chain)
+	if err != nil {
+		return err
+	}
+
+	startBlockID, err := messageToStartBlockID(req.GetStartBlockId())
+	if err != nil {
+		return err
+	}
+
+	return h.backend.SubscribeEvents(
+		stream.Context(),
+		startBlockID,
+		req.GetStartHeight(),
+		blockStatus,
+		filter,
+		func(blockEvents flow.BlockEvents) error {
+			timestamp, err := ptypes.TimestampProto(blockEvents.BlockTimestamp)
+			if err != nil {
+				return status.Errorf(codes.Internal, "invalid block timestamp: %v", err)
+			}
+
+			return stream.Send(&streamingproto.SubscribeEventsResponse{
+				BlockId:        blockEvents.BlockID[:],
+				BlockHeight:    blockEvents.BlockHeight,
+				BlockTimestamp: timestamp,
+				Events:         convert.EventsToMessages(blockEvents.Events),
+			})
+		},
+	)
+}
+
+// SubscribeBlockHeaders streams the header of every block, starting at the given block.
+func (h *Handler) SubscribeBlockHeaders(req *streamingproto.SubscribeBlocksRequest, stream streamingproto.StreamingAPI_SubscribeBlockHeadersServer) error {
+	blockStatus, err := messageToBlockStatus(req.GetBlockStatus())
+	if err != nil {
+		return err
+	}
+
+	startBlockID, err := messageToStartBlockID(req.GetStartBlockId())
+	if err != nil {
+		return err
+	}
+
+	return h.backend.SubscribeBlockHeaders(
+		stream.Context(),
+		startBlockID,
+		req.GetStartHeight(),
+		blockStatus,
+		func(header *flow.Header) error {
+			msg, err := convert.BlockHeaderToMessage(header)
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to convert block header: %v", err)
+			}
+
+			return stream.Send(&streamingproto.SubscribeBlockHeadersResponse{
+				BlockHeader: msg,
+			})
+		},
+	)
+}
+
+// SubscribeBlocks streams every block, starting at the given block.
+func (h *Handler) SubscribeBlocks(req *streamingproto.SubscribeBlocksRequest, stream streamingproto.StreamingAPI_SubscribeBlocksServer) error {
+	blockStatus, err := messageToBlockStatus(req.GetBlockStatus())
+	if err != nil {
+		return err
+	}
+
+	startBlockID, err := messageToStartBlockID(req.GetStartBlockId())
+	if err != nil {
+		return err
+	}
+
+	return h.backend.SubscribeBlocks(
+		stream.Context(),
+		startBlockID,
+		req.GetStartHeight(),
+		blockStatus,
+		func(block *flow.Block) error {
+			msg, err := convert.BlockToMessage(block)
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to convert block: %v", err)
+			}
+
+			return stream.Send(&streamingproto.SubscribeBlocksResponse{
+				Block: msg,
+			})
+		},
+	)
+}
+
+// SendAndSubscribeTransactionStatuses sends the transaction, then streams every transition of its status once.
+func (h *Handler) SendAndSubscribeTransactionStatuses(
+	req *streamingproto.SendAndSubscribeTransactionStatusesRequest,
+	stream streamingproto.StreamingAPI_SendAndSubscribeTransactionStatusesServer,
+) error {
+	tx, err := convert.MessageToTransaction(req.GetTransaction(), h.chain)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return h.backend.SendAndSubscribeTransactionStatuses(
+		stream.Context(),
+		&tx,
+		func(update TransactionStatusUpdate) error {
+			return stream.Send(transactionStatusUpdateToMessage(update))
+		},
+	)
+}
+
+func transactionStatusUpdateToMessage(update TransactionStatusUpdate) *streamingproto.SendAndSubscribeTransactionStatusesResponse {
+	msg := &streamingproto.SendAndSubscribeTransactionStatusesResponse{
+		Id:     update.TransactionID[:],
+		Status: entities.TransactionStatus(update.Status),
+	}
+
+	if update.BlockID != flow.ZeroID {
+		msg.BlockId = update.BlockID[:]
+		msg.BlockHeight = update.BlockHeight
+	}
+
+	if update.Result != nil {
+		msg.StatusCode = uint32(update.Result.StatusCode)
+		msg.ErrorMessage = update.Result.ErrorMessage
+		msg.Events = convert.EventsToMessages(update.Result.Events)
+	}
+
+	return msg
+}
+
+// messageToStartBlockID returns the start block ID of a subscription, or the zero ID if none is given
+func messageToStartBlockID(m []byte) (flow.Identifier, error) {
+	if len(m) == 0 {
+		return flow.ZeroID, nil
+	}
+	return convert.BlockID(m)
+}
+
+func messageToBlockStatus(m streamingproto.BlockStatus) (BlockStatus, error) {
+	switch m {
+	case streamingproto.BlockStatus_BLOCK_STATUS_SEALED:
+		return BlockStatusSealed, nil
+	case streamingproto.BlockStatus_BLOCK_STATUS_FINALIZED:
+		return BlockStatusFinalized, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "invalid block status %v", m)
+	}
+}
+
+func messageToEventFilter(m *streamingproto.EventFilter, chain flow.Chain) (EventFilter, error) {
+	var filter EventFilter
+
+	for _, eventType := range m.GetEventTypes() {
+		t, err := convert.EventType(eventType)
+		if err != nil {
+			return EventFilter{}, err
+		}
+		filter.EventTypes = append(filter.EventTypes, flow.EventType(t))
+	}
+
+	for _, address := range m.GetAddresses() {
+		a, err := convert.Address(address, chain)
+		if err != nil {
+			return EventFilter{}, err
+		}
+		filter.Addresses = append(filter.Addresses, a)
+	}
+
+	for _, txID := range m.GetTransactionIds() {
+		id, err := convert.TransactionID(txID)
+		if err != nil {
+			return EventFilter{}, err
+		}
+		filter.TransactionIDs = append(filter.TransactionIDs, id)
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,37 @@
+package streaming
+
+import (
+	"sync"
+)
+
+// Notifier wakes up the subscriptions waiting for the access node to learn about new data,
+// e.g. a newly finalized block or a new execution receipt.
+type Notifier struct {
+	mu      sync.Mutex
+	changed chan struct{}
+}
+
+// NewNotifier returns a new notifier.
+func NewNotifier() *Notifier {
+	return &Notifier{
+		changed: make(chan struct{}),
+	}
+}
+
+// Notify wakes up all the subscriptions waiting on the current changed channel.
+func (n *Notifier) Notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	close(n.changed)
+	n.changed = make(chan struct{})
+}
+
+// Changed returns a channel which is closed on the next notification. To not miss a notification,
+// subscriptions must get the channel before checking whether the data they wait for is available.
+func (n *Notifier) Changed() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.changed
+}
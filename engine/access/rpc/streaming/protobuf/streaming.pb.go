@@ -0,0 +1,898 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: streaming.proto
+
+package streaming
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	entities "github.com/onflow/flow/protobuf/go/flow/entities"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// BlockStatus is the status a block must reach before it is streamed
+type BlockStatus int32
+
+const (
+	BlockStatus_BLOCK_STATUS_SEALED    BlockStatus = 0
+	BlockStatus_BLOCK_STATUS_FINALIZED BlockStatus = 1
+)
+
+var BlockStatus_name = map[int32]string{
+	0: "BLOCK_STATUS_SEALED",
+	1: "BLOCK_STATUS_FINALIZED",
+}
+
+var BlockStatus_value = map[string]int32{
+	"BLOCK_STATUS_SEALED":    0,
+	"BLOCK_STATUS_FINALIZED": 1,
+}
+
+func (x BlockStatus) String() string {
+	return proto.EnumName(BlockStatus_name, int32(x))
+}
+
+func (BlockStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{0}
+}
+
+// EventFilter selects events by type, by the address of the emitting contract
+// and by the emitting transaction, at least one of them must be given
+type EventFilter struct {
+	EventTypes           []string `protobuf:"bytes,1,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Addresses            [][]byte `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	TransactionIds       [][]byte `protobuf:"bytes,3,rep,name=transaction_ids,json=transactionIds,proto3" json:"transaction_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventFilter) Reset()         { *m = EventFilter{} }
+func (m *EventFilter) String() string { return proto.CompactTextString(m) }
+func (*EventFilter) ProtoMessage()    {}
+func (*EventFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{0}
+}
+
+func (m *EventFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventFilter.Unmarshal(m, b)
+}
+func (m *EventFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventFilter.Marshal(b, m, deterministic)
+}
+func (m *EventFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventFilter.Merge(m, src)
+}
+func (m *EventFilter) XXX_Size() int {
+	return xxx_messageInfo_EventFilter.Size(m)
+}
+func (m *EventFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventFilter proto.InternalMessageInfo
+
+func (m *EventFilter) GetEventTypes() []string {
+	if m != nil {
+		return m.EventTypes
+	}
+	return nil
+}
+
+func (m *EventFilter) GetAddresses() [][]byte {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *EventFilter) GetTransactionIds() [][]byte {
+	if m != nil {
+		return m.TransactionIds
+	}
+	return nil
+}
+
+type SubscribeEventsRequest struct {
+	// start_block_id is the ID of the first block to stream, it takes precedence over the start height
+	StartBlockId []byte `protobuf:"bytes,1,opt,name=start_block_id,json=startBlockId,proto3" json:"start_block_id,omitempty"`
+	// start_height is the height of the first block to stream, the stream starts at the latest
+	// block with the requested status if neither a start block ID nor a start height is given
+	StartHeight          uint64       `protobuf:"varint,2,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+	BlockStatus          BlockStatus  `protobuf:"varint,3,opt,name=block_status,json=blockStatus,proto3,enum=streaming.BlockStatus" json:"block_status,omitempty"`
+	Filter               *EventFilter `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SubscribeEventsRequest) Reset()         { *m = SubscribeEventsRequest{} }
+func (m *SubscribeEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeEventsRequest) ProtoMessage()    {}
+func (*SubscribeEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{1}
+}
+
+func (m *SubscribeEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeEventsRequest.Unmarshal(m, b)
+}
+func (m *SubscribeEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeEventsRequest.Merge(m, src)
+}
+func (m *SubscribeEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeEventsRequest.Size(m)
+}
+func (m *SubscribeEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeEventsRequest proto.InternalMessageInfo
+
+func (m *SubscribeEventsRequest) GetStartBlockId() []byte {
+	if m != nil {
+		return m.StartBlockId
+	}
+	return nil
+}
+
+func (m *SubscribeEventsRequest) GetStartHeight() uint64 {
+	if m != nil {
+		return m.StartHeight
+	}
+	return 0
+}
+
+func (m *SubscribeEventsRequest) GetBlockStatus() BlockStatus {
+	if m != nil {
+		return m.BlockStatus
+	}
+	return BlockStatus_BLOCK_STATUS_SEALED
+}
+
+func (m *SubscribeEventsRequest) GetFilter() *EventFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+type SubscribeEventsResponse struct {
+	BlockId        []byte               `protobuf:"bytes,1,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+	BlockHeight    uint64               `protobuf:"varint,2,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	BlockTimestamp *timestamp.Timestamp `protobuf:"bytes,3,opt,name=block_timestamp,json=blockTimestamp,proto3" json:"block_timestamp,omitempty"`
+	// events are the events of the block matching the filter, a response without events is a heartbeat
+	Events               []*entities.Event `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SubscribeEventsResponse) Reset()         { *m = SubscribeEventsResponse{} }
+func (m *SubscribeEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*SubscribeEventsResponse) ProtoMessage()    {}
+func (*SubscribeEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{2}
+}
+
+func (m *SubscribeEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeEventsResponse.Unmarshal(m, b)
+}
+func (m *SubscribeEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *SubscribeEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeEventsResponse.Merge(m, src)
+}
+func (m *SubscribeEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_SubscribeEventsResponse.Size(m)
+}
+func (m *SubscribeEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeEventsResponse proto.InternalMessageInfo
+
+func (m *SubscribeEventsResponse) GetBlockId() []byte {
+	if m != nil {
+		return m.BlockId
+	}
+	return nil
+}
+
+func (m *SubscribeEventsResponse) GetBlockHeight() uint64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *SubscribeEventsResponse) GetBlockTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.BlockTimestamp
+	}
+	return nil
+}
+
+func (m *SubscribeEventsResponse) GetEvents() []*entities.Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type SubscribeBlocksRequest struct {
+	// start_block_id is the ID of the first block to stream, it takes precedence over the start height
+	StartBlockId []byte `protobuf:"bytes,1,opt,name=start_block_id,json=startBlockId,proto3" json:"start_block_id,omitempty"`
+	// start_height is the height of the first block to stream, the stream starts at the latest
+	// block with the requested status if neither a start block ID nor a start height is given
+	StartHeight          uint64      `protobuf:"varint,2,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+	BlockStatus          BlockStatus `protobuf:"varint,3,opt,name=block_status,json=blockStatus,proto3,enum=streaming.BlockStatus" json:"block_status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SubscribeBlocksRequest) Reset()         { *m = SubscribeBlocksRequest{} }
+func (m *SubscribeBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBlocksRequest) ProtoMessage()    {}
+func (*SubscribeBlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{3}
+}
+
+func (m *SubscribeBlocksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeBlocksRequest.Unmarshal(m, b)
+}
+func (m *SubscribeBlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeBlocksRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeBlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeBlocksRequest.Merge(m, src)
+}
+func (m *SubscribeBlocksRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeBlocksRequest.Size(m)
+}
+func (m *SubscribeBlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeBlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeBlocksRequest proto.InternalMessageInfo
+
+func (m *SubscribeBlocksRequest) GetStartBlockId() []byte {
+	if m != nil {
+		return m.StartBlockId
+	}
+	return nil
+}
+
+func (m *SubscribeBlocksRequest) GetStartHeight() uint64 {
+	if m != nil {
+		return m.StartHeight
+	}
+	return 0
+}
+
+func (m *SubscribeBlocksRequest) GetBlockStatus() BlockStatus {
+	if m != nil {
+		return m.BlockStatus
+	}
+	return BlockStatus_BLOCK_STATUS_SEALED
+}
+
+type SubscribeBlockHeadersResponse struct {
+	BlockHeader          *entities.BlockHeader `protobuf:"bytes,1,opt,name=block_header,json=blockHeader,proto3" json:"block_header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *SubscribeBlockHeadersResponse) Reset()         { *m = SubscribeBlockHeadersResponse{} }
+func (m *SubscribeBlockHeadersResponse) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBlockHeadersResponse) ProtoMessage()    {}
+func (*SubscribeBlockHeadersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{4}
+}
+
+func (m *SubscribeBlockHeadersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeBlockHeadersResponse.Unmarshal(m, b)
+}
+func (m *SubscribeBlockHeadersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeBlockHeadersResponse.Marshal(b, m, deterministic)
+}
+func (m *SubscribeBlockHeadersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeBlockHeadersResponse.Merge(m, src)
+}
+func (m *SubscribeBlockHeadersResponse) XXX_Size() int {
+	return xxx_messageInfo_SubscribeBlockHeadersResponse.Size(m)
+}
+func (m *SubscribeBlockHeadersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeBlockHeadersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeBlockHeadersResponse proto.InternalMessageInfo
+
+func (m *SubscribeBlockHeadersResponse) GetBlockHeader() *entities.BlockHeader {
+	if m != nil {
+		return m.BlockHeader
+	}
+	return nil
+}
+
+type SubscribeBlocksResponse struct {
+	Block                *entities.Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *SubscribeBlocksResponse) Reset()         { *m = SubscribeBlocksResponse{} }
+func (m *SubscribeBlocksResponse) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBlocksResponse) ProtoMessage()    {}
+func (*SubscribeBlocksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{5}
+}
+
+func (m *SubscribeBlocksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeBlocksResponse.Unmarshal(m, b)
+}
+func (m *SubscribeBlocksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeBlocksResponse.Marshal(b, m, deterministic)
+}
+func (m *SubscribeBlocksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeBlocksResponse.Merge(m, src)
+}
+func (m *SubscribeBlocksResponse) XXX_Size() int {
+	return xxx_messageInfo_SubscribeBlocksResponse.Size(m)
+}
+func (m *SubscribeBlocksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeBlocksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeBlocksResponse proto.InternalMessageInfo
+
+func (m *SubscribeBlocksResponse) GetBlock() *entities.Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type SendAndSubscribeTransactionStatusesRequest struct {
+	Transaction          *entities.Transaction `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *SendAndSubscribeTransactionStatusesRequest) Reset() {
+	*m = SendAndSubscribeTransactionStatusesRequest{}
+}
+func (m *SendAndSubscribeTransactionStatusesRequest) String() string {
+	return proto.CompactTextString(m)
+}
+func (*SendAndSubscribeTransactionStatusesRequest) ProtoMessage() {}
+func (*SendAndSubscribeTransactionStatusesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{6}
+}
+
+func (m *SendAndSubscribeTransactionStatusesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendAndSubscribeTransactionStatusesRequest.Unmarshal(m, b)
+}
+func (m *SendAndSubscribeTransactionStatusesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendAndSubscribeTransactionStatusesRequest.Marshal(b, m, deterministic)
+}
+func (m *SendAndSubscribeTransactionStatusesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendAndSubscribeTransactionStatusesRequest.Merge(m, src)
+}
+func (m *SendAndSubscribeTransactionStatusesRequest) XXX_Size() int {
+	return xxx_messageInfo_SendAndSubscribeTransactionStatusesRequest.Size(m)
+}
+func (m *SendAndSubscribeTransactionStatusesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendAndSubscribeTransactionStatusesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendAndSubscribeTransactionStatusesRequest proto.InternalMessageInfo
+
+func (m *SendAndSubscribeTransactionStatusesRequest) GetTransaction() *entities.Transaction {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+type SendAndSubscribeTransactionStatusesResponse struct {
+	Id     []byte                     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status entities.TransactionStatus `protobuf:"varint,2,opt,name=status,proto3,enum=flow.entities.TransactionStatus" json:"status,omitempty"`
+	// block_id and block_height identify the block including the transaction, once it is finalized
+	BlockId     []byte `protobuf:"bytes,3,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+	BlockHeight uint64 `protobuf:"varint,4,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	// status_code, error_message and events are the result of the transaction, once it is executed
+	StatusCode           uint32            `protobuf:"varint,5,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ErrorMessage         string            `protobuf:"bytes,6,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Events               []*entities.Event `protobuf:"bytes,7,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) Reset() {
+	*m = SendAndSubscribeTransactionStatusesResponse{}
+}
+func (m *SendAndSubscribeTransactionStatusesResponse) String() string {
+	return proto.CompactTextString(m)
+}
+func (*SendAndSubscribeTransactionStatusesResponse) ProtoMessage() {}
+func (*SendAndSubscribeTransactionStatusesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2e8ceba11142904, []int{7}
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendAndSubscribeTransactionStatusesResponse.Unmarshal(m, b)
+}
+func (m *SendAndSubscribeTransactionStatusesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendAndSubscribeTransactionStatusesResponse.Marshal(b, m, deterministic)
+}
+func (m *SendAndSubscribeTransactionStatusesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendAndSubscribeTransactionStatusesResponse.Merge(m, src)
+}
+func (m *SendAndSubscribeTransactionStatusesResponse) XXX_Size() int {
+	return xxx_messageInfo_SendAndSubscribeTransactionStatusesResponse.Size(m)
+}
+func (m *SendAndSubscribeTransactionStatusesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendAndSubscribeTransactionStatusesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendAndSubscribeTransactionStatusesResponse proto.InternalMessageInfo
+
+func (m *SendAndSubscribeTransactionStatusesResponse) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) GetStatus() entities.TransactionStatus {
+	if m != nil {
+		return m.Status
+	}
+	return entities.TransactionStatus_UNKNOWN
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) GetBlockId() []byte {
+	if m != nil {
+		return m.BlockId
+	}
+	return nil
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) GetBlockHeight() uint64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) GetStatusCode() uint32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) GetErrorMessage() string {
+	if m != nil {
+		return m.ErrorMessage
+	}
+	return ""
+}
+
+func (m *SendAndSubscribeTransactionStatusesResponse) GetEvents() []*entities.Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("streaming.BlockStatus", BlockStatus_name, BlockStatus_value)
+	proto.RegisterType((*EventFilter)(nil), "streaming.EventFilter")
+	proto.RegisterType((*SubscribeEventsRequest)(nil), "streaming.SubscribeEventsRequest")
+	proto.RegisterType((*SubscribeEventsResponse)(nil), "streaming.SubscribeEventsResponse")
+	proto.RegisterType((*SubscribeBlocksRequest)(nil), "streaming.SubscribeBlocksRequest")
+	proto.RegisterType((*SubscribeBlockHeadersResponse)(nil), "streaming.SubscribeBlockHeadersResponse")
+	proto.RegisterType((*SubscribeBlocksResponse)(nil), "streaming.SubscribeBlocksResponse")
+	proto.RegisterType((*SendAndSubscribeTransactionStatusesRequest)(nil), "streaming.SendAndSubscribeTransactionStatusesRequest")
+	proto.RegisterType((*SendAndSubscribeTransactionStatusesResponse)(nil), "streaming.SendAndSubscribeTransactionStatusesResponse")
+}
+
+func init() { proto.RegisterFile("streaming.proto", fileDescriptor_f2e8ceba11142904) }
+
+var fileDescriptor_f2e8ceba11142904 = []byte{
+	// 762 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x55, 0xdd, 0x6e, 0xd3, 0x4a,
+	0x10, 0x3e, 0x4e, 0xd2, 0xf4, 0x64, 0x9c, 0x26, 0xd5, 0x9e, 0x9e, 0xd6, 0x8d, 0xce, 0x51, 0x5c,
+	0x17, 0x09, 0xab, 0x80, 0x5d, 0x05, 0x81, 0x40, 0xc0, 0x45, 0xd2, 0xa6, 0x6a, 0xa0, 0xfc, 0xc8,
+	0x09, 0x37, 0xbd, 0xc0, 0xf2, 0xcf, 0xc6, 0x31, 0x24, 0x76, 0xf0, 0x6e, 0x40, 0xbc, 0x08, 0x57,
+	0xbc, 0x00, 0x4f, 0x82, 0x84, 0x78, 0x28, 0xe4, 0x5d, 0xc7, 0xb1, 0xdb, 0xb4, 0x84, 0x3b, 0x6e,
+	0x22, 0xed, 0x37, 0xdf, 0xce, 0xec, 0x37, 0xf3, 0x4d, 0x0c, 0x75, 0x42, 0x23, 0x6c, 0x4d, 0xfc,
+	0xc0, 0xd3, 0xa6, 0x51, 0x48, 0x43, 0x54, 0x49, 0x81, 0x46, 0xd3, 0x0b, 0x43, 0x6f, 0x8c, 0x75,
+	0x16, 0xb0, 0x67, 0x43, 0x9d, 0xfa, 0x13, 0x4c, 0xa8, 0x35, 0x99, 0x72, 0x6e, 0x63, 0x77, 0x38,
+	0x0e, 0x3f, 0xea, 0x38, 0xa0, 0x3e, 0xf5, 0x31, 0xd1, 0xf1, 0x07, 0x1c, 0xd0, 0x24, 0x24, 0xe7,
+	0x43, 0xf6, 0x38, 0x74, 0xde, 0x99, 0x23, 0x6c, 0xb9, 0x38, 0x5a, 0x7e, 0x99, 0x31, 0x92, 0x50,
+	0x33, 0x1f, 0xa2, 0x91, 0x15, 0x10, 0xcb, 0xa1, 0x7e, 0x18, 0x70, 0x82, 0x32, 0x03, 0xb1, 0x1b,
+	0x17, 0x3b, 0xf1, 0xc7, 0x14, 0x47, 0xa8, 0x09, 0x22, 0xab, 0x6d, 0xd2, 0x4f, 0x53, 0x4c, 0x24,
+	0x41, 0x2e, 0xaa, 0x15, 0x03, 0x18, 0x34, 0x88, 0x11, 0xf4, 0x1f, 0x54, 0x2c, 0xd7, 0x8d, 0x30,
+	0x21, 0x98, 0x48, 0x05, 0xb9, 0xa8, 0x56, 0x8d, 0x05, 0x80, 0x6e, 0x42, 0x3d, 0x53, 0xc2, 0xf4,
+	0x5d, 0x22, 0x15, 0x19, 0xa7, 0x96, 0x81, 0x7b, 0x2e, 0x51, 0x7e, 0x08, 0xb0, 0xdd, 0x9f, 0xd9,
+	0xc4, 0x89, 0x7c, 0x1b, 0xb3, 0x07, 0x10, 0x03, 0xbf, 0x9f, 0x61, 0x42, 0xd1, 0x0d, 0xa8, 0x11,
+	0x6a, 0x45, 0xd4, 0xe4, 0x4a, 0x7d, 0x57, 0x12, 0x64, 0x41, 0xad, 0x1a, 0x55, 0x86, 0x76, 0x62,
+	0xb0, 0xe7, 0xa2, 0x3d, 0xe0, 0x67, 0x73, 0x84, 0x7d, 0x6f, 0x44, 0xa5, 0x82, 0x2c, 0xa8, 0x25,
+	0x43, 0x64, 0xd8, 0x29, 0x83, 0xd0, 0x43, 0xa8, 0xf2, 0x14, 0x84, 0x5a, 0x74, 0x16, 0xbf, 0x44,
+	0x50, 0x6b, 0xad, 0x6d, 0x6d, 0x31, 0x27, 0x96, 0xac, 0xcf, 0xa2, 0x86, 0x68, 0x2f, 0x0e, 0x48,
+	0x83, 0xf2, 0x90, 0x35, 0x44, 0x2a, 0xc9, 0x82, 0x2a, 0xe6, 0x2e, 0x65, 0xda, 0x65, 0x24, 0x2c,
+	0xe5, 0xbb, 0x00, 0x3b, 0x97, 0xe4, 0x90, 0x69, 0x18, 0x10, 0x8c, 0x76, 0xe1, 0xef, 0x0b, 0x4a,
+	0xd6, 0xed, 0x85, 0x88, 0xf9, 0x38, 0xb3, 0x22, 0x18, 0x96, 0x88, 0x38, 0x82, 0x3a, 0xa7, 0xa4,
+	0x8e, 0x61, 0x3a, 0xc4, 0x56, 0x43, 0xe3, 0x9e, 0xd2, 0xe6, 0x9e, 0xd2, 0x06, 0x73, 0x86, 0x51,
+	0x63, 0x57, 0xd2, 0x33, 0xba, 0x0d, 0x65, 0x36, 0x42, 0x22, 0x95, 0xe4, 0xa2, 0x2a, 0xb6, 0xb6,
+	0xb4, 0xd8, 0x16, 0xda, 0xdc, 0x16, 0x5c, 0x92, 0x91, 0x70, 0x94, 0x2f, 0xd9, 0xd9, 0xb0, 0x16,
+	0xfd, 0x49, 0xb3, 0x51, 0xde, 0xc0, 0xff, 0xf9, 0xd7, 0x9d, 0xb2, 0x5d, 0x58, 0x34, 0xfc, 0xc9,
+	0xa2, 0xab, 0x71, 0x40, 0x12, 0x92, 0x7e, 0xe5, 0x35, 0x67, 0xae, 0xa6, 0x1d, 0x8f, 0x0f, 0x4a,
+	0x37, 0x33, 0xca, 0xb9, 0xfa, 0x24, 0xf3, 0x01, 0xac, 0x31, 0x66, 0x92, 0x72, 0x6b, 0x59, 0x4a,
+	0x83, 0x53, 0x94, 0xb7, 0x70, 0xd0, 0xc7, 0x81, 0xdb, 0x0e, 0xdc, 0x34, 0xdb, 0x60, 0xb1, 0x03,
+	0x5c, 0x0b, 0x4e, 0x1b, 0xfb, 0x18, 0xc4, 0xcc, 0x86, 0x5c, 0xf1, 0xe4, 0xcc, 0x7d, 0x23, 0x4b,
+	0x57, 0xbe, 0x16, 0xe0, 0xd6, 0x4a, 0xc5, 0x12, 0x1d, 0x35, 0x28, 0xa4, 0xa3, 0x2b, 0xf8, 0x2e,
+	0x7a, 0x00, 0xe5, 0x64, 0x0e, 0x05, 0x36, 0x07, 0xf9, 0xea, 0xc2, 0xc9, 0x44, 0x12, 0x7e, 0xce,
+	0xdc, 0xc5, 0xeb, 0xcd, 0x5d, 0xba, 0x6c, 0xee, 0x26, 0x88, 0x3c, 0x8f, 0xe9, 0x84, 0x2e, 0x96,
+	0xd6, 0x64, 0x41, 0xdd, 0x30, 0x80, 0x43, 0x47, 0xa1, 0x8b, 0xd1, 0x3e, 0x6c, 0xe0, 0x28, 0x0a,
+	0x23, 0x73, 0x82, 0x09, 0xb1, 0x3c, 0x2c, 0x95, 0x65, 0x41, 0xad, 0x18, 0x55, 0x06, 0x3e, 0xe7,
+	0x58, 0xc6, 0xdd, 0xeb, 0xbf, 0x76, 0xf7, 0x41, 0x07, 0xc4, 0x8c, 0xb5, 0xd0, 0x0e, 0xfc, 0xd3,
+	0x39, 0x7b, 0x79, 0xf4, 0xcc, 0xec, 0x0f, 0xda, 0x83, 0xd7, 0x7d, 0xb3, 0xdf, 0x6d, 0x9f, 0x75,
+	0x8f, 0x37, 0xff, 0x42, 0x0d, 0xd8, 0xce, 0x05, 0x4e, 0x7a, 0x2f, 0xda, 0x67, 0xbd, 0xf3, 0xee,
+	0xf1, 0xa6, 0xd0, 0xfa, 0x56, 0x84, 0x6a, 0x7f, 0xee, 0xd4, 0xf6, 0xab, 0x1e, 0x3a, 0x87, 0xfa,
+	0x85, 0xf5, 0x47, 0x7b, 0x19, 0x2f, 0x2f, 0xff, 0xa7, 0x6b, 0x28, 0xd7, 0x51, 0xf8, 0xa8, 0x0e,
+	0x05, 0x34, 0x84, 0x7f, 0x97, 0xfa, 0x7d, 0x79, 0x85, 0xdc, 0xbe, 0x36, 0xd4, 0x2b, 0x29, 0x17,
+	0x96, 0xe6, 0x50, 0xc8, 0x69, 0xe0, 0x59, 0x56, 0xa9, 0xa0, 0x5c, 0x47, 0x49, 0x73, 0x7f, 0x16,
+	0x60, 0x7f, 0x05, 0x83, 0xa2, 0x7b, 0xd9, 0x6c, 0x2b, 0x6f, 0x4f, 0xe3, 0xfe, 0xef, 0x5e, 0x9b,
+	0x3f, 0xac, 0xf3, 0xf4, 0xfc, 0xd4, 0xf3, 0xe9, 0x68, 0x66, 0x6b, 0x4e, 0x38, 0xd1, 0xc3, 0x80,
+	0x7d, 0x2e, 0xe3, 0x9f, 0x3b, 0x5e, 0xa8, 0xe3, 0xc0, 0xf3, 0x03, 0xac, 0x5b, 0x8e, 0x83, 0x09,
+	0xd1, 0xa3, 0xa9, 0xa3, 0xa7, 0x65, 0xd2, 0x2f, 0xf9, 0xa3, 0x14, 0xb2, 0xcb, 0x0c, 0xbb, 0xfb,
+	0x73, 0x00, 0x6e, 0x21, 0x7b, 0xaf, 0x09, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// StreamingAPIClient is the client API for StreamingAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StreamingAPIClient interface {
+	// SubscribeEvents streams the events matching the filter of every block,
+	// starting at the given block, once the block reached the requested status.
+	// A response is sent for every block, with no events if none match, so the
+	// height of the last received block can be used as a cursor to resume from.
+	SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (StreamingAPI_SubscribeEventsClient, error)
+	// SubscribeBlockHeaders streams the header of every block, starting at the
+	// given block, once the block reached the requested status.
+	SubscribeBlockHeaders(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (StreamingAPI_SubscribeBlockHeadersClient, error)
+	// SubscribeBlocks streams every block, starting at the given block, once
+	// the block reached the requested status.
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (StreamingAPI_SubscribeBlocksClient, error)
+	// SendAndSubscribeTransactionStatuses sends the transaction, then streams
+	// every transition of its status once, in order: pending, finalized,
+	// executed and sealed, or pending and expired. The stream ends once the
+	// transaction is sealed or expired.
+	SendAndSubscribeTransactionStatuses(ctx context.Context, in *SendAndSubscribeTransactionStatusesRequest, opts ...grpc.CallOption) (StreamingAPI_SendAndSubscribeTransactionStatusesClient, error)
+}
+
+type streamingAPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStreamingAPIClient(cc *grpc.ClientConn) StreamingAPIClient {
+	return &streamingAPIClient{cc}
+}
+
+func (c *streamingAPIClient) SubscribeEvents(ctx context.Context, in *SubscribeEventsRequest, opts ...grpc.CallOption) (StreamingAPI_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingAPI_serviceDesc.Streams[0], "/streaming.StreamingAPI/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamingAPISubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamingAPI_SubscribeEventsClient interface {
+	Recv() (*SubscribeEventsResponse, error)
+	grpc.ClientStream
+}
+
+type streamingAPISubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingAPISubscribeEventsClient) Recv() (*SubscribeEventsResponse, error) {
+	m := new(SubscribeEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamingAPIClient) SubscribeBlockHeaders(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (StreamingAPI_SubscribeBlockHeadersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingAPI_serviceDesc.Streams[1], "/streaming.StreamingAPI/SubscribeBlockHeaders", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamingAPISubscribeBlockHeadersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamingAPI_SubscribeBlockHeadersClient interface {
+	Recv() (*SubscribeBlockHeadersResponse, error)
+	grpc.ClientStream
+}
+
+type streamingAPISubscribeBlockHeadersClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingAPISubscribeBlockHeadersClient) Recv() (*SubscribeBlockHeadersResponse, error) {
+	m := new(SubscribeBlockHeadersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamingAPIClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (StreamingAPI_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingAPI_serviceDesc.Streams[2], "/streaming.StreamingAPI/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamingAPISubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamingAPI_SubscribeBlocksClient interface {
+	Recv() (*SubscribeBlocksResponse, error)
+	grpc.ClientStream
+}
+
+type streamingAPISubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingAPISubscribeBlocksClient) Recv() (*SubscribeBlocksResponse, error) {
+	m := new(SubscribeBlocksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamingAPIClient) SendAndSubscribeTransactionStatuses(ctx context.Context, in *SendAndSubscribeTransactionStatusesRequest, opts ...grpc.CallOption) (StreamingAPI_SendAndSubscribeTransactionStatusesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamingAPI_serviceDesc.Streams[3], "/streaming.StreamingAPI/SendAndSubscribeTransactionStatuses", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamingAPISendAndSubscribeTransactionStatusesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamingAPI_SendAndSubscribeTransactionStatusesClient interface {
+	Recv() (*SendAndSubscribeTransactionStatusesResponse, error)
+	grpc.ClientStream
+}
+
+type streamingAPISendAndSubscribeTransactionStatusesClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamingAPISendAndSubscribeTransactionStatusesClient) Recv() (*SendAndSubscribeTransactionStatusesResponse, error) {
+	m := new(SendAndSubscribeTransactionStatusesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamingAPIServer is the server API for StreamingAPI service.
+type StreamingAPIServer interface {
+	// SubscribeEvents streams the events matching the filter of every block,
+	// starting at the given block, once the block reached the requested status.
+	// A response is sent for every block, with no events if none match, so the
+	// height of the last received block can be used as a cursor to resume from.
+	SubscribeEvents(*SubscribeEventsRequest, StreamingAPI_SubscribeEventsServer) error
+	// SubscribeBlockHeaders streams the header of every block, starting at the
+	// given block, once the block reached the requested status.
+	SubscribeBlockHeaders(*SubscribeBlocksRequest, StreamingAPI_SubscribeBlockHeadersServer) error
+	// SubscribeBlocks streams every block, starting at the given block, once
+	// the block reached the requested status.
+	SubscribeBlocks(*SubscribeBlocksRequest, StreamingAPI_SubscribeBlocksServer) error
+	// SendAndSubscribeTransactionStatuses sends the transaction, then streams
+	// every transition of its status once, in order: pending, finalized,
+	// executed and sealed, or pending and expired. The stream ends once the
+	// transaction is sealed or expired.
+	SendAndSubscribeTransactionStatuses(*SendAndSubscribeTransactionStatusesRequest, StreamingAPI_SendAndSubscribeTransactionStatusesServer) error
+}
+
+// UnimplementedStreamingAPIServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamingAPIServer struct {
+}
+
+func (*UnimplementedStreamingAPIServer) SubscribeEvents(req *SubscribeEventsRequest, srv StreamingAPI_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+func (*UnimplementedStreamingAPIServer) SubscribeBlockHeaders(req *SubscribeBlocksRequest, srv StreamingAPI_SubscribeBlockHeadersServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlockHeaders not implemented")
+}
+func (*UnimplementedStreamingAPIServer) SubscribeBlocks(req *SubscribeBlocksRequest, srv StreamingAPI_SubscribeBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+func (*UnimplementedStreamingAPIServer) SendAndSubscribeTransactionStatuses(req *SendAndSubscribeTransactionStatusesRequest, srv StreamingAPI_SendAndSubscribeTransactionStatusesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendAndSubscribeTransactionStatuses not implemented")
+}
+
+func RegisterStreamingAPIServer(s *grpc.Server, srv StreamingAPIServer) {
+	s.RegisterService(&_StreamingAPI_serviceDesc, srv)
+}
+
+func _StreamingAPI_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamingAPIServer).SubscribeEvents(m, &streamingAPISubscribeEventsServer{stream})
+}
+
+type StreamingAPI_SubscribeEventsServer interface {
+	Send(*SubscribeEventsResponse) error
+	grpc.ServerStream
+}
+
+type streamingAPISubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingAPISubscribeEventsServer) Send(m *SubscribeEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StreamingAPI_SubscribeBlockHeaders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamingAPIServer).SubscribeBlockHeaders(m, &streamingAPISubscribeBlockHeadersServer{stream})
+}
+
+type StreamingAPI_SubscribeBlockHeadersServer interface {
+	Send(*SubscribeBlockHeadersResponse) error
+	grpc.ServerStream
+}
+
+type streamingAPISubscribeBlockHeadersServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingAPISubscribeBlockHeadersServer) Send(m *SubscribeBlockHeadersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StreamingAPI_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamingAPIServer).SubscribeBlocks(m, &streamingAPISubscribeBlocksServer{stream})
+}
+
+type StreamingAPI_SubscribeBlocksServer interface {
+	Send(*SubscribeBlocksResponse) error
+	grpc.ServerStream
+}
+
+type streamingAPISubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingAPISubscribeBlocksServer) Send(m *SubscribeBlocksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StreamingAPI_SendAndSubscribeTransactionStatuses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SendAndSubscribeTransactionStatusesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamingAPIServer).SendAndSubscribeTransactionStatuses(m, &streamingAPISendAndSubscribeTransactionStatusesServer{stream})
+}
+
+type StreamingAPI_SendAndSubscribeTransactionStatusesServer interface {
+	Send(*SendAndSubscribeTransactionStatusesResponse) error
+	grpc.ServerStream
+}
+
+type streamingAPISendAndSubscribeTransactionStatusesServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamingAPISendAndSubscribeTransactionStatusesServer) Send(m *SendAndSubscribeTransactionStatusesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _StreamingAPI_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "streaming.StreamingAPI",
+	HandlerType: (*StreamingAPIServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _StreamingAPI_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeBlockHeaders",
+			Handler:       _StreamingAPI_SubscribeBlockHeaders_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _StreamingAPI_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SendAndSubscribeTransactionStatuses",
+			Handler:       _StreamingAPI_SendAndSubscribeTransactionStatuses_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "streaming.proto",
+}
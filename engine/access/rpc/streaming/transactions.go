@@ -0,0 +1,266 @@
+package streaming
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TransactionStatusUpdate is a transition of the status of a transaction.
+type TransactionStatusUpdate struct {
+	TransactionID flow.Identifier
+	Status        flow.TransactionStatus
+	// BlockID and BlockHeight identify the block including the transaction, once it is finalized
+	BlockID     flow.Identifier
+	BlockHeight uint64
+	// Result is the result of the transaction, with its events, once it is executed
+	Result *access.TransactionResult
+}
+
+// transactionStatuses are the statuses a transaction goes through, in order, unless it expires while pending.
+var transactionStatuses = []flow.TransactionStatus{
+	flow.TransactionStatusPending,
+	flow.TransactionStatusFinalized,
+	flow.TransactionStatusExecuted,
+	flow.TransactionStatusSealed,
+}
+
+// TransactionTransition is a transition of the status of transactions, emitted by the ingestion engine once the
+// block including them is finalized, executed or sealed, and by the access backend once they expired.
+type TransactionTransition struct {
+	TransactionIDs []flow.Identifier
+	Status         flow.TransactionStatus
+	// BlockID and BlockHeight identify the block including the transactions, unless they expired
+	BlockID     flow.Identifier
+	BlockHeight uint64
+}
+
+// NotifyTransactions passes the transition of the status of transactions to the subscriptions streaming them.
+func (b *Backend) NotifyTransactions(transition TransactionTransition) {
+	b.transactions.notify(transition)
+}
+
+// SendAndSubscribeTransactionStatuses sends the transaction to the collection nodes, then streams every transition
+// of its status once, in order: pending, finalized, executed and sealed, or pending and expired. The subscription
+// ends once the transaction is sealed or expired, the context is cancelled or sending fails.
+//
+// The transitions are notified by the ingestion engine and the access backend, a notified transition may make
+// the transaction go through several transitions at once. The result of the transaction is fetched from the
+// execution nodes once it is executed: if they do not serve it yet, it is fetched again on the next notification.
+func (b *Backend) SendAndSubscribeTransactionStatuses(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	send func(TransactionStatusUpdate) error,
+) error {
+
+	txID := tx.ID()
+
+	// subscribe before sending, so that no transition is missed
+	subscription := b.transactions.subscribe(txID)
+	defer b.transactions.unsubscribe(txID, subscription)
+
+	err := b.api.SendTransaction(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	last := TransactionStatusUpdate{
+		TransactionID: txID,
+		Status:        flow.TransactionStatusPending,
+	}
+
+	err = send(last)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// only used to fetch the result again while the execution nodes do not serve it
+		changed := b.notifier.Changed()
+
+		transition := subscription.transition()
+		current, err := b.transactionStatus(ctx, last, transition)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range transactionTransitions(last.Status, current.Status) {
+			update := current
+			update.Status = status
+			if status == flow.TransactionStatusFinalized {
+				update.Result = nil
+			}
+
+			err = send(update)
+			if err != nil {
+				return err
+			}
+		}
+
+		if current.Status == flow.TransactionStatusSealed || current.Status == flow.TransactionStatusExpired {
+			return nil
+		}
+		last = current
+
+		if current.Status == transition.Status {
+			changed = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return contextError(ctx.Err())
+		case <-subscription.changed:
+		case <-changed:
+		}
+	}
+}
+
+// transactionStatus returns the status of the transaction after the notified transition, along with the block
+// including it and its result, given the last status.
+func (b *Backend) transactionStatus(
+	ctx context.Context,
+	last TransactionStatusUpdate,
+	transition TransactionTransition,
+) (TransactionStatusUpdate, error) {
+
+	current := last
+
+	if transition.Status == flow.TransactionStatusExpired {
+		if last.Status == flow.TransactionStatusPending {
+			current.Status = flow.TransactionStatusExpired
+		}
+		return current, nil
+	}
+
+	if transition.Status <= last.Status {
+		return current, nil
+	}
+
+	current.BlockID = transition.BlockID
+	current.BlockHeight = transition.BlockHeight
+
+	if transition.Status >= flow.TransactionStatusExecuted && current.Result == nil {
+		result, err := b.api.GetTransactionResult(ctx, last.TransactionID)
+		if err != nil {
+			return TransactionStatusUpdate{}, err
+		}
+
+		// the execution nodes may not serve the result of the block yet
+		if result.Status != flow.TransactionStatusExecuted && result.Status != flow.TransactionStatusSealed {
+			current.Status = flow.TransactionStatusFinalized
+			return current, nil
+		}
+		current.Result = result
+	}
+
+	current.Status = transition.Status
+
+	return current, nil
+}
+
+// transactionSubscriptions passes the transitions of the status of transactions to the subscriptions streaming them.
+type transactionSubscriptions struct {
+	mu            sync.Mutex
+	subscriptions map[flow.Identifier]map[*transactionSubscription]struct{}
+}
+
+// transactionSubscription holds the furthest transition notified for the transaction of a subscription.
+type transactionSubscription struct {
+	mu      sync.Mutex
+	latest  TransactionTransition
+	changed chan struct{}
+}
+
+func newTransactionSubscriptions() *transactionSubscriptions {
+	return &transactionSubscriptions{
+		subscriptions: make(map[flow.Identifier]map[*transactionSubscription]struct{}),
+	}
+}
+
+func (t *transactionSubscriptions) subscribe(txID flow.Identifier) *transactionSubscription {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	subscription := &transactionSubscription{
+		latest:  TransactionTransition{Status: flow.TransactionStatusPending},
+		changed: make(chan struct{}, 1),
+	}
+
+	if t.subscriptions[txID] == nil {
+		t.subscriptions[txID] = make(map[*transactionSubscription]struct{})
+	}
+	t.subscriptions[txID][subscription] = struct{}{}
+
+	return subscription
+}
+
+func (t *transactionSubscriptions) unsubscribe(txID flow.Identifier, subscription *transactionSubscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.subscriptions[txID], subscription)
+	if len(t.subscriptions[txID]) == 0 {
+		delete(t.subscriptions, txID)
+	}
+}
+
+func (t *transactionSubscriptions) notify(transition TransactionTransition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, txID := range transition.TransactionIDs {
+		for subscription := range t.subscriptions[txID] {
+			subscription.notify(transition)
+		}
+	}
+}
+
+// notify records the transition if it is further than the latest one, and wakes up the subscription.
+func (s *transactionSubscription) notify(transition TransactionTransition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	further := transition.Status > s.latest.Status
+	if transition.Status == flow.TransactionStatusExpired || s.latest.Status == flow.TransactionStatusExpired {
+		// only pending transactions expire
+		further = s.latest.Status == flow.TransactionStatusPending
+	}
+	if !further {
+		return
+	}
+	s.latest = transition
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// transition returns the furthest transition notified for the transaction.
+func (s *transactionSubscription) transition() TransactionTransition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.latest
+}
+
+// transactionTransitions returns the statuses a transaction went through to get from one status to another,
+// excluding the first one.
+func transactionTransitions(from flow.TransactionStatus, to flow.TransactionStatus) []flow.TransactionStatus {
+	if to == flow.TransactionStatusExpired {
+		if from == flow.TransactionStatusPending {
+			return []flow.TransactionStatus{flow.TransactionStatusExpired}
+		}
+		return nil
+	}
+
+	var transitions []flow.TransactionStatus
+	for _, s := range transactionStatuses {
+		if s > from && s <= to {
+			transitions = append(transitions, s)
+		}
+	}
+	return transitions
+}
@@ -112,6 +112,7 @@ const (
 	PushBlocks       = network.Channel("push-blocks")
 	PushReceipts     = network.Channel("push-receipts")
 	PushApprovals    = network.Channel("push-approvals")
+	PushStateDeltas  = network.Channel("push-state-deltas")
 
 	// Channels for actively requesting missing entities
 	RequestCollections       = network.Channel("request-collections")
@@ -125,6 +126,7 @@ const (
 	ReceiveBlocks       = PushBlocks
 	ReceiveReceipts     = PushReceipts
 	ReceiveApprovals    = PushApprovals
+	ReceiveStateDeltas  = PushStateDeltas
 
 	ProvideCollections       = RequestCollections
 	ProvideChunks            = RequestChunks
@@ -159,6 +161,7 @@ func initializeChannelRoleMap() {
 	channelRoleMap[PushReceipts] = flow.RoleList{flow.RoleConsensus, flow.RoleExecution, flow.RoleVerification,
 		flow.RoleAccess}
 	channelRoleMap[PushApprovals] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
+	channelRoleMap[PushStateDeltas] = flow.RoleList{flow.RoleExecution, flow.RoleAccess}
 
 	// Channels for actively requesting missing entities
 	channelRoleMap[RequestCollections] = flow.RoleList{flow.RoleCollection, flow.RoleExecution}
@@ -173,6 +176,7 @@ func initializeChannelRoleMap() {
 	channelRoleMap[ReceiveReceipts] = flow.RoleList{flow.RoleConsensus, flow.RoleExecution, flow.RoleVerification,
 		flow.RoleAccess}
 	channelRoleMap[ReceiveApprovals] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
+	channelRoleMap[ReceiveStateDeltas] = flow.RoleList{flow.RoleExecution, flow.RoleAccess}
 
 	channelRoleMap[ProvideCollections] = flow.RoleList{flow.RoleCollection, flow.RoleExecution}
 	channelRoleMap[ProvideChunks] = flow.RoleList{flow.RoleExecution, flow.RoleVerification}
@@ -3,7 +3,11 @@ package computer
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/log"
 	"github.com/rs/zerolog"
@@ -37,15 +41,21 @@ type blockComputer struct {
 	tracer         module.Tracer
 	log            zerolog.Logger
 	systemChunkCtx fvm.Context
+	parallelism    uint
 }
 
 // NewBlockComputer creates a new block executor.
+//
+// If parallelism is greater than one, and the context doesn't deduct transaction fees, the transactions
+// of each collection are executed optimistically by that many workers, see executeCollectionOptimistically.
+// Otherwise they are executed one after the other.
 func NewBlockComputer(
 	vm VirtualMachine,
 	vmCtx fvm.Context,
 	metrics module.ExecutionMetrics,
 	tracer module.Tracer,
 	logger zerolog.Logger,
+	parallelism uint,
 ) (BlockComputer, error) {
 	systemChunkASTCache, err := fvm.NewLRUASTCache(SystemChunkASTCacheSize)
 	if err != nil {
@@ -67,6 +77,7 @@ func NewBlockComputer(
 		tracer:         tracer,
 		log:            logger,
 		systemChunkCtx: systemChunkCtx,
+		parallelism:    parallelism,
 	}, nil
 }
 
@@ -203,6 +214,10 @@ func (e *blockComputer) executeCollection(
 
 	}
 
+	if e.parallelism > 1 && len(collection.Transactions) > 1 {
+		return e.executeCollectionOptimistically(colSpan, txIndex, blockCtx, collectionView, collection)
+	}
+
 	var (
 		events        []flow.Event
 		serviceEvents []flow.Event
@@ -210,11 +225,10 @@ func (e *blockComputer) executeCollection(
 		gasUsed       uint64
 	)
 
-	txMetrics := fvm.NewMetricsCollector()
-
-	txCtx := fvm.NewContextFromParent(blockCtx, fvm.WithMetricsCollector(txMetrics))
-
 	for _, txBody := range collection.Transactions {
+		txMetrics := fvm.NewMetricsCollector()
+		txCtx := fvm.NewContextFromParent(blockCtx, fvm.WithMetricsCollector(txMetrics))
+
 		txEvents, txServiceEvents, txResult, txGasUsed, err := e.executeTransaction(txBody, colSpan, txMetrics, collectionView, txCtx, txIndex)
 
 		txIndex++
@@ -240,47 +254,131 @@ func (e *blockComputer) executeTransaction(
 	txIndex uint32,
 ) ([]flow.Event, []flow.Event, flow.TransactionResult, uint64, error) {
 	if e.tracer != nil {
-		txSpan := e.tracer.StartSpanFromParent(colSpan, trace.EXEComputeTransaction)
-
-		defer func() {
-			// Attach runtime metrics to the transaction span.
-			//
-			// Each duration is the sum of all sub-programs in the transaction.
-			//
-			// For example, metrics.Parsed() returns the total time spent parsing the transaction itself,
-			// as well as any imported programs.
-			txSpan.SetTag("transaction.proposer", txBody.ProposalKey.Address.String())
-			txSpan.SetTag("transaction.payer", txBody.Payer.String())
-			txSpan.LogFields(
-				log.String("transaction.hash", txBody.ID().String()),
-				log.Int64(trace.EXEParseDurationTag, int64(txMetrics.Parsed())),
-				log.Int64(trace.EXECheckDurationTag, int64(txMetrics.Checked())),
-				log.Int64(trace.EXEInterpretDurationTag, int64(txMetrics.Interpreted())),
-				log.Int64(trace.EXEValueEncodingDurationTag, int64(txMetrics.ValueEncoded())),
-				log.Int64(trace.EXEValueDecodingDurationTag, int64(txMetrics.ValueDecoded())),
-			)
-			txSpan.Finish()
-		}()
+		defer e.traceTransaction(txBody, colSpan, txMetrics)()
 	}
 
 	txView := collectionView.NewChild()
 
 	tx := fvm.Transaction(txBody, txIndex)
 
+	start := time.Now()
 	err := e.vm.Run(ctx, tx, txView)
+	duration := time.Since(start)
+	if err != nil {
+		e.reportTransactionMetrics(txMetrics)
+		return nil, nil, flow.TransactionResult{}, 0, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+
+	return e.completeTransaction(txBody, tx, txMetrics, duration, txView, collectionView)
+}
 
+// traceTransaction starts the span of a transaction, and returns the function finishing it.
+func (e *blockComputer) traceTransaction(
+	txBody *flow.TransactionBody,
+	colSpan opentracing.Span,
+	txMetrics *fvm.MetricsCollector,
+) func() {
+	txSpan := e.tracer.StartSpanFromParent(colSpan, trace.EXEComputeTransaction)
+
+	return func() {
+		// Attach runtime metrics to the transaction span.
+		//
+		// Each duration is the sum of all sub-programs in the transaction.
+		//
+		// For example, metrics.Parsed() returns the total time spent parsing the transaction itself,
+		// as well as any imported programs.
+		txSpan.SetTag("transaction.proposer", txBody.ProposalKey.Address.String())
+		txSpan.SetTag("transaction.payer", txBody.Payer.String())
+		txSpan.SetTag(trace.EXEImportsTag, strings.Join(transactionImports(txBody.ID(), txMetrics), ","))
+		txSpan.LogFields(
+			log.String("transaction.hash", txBody.ID().String()),
+			log.Int64(trace.EXEParseDurationTag, int64(txMetrics.Parsed())),
+			log.Int64(trace.EXECheckDurationTag, int64(txMetrics.Checked())),
+			log.Int64(trace.EXEInterpretDurationTag, int64(txMetrics.Interpreted())),
+			log.Int64(trace.EXEValueEncodingDurationTag, int64(txMetrics.ValueEncoded())),
+			log.Int64(trace.EXEValueDecodingDurationTag, int64(txMetrics.ValueDecoded())),
+		)
+
+		// Break the durations down by the location of each program, including the transaction itself.
+		programs := txMetrics.Programs()
+		locations := make([]string, 0, len(programs))
+		for location := range programs {
+			locations = append(locations, string(location))
+		}
+		sort.Strings(locations)
+
+		for _, location := range locations {
+			program := programs[common.LocationID(location)]
+			txSpan.LogFields(
+				log.String(trace.EXEProgramLocationTag, location),
+				log.Int64(trace.EXEProgramParseDurationTag, int64(program.Parsed)),
+				log.Int64(trace.EXEProgramCheckDurationTag, int64(program.Checked)),
+				log.Int64(trace.EXEProgramInterpretDurationTag, int64(program.Interpreted)),
+			)
+		}
+
+		txSpan.Finish()
+	}
+}
+
+func (e *blockComputer) reportTransactionMetrics(txMetrics *fvm.MetricsCollector) {
 	if e.metrics != nil {
 		e.metrics.TransactionParsed(txMetrics.Parsed())
 		e.metrics.TransactionChecked(txMetrics.Checked())
 		e.metrics.TransactionInterpreted(txMetrics.Interpreted())
 	}
+}
 
-	if err != nil {
-		return nil, nil, flow.TransactionResult{}, 0, fmt.Errorf("failed to execute transaction: %w", err)
+// reportTransactionProfile reports the parse and check durations of the contracts imported
+// by a transaction, and adds the transaction to the slowest transactions report.
+func (e *blockComputer) reportTransactionProfile(txID flow.Identifier, txMetrics *fvm.MetricsCollector, duration time.Duration) {
+	if e.metrics == nil {
+		return
 	}
 
+	contracts := transactionImports(txID, txMetrics)
+	for _, contract := range contracts {
+		program := txMetrics.Program(common.LocationID(contract))
+		e.metrics.ExecutionContractParsed(contract, program.Parsed)
+		e.metrics.ExecutionContractChecked(contract, program.Checked)
+	}
+
+	e.metrics.ExecutionTransactionExecuted(txID, contracts, duration)
+}
+
+// transactionImports returns the locations of the contracts imported at the top level of a transaction.
+func transactionImports(txID flow.Identifier, txMetrics *fvm.MetricsCollector) []string {
+	imports := txMetrics.Program(common.TransactionLocation(txID[:]).ID()).Imports
+
+	contracts := make([]string, len(imports))
+	for i, location := range imports {
+		contracts[i] = string(location)
+	}
+	return contracts
+}
+
+// completeTransaction reports the result of an executed transaction, and merges its view
+// into the collection view. The views of failed transactions are merged too: the FVM only rolls back
+// the changes of their bodies, and keeps their fees and sequence number increments.
+func (e *blockComputer) completeTransaction(
+	txBody *flow.TransactionBody,
+	tx *fvm.TransactionProcedure,
+	txMetrics *fvm.MetricsCollector,
+	duration time.Duration,
+	txView *delta.View,
+	collectionView *delta.View,
+) ([]flow.Event, []flow.Event, flow.TransactionResult, uint64, error) {
+
+	e.reportTransactionMetrics(txMetrics)
+	e.reportTransactionProfile(tx.ID, txMetrics, duration)
+
 	txResult := flow.TransactionResult{
-		TransactionID: tx.ID,
+		TransactionID:   tx.ID,
+		ComputationUsed: tx.GasUsed,
+	}
+
+	if e.metrics != nil {
+		e.metrics.ExecutionComputationUsedPerTransaction(tx.GasUsed)
 	}
 
 	if tx.Err != nil {
@@ -296,9 +394,7 @@ func (e *blockComputer) executeTransaction(
 			Msg("transaction executed successfully")
 	}
 
-	if tx.Err == nil {
-		collectionView.MergeView(txView)
-	}
+	collectionView.MergeView(txView)
 
 	return tx.Events, tx.ServiceEvents, txResult, tx.GasUsed, nil
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/crypto/hash"
 	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	computermock "github.com/onflow/flow-go/engine/execution/computation/computer/mock"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
@@ -33,7 +34,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 
 		vm := new(computermock.VirtualMachine)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop())
+		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop(), 1)
 		require.NoError(t, err)
 
 		// create a block with 1 collection with 2 transactions
@@ -60,7 +61,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 
 		vm := new(computermock.VirtualMachine)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop())
+		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop(), 1)
 		require.NoError(t, err)
 
 		// create an empty block
@@ -87,7 +88,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 
 		vm := new(computermock.VirtualMachine)
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop())
+		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop(), 1)
 		require.NoError(t, err)
 
 		collectionCount := 2
@@ -104,6 +105,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 				tx := args[1].(*fvm.TransactionProcedure)
 
 				tx.Err = &fvm.MissingPayerError{}
+				tx.GasUsed = 42
 				// create dummy events
 				tx.Events = generateEvents(eventsPerTransaction, tx.TxIndex)
 			}).
@@ -138,8 +140,9 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 		for _, c := range block.CompleteCollections {
 			for _, t := range c.Transactions {
 				txResult := flow.TransactionResult{
-					TransactionID: t.ID(),
-					ErrorMessage:  "no payer address provided",
+					TransactionID:   t.ID(),
+					ErrorMessage:    "no payer address provided",
+					ComputationUsed: 42,
 				}
 				expectedResults = append(expectedResults, txResult)
 			}
@@ -199,7 +202,7 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 
 		vm := &fvm.VirtualMachine{Runtime: emittingRuntime}
 
-		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop())
+		exe, err := computer.NewBlockComputer(vm, execCtx, nil, nil, zerolog.Nop(), 1)
 		require.NoError(t, err)
 
 		//vm.On("Run", mock.Anything, mock.Anything, mock.Anything).
@@ -231,6 +234,47 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 	})
 }
 
+func TestBlockExecutor_ExecuteFailingTransaction(t *testing.T) {
+	chain := flow.Mainnet.Chain()
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+
+	keys := generateAccountKeys(t, 2)
+	ledger, addresses := bootstrapAccounts(t, vm, chain, keys, fvm.WithTransactionFee(fvm.DefaultTransactionFees))
+
+	// the sender only has 10 tokens, so the transfer fails
+	failing := transferTokens(t, chain, addresses[0], addresses[1], 100_0000_0000).
+		SetProposalKey(addresses[0], 0, 0).
+		SetPayer(addresses[0])
+	err := failing.SignEnvelope(addresses[0], 0, keys[0].PrivateKey, hash.NewSHA3_256())
+	require.NoError(t, err)
+
+	// the following transaction of the sender has to use the next sequence number
+	succeeding := signedTransfer(t, chain, addresses[0], keys[0], 1, addresses[1])
+
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+	exe, err := computer.NewBlockComputer(vm, ctx, nil, nil, zerolog.Nop(), 1)
+	require.NoError(t, err)
+
+	view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		return ledger.Get(owner, controller, key)
+	})
+
+	result, err := exe.ExecuteBlock(context.Background(), blockOf([]*flow.TransactionBody{failing, succeeding}), view)
+	require.NoError(t, err)
+
+	// the transactions and the system chunk transaction
+	require.Len(t, result.TransactionResult, 3)
+	require.NotEmpty(t, result.TransactionResult[0].ErrorMessage)
+	require.Empty(t, result.TransactionResult[1].ErrorMessage)
+
+	sender, err := vm.GetAccount(ctx, addresses[0], view)
+	require.NoError(t, err)
+
+	// both transactions paid their fee and incremented the sequence number, but only the second one transferred tokens
+	assert.Equal(t, uint64(10_0000_0000-1-2*fvm.DefaultTransactionFees), sender.Balance)
+	assert.Equal(t, uint64(2), sender.Keys[0].SeqNumber)
+}
+
 type eventEmittingRuntime struct {
 	events [][]cadence.Event
 }
@@ -0,0 +1,212 @@
+package computer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// speculativeTransaction is a transaction executed on the state of its collection
+// before any of the transactions of the collection were committed.
+type speculativeTransaction struct {
+	tx      *fvm.TransactionProcedure
+	view    *delta.View
+	metrics *fvm.MetricsCollector
+	// reads contains the registers read from the collection view, in order
+	reads    []flow.RegisterID
+	duration time.Duration
+	err      error
+}
+
+// executeCollectionOptimistically executes the transactions of a collection concurrently and commits
+// them in order, producing exactly the same results and collection view as executing them one after the other.
+//
+// All transactions are first executed speculatively on views peeking into the collection view, recording
+// the registers they read from it. A speculative transaction is then committed by replaying its reads on the
+// collection view, which records them as serial execution would have, and merging its view. If a transaction
+// touched a register written by a previously committed transaction of the collection, its speculative results
+// are stale, and it is executed again on top of the committed transactions instead.
+//
+// The transaction fees are deducted when a speculative transaction is committed, on top of the committed
+// transactions: every transaction paying fees deposits them into the same vault, so the fee deductions of
+// all transactions conflict, and are excluded from the conflict detection by deferring them.
+//
+// Only transactions which don't depend on each other benefit from being executed optimistically. Registers
+// written by most transactions serialize them, e.g. the UUID register, written by every transaction creating
+// a resource, like withdrawing tokens from a vault.
+//
+// The block context is shared by the workers: its AST cache is safe for concurrent use,
+// and so must be its set value handler. Each transaction has its own metrics collector.
+func (e *blockComputer) executeCollectionOptimistically(
+	colSpan opentracing.Span,
+	txIndex uint32,
+	blockCtx fvm.Context,
+	collectionView *delta.View,
+	collection *entity.CompleteCollection,
+) ([]flow.Event, []flow.Event, []flow.TransactionResult, uint32, uint64, error) {
+
+	var (
+		events        []flow.Event
+		serviceEvents []flow.Event
+		txResults     []flow.TransactionResult
+		gasUsed       uint64
+		reExecuted    int
+	)
+
+	speculations := e.speculate(txIndex, blockCtx, collectionView, collection.Transactions)
+
+	for i, txBody := range collection.Transactions {
+		var (
+			txEvents        []flow.Event
+			txServiceEvents []flow.Event
+			txResult        flow.TransactionResult
+			txGasUsed       uint64
+			err             error
+		)
+
+		speculation := speculations[i]
+		if speculation.err == nil && !conflicts(speculation.view, collectionView) {
+			txEvents, txServiceEvents, txResult, txGasUsed, err = e.commitSpeculativeTransaction(txBody, colSpan, speculation, collectionView)
+		} else {
+			reExecuted++
+			txMetrics := fvm.NewMetricsCollector()
+			txCtx := fvm.NewContextFromParent(blockCtx, fvm.WithMetricsCollector(txMetrics))
+			txEvents, txServiceEvents, txResult, txGasUsed, err = e.executeTransaction(txBody, colSpan, txMetrics, collectionView, txCtx, txIndex)
+		}
+
+		txIndex++
+		events = append(events, txEvents...)
+		serviceEvents = append(serviceEvents, txServiceEvents...)
+		txResults = append(txResults, txResult)
+		gasUsed += txGasUsed
+
+		if err != nil {
+			return nil, nil, nil, txIndex, 0, err
+		}
+	}
+
+	e.log.Debug().
+		Hex("collection_id", logging.Entity(collection.Guarantee)).
+		Int("transactions", len(collection.Transactions)).
+		Int("re_executed", reExecuted).
+		Msg("collection executed optimistically")
+
+	return events, serviceEvents, txResults, txIndex, gasUsed, nil
+}
+
+// speculate executes the given transactions concurrently on views peeking into the collection view.
+// The collection view must not be modified until all transactions have been executed.
+func (e *blockComputer) speculate(
+	txIndex uint32,
+	blockCtx fvm.Context,
+	collectionView *delta.View,
+	txBodies []*flow.TransactionBody,
+) []*speculativeTransaction {
+
+	speculations := make([]*speculativeTransaction, len(txBodies))
+
+	workers := int(e.parallelism)
+	if workers > len(txBodies) {
+		workers = len(txBodies)
+	}
+
+	indices := make(chan int, len(txBodies))
+	for i := range txBodies {
+		indices <- i
+	}
+	close(indices)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				speculations[i] = e.speculateTransaction(txBodies[i], blockCtx, collectionView, txIndex+uint32(i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	return speculations
+}
+
+func (e *blockComputer) speculateTransaction(
+	txBody *flow.TransactionBody,
+	blockCtx fvm.Context,
+	collectionView *delta.View,
+	txIndex uint32,
+) *speculativeTransaction {
+
+	speculation := &speculativeTransaction{
+		tx:      fvm.Transaction(txBody, txIndex),
+		metrics: fvm.NewMetricsCollector(),
+	}
+
+	speculation.view = delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		speculation.reads = append(speculation.reads, flow.NewRegisterID(owner, controller, key))
+		return collectionView.Peek(owner, controller, key)
+	})
+
+	txCtx := fvm.NewContextFromParent(
+		blockCtx,
+		fvm.WithMetricsCollector(speculation.metrics),
+		fvm.WithTransactionFeesDeferred(true),
+	)
+
+	start := time.Now()
+	speculation.err = e.vm.Run(txCtx, speculation.tx, speculation.view)
+	speculation.duration = time.Since(start)
+
+	return speculation
+}
+
+// conflicts returns true if the transaction view touched a register written
+// to the collection view since the transaction was executed.
+func conflicts(txView *delta.View, collectionView *delta.View) bool {
+	written := collectionView.Delta()
+	for _, id := range txView.Interactions().RegisterTouches() {
+		if _, ok := written.Get(id.Owner, id.Controller, id.Key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *blockComputer) commitSpeculativeTransaction(
+	txBody *flow.TransactionBody,
+	colSpan opentracing.Span,
+	speculation *speculativeTransaction,
+	collectionView *delta.View,
+) ([]flow.Event, []flow.Event, flow.TransactionResult, uint64, error) {
+	if e.tracer != nil {
+		defer e.traceTransaction(txBody, colSpan, speculation.metrics)()
+	}
+
+	// the deferred fees are deducted on top of the committed transactions, reading the registers the transaction
+	// didn't read yet from the collection view, and the changes of the transaction are written to its view
+	start := time.Now()
+	err := speculation.tx.DeductDeferredFees()
+	if err != nil {
+		return nil, nil, flow.TransactionResult{}, 0, fmt.Errorf("failed to deduct transaction fees: %w", err)
+	}
+	speculation.duration += time.Since(start)
+
+	// replay the reads, so they are recorded by the collection view in the same order as during serial execution
+	for _, id := range speculation.reads {
+		_, err := collectionView.Get(id.Owner, id.Controller, id.Key)
+		if err != nil {
+			return nil, nil, flow.TransactionResult{}, 0, fmt.Errorf("failed to replay read of register %s: %w", id.String(), err)
+		}
+	}
+
+	return e.completeTransaction(txBody, speculation.tx, speculation.metrics, speculation.duration, speculation.view, collectionView)
+}
@@ -0,0 +1,474 @@
+package computer_test
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// registerVM is a virtual machine executing a random, but deterministic program for each transaction,
+// derived from the transaction ID. The program reads, writes, touches and deletes registers of one of the
+// given owners, or of its own owner if none are given, and the values it writes and the events it emits depend on the values it read before,
+// so executing a transaction on a stale state produces different results.
+type registerVM struct {
+	owners    []string
+	registers int
+	runs      int64
+}
+
+func (vm *registerVM) Run(_ fvm.Context, proc fvm.Procedure, ledger state.Ledger) error {
+	atomic.AddInt64(&vm.runs, 1)
+
+	tx := proc.(*fvm.TransactionProcedure)
+	id := tx.Transaction.ID()
+	r := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(id[:8]))))
+
+	owner := id.String()
+	if len(vm.owners) > 0 {
+		owner = vm.owners[r.Intn(len(vm.owners))]
+	}
+
+	hasher := hash.NewSHA3_256()
+	for op := 0; op < 10; op++ {
+		key := fmt.Sprintf("key%d", r.Intn(vm.registers))
+		switch r.Intn(5) {
+		case 0, 1:
+			value, err := ledger.Get(owner, "", key)
+			if err != nil {
+				return err
+			}
+			_, _ = hasher.Write(value)
+		case 2:
+			err := ledger.Set(owner, "", key, hasher.ComputeHash([]byte{byte(op)}))
+			if err != nil {
+				return err
+			}
+		case 3:
+			err := ledger.Touch(owner, "", key)
+			if err != nil {
+				return err
+			}
+		case 4:
+			err := ledger.Delete(owner, "", key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	state := hasher.SumHash()
+	tx.Events = []flow.Event{{
+		Type:             "state",
+		TransactionID:    tx.ID,
+		TransactionIndex: tx.TxIndex,
+		Payload:          state,
+	}}
+
+	// the outcome depends on the state, like failing to withdraw from an empty vault
+	if state[0]%5 == 0 {
+		tx.Err = &fvm.MissingPayerError{}
+	}
+
+	return nil
+}
+
+func executeWithParallelism(t *testing.T, vm computer.VirtualMachine, block *entity.ExecutableBlock, parallelism uint) (*execution.ComputationResult, *delta.View) {
+	exe, err := computer.NewBlockComputer(vm, fvm.NewContext(zerolog.Nop()), nil, nil, zerolog.Nop(), parallelism)
+	require.NoError(t, err)
+
+	view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		// every register has an initial value, so reads are observable
+		return flow.RegisterValue(owner + key), nil
+	})
+
+	result, err := exe.ExecuteBlock(context.Background(), block, view)
+	require.NoError(t, err)
+
+	return result, view
+}
+
+// requireSameExecution compares the results of executing a block serially and optimistically
+func requireSameExecution(t *testing.T, serial *execution.ComputationResult, serialView *delta.View, parallel *execution.ComputationResult, parallelView *delta.View) {
+	requireSameResults(t, serial, serialView, parallel, parallelView)
+	require.Equal(t, serial.StateReads, parallel.StateReads)
+
+	for i := range serial.StateSnapshots {
+		require.Equal(t, serial.StateSnapshots[i].SpockSecret, parallel.StateSnapshots[i].SpockSecret, "SPoCK secret of chunk %d differs", i)
+	}
+	require.Equal(t, serialView.SpockSecret(), parallelView.SpockSecret())
+}
+
+// requireSameResults compares the results of executing a block serially and optimistically, except for the SPoCK secrets
+// and the number of state reads, which depends on the programs found in the program cache
+func requireSameResults(t *testing.T, serial *execution.ComputationResult, serialView *delta.View, parallel *execution.ComputationResult, parallelView *delta.View) {
+	require.Equal(t, serial.Events, parallel.Events)
+	require.Equal(t, serial.ServiceEvents, parallel.ServiceEvents)
+	require.Equal(t, serial.TransactionResult, parallel.TransactionResult)
+	require.Equal(t, serial.GasUsed, parallel.GasUsed)
+
+	require.Len(t, parallel.StateSnapshots, len(serial.StateSnapshots))
+	for i := range serial.StateSnapshots {
+		require.Equal(t, serial.StateSnapshots[i].Delta, parallel.StateSnapshots[i].Delta, "delta of chunk %d differs", i)
+		require.ElementsMatch(t, serial.StateSnapshots[i].Reads, parallel.StateSnapshots[i].Reads, "reads of chunk %d differ", i)
+	}
+
+	require.Equal(t, serialView.Delta(), parallelView.Delta())
+}
+
+func TestBlockExecutor_ExecuteBlockOptimistically(t *testing.T) {
+
+	t.Run("conflicting transactions", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			block := generateBlock(3, 30)
+
+			// few registers of few owners, so most transactions conflict
+			vm := &registerVM{owners: []string{"alice", "bob"}, registers: 8}
+
+			serial, serialView := executeWithParallelism(t, vm, block, 1)
+			parallel, parallelView := executeWithParallelism(t, vm, block, 8)
+
+			requireSameExecution(t, serial, serialView, parallel, parallelView)
+		}
+	})
+
+	t.Run("independent transactions are not re-executed", func(t *testing.T) {
+		collectionCount := 3
+		transactionsPerCollection := 30
+		block := generateBlock(collectionCount, transactionsPerCollection)
+
+		// make sure transactions are unique, as the payers of generated transactions might collide
+		i := 0
+		for _, collection := range block.CompleteCollections {
+			for _, tx := range collection.Transactions {
+				tx.GasLimit = uint64(i)
+				i++
+			}
+		}
+
+		// every transaction has its own owner
+		serialVM := &registerVM{registers: 8}
+		serial, serialView := executeWithParallelism(t, serialVM, block, 1)
+
+		parallelVM := &registerVM{registers: 8}
+		parallel, parallelView := executeWithParallelism(t, parallelVM, block, 8)
+
+		requireSameExecution(t, serial, serialView, parallel, parallelView)
+
+		// all transactions and the system chunk are executed exactly once
+		assert.Equal(t, int64(collectionCount*transactionsPerCollection+1), atomic.LoadInt64(&parallelVM.runs))
+	})
+
+	t.Run("virtual machine error", func(t *testing.T) {
+		block := generateBlock(1, 10)
+
+		vm := &failingVM{}
+
+		exe, err := computer.NewBlockComputer(vm, fvm.NewContext(zerolog.Nop()), nil, nil, zerolog.Nop(), 4)
+		require.NoError(t, err)
+
+		view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+			return nil, nil
+		})
+
+		_, err = exe.ExecuteBlock(context.Background(), block, view)
+		require.Error(t, err)
+	})
+}
+
+// failingVM fails to execute any transaction
+type failingVM struct{}
+
+func (vm *failingVM) Run(fvm.Context, fvm.Procedure, state.Ledger) error {
+	return fmt.Errorf("virtual machine failure")
+}
+
+// countingVM counts the procedures run by a virtual machine
+type countingVM struct {
+	vm   computer.VirtualMachine
+	runs int64
+}
+
+func (vm *countingVM) Run(ctx fvm.Context, proc fvm.Procedure, ledger state.Ledger) error {
+	atomic.AddInt64(&vm.runs, 1)
+	return vm.vm.Run(ctx, proc, ledger)
+}
+
+const transferTokensTransaction = `
+  import FungibleToken from 0x%s
+  import FlowToken from 0x%s
+
+  transaction(amount: UFix64, to: Address) {
+    let sentVault: @FungibleToken.Vault
+
+    prepare(signer: AuthAccount) {
+      let vaultRef = signer.borrow<&FlowToken.Vault>(from: /storage/flowTokenVault)
+        ?? panic("Could not borrow reference to the owner's Vault!")
+      self.sentVault <- vaultRef.withdraw(amount: amount)
+    }
+
+    execute {
+      let receiverRef = getAccount(to).getCapability(/public/flowTokenReceiver)!.borrow<&{FungibleToken.Receiver}>()
+        ?? panic("Could not borrow receiver reference to the recipient's Vault")
+      receiverRef.deposit(from: <-self.sentVault)
+    }
+  }
+`
+
+func transferTokens(t *testing.T, chain flow.Chain, from flow.Address, to flow.Address, amount cadence.UFix64) *flow.TransactionBody {
+	encodedAmount, err := jsoncdc.Encode(amount)
+	require.NoError(t, err)
+	encodedTo, err := jsoncdc.Encode(cadence.NewAddress(to))
+	require.NoError(t, err)
+
+	script := fmt.Sprintf(transferTokensTransaction, fvm.FungibleTokenAddress(chain), fvm.FlowTokenAddress(chain))
+
+	return flow.NewTransactionBody().
+		SetScript([]byte(script)).
+		AddArgument(encodedAmount).
+		AddArgument(encodedTo).
+		AddAuthorizer(from)
+}
+
+// signedTransfer returns a transaction transferring tokens, proposed, paid and signed by the sender
+func signedTransfer(t *testing.T, chain flow.Chain, from flow.Address, key flow.AccountPrivateKey, seqNum uint64, to flow.Address) *flow.TransactionBody {
+	return signed(t, transferTokens(t, chain, from, to, 1), from, key, seqNum)
+}
+
+// signed returns the transaction proposed, paid and signed by its authorizer
+func signed(t *testing.T, txBody *flow.TransactionBody, from flow.Address, key flow.AccountPrivateKey, seqNum uint64) *flow.TransactionBody {
+	txBody.
+		SetProposalKey(from, 0, seqNum).
+		SetPayer(from)
+
+	err := txBody.SignEnvelope(from, 0, key.PrivateKey, hash.NewSHA3_256())
+	require.NoError(t, err)
+
+	return txBody
+}
+
+// generateAccountKeys generates the given number of account keys
+func generateAccountKeys(t *testing.T, count int) []flow.AccountPrivateKey {
+	keys := make([]flow.AccountPrivateKey, count)
+	for i := range keys {
+		seed := make([]byte, crypto.KeyGenSeedMinLenECDSAP256)
+		_, err := crand.Read(seed)
+		require.NoError(t, err)
+		privateKey, err := crypto.GeneratePrivateKey(crypto.ECDSAP256, seed)
+		require.NoError(t, err)
+		keys[i] = flow.AccountPrivateKey{PrivateKey: privateKey, SignAlgo: crypto.ECDSAP256, HashAlgo: hash.SHA3_256}
+	}
+	return keys
+}
+
+// bootstrapAccounts bootstraps a ledger with the given options, and creates an account for each of the given keys,
+// funded with 10 tokens.
+func bootstrapAccounts(
+	t *testing.T,
+	vm *fvm.VirtualMachine,
+	chain flow.Chain,
+	keys []flow.AccountPrivateKey,
+	opts ...fvm.BootstrapProcedureOption,
+) (*state.MapLedger, []flow.Address) {
+	ctx := fvm.NewContext(
+		zerolog.Nop(),
+		fvm.WithChain(chain),
+		fvm.WithTransactionProcessors(fvm.NewTransactionInvocator(zerolog.Nop())),
+	)
+
+	ledger := state.NewMapLedger()
+
+	opts = append([]fvm.BootstrapProcedureOption{fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply)}, opts...)
+	bootstrap := fvm.Bootstrap(unittest.ServiceAccountPublicKey, opts...)
+	err := vm.Run(ctx, bootstrap, ledger)
+	require.NoError(t, err)
+
+	createAccount := []byte(`
+      transaction(publicKey: [UInt8]) {
+        prepare(signer: AuthAccount) {
+          let acct = AuthAccount(payer: signer)
+          acct.addPublicKey(publicKey)
+        }
+      }
+    `)
+
+	addresses := make([]flow.Address, len(keys))
+	for i, key := range keys {
+		encodedKey, err := flow.EncodeRuntimeAccountPublicKey(key.PublicKey(fvm.AccountKeyWeightThreshold))
+		require.NoError(t, err)
+
+		values := make([]cadence.Value, len(encodedKey))
+		for j, b := range encodedKey {
+			values[j] = cadence.NewUInt8(b)
+		}
+		argument, err := jsoncdc.Encode(cadence.NewArray(values))
+		require.NoError(t, err)
+
+		tx := fvm.Transaction(flow.NewTransactionBody().
+			SetScript(createAccount).
+			AddArgument(argument).
+			AddAuthorizer(chain.ServiceAddress()), 0)
+		err = vm.Run(ctx, tx, ledger)
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+
+		for _, event := range tx.Events {
+			if event.Type == flow.EventAccountCreated {
+				data, err := jsoncdc.Decode(event.Payload)
+				require.NoError(t, err)
+				addresses[i] = flow.Address(data.(cadence.Event).Fields[0].(cadence.Address))
+			}
+		}
+		require.NotEqual(t, flow.EmptyAddress, addresses[i])
+
+		tx = fvm.Transaction(transferTokens(t, chain, chain.ServiceAddress(), addresses[i], 10_0000_0000), 0)
+		err = vm.Run(ctx, tx, ledger)
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+	}
+
+	return ledger, addresses
+}
+
+// blockOf returns a block with one collection of the given transactions
+func blockOf(transactions []*flow.TransactionBody) *entity.ExecutableBlock {
+	collection := flow.Collection{Transactions: transactions}
+	guarantee := &flow.CollectionGuarantee{CollectionID: collection.ID()}
+
+	block := flow.Block{
+		Header: &flow.Header{
+			View: 42,
+		},
+		Payload: &flow.Payload{
+			Guarantees: []*flow.CollectionGuarantee{guarantee},
+		},
+	}
+
+	return &entity.ExecutableBlock{
+		Block: &block,
+		CompleteCollections: map[flow.Identifier]*entity.CompleteCollection{
+			guarantee.ID(): {Guarantee: guarantee, Transactions: transactions},
+		},
+	}
+}
+
+// TestBlockExecutor_ExecuteTransfersOptimistically executes token transfers on the virtual machine concurrently,
+// and is also run with the race detector.
+func TestBlockExecutor_ExecuteTransfersOptimistically(t *testing.T) {
+	chain := flow.Mainnet.Chain()
+	vm := fvm.New(runtime.NewInterpreterRuntime())
+
+	keys := generateAccountKeys(t, 8)
+
+	ledger, addresses := bootstrapAccounts(t, vm, chain, keys, fvm.WithTransactionFee(fvm.DefaultTransactionFees))
+
+	// pairs of accounts send tokens to each other, and every account proposes two transactions
+	var transfers []*flow.TransactionBody
+	for seqNum := uint64(0); seqNum < 2; seqNum++ {
+		for i, address := range addresses {
+			transfers = append(transfers, signedTransfer(t, chain, address, keys[i], seqNum, addresses[i^1]))
+		}
+	}
+
+	// every account stores a value in its own storage, without creating any resource
+	var stores []*flow.TransactionBody
+	for i, address := range addresses {
+		txBody := flow.NewTransactionBody().
+			SetScript([]byte(`transaction { prepare(signer: AuthAccount) { signer.save("hello", to: /storage/hello) } }`)).
+			AddAuthorizer(address)
+		stores = append(stores, signed(t, txBody, address, keys[i], 0))
+	}
+
+	// the map ledger records reads, so the views read the registers from a map safe for concurrent reads instead
+	registers := make(map[flow.RegisterID]flow.RegisterValue, len(ledger.Registers))
+	for _, entry := range ledger.Registers {
+		registers[entry.Key] = entry.Value
+	}
+
+	astCache, err := fvm.NewLRUASTCache(computer.SystemChunkASTCacheSize)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, ctx fvm.Context, block *entity.ExecutableBlock, parallelism uint) (*execution.ComputationResult, *delta.View, int64) {
+		counting := &countingVM{vm: vm}
+		exe, err := computer.NewBlockComputer(counting, ctx, nil, nil, zerolog.Nop(), parallelism)
+		require.NoError(t, err)
+
+		view := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+			return registers[flow.NewRegisterID(owner, controller, key)], nil
+		})
+
+		result, err := exe.ExecuteBlock(context.Background(), block, view)
+		require.NoError(t, err)
+
+		for _, txResult := range result.TransactionResult {
+			require.Empty(t, txResult.ErrorMessage)
+		}
+
+		return result, view, atomic.LoadInt64(&counting.runs)
+	}
+
+	// The Cadence runtime writes the values of a transaction in no particular order, reading the registers
+	// updated before, so the SPoCK secrets of transactions writing several values differ between executions.
+
+	t.Run("transfers", func(t *testing.T) {
+		ctx := fvm.NewContext(
+			zerolog.Nop(),
+			fvm.WithChain(chain),
+			fvm.WithASTCache(astCache),
+			fvm.WithTransactionProcessors(
+				fvm.NewTransactionSignatureVerifier(fvm.AccountKeyWeightThreshold),
+				fvm.NewTransactionSequenceNumberChecker(),
+				fvm.NewTransactionInvocator(zerolog.Nop()),
+				fvm.NewTransactionStorageLimiter(),
+			),
+		)
+
+		serial, serialView, _ := execute(t, ctx, blockOf(transfers), 1)
+		parallel, parallelView, _ := execute(t, ctx, blockOf(transfers), 4)
+
+		requireSameResults(t, serial, serialView, parallel, parallelView)
+	})
+
+	t.Run("transfers paying fees", func(t *testing.T) {
+		ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain), fvm.WithASTCache(astCache))
+
+		serial, serialView, _ := execute(t, ctx, blockOf(transfers), 1)
+		parallel, parallelView, _ := execute(t, ctx, blockOf(transfers), 4)
+
+		requireSameResults(t, serial, serialView, parallel, parallelView)
+	})
+
+	t.Run("independent transactions paying fees are not re-executed", func(t *testing.T) {
+		ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain), fvm.WithASTCache(astCache))
+
+		serial, serialView, _ := execute(t, ctx, blockOf(stores), 1)
+		parallel, parallelView, runs := execute(t, ctx, blockOf(stores), 4)
+
+		requireSameResults(t, serial, serialView, parallel, parallelView)
+
+		// all transactions and the system chunk are executed exactly once,
+		// even though all of them deposit their fees into the same vault
+		assert.Equal(t, int64(len(stores)+1), runs)
+	})
+}
@@ -52,6 +52,7 @@ func New(
 	protoState protocol.State,
 	vm VirtualMachine,
 	vmCtx fvm.Context,
+	parallelism uint,
 ) (*Manager, error) {
 	log := logger.With().Str("engine", "computation").Logger()
 
@@ -61,6 +62,7 @@ func New(
 		metrics,
 		tracer,
 		log.With().Str("component", "block_computer").Logger(),
+		parallelism,
 	)
 
 	if err != nil {
@@ -90,7 +92,7 @@ func (e *Manager) ExecuteScript(code []byte, arguments [][]byte, blockHeader *fl
 	}
 
 	if script.Err != nil {
-		return nil, fmt.Errorf("failed to execute script at block (%s): %s", blockHeader.ID(), script.Err.Error())
+		return nil, fmt.Errorf("failed to execute script at block (%s): %w", blockHeader.ID(), script.Err)
 	}
 
 	encodedValue, err := jsoncdc.Encode(script.Value)
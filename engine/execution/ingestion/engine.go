@@ -20,8 +20,10 @@ import (
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/engine/execution/utils"
+	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/mempool/entity"
@@ -59,6 +61,7 @@ type Engine struct {
 	tracer             module.Tracer
 	extensiveLogging   bool
 	spockHasher        hash.Hasher
+	retention          state.RetentionPolicy
 	syncThreshold      int                 // the threshold for how many sealed unexecuted blocks to trigger state syncing.
 	syncFilter         flow.IdentityFilter // specify the filter to sync state from
 	syncConduit        network.Conduit     // sending state syncing requests
@@ -87,6 +90,7 @@ func New(
 	syncDeltas mempool.Deltas,
 	syncThreshold int,
 	syncFast bool,
+	retention state.RetentionPolicy,
 ) (*Engine, error) {
 	log := logger.With().Str("engine", "ingestion").Logger()
 
@@ -116,6 +120,7 @@ func New(
 		syncThreshold:      syncThreshold,
 		syncDeltas:         syncDeltas,
 		syncFast:           syncFast,
+		retention:          retention,
 	}
 
 	// move to state syncing engine
@@ -566,6 +571,12 @@ func (e *Engine) executeBlock(ctx context.Context, executableBlock *entity.Execu
 		}
 	}
 
+	// the register delta is pushed even if the block is sealed, as access nodes index the registers of every block
+	err = e.providerEngine.BroadcastRegisterDelta(ctx, registerDelta(computationResult, finalState))
+	if err != nil {
+		e.log.Err(err).Msg("failed to broadcast the register delta")
+	}
+
 	e.log.Info().
 		Hex("block_id", logging.Entity(executableBlock)).
 		Hex("parent_block", executableBlock.Block.Header.ParentID[:]).
@@ -957,6 +968,11 @@ func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, argu
 		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
+	err = e.ensureStateAvailable(ctx, block, stateCommit)
+	if err != nil {
+		return nil, err
+	}
+
 	blockView := e.execState.NewView(stateCommit)
 
 	if e.extensiveLogging {
@@ -986,11 +1002,46 @@ func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow
 		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
+	err = e.ensureStateAvailable(ctx, block, stateCommit)
+	if err != nil {
+		return nil, err
+	}
+
 	blockView := e.execState.NewView(stateCommit)
 
 	return e.computationManager.GetAccount(addr, block, blockView)
 }
 
+// ensureStateAvailable makes sure the state of the given block can be read. States evicted from memory are
+// restored if they are retained by the retention policy, otherwise a ledger.ErrStatePruned is returned.
+func (e *Engine) ensureStateAvailable(ctx context.Context, block *flow.Header, stateCommit flow.StateCommitment) error {
+	if e.execState.HasState(stateCommit) {
+		return nil
+	}
+
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed block: %w", err)
+	}
+
+	if !e.retention.Retains(block.Height, sealed.Height) {
+		return fmt.Errorf("state of block (%s) at height %d is not retained: %w", block.ID(), block.Height, ledger.NewErrStatePruned(ledger.State(stateCommit)))
+	}
+
+	e.log.Info().
+		Hex("block_id", logging.Entity(block)).
+		Uint64("block_height", block.Height).
+		Hex("state_commitment", stateCommit).
+		Msg("restoring retained state")
+
+	err = e.execState.RestoreState(ctx, stateCommit)
+	if err != nil {
+		return fmt.Errorf("could not restore state of block (%s): %w", block.ID(), err)
+	}
+
+	return nil
+}
+
 func (e *Engine) handleComputationResult(
 	ctx context.Context,
 	result *execution.ComputationResult,
@@ -1049,6 +1100,16 @@ func (e *Engine) handleComputationResult(
 	return finalState, receipt, nil
 }
 
+// registerDelta returns the register delta of an executed block, with the registers updated by its chunks
+func registerDelta(result *execution.ComputationResult, endState flow.StateCommitment) *messages.ExecutionRegisterDelta {
+	snapshots := make([]*delta.Snapshot, len(result.StateSnapshots))
+	for i, stateSnapshot := range result.StateSnapshots {
+		snapshots[i] = &stateSnapshot.Snapshot
+	}
+
+	return provider.NewRegisterDelta(result.ExecutableBlock.Block.Header, endState, snapshots)
+}
+
 // save the execution result of a block
 func (e *Engine) saveExecutionResults(
 	ctx context.Context,
@@ -1093,7 +1154,7 @@ func (e *Engine) saveExecutionResults(
 			collectionID = flow.ZeroID
 		}
 
-		chunk := generateChunk(i, startState, endState, collectionID, blockID)
+		chunk := GenerateChunk(i, startState, endState, collectionID, blockID)
 
 		// chunkDataPack
 		allRegisters := view.AllRegisters()
@@ -1222,8 +1283,8 @@ func (e *Engine) logExecutableBlock(eb *entity.ExecutableBlock) {
 	}
 }
 
-// generateChunk creates a chunk from the provided computation data.
-func generateChunk(colIndex int,
+// GenerateChunk creates a chunk from the provided computation data.
+func GenerateChunk(colIndex int,
 	startState, endState flow.StateCommitment,
 	colID, blockID flow.Identifier) *flow.Chunk {
 	return &flow.Chunk{
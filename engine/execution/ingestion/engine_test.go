@@ -18,12 +18,14 @@ import (
 	engineCommon "github.com/onflow/flow-go/engine"
 	computation "github.com/onflow/flow-go/engine/execution/computation/mock"
 	provider "github.com/onflow/flow-go/engine/execution/provider/mock"
+	exeState "github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	state "github.com/onflow/flow-go/engine/execution/state/mock"
 	executionUnittest "github.com/onflow/flow-go/engine/execution/state/unittest"
 	"github.com/onflow/flow-go/engine/testutil/mocklocal"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module/mempool/entity"
 	"github.com/onflow/flow-go/module/metrics"
 	module "github.com/onflow/flow-go/module/mocks"
@@ -162,6 +164,7 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 		deltas,
 		10,
 		false,
+		exeState.RetentionPolicy{},
 	)
 	require.NoError(t, err)
 
@@ -281,6 +284,17 @@ func (ctx *testingContext) assertSuccessfulBlockComputation(executableBlock *ent
 
 		}).
 		Return(nil)
+
+	ctx.providerEngine.
+		On(
+			"BroadcastRegisterDelta",
+			mock.Anything,
+			mock.MatchedBy(func(registerDelta *messages.ExecutionRegisterDelta) bool {
+				return registerDelta.BlockID == executableBlock.Block.ID() &&
+					registerDelta.Height == executableBlock.Block.Header.Height
+			}),
+		).
+		Return(nil)
 }
 
 func (ctx *testingContext) stateCommitmentExist(blockID flow.Identifier, commit flow.StateCommitment) {
@@ -331,7 +345,7 @@ func (ctx *testingContext) mockStateCommitsWithMap(commits map[flow.Identifier]f
 func TestChunkIndexIsSet(t *testing.T) {
 
 	i := mathRand.Int()
-	chunk := generateChunk(i, unittest.StateCommitmentFixture(), unittest.StateCommitmentFixture(), unittest.IdentifierFixture(), unittest.IdentifierFixture())
+	chunk := GenerateChunk(i, unittest.StateCommitmentFixture(), unittest.StateCommitmentFixture(), unittest.IdentifierFixture(), unittest.IdentifierFixture())
 
 	assert.Equal(t, i, int(chunk.Index))
 	assert.Equal(t, i, int(chunk.CollectionIndex))
@@ -632,6 +646,7 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mocks.Executi
 		deltas,
 		10,
 		false,
+		exeState.RetentionPolicy{},
 	)
 
 	require.NoError(t, err)
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/model/messages"
@@ -24,6 +26,7 @@ import (
 type ProviderEngine interface {
 	network.Engine
 	BroadcastExecutionReceipt(context.Context, *flow.ExecutionReceipt) error
+	BroadcastRegisterDelta(context.Context, *messages.ExecutionRegisterDelta) error
 }
 
 // An Engine provides means of accessing data about execution state and broadcasts execution receipts to nodes in the network.
@@ -37,6 +40,7 @@ type Engine struct {
 	execState     state.ReadOnlyExecutionState
 	me            module.Local
 	chunksConduit network.Conduit
+	deltasConduit network.Conduit // nil if the register deltas are not pushed to access nodes
 	metrics       module.ExecutionMetrics
 }
 
@@ -48,6 +52,7 @@ func New(
 	me module.Local,
 	execState state.ReadOnlyExecutionState,
 	metrics module.ExecutionMetrics,
+	pushStateDeltas bool,
 ) (*Engine, error) {
 
 	log := logger.With().Str("engine", "receipts").Logger()
@@ -75,6 +80,13 @@ func New(
 	}
 	eng.chunksConduit = chunksConduit
 
+	if pushStateDeltas {
+		eng.deltasConduit, err = net.Register(engine.PushStateDeltas, &eng)
+		if err != nil {
+			return nil, fmt.Errorf("could not register register delta provider engine: %w", err)
+		}
+	}
+
 	return &eng, nil
 }
 
@@ -122,6 +134,12 @@ func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 			return fmt.Errorf("could not answer chunk data request: %w", err)
 		}
 		return err
+	case *messages.ExecutionRegisterDeltaRequest:
+		err := e.onRegisterDeltaRequest(ctx, originID, v)
+		if err != nil {
+			return fmt.Errorf("could not answer register delta request: %w", err)
+		}
+		return nil
 	default:
 		return fmt.Errorf("invalid event type (%T)", event)
 	}
@@ -228,3 +246,101 @@ func (e *Engine) BroadcastExecutionReceipt(ctx context.Context, receipt *flow.Ex
 
 	return nil
 }
+
+// onRegisterDeltaRequest receives a request for the register delta of an executed block from an access node, which
+// lost the register delta pushed to it. If the block was executed, its register delta is sent to the requester.
+func (e *Engine) onRegisterDeltaRequest(
+	ctx context.Context,
+	originID flow.Identifier,
+	req *messages.ExecutionRegisterDeltaRequest,
+) error {
+
+	log := e.log.With().
+		Hex("origin_id", logging.ID(originID)).
+		Hex("block_id", logging.ID(req.BlockID)).
+		Logger()
+
+	log.Debug().Msg("received register delta request")
+
+	origin, err := e.state.Final().Identity(originID)
+	if err != nil {
+		return engine.NewInvalidInputErrorf("invalid origin id (%s): %w", originID, err)
+	}
+
+	// only access nodes index the registers
+	if origin.Role != flow.RoleAccess {
+		return engine.NewInvalidInputErrorf("invalid role for requesting register delta: %s", origin.Role)
+	}
+
+	stateDelta, err := e.execState.RetrieveStateDelta(ctx, req.BlockID)
+	// we might not have executed the block yet, or not anymore have its state delta
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Warn().Msg("state delta not found")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not retrieve state delta of block %x: %w", req.BlockID, err)
+	}
+
+	registerDelta := NewRegisterDelta(stateDelta.Block.Header, stateDelta.EndState, stateDelta.StateInteractions)
+
+	err = e.deltasConduit.Unicast(registerDelta, originID)
+	if err != nil {
+		return fmt.Errorf("could not send requested register delta to (%s): %w", originID, err)
+	}
+
+	log.Debug().Msg("register delta request successfully replied")
+
+	return nil
+}
+
+// BroadcastRegisterDelta pushes the register delta of an executed block to the access nodes, so they can index
+// the registers it updates. It is a no-op if pushing register deltas is disabled.
+func (e *Engine) BroadcastRegisterDelta(ctx context.Context, delta *messages.ExecutionRegisterDelta) error {
+	if e.deltasConduit == nil {
+		return nil
+	}
+
+	span, _ := e.tracer.StartSpanFromContext(ctx, trace.EXEBroadcastRegisterDelta)
+	defer span.Finish()
+
+	e.log.Debug().
+		Hex("block_id", logging.ID(delta.BlockID)).
+		Hex("end_state", delta.EndState).
+		Int("registers", len(delta.Registers)).
+		Msg("broadcasting register delta")
+
+	identities, err := e.state.Final().Identities(filter.HasRole(flow.RoleAccess))
+	if err != nil {
+		return fmt.Errorf("could not get access identities: %w", err)
+	}
+
+	err = e.deltasConduit.Publish(delta, identities.NodeIDs()...)
+	if err != nil {
+		return fmt.Errorf("could not submit register delta: %w", err)
+	}
+
+	return nil
+}
+
+// NewRegisterDelta returns the register delta of an executed block, with the registers updated by its chunks. The
+// registers are sorted, so the execution nodes agreeing on the execution of the block send identical register deltas.
+func NewRegisterDelta(header *flow.Header, endState flow.StateCommitment, snapshots []*delta.Snapshot) *messages.ExecutionRegisterDelta {
+	updates := delta.NewDelta()
+	for _, snapshot := range snapshots {
+		updates.MergeWith(snapshot.Delta)
+	}
+
+	registers := make(flow.RegisterEntries, 0, len(updates.Data))
+	for _, entry := range updates.Data {
+		registers = append(registers, entry)
+	}
+	sort.Sort(registers)
+
+	return &messages.ExecutionRegisterDelta{
+		BlockID:   header.ID(),
+		Height:    header.Height,
+		EndState:  endState,
+		Registers: registers,
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/engine/execution/state/delta"
 	state "github.com/onflow/flow-go/engine/execution/state/mock"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/messages"
@@ -125,3 +126,82 @@ func TestProviderEngine_onChunkDataRequest(t *testing.T) {
 		execState.AssertExpectations(t)
 	})
 }
+
+func TestProviderEngine_onRegisterDeltaRequest(t *testing.T) {
+	t.Run("non-access node", func(t *testing.T) {
+		ps := new(protocol.State)
+		ss := new(protocol.Snapshot)
+
+		execState := new(state.ExecutionState)
+
+		e := Engine{state: ps, execState: execState, metrics: metrics.NewNoopCollector()}
+
+		originID := unittest.IdentifierFixture()
+
+		ps.On("Final").Return(ss)
+		ss.On("Identity", originID).
+			Return(unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution)), nil)
+
+		req := &messages.ExecutionRegisterDeltaRequest{
+			BlockID: unittest.IdentifierFixture(),
+			Nonce:   rand.Uint64(),
+		}
+		// submit using origin ID with invalid role
+		err := e.onRegisterDeltaRequest(context.Background(), originID, req)
+		assert.Error(t, err)
+
+		ps.AssertExpectations(t)
+		ss.AssertExpectations(t)
+		execState.AssertExpectations(t)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ps := new(protocol.State)
+		ss := new(protocol.Snapshot)
+		con := new(mocknetwork.Conduit)
+
+		execState := new(state.ExecutionState)
+
+		e := Engine{state: ps, deltasConduit: con, execState: execState, metrics: metrics.NewNoopCollector()}
+
+		originIdentity := unittest.IdentityFixture(unittest.WithRole(flow.RoleAccess))
+
+		stateDelta := unittest.StateDeltaFixture()
+		register := flow.NewRegisterID("owner", "", "key")
+		first := delta.NewDelta()
+		first.Set(register.Owner, register.Controller, register.Key, []byte("first"))
+		second := delta.NewDelta()
+		second.Set(register.Owner, register.Controller, register.Key, []byte("second"))
+		stateDelta.StateInteractions = []*delta.Snapshot{{Delta: first}, {Delta: second}}
+
+		ps.On("Final").Return(ss)
+		ss.On("Identity", originIdentity.NodeID).Return(originIdentity, nil)
+		con.On("Unicast", mock.Anything, originIdentity.NodeID).
+			Run(func(args mock.Arguments) {
+				res, ok := args[0].(*messages.ExecutionRegisterDelta)
+				require.True(t, ok)
+
+				assert.Equal(t, stateDelta.ID(), res.BlockID)
+				assert.Equal(t, stateDelta.EndState, res.EndState)
+				assert.Equal(t, flow.RegisterEntries{{Key: register, Value: []byte("second")}}, res.Registers)
+			}).
+			Return(nil)
+
+		execState.
+			On("RetrieveStateDelta", mock.Anything, stateDelta.ID()).
+			Return(stateDelta, nil)
+
+		req := &messages.ExecutionRegisterDeltaRequest{
+			BlockID: stateDelta.ID(),
+			Nonce:   rand.Uint64(),
+		}
+
+		err := e.onRegisterDeltaRequest(context.Background(), originIdentity.NodeID, req)
+		assert.NoError(t, err)
+
+		ps.AssertExpectations(t)
+		ss.AssertExpectations(t)
+		con.AssertExpectations(t)
+		execState.AssertExpectations(t)
+	})
+}
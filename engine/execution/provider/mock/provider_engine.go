@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	flow "github.com/onflow/flow-go/model/flow"
+	messages "github.com/onflow/flow-go/model/messages"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -28,6 +30,20 @@ func (_m *ProviderEngine) BroadcastExecutionReceipt(_a0 context.Context, _a1 *fl
 	return r0
 }
 
+// BroadcastRegisterDelta provides a mock function with given fields: _a0, _a1
+func (_m *ProviderEngine) BroadcastRegisterDelta(_a0 context.Context, _a1 *messages.ExecutionRegisterDelta) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *messages.ExecutionRegisterDelta) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Process provides a mock function with given fields: originID, event
 func (_m *ProviderEngine) Process(originID flow.Identifier, event interface{}) error {
 	ret := _m.Called(originID, event)
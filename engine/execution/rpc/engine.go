@@ -15,6 +15,8 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	grpcutils "github.com/onflow/flow-go/utils/grpc"
@@ -134,6 +136,14 @@ func (h *handler) ExecuteScriptAtBlockID(
 	}
 
 	value, err := h.engine.ExecuteScriptAtBlockID(ctx, req.GetScript(), req.GetArguments(), blockID)
+	if errors.Is(err, ledger.ErrStatePruned{}) {
+		return nil, status.Errorf(codes.NotFound, "state of block %s is not available anymore: %v", blockID, err)
+	}
+	var scriptErr fvm.Error
+	if errors.As(err, &scriptErr) {
+		// the script itself failed, e.g. it doesn't parse or it panicked
+		return nil, status.Errorf(codes.InvalidArgument, "failed to execute script: %v", err)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to execute script: %v", err)
 	}
@@ -145,6 +155,7 @@ func (h *handler) ExecuteScriptAtBlockID(
 	return res, nil
 }
 
+// GetEventsForBlockIDs returns the events of the given type of the blocks, or all their events if the type is empty.
 func (h *handler) GetEventsForBlockIDs(_ context.Context,
 	req *execution.GetEventsForBlockIDsRequest) (*execution.GetEventsForBlockIDsResponse, error) {
 
@@ -154,11 +165,7 @@ func (h *handler) GetEventsForBlockIDs(_ context.Context,
 	if err != nil {
 		return nil, err
 	}
-	reqEvent := req.GetType()
-	eType, err := convert.EventType(reqEvent)
-	if err != nil {
-		return nil, err
-	}
+	eType := req.GetType()
 
 	results := make([]*execution.GetEventsForBlockIDsResponse_Result, len(blockIDs))
 
@@ -173,7 +180,12 @@ func (h *handler) GetEventsForBlockIDs(_ context.Context,
 		}
 
 		// lookup events
-		blockEvents, err := h.events.ByBlockIDEventType(bID, flow.EventType(eType))
+		var blockEvents []flow.Event
+		if eType == "" {
+			blockEvents, err = h.events.ByBlockID(bID)
+		} else {
+			blockEvents, err = h.events.ByBlockIDEventType(bID, flow.EventType(eType))
+		}
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "failed to get events for block: %v", err)
 		}
@@ -278,6 +290,9 @@ func (h *handler) GetAccountAtBlockID(
 	}
 
 	value, err := h.engine.GetAccount(ctx, flowAddress, blockFlowID)
+	if errors.Is(err, ledger.ErrStatePruned{}) {
+		return nil, status.Errorf(codes.NotFound, "state of block %s is not available anymore: %v", blockFlowID, err)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get account: %v", err)
 	}
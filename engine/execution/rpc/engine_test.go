@@ -50,6 +50,8 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 	eventsPerBlock := 10
 
 	blockIDs := make([][]byte, totalBlocks)
+	blocks := make([]*flow.Block, totalBlocks)
+	eventsOf := make([][]flow.Event, totalBlocks)
 	expectedResult := make([]*execution.GetEventsForBlockIDsResponse_Result, totalBlocks)
 
 	// setup the events storage mock
@@ -58,6 +60,7 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 		block.Header.Height = uint64(i)
 		id := block.ID()
 		blockIDs[i] = id[:]
+		blocks[i] = &block
 		eventsForBlock := make([]flow.Event, eventsPerBlock)
 		eventMessages := make([]*entities.Event, eventsPerBlock)
 		for j := range eventsForBlock {
@@ -65,6 +68,7 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 			eventsForBlock[j] = e
 			eventMessages[j] = convert.EventToMessage(e)
 		}
+		eventsOf[i] = eventsForBlock
 		// expect one call to lookup result for each block ID
 		suite.exeResults.On("ByBlockID", id).Return(nil, nil).Once()
 
@@ -117,19 +121,24 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 		suite.events.AssertExpectations(suite.T())
 	})
 
-	// failure path - empty even type in the request results in an error
+	// happy path - empty event type in the request returns all events of the blocks
 	suite.Run("request with empty event type", func() {
 
+		for i, id := range blockIDs {
+			blockID := flow.HashToID(id)
+			suite.exeResults.On("ByBlockID", blockID).Return(nil, nil).Once()
+			suite.events.On("ByBlockID", blockID).Return(eventsOf[i], nil).Once()
+			suite.blocks.On("ByID", blockID).Return(blocks[i], nil).Once()
+		}
+
 		// create an API request with empty even type
 		req := concoctReq("", blockIDs)
 
-		_, err := handler.GetEventsForBlockIDs(context.Background(), req)
-
-		// check that an error was received
-		suite.Require().Error(err)
-		errors.Is(err, status.Error(codes.InvalidArgument, ""))
+		resp, err := handler.GetEventsForBlockIDs(context.Background(), req)
+		suite.Require().NoError(err)
+		suite.Require().ElementsMatch(expectedResult, resp.GetResults())
 
-		// check that no storage calls was made
+		// check that the events of the blocks were looked up regardless of their type
 		suite.events.AssertExpectations(suite.T())
 	})
 
@@ -23,6 +23,7 @@ type View struct {
 	// for views other than collection views to improve performance
 	spockSecretHasher hash.Hasher
 	readFunc          GetRegisterFunc
+	peekFunc          GetRegisterFunc
 }
 
 type Snapshot struct {
@@ -42,6 +43,7 @@ func NewView(readFunc GetRegisterFunc) *View {
 		delta:             NewDelta(),
 		regTouchSet:       make(map[string]flow.RegisterID),
 		readFunc:          readFunc,
+		peekFunc:          readFunc,
 		spockSecretHasher: hash.NewSHA3_256(),
 	}
 }
@@ -94,7 +96,9 @@ func (r *Snapshot) AllRegisters() []flow.RegisterID {
 
 // NewChild generates a new child view, with the current view as the base, sharing the Get function
 func (v *View) NewChild() *View {
-	return NewView(v.Get)
+	child := NewView(v.Get)
+	child.peekFunc = v.Peek
+	return child
 }
 
 // Get gets a register value from this view.
@@ -130,6 +134,20 @@ func (v *View) Get(owner, controller, key string) (flow.RegisterValue, error) {
 	return value, err
 }
 
+// Peek gets a register value from this view, without recording the read.
+//
+// Unlike Get, it neither captures a register touch nor updates the SPoCK secret
+// of this view or of any of its parents, and it is safe to call concurrently
+// as long as the views are not modified.
+func (v *View) Peek(owner, controller, key string) (flow.RegisterValue, error) {
+	value, exists := v.delta.Get(owner, controller, key)
+	if exists {
+		return value, nil
+	}
+
+	return v.peekFunc(owner, controller, key)
+}
+
 // Set sets a register value in this view.
 func (v *View) Set(owner, controller, key string, value flow.RegisterValue) error {
 	// every time we write something to delta (order preserving) we update spock
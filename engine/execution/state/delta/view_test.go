@@ -541,6 +541,58 @@ func TestView_Reads(t *testing.T) {
 	})
 }
 
+func TestView_Peek(t *testing.T) {
+	registerID1 := "fruit"
+	registerID2 := "vegetable"
+
+	v := delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		if owner == registerID1 {
+			return flow.RegisterValue("orange"), nil
+		}
+		return nil, nil
+	})
+
+	err := v.Set(registerID2, "", "", flow.RegisterValue("carrot"))
+	assert.NoError(t, err)
+
+	child := v.NewChild()
+	err = child.Set(registerID1, "", "", flow.RegisterValue("apple"))
+	assert.NoError(t, err)
+
+	parentSpock := v.SpockSecret()
+	childSpock := child.SpockSecret()
+
+	t.Run("ValueInParent", func(t *testing.T) {
+		b, err := v.NewChild().Peek(registerID2, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("carrot"), b)
+	})
+
+	t.Run("ValueNotInCache", func(t *testing.T) {
+		b, err := v.NewChild().Peek(registerID1, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("orange"), b)
+	})
+
+	t.Run("ValueInCache", func(t *testing.T) {
+		b, err := child.Peek(registerID1, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("apple"), b)
+	})
+
+	t.Run("NothingRecorded", func(t *testing.T) {
+		_, err := child.Peek(registerID2, "", "")
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(0), v.ReadsCount())
+		assert.Equal(t, uint64(0), child.ReadsCount())
+		assert.Equal(t, parentSpock, v.SpockSecret())
+		assert.Equal(t, childSpock, child.SpockSecret())
+		assert.Len(t, v.Interactions().Reads, 1)
+		assert.Len(t, child.Interactions().Reads, 1)
+	})
+}
+
 func hashIt(spock hash.Hasher, value []byte) error {
 	_, err := spock.Write(value)
 	return err
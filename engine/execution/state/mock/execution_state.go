@@ -186,6 +186,20 @@ func (_m *ExecutionState) GetRegisters(_a0 context.Context, _a1 []byte, _a2 []fl
 	return r0, r1
 }
 
+// HasState provides a mock function with given fields: _a0
+func (_m *ExecutionState) HasState(_a0 []byte) bool {
+	ret := _m.Called(_a0)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func([]byte) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // NewView provides a mock function with given fields: _a0
 func (_m *ExecutionState) NewView(_a0 []byte) *delta.View {
 	ret := _m.Called(_a0)
@@ -272,6 +286,20 @@ func (_m *ExecutionState) PersistStateInteractions(_a0 context.Context, _a1 flow
 	return r0
 }
 
+// RestoreState provides a mock function with given fields: _a0, _a1
+func (_m *ExecutionState) RestoreState(_a0 context.Context, _a1 []byte) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RetrieveStateDelta provides a mock function with given fields: _a0, _a1
 func (_m *ExecutionState) RetrieveStateDelta(_a0 context.Context, _a1 flow.Identifier) (*messages.ExecutionStateDelta, error) {
 	ret := _m.Called(_a0, _a1)
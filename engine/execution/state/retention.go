@@ -0,0 +1,20 @@
+package state
+
+// RetentionPolicy defines which historical states remain readable after the ledger evicted them from memory.
+// Retained states are restored from checkpoints and the WAL on demand, reading any other evicted state fails
+// with a ledger.ErrStatePruned. The zero value retains no evicted states.
+type RetentionPolicy struct {
+	// RecentHeights is the number of most recent sealed heights whose states are retained.
+	// States of heights which are not sealed yet are always retained.
+	RecentHeights uint64
+	// Interval additionally retains the states of every Interval-th height, 0 to disable
+	Interval uint64
+}
+
+// Retains returns true if the state of the given height is retained, given the latest sealed height
+func (p RetentionPolicy) Retains(height uint64, sealedHeight uint64) bool {
+	if height+p.RecentHeights > sealedHeight {
+		return true
+	}
+	return p.Interval > 0 && height%p.Interval == 0
+}
@@ -0,0 +1,35 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+
+	t.Run("zero value only retains unsealed heights", func(t *testing.T) {
+		policy := state.RetentionPolicy{}
+		assert.True(t, policy.Retains(101, 100))
+		assert.False(t, policy.Retains(100, 100))
+		assert.False(t, policy.Retains(0, 100))
+	})
+
+	t.Run("recent heights", func(t *testing.T) {
+		policy := state.RetentionPolicy{RecentHeights: 10}
+		assert.True(t, policy.Retains(100, 100))
+		assert.True(t, policy.Retains(91, 100))
+		assert.False(t, policy.Retains(90, 100))
+		assert.True(t, policy.Retains(0, 5))
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		policy := state.RetentionPolicy{RecentHeights: 10, Interval: 1000}
+		assert.True(t, policy.Retains(95, 100))
+		assert.False(t, policy.Retains(1999, 5000))
+		assert.True(t, policy.Retains(2000, 5000))
+		assert.True(t, policy.Retains(0, 5000))
+	})
+}
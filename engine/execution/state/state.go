@@ -49,6 +49,13 @@ type ReadOnlyExecutionState interface {
 	GetHighestExecutedBlockID(context.Context) (uint64, flow.Identifier, error)
 
 	GetCollection(identifier flow.Identifier) (*flow.Collection, error)
+
+	// HasState returns false if the given state has been evicted from memory by the ledger.
+	// Reading an evicted state fails with a ledger.ErrStatePruned, unless it is restored.
+	HasState(flow.StateCommitment) bool
+
+	// RestoreState restores the given state from checkpoints and the WAL, if it has been evicted from memory.
+	RestoreState(context.Context, flow.StateCommitment) error
 }
 
 // IsBlockExecuted returns whether the block has been executed.
@@ -135,6 +142,22 @@ func RegisterIDToKey(reg flow.RegisterID) ledger.Key {
 	})
 }
 
+// KeyToRegisterID returns the register ID of a ledger key, or an error if the key is not a register key.
+func KeyToRegisterID(key ledger.Key) (flow.RegisterID, error) {
+	if len(key.KeyParts) != 3 ||
+		key.KeyParts[0].Type != KeyPartOwner ||
+		key.KeyParts[1].Type != KeyPartController ||
+		key.KeyParts[2].Type != KeyPartKey {
+		return flow.RegisterID{}, fmt.Errorf("key not in expected format %s", key.String())
+	}
+
+	return flow.NewRegisterID(
+		string(key.KeyParts[0].Value),
+		string(key.KeyParts[1].Value),
+		string(key.KeyParts[2].Value),
+	), nil
+}
+
 // NewExecutionState returns a new execution state access layer for the given ledger storage.
 func NewExecutionState(
 	ls ledger.Ledger,
@@ -309,6 +332,29 @@ func (s *state) GetProof(
 	return proof, nil
 }
 
+// historicalLedger is implemented by ledgers which evict states from memory and can restore them
+// on demand, like the complete ledger. Other ledgers are assumed to hold all states.
+type historicalLedger interface {
+	HasState(state ledger.State) bool
+	RestoreState(state ledger.State) error
+}
+
+func (s *state) HasState(commit flow.StateCommitment) bool {
+	ls, ok := s.ls.(historicalLedger)
+	if !ok {
+		return true
+	}
+	return ls.HasState(ledger.State(commit))
+}
+
+func (s *state) RestoreState(ctx context.Context, commit flow.StateCommitment) error {
+	ls, ok := s.ls.(historicalLedger)
+	if !ok {
+		return nil
+	}
+	return ls.RestoreState(ledger.State(commit))
+}
+
 func (s *state) StateCommitmentByBlockID(ctx context.Context, blockID flow.Identifier) (flow.StateCommitment, error) {
 	return s.commits.ByBlockID(blockID)
 }
@@ -45,6 +45,49 @@ func getAccount(
 	return account, nil
 }
 
+// AccountStorage is the storage used by an account and its storage capacity, in bytes.
+type AccountStorage struct {
+	Used     uint64
+	Capacity uint64
+}
+
+// getAccountStorage returns the storage used by the account, as accounted on the state,
+// and its storage capacity, as computed by the storage fees contract of the service account.
+func getAccountStorage(
+	vm *VirtualMachine,
+	ctx Context,
+	st *state.State,
+	address flow.Address,
+) (*AccountStorage, error) {
+	exists, err := state.NewAccounts(st).Exists(address)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+
+	env, err := newEnvironment(ctx, vm, st)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := env.GetStorageUsed(common.BytesToAddress(address.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	capacity, err := env.GetStorageCapacity(common.BytesToAddress(address.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountStorage{
+		Used:     used,
+		Capacity: capacity,
+	}, nil
+}
+
 const initAccountTransactionTemplate = `
 import FlowServiceAccount from 0x%s
 
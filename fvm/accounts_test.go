@@ -701,3 +701,29 @@ func TestRemoveAccountKey(t *testing.T) {
 			}),
 	)
 }
+
+func TestGetAccountStorage(t *testing.T) {
+	t.Run("Existing account", newVMTest().
+		withBootstrapProcedureOptions(fvm.WithMinimumStorageReservation(fvm.DefaultMinimumStorageReservation)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			storage, err := vm.GetAccountStorage(ctx, chain.ServiceAddress(), ledger)
+			require.NoError(t, err)
+
+			used, err := state.NewAccounts(state.NewState(ledger)).GetStorageUsed(chain.ServiceAddress())
+			require.NoError(t, err)
+
+			assert.Equal(t, used, storage.Used)
+			assert.Greater(t, storage.Capacity, uint64(0))
+		}),
+	)
+
+	t.Run("Non-existent account", newVMTest().
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			address, err := chain.AddressAtIndex(100)
+			require.NoError(t, err)
+
+			_, err = vm.GetAccountStorage(ctx, address, ledger)
+			assert.Equal(t, fvm.ErrAccountNotFound, err)
+		}),
+	)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/onflow/cadence"
 	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
 
 	"github.com/onflow/flow-core-contracts/lib/go/contracts"
 
@@ -527,3 +528,21 @@ func FlowFeesAddress(chain flow.Chain) flow.Address {
 	address, _ := chain.AddressAtIndex(flowFeesAccountIndex)
 	return address
 }
+
+// CoreContractLocations returns the location IDs of the contracts deployed when bootstrapping the chain.
+func CoreContractLocations(chain flow.Chain) []common.LocationID {
+	location := func(address flow.Address, name string) common.LocationID {
+		return common.AddressLocation{
+			Address: common.BytesToAddress(address.Bytes()),
+			Name:    name,
+		}.ID()
+	}
+
+	return []common.LocationID{
+		location(FungibleTokenAddress(chain), "FungibleToken"),
+		location(FlowTokenAddress(chain), "FlowToken"),
+		location(FlowFeesAddress(chain), "FlowFees"),
+		location(chain.ServiceAddress(), "FlowServiceAccount"),
+		location(chain.ServiceAddress(), "FlowStorageFees"),
+	}
+}
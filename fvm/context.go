@@ -15,6 +15,8 @@ type Context struct {
 	Blocks                           Blocks
 	Metrics                          *MetricsCollector
 	GasLimit                         uint64
+	MeteringTable                    MeteringTable
+	FeeCalculator                    FeeCalculator
 	MaxStateKeySize                  uint64
 	MaxStateValueSize                uint64
 	MaxStateInteractionSize          uint64
@@ -25,6 +27,8 @@ type Context struct {
 	RestrictedDeploymentEnabled      bool
 	LimitAccountStorage              bool
 	CadenceLoggingEnabled            bool
+	TransactionTraceEnabled          bool
+	TransactionFeesDeferred          bool
 	SetValueHandler                  SetValueHandler
 	SignatureVerifier                SignatureVerifier
 	TransactionProcessors            []TransactionProcessor
@@ -67,6 +71,8 @@ func defaultContext(logger zerolog.Logger) Context {
 		Blocks:                           nil,
 		Metrics:                          nil,
 		GasLimit:                         DefaultGasLimit,
+		MeteringTable:                    nil,
+		FeeCalculator:                    nil,
 		MaxStateKeySize:                  state.DefaultMaxKeySize,
 		MaxStateValueSize:                state.DefaultMaxValueSize,
 		MaxStateInteractionSize:          state.DefaultMaxInteractionSize,
@@ -76,13 +82,15 @@ func defaultContext(logger zerolog.Logger) Context {
 		RestrictedAccountCreationEnabled: true,
 		RestrictedDeploymentEnabled:      true,
 		CadenceLoggingEnabled:            false,
+		TransactionTraceEnabled:          false,
+		TransactionFeesDeferred:          false,
 		SetValueHandler:                  nil,
 		SignatureVerifier:                NewDefaultSignatureVerifier(),
 		TransactionProcessors: []TransactionProcessor{
 			NewTransactionSignatureVerifier(AccountKeyWeightThreshold),
 			NewTransactionSequenceNumberChecker(),
-			NewTransactionFeeDeductor(),
 			NewTransactionInvocator(logger),
+			NewTransactionFeeDeductor(),
 			NewTransactionStorageLimiter(),
 		},
 		ScriptProcessors: []ScriptProcessor{
@@ -119,6 +127,29 @@ func WithGasLimit(limit uint64) Option {
 	}
 }
 
+// WithMeteringTable sets the computation charged for host environment operations
+// for a virtual machine context.
+//
+// The computation charged counts towards the gas limit, and is reported
+// in addition to the computation reported by Cadence.
+func WithMeteringTable(table MeteringTable) Option {
+	return func(ctx Context) Context {
+		ctx.MeteringTable = table
+		return ctx
+	}
+}
+
+// WithFeeCalculator sets the fee calculator for a virtual machine context.
+//
+// If no fee calculator is set, transaction fees are computed according to
+// the fee schedule of the chain of the context.
+func WithFeeCalculator(calculator FeeCalculator) Option {
+	return func(ctx Context) Context {
+		ctx.FeeCalculator = calculator
+		return ctx
+	}
+}
+
 // WithMaxStateKeySize sets the byte size limit for ledger keys
 func WithMaxStateKeySize(limit uint64) Option {
 	return func(ctx Context) Context {
@@ -219,6 +250,30 @@ func WithCadenceLogging(enabled bool) Option {
 	}
 }
 
+// WithTransactionTrace enables or disables tracing of transactions for a
+// virtual machine context.
+//
+// If enabled, all register accesses of a transaction and the events it emits
+// are recorded in the trace of the transaction procedure, including those
+// of the meta transactions it invokes, e.g. the fee deduction.
+func WithTransactionTrace(enabled bool) Option {
+	return func(ctx Context) Context {
+		ctx.TransactionTraceEnabled = enabled
+		return ctx
+	}
+}
+
+// WithTransactionFeesDeferred defers the fee deduction of transactions for a virtual machine context.
+//
+// If deferred, running a transaction stops before deducting its fee, and the changes of the transaction
+// are only written to the ledger once the fee is deducted with DeductDeferredFees.
+func WithTransactionFeesDeferred(deferred bool) Option {
+	return func(ctx Context) Context {
+		ctx.TransactionFeesDeferred = deferred
+		return ctx
+	}
+}
+
 // WithRestrictedAccountCreation enables or disables restricted account creation for a
 // virtual machine context
 func WithRestrictedAccountCreation(enabled bool) Option {
@@ -230,6 +285,8 @@ func WithRestrictedAccountCreation(enabled bool) Option {
 
 // WithSetValueHandler sets a handler that is called when a value is written
 // by the Cadence runtime.
+//
+// The handler must be safe for concurrent use, as procedures sharing the context may be run concurrently.
 func WithSetValueHandler(handler SetValueHandler) Option {
 	return func(ctx Context) Context {
 		ctx.SetValueHandler = handler
@@ -36,11 +36,16 @@ type hostEnv struct {
 	totalEventByteSize uint64
 	logs               []string
 	totalGasUsed       uint64
+	computationMeter   *computationMeter
 	transactionEnv     *transactionEnv
 	rng                *rand.Rand
 }
 
 func (e *hostEnv) Hash(data []byte, hashAlgorithm string) ([]byte, error) {
+	err := e.meter(MeteringHash)
+	if err != nil {
+		return nil, err
+	}
 
 	hasher, err := crypto.NewHasher(crypto.StringToHashAlgorithm(hashAlgorithm))
 	if err != nil {
@@ -68,6 +73,7 @@ func newEnvironment(ctx Context, vm *VirtualMachine, st *state.State) (*hostEnv,
 		addressGenerator:   generator,
 		uuidGenerator:      uuidGenerator,
 		totalEventByteSize: uint64(0),
+		computationMeter:   newComputationMeter(ctx.MeteringTable, st),
 	}
 
 	if ctx.BlockHeader != nil {
@@ -113,20 +119,49 @@ func (e *hostEnv) getLogs() []string {
 	return e.logs
 }
 
+// getGasUsed returns the computation reported by Cadence,
+// and the computation charged for host environment operations
+func (e *hostEnv) getGasUsed() uint64 {
+	return e.totalGasUsed + e.computationMeter.computationUsed()
+}
+
+// meter charges the computation of a host environment operation,
+// and fails if the computation limit is exceeded
+func (e *hostEnv) meter(operation MeteringOperation) error {
+	return e.computationMeter.meter(operation, e.GetComputationLimit())
+}
+
+// checkComputationLimit fails if the computation charged for host environment operations
+// exceeds the computation limit
+func (e *hostEnv) checkComputationLimit() error {
+	return e.computationMeter.check(e.GetComputationLimit())
+}
+
 func (e *hostEnv) GetValue(owner, key []byte) ([]byte, error) {
 	v, _ := e.accounts.GetValue(
 		flow.BytesToAddress(owner),
 		string(key),
 	)
+
+	err := e.meter(MeteringGetValue)
+	if err != nil {
+		return nil, err
+	}
+
 	return v, nil
 }
 
 func (e *hostEnv) SetValue(owner, key, value []byte) error {
-	return e.accounts.SetValue(
+	err := e.accounts.SetValue(
 		flow.BytesToAddress(owner),
 		string(key),
 		value,
 	)
+	if err != nil {
+		return err
+	}
+
+	return e.meter(MeteringSetValue)
 }
 
 func (e *hostEnv) ValueExists(owner, key []byte) (exists bool, err error) {
@@ -143,6 +178,11 @@ func (e *hostEnv) GetStorageUsed(address common.Address) (value uint64, err erro
 }
 
 func (e *hostEnv) GetStorageCapacity(address common.Address) (value uint64, err error) {
+	err = e.meter(MeteringGetStorageCapacity)
+	if err != nil {
+		return 0, err
+	}
+
 	script := getStorageCapacityScript(flow.BytesToAddress(address.Bytes()), e.ctx.Chain.ServiceAddress())
 
 	err = e.vm.Run(
@@ -170,6 +210,11 @@ func (e *hostEnv) GetStorageCapacity(address common.Address) (value uint64, err
 }
 
 func (e *hostEnv) GetAccountBalance(address common.Address) (value uint64, err error) {
+	err = e.meter(MeteringGetAccountBalance)
+	if err != nil {
+		return 0, err
+	}
+
 	script := getFlowTokenBalanceScript(flow.BytesToAddress(address.Bytes()), e.ctx.Chain.ServiceAddress())
 
 	err = e.vm.Run(
@@ -279,6 +324,8 @@ func (e *hostEnv) GetCachedProgram(location common.Location) (*ast.Program, erro
 
 	program, err := e.ctx.ASTCache.GetProgram(location)
 	if program != nil {
+		e.recordImports(location, program)
+
 		// Program was found within cache, do an explicit ledger register touch
 		// to ensure consistent reads during chunk verification.
 		if addressLocation, ok := location.(common.AddressLocation); ok {
@@ -291,6 +338,8 @@ func (e *hostEnv) GetCachedProgram(location common.Location) (*ast.Program, erro
 }
 
 func (e *hostEnv) CacheProgram(location common.Location, program *ast.Program) error {
+	e.recordImports(location, program)
+
 	if e.ctx.ASTCache == nil {
 		return nil
 	}
@@ -299,6 +348,37 @@ func (e *hostEnv) CacheProgram(location common.Location, program *ast.Program) e
 	return e.ctx.ASTCache.SetProgram(location, program)
 }
 
+// recordImports records the contracts imported at the top level of a transaction or script
+// in the metrics collector of the context.
+func (e *hostEnv) recordImports(location common.Location, program *ast.Program) {
+	if _, ok := location.(common.AddressLocation); ok {
+		return
+	}
+
+	var imports []common.LocationID
+
+	for _, declaration := range program.ImportDeclarations() {
+		addressLocation, ok := declaration.Location.(common.AddressLocation)
+		if !ok {
+			continue
+		}
+
+		if len(declaration.Identifiers) == 0 {
+			imports = append(imports, addressLocation.ID())
+			continue
+		}
+
+		for _, identifier := range declaration.Identifiers {
+			imports = append(imports, common.AddressLocation{
+				Address: addressLocation.Address,
+				Name:    identifier.Identifier,
+			}.ID())
+		}
+	}
+
+	e.ctx.Metrics.programImports(location, imports)
+}
+
 func (e *hostEnv) Log(message string) error {
 	if e.ctx.CadenceLoggingEnabled {
 		e.logs = append(e.logs, message)
@@ -312,6 +392,11 @@ func (e *hostEnv) EmitEvent(event cadence.Event) error {
 		return errors.New("emitting events is not supported")
 	}
 
+	err := e.meter(MeteringEmitEvent)
+	if err != nil {
+		return err
+	}
+
 	payload, err := jsoncdc.Encode(event)
 	if err != nil {
 		return fmt.Errorf("failed to json encode a cadence event: %w", err)
@@ -342,10 +427,16 @@ func (e *hostEnv) EmitEvent(event cadence.Event) error {
 	}
 
 	e.events = append(e.events, flowEvent)
+	e.st.RecordEvent(flowEvent)
 	return nil
 }
 
 func (e *hostEnv) GenerateUUID() (uint64, error) {
+	err := e.meter(MeteringGenerateUUID)
+	if err != nil {
+		return 0, err
+	}
+
 	// TODO add not supported
 	uuid, err := e.uuidGenerator.GenerateUUID()
 	return uuid, err
@@ -384,6 +475,11 @@ func (e *hostEnv) VerifySignature(
 	rawSigAlgo string,
 	rawHashAlgo string,
 ) (bool, error) {
+	err := e.meter(MeteringVerifySignature)
+	if err != nil {
+		return false, err
+	}
+
 	valid, err := verifySignatureFromRuntime(
 		e.ctx.SignatureVerifier,
 		signature,
@@ -441,6 +537,11 @@ func runtimeBlockFromHeader(header *flow.Header) runtime.Block {
 
 // GetBlockAtHeight returns the block at the given height.
 func (e *hostEnv) GetBlockAtHeight(height uint64) (runtime.Block, bool, error) {
+	err := e.meter(MeteringGetBlockAtHeight)
+	if err != nil {
+		return runtime.Block{}, false, err
+	}
+
 	if e.ctx.Blocks == nil {
 		return runtime.Block{}, false, errors.New("getting block information is not supported")
 	}
@@ -469,6 +570,11 @@ func (e *hostEnv) CreateAccount(payer runtime.Address) (address runtime.Address,
 		return runtime.Address{}, errors.New("creating accounts is not supported")
 	}
 
+	err = e.meter(MeteringCreateAccount)
+	if err != nil {
+		return runtime.Address{}, err
+	}
+
 	// TODO: improve error passing https://github.com/onflow/cadence/issues/202
 	return e.transactionEnv.CreateAccount(payer)
 }
@@ -478,6 +584,11 @@ func (e *hostEnv) AddAccountKey(address runtime.Address, publicKey []byte) error
 		return errors.New("adding account keys is not supported")
 	}
 
+	err := e.meter(MeteringAddAccountKey)
+	if err != nil {
+		return err
+	}
+
 	// TODO: improve error passing https://github.com/onflow/cadence/issues/202
 	return e.transactionEnv.AddAccountKey(address, publicKey)
 }
@@ -487,6 +598,11 @@ func (e *hostEnv) RemoveAccountKey(address runtime.Address, index int) (publicKe
 		return nil, errors.New("removing account keys is not supported")
 	}
 
+	err = e.meter(MeteringRemoveAccountKey)
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO: improve error passing https://github.com/onflow/cadence/issues/202
 	return e.transactionEnv.RemoveAccountKey(address, index)
 }
@@ -496,6 +612,11 @@ func (e *hostEnv) UpdateAccountContractCode(address runtime.Address, name string
 		return errors.New("updating account contract code is not supported")
 	}
 
+	err = e.meter(MeteringUpdateAccountContractCode)
+	if err != nil {
+		return err
+	}
+
 	// TODO: improve error passing https://github.com/onflow/cadence/issues/202
 	return e.transactionEnv.UpdateAccountContractCode(address, name, code)
 }
@@ -512,6 +633,11 @@ func (e *hostEnv) RemoveAccountContractCode(address runtime.Address, name string
 		return errors.New("removing account contracts is not supported")
 	}
 
+	err = e.meter(MeteringRemoveAccountContractCode)
+	if err != nil {
+		return err
+	}
+
 	// TODO: improve error passing https://github.com/onflow/cadence/issues/202
 	return e.transactionEnv.RemoveAccountContractCode(address, name)
 }
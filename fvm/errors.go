@@ -27,6 +27,10 @@ const (
 
 	errCodeStorageCapacityExceeded = 11
 
+	errCodeComputationLimitExceeded = 12
+
+	errCodeFeeOverflow = 13
+
 	errCodeEventLimitExceededError = 20
 
 	errCodeExecution = 100
@@ -251,6 +255,35 @@ func (e *StorageCapacityExceededError) Code() uint32 {
 	return errCodeStorageCapacityExceeded
 }
 
+// A ComputationLimitExceededError indicates that the computation charged for host environment operations
+// exceeded the computation limit.
+type ComputationLimitExceededError struct {
+	Used  uint64
+	Limit uint64
+}
+
+func (e *ComputationLimitExceededError) Error() string {
+	return fmt.Sprintf("metered computation (%d) exceeds limit (%d)", e.Used, e.Limit)
+}
+
+func (e *ComputationLimitExceededError) Code() uint32 {
+	return errCodeComputationLimitExceeded
+}
+
+// A FeeOverflowError indicates that the fee of a transaction overflows with its fee parameters.
+type FeeOverflowError struct {
+	Parameters FeeParameters
+}
+
+func (e *FeeOverflowError) Error() string {
+	return fmt.Sprintf("transaction fee overflows for inclusion effort %d, computation used %d and storage delta %d",
+		e.Parameters.InclusionEffort, e.Parameters.ComputationUsed, e.Parameters.StorageDelta)
+}
+
+func (e *FeeOverflowError) Code() uint32 {
+	return errCodeFeeOverflow
+}
+
 type ExecutionError struct {
 	Err runtime.Error
 }
@@ -295,6 +328,13 @@ func handleRuntimeError(err runtime.Error) (vmErr Error, fatalErr error) {
 		panic(externalErr.Recovered)
 	}
 
+	// Exceeding the computation limit in a host environment function
+	// is reported as is, instead of as a Cadence error.
+	var computationLimitErr *ComputationLimitExceededError
+	if errors.As(err, &computationLimitErr) {
+		return computationLimitErr, nil
+	}
+
 	// All other errors are non-fatal Cadence errors.
 	return &ExecutionError{Err: err}, nil
 }
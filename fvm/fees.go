@@ -2,25 +2,280 @@ package fvm
 
 import (
 	"fmt"
+	"math/bits"
 
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
 )
 
+// FeeParameters are the properties of a transaction its fee is computed from.
+type FeeParameters struct {
+	// InclusionEffort is the byte size of the script and the arguments of the transaction
+	InclusionEffort uint64
+	// ComputationUsed is the computation used by the transaction
+	ComputationUsed uint64
+	// StorageDelta is the change of the storage used by all accounts updated by the transaction, in bytes
+	StorageDelta int64
+}
+
+// A FeeCalculator computes the fee a transaction is charged.
+type FeeCalculator interface {
+	TransactionFee(vm *VirtualMachine, ctx Context, st *state.State, params FeeParameters) (cadence.UFix64, error)
+}
+
+// NewFeeCalculator returns the fee calculator implementing the given fee schedule.
+func NewFeeCalculator(schedule flow.TransactionFeeSchedule) FeeCalculator {
+	if schedule.Fixed {
+		return NewServiceAccountFeeCalculator()
+	}
+
+	return &LinearFeeCalculator{
+		InclusionEffortFee: schedule.InclusionEffortFee,
+		ComputationFee:     schedule.ComputationFee,
+		StorageFee:         schedule.StorageFee,
+	}
+}
+
+// ServiceAccountFeeCalculator charges every transaction the fixed transaction fee
+// set in the service account, regardless of its fee parameters.
+type ServiceAccountFeeCalculator struct{}
+
+func NewServiceAccountFeeCalculator() *ServiceAccountFeeCalculator {
+	return &ServiceAccountFeeCalculator{}
+}
+
+func (c *ServiceAccountFeeCalculator) TransactionFee(
+	vm *VirtualMachine,
+	ctx Context,
+	st *state.State,
+	_ FeeParameters,
+) (cadence.UFix64, error) {
+	script := getTransactionFeeScript(ctx.Chain.ServiceAddress())
+
+	err := vm.Run(ctx, script, st)
+	if err != nil {
+		return 0, err
+	}
+	if script.Err != nil {
+		return 0, script.Err
+	}
+
+	fee, ok := script.Value.(cadence.UFix64)
+	if !ok {
+		return 0, fmt.Errorf("transaction fee has unexpected type %T", script.Value)
+	}
+
+	return fee, nil
+}
+
+// LinearFeeCalculator charges transactions the given fees (in units of 1e-8 FLOW) per unit of inclusion effort,
+// per unit of computation used and per byte of storage added. Storage freed by a transaction is not refunded.
+type LinearFeeCalculator struct {
+	InclusionEffortFee uint64
+	ComputationFee     uint64
+	StorageFee         uint64
+}
+
+// TransactionFee returns the fee of a transaction with the given fee parameters,
+// or an error if the fee overflows.
+func (c *LinearFeeCalculator) TransactionFee(
+	_ *VirtualMachine,
+	_ Context,
+	_ *state.State,
+	params FeeParameters,
+) (cadence.UFix64, error) {
+	var storageAdded uint64
+	if params.StorageDelta > 0 {
+		storageAdded = uint64(params.StorageDelta)
+	}
+
+	var fee uint64
+	for _, charge := range []struct {
+		units uint64
+		fee   uint64
+	}{
+		{params.InclusionEffort, c.InclusionEffortFee},
+		{params.ComputationUsed, c.ComputationFee},
+		{storageAdded, c.StorageFee},
+	} {
+		overflow, product := bits.Mul64(charge.units, charge.fee)
+		if overflow != 0 {
+			return 0, &FeeOverflowError{Parameters: params}
+		}
+
+		var carry uint64
+		fee, carry = bits.Add64(fee, product, 0)
+		if carry != 0 {
+			return 0, &FeeOverflowError{Parameters: params}
+		}
+	}
+
+	return cadence.UFix64(fee), nil
+}
+
+// feeCalculator returns the fee calculator of the context,
+// or the one implementing the fee schedule of the chain if none is set.
+func feeCalculator(ctx Context) FeeCalculator {
+	if ctx.FeeCalculator != nil {
+		return ctx.FeeCalculator
+	}
+	return NewFeeCalculator(ctx.Chain.TransactionFeeSchedule())
+}
+
+// transactionFeeParameters returns the fee parameters of the transaction executed on the state.
+func transactionFeeParameters(proc *TransactionProcedure, st *state.State) (FeeParameters, error) {
+	storageDelta, err := storageDelta(st)
+	if err != nil {
+		return FeeParameters{}, err
+	}
+
+	return FeeParameters{
+		InclusionEffort: inclusionEffort(proc.Transaction),
+		ComputationUsed: proc.GasUsed,
+		StorageDelta:    storageDelta,
+	}, nil
+}
+
+// inclusionEffort returns the byte size of the script and the arguments of the transaction.
+//
+// Signatures are not included, so that the fee of a transaction can be estimated before it is signed.
+func inclusionEffort(tx *flow.TransactionBody) uint64 {
+	effort := uint64(len(tx.Script))
+	for _, argument := range tx.Arguments {
+		effort += uint64(len(argument))
+	}
+	return effort
+}
+
+// storageDelta returns the change of the storage used by all accounts updated on the state,
+// compared to the underlying ledger. Accounts created on the state start from zero storage used.
+func storageDelta(st *state.State) (int64, error) {
+	accounts := state.NewAccounts(st)
+	initialAccounts := state.NewAccounts(state.NewState(st.Ledger()))
+
+	var delta int64
+
+	for _, address := range st.UpdatedAddresses() {
+		used, err := storageUsed(accounts, address)
+		if err != nil {
+			return 0, err
+		}
+
+		initialUsed, err := storageUsed(initialAccounts, address)
+		if err != nil {
+			return 0, err
+		}
+
+		delta += int64(used) - int64(initialUsed)
+	}
+
+	return delta, nil
+}
+
+// storageUsed returns the storage used by the account, or zero if the account does not exist.
+func storageUsed(accounts *state.Accounts, address flow.Address) (uint64, error) {
+	exists, err := accounts.Exists(address)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	return accounts.GetStorageUsed(address)
+}
+
+// A FeeEstimate is the fee a transaction would be charged if it was executed.
+type FeeEstimate struct {
+	Fee        cadence.UFix64
+	Parameters FeeParameters
+	// Err is the error the transaction would fail with, in which case no fee is estimated
+	Err Error
+}
+
+// EstimateTransactionFee executes the transaction against the ledger and returns the fee it would be charged.
+//
+// The transaction is executed without verifying its signatures and sequence number,
+// so that the fee can be estimated before the transaction is signed. No changes are written to the ledger.
+func (vm *VirtualMachine) EstimateTransactionFee(ctx Context, tx *flow.TransactionBody, ledger state.Ledger) (*FeeEstimate, error) {
+	st := state.NewState(ledger,
+		state.WithMaxKeySizeAllowed(ctx.MaxStateKeySize),
+		state.WithMaxValueSizeAllowed(ctx.MaxStateValueSize),
+		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize))
+
+	proc := Transaction(tx, 0)
+
+	err := NewTransactionInvocator(zerolog.Nop()).Process(vm, ctx, proc, st)
+	vmErr, fatalErr := handleError(err)
+	if fatalErr != nil {
+		return nil, fatalErr
+	}
+	if vmErr != nil {
+		return &FeeEstimate{Err: vmErr}, nil
+	}
+
+	params, err := transactionFeeParameters(proc, st)
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute fee parameters: %w", err)
+	}
+
+	fee, err := feeCalculator(ctx).TransactionFee(vm, ctx, st, params)
+	vmErr, fatalErr = handleError(err)
+	if fatalErr != nil {
+		return nil, fmt.Errorf("cannot compute transaction fee: %w", fatalErr)
+	}
+	if vmErr != nil {
+		return &FeeEstimate{Parameters: params, Err: vmErr}, nil
+	}
+
+	return &FeeEstimate{
+		Fee:        fee,
+		Parameters: params,
+	}, nil
+}
+
+const getTransactionFeeScriptTemplate = `
+import FlowServiceAccount from 0x%s
+
+pub fun main(): UFix64 {
+  return FlowServiceAccount.transactionFee
+}
+`
+
+func getTransactionFeeScript(serviceAddress flow.Address) *ScriptProcedure {
+	return Script([]byte(fmt.Sprintf(getTransactionFeeScriptTemplate, serviceAddress)))
+}
+
 const deductTransactionFeeTransactionTemplate = `
 import FlowServiceAccount from 0x%s
+import FlowFees from 0x%s
 
-transaction {
+transaction(amount: UFix64) {
   prepare(account: AuthAccount) {
- 	FlowServiceAccount.deductTransactionFee(account)
+    let tokenVault = FlowServiceAccount.defaultTokenVault(account)
+    FlowFees.deposit(from: <-tokenVault.withdraw(amount: amount))
   }
 }
 `
 
-func deductTransactionFeeTransaction(accountAddress, serviceAddress flow.Address) *TransactionProcedure {
+func deductTransactionFeeTransaction(
+	accountAddress, serviceAddress, flowFeesAddress flow.Address,
+	amount cadence.UFix64,
+) (*TransactionProcedure, error) {
+	encodedAmount, err := jsoncdc.Encode(amount)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode transaction fee: %w", err)
+	}
+
 	return Transaction(
 		flow.NewTransactionBody().
-			SetScript([]byte(fmt.Sprintf(deductTransactionFeeTransactionTemplate, serviceAddress))).
+			SetScript([]byte(fmt.Sprintf(deductTransactionFeeTransactionTemplate, serviceAddress, flowFeesAddress))).
+			AddArgument(encodedAmount).
 			AddAuthorizer(accountAddress),
 		0,
-	)
+	), nil
 }
@@ -0,0 +1,288 @@
+package fvm_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/onflow/cadence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestLinearFeeCalculator(t *testing.T) {
+	calculator := &fvm.LinearFeeCalculator{
+		InclusionEffortFee: 1,
+		ComputationFee:     10,
+		StorageFee:         100,
+	}
+
+	t.Run("storage added", func(t *testing.T) {
+		fee, err := calculator.TransactionFee(nil, fvm.Context{}, nil, fvm.FeeParameters{
+			InclusionEffort: 3,
+			ComputationUsed: 2,
+			StorageDelta:    1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, cadence.UFix64(123), fee)
+	})
+
+	t.Run("storage freed is not refunded", func(t *testing.T) {
+		fee, err := calculator.TransactionFee(nil, fvm.Context{}, nil, fvm.FeeParameters{
+			InclusionEffort: 3,
+			ComputationUsed: 2,
+			StorageDelta:    -1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, cadence.UFix64(23), fee)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		for _, params := range []fvm.FeeParameters{
+			{ComputationUsed: math.MaxUint64 / 2},
+			{InclusionEffort: math.MaxUint64 - 1, ComputationUsed: 1},
+		} {
+			_, err := calculator.TransactionFee(nil, fvm.Context{}, nil, params)
+
+			var overflowErr *fvm.FeeOverflowError
+			require.True(t, errors.As(err, &overflowErr))
+			assert.Equal(t, params, overflowErr.Parameters)
+		}
+	})
+}
+
+func TestNewFeeCalculator(t *testing.T) {
+	calculator := fvm.NewFeeCalculator(flow.FixedTransactionFeeSchedule)
+	assert.IsType(t, &fvm.ServiceAccountFeeCalculator{}, calculator)
+
+	calculator = fvm.NewFeeCalculator(flow.TransactionFeeSchedule{ComputationFee: 1})
+	assert.Equal(t, &fvm.LinearFeeCalculator{ComputationFee: 1}, calculator)
+}
+
+func TestBlockContext_TransactionFees(t *testing.T) {
+	const storeScript = `
+		transaction {
+			prepare(signer: AuthAccount) {
+				signer.save("hello", to: /storage/hello)
+			}
+		}
+	`
+
+	const failingScript = `
+		transaction {
+			prepare(signer: AuthAccount) {
+				panic("fail")
+			}
+		}
+	`
+
+	const loopScript = `
+		transaction {
+			prepare(signer: AuthAccount) {
+				var i = 0
+				while i < 100 {
+					i = i + 1
+				}
+			}
+		}
+	`
+
+	newTransaction := func(t *testing.T, chain flow.Chain, script string, sequenceNumber uint64) *flow.TransactionBody {
+		txBody := flow.NewTransactionBody().
+			SetScript([]byte(script)).
+			AddAuthorizer(chain.ServiceAddress())
+
+		err := testutil.SignTransactionAsServiceAccount(txBody, sequenceNumber, chain)
+		require.NoError(t, err)
+
+		return txBody
+	}
+
+	balance := func(t *testing.T, vm *fvm.VirtualMachine, ctx fvm.Context, chain flow.Chain, ledger state.Ledger) uint64 {
+		account, err := vm.GetAccount(ctx, chain.ServiceAddress(), ledger)
+		require.NoError(t, err)
+		return account.Balance
+	}
+
+	linearCalculator := &fvm.LinearFeeCalculator{
+		InclusionEffortFee: 1,
+		StorageFee:         1,
+	}
+
+	t.Run("fixed fee is charged", newVMTest().
+		withBootstrapProcedureOptions(fvm.WithTransactionFee(fvm.DefaultTransactionFees)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			before := balance(t, vm, ctx, chain, ledger)
+
+			tx := fvm.Transaction(newTransaction(t, chain, storeScript, 0), 0)
+			err := vm.Run(ctx, tx, ledger)
+			require.NoError(t, err)
+			require.NoError(t, tx.Err)
+
+			after := balance(t, vm, ctx, chain, ledger)
+			assert.Equal(t, uint64(fvm.DefaultTransactionFees), before-after)
+		}))
+
+	t.Run("failed transaction is charged", newVMTest().
+		withBootstrapProcedureOptions(fvm.WithTransactionFee(fvm.DefaultTransactionFees)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			before := balance(t, vm, ctx, chain, ledger)
+
+			tx := fvm.Transaction(newTransaction(t, chain, failingScript, 0), 0)
+			err := vm.Run(ctx, tx, ledger)
+			require.NoError(t, err)
+			require.Error(t, tx.Err)
+
+			after := balance(t, vm, ctx, chain, ledger)
+			assert.Equal(t, uint64(fvm.DefaultTransactionFees), before-after)
+		}))
+
+	t.Run("deferred fee is charged once deducted", newVMTest().
+		withBootstrapProcedureOptions(fvm.WithTransactionFee(fvm.DefaultTransactionFees)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			view := ledger.(*delta.View)
+			before := balance(t, vm, ctx, chain, ledger)
+
+			txCtx := fvm.NewContextFromParent(ctx, fvm.WithTransactionFeesDeferred(true))
+			txView := view.NewChild()
+
+			tx := fvm.Transaction(newTransaction(t, chain, storeScript, 0), 0)
+			err := vm.Run(txCtx, tx, txView)
+			require.NoError(t, err)
+			require.NoError(t, tx.Err)
+
+			// nothing is written until the fee is deducted
+			ids, _ := txView.Delta().RegisterUpdates()
+			assert.Empty(t, ids)
+
+			err = tx.DeductDeferredFees()
+			require.NoError(t, err)
+			require.NoError(t, tx.Err)
+			view.MergeView(txView)
+
+			after := balance(t, vm, ctx, chain, ledger)
+			assert.Equal(t, uint64(fvm.DefaultTransactionFees), before-after)
+		}))
+
+	t.Run("deferred fee of failed transaction is charged", newVMTest().
+		withBootstrapProcedureOptions(fvm.WithTransactionFee(fvm.DefaultTransactionFees)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			before := balance(t, vm, ctx, chain, ledger)
+
+			txCtx := fvm.NewContextFromParent(ctx, fvm.WithTransactionFeesDeferred(true))
+
+			tx := fvm.Transaction(newTransaction(t, chain, failingScript, 0), 0)
+			err := vm.Run(txCtx, tx, ledger)
+			require.NoError(t, err)
+			require.Error(t, tx.Err)
+
+			err = tx.DeductDeferredFees()
+			require.NoError(t, err)
+
+			after := balance(t, vm, ctx, chain, ledger)
+			assert.Equal(t, uint64(fvm.DefaultTransactionFees), before-after)
+		}))
+
+	t.Run("fee calculator of the context is used", newVMTest().
+		withContextOptions(fvm.WithFeeCalculator(linearCalculator)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			before := balance(t, vm, ctx, chain, ledger)
+
+			txBody := newTransaction(t, chain, storeScript, 0)
+
+			tx := fvm.Transaction(txBody, 0)
+			err := vm.Run(ctx, tx, ledger)
+			require.NoError(t, err)
+			require.NoError(t, tx.Err)
+
+			after := balance(t, vm, ctx, chain, ledger)
+			// the value stored adds to the storage used, and is charged in addition to the script
+			assert.Greater(t, before-after, uint64(len(txBody.Script)))
+		}))
+
+	t.Run("failed transaction is charged for the computation used", newVMTest().
+		withContextOptions(fvm.WithFeeCalculator(&fvm.LinearFeeCalculator{
+			InclusionEffortFee: 1,
+			ComputationFee:     1,
+		})).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			before := balance(t, vm, ctx, chain, ledger)
+
+			txBody := flow.NewTransactionBody().
+				SetScript([]byte(loopScript)).
+				SetGasLimit(10).
+				AddAuthorizer(chain.ServiceAddress())
+
+			err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+			require.NoError(t, err)
+
+			tx := fvm.Transaction(txBody, 0)
+			err = vm.Run(ctx, tx, ledger)
+			require.NoError(t, err)
+			require.Error(t, tx.Err)
+
+			// the computation used up to the limit is charged, not the gas limit
+			assert.Greater(t, tx.GasUsed, txBody.GasLimit)
+
+			after := balance(t, vm, ctx, chain, ledger)
+			assert.Equal(t, uint64(len(txBody.Script))+tx.GasUsed, before-after)
+		}))
+
+	t.Run("estimate", newVMTest().
+		withContextOptions(fvm.WithFeeCalculator(linearCalculator)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			view := ledger.(*delta.View)
+			ids, _ := view.Delta().RegisterUpdates()
+			registerCount := len(ids)
+
+			txBody := flow.NewTransactionBody().
+				SetScript([]byte(storeScript)).
+				AddAuthorizer(chain.ServiceAddress())
+
+			estimate, err := vm.EstimateTransactionFee(ctx, txBody, ledger)
+			require.NoError(t, err)
+			require.NoError(t, estimate.Err)
+
+			assert.Equal(t, uint64(len(storeScript)), estimate.Parameters.InclusionEffort)
+			assert.Greater(t, estimate.Parameters.StorageDelta, int64(0))
+			assert.Equal(t,
+				cadence.UFix64(estimate.Parameters.InclusionEffort+uint64(estimate.Parameters.StorageDelta)),
+				estimate.Fee,
+			)
+
+			// nothing is written to the ledger
+			ids, _ = view.Delta().RegisterUpdates()
+			assert.Len(t, ids, registerCount)
+		}))
+
+	t.Run("estimate of fixed fee", newVMTest().
+		withBootstrapProcedureOptions(fvm.WithTransactionFee(fvm.DefaultTransactionFees)).
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			txBody := flow.NewTransactionBody().
+				SetScript([]byte(storeScript)).
+				AddAuthorizer(chain.ServiceAddress())
+
+			estimate, err := vm.EstimateTransactionFee(ctx, txBody, ledger)
+			require.NoError(t, err)
+			require.NoError(t, estimate.Err)
+
+			assert.Equal(t, fvm.DefaultTransactionFees, estimate.Fee)
+		}))
+
+	t.Run("estimate of failing transaction", newVMTest().
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			txBody := flow.NewTransactionBody().
+				SetScript([]byte(failingScript)).
+				AddAuthorizer(chain.ServiceAddress())
+
+			estimate, err := vm.EstimateTransactionFee(ctx, txBody, ledger)
+			require.NoError(t, err)
+			assert.Error(t, estimate.Err)
+		}))
+}
@@ -28,15 +28,29 @@ func New(rt runtime.Runtime) *VirtualMachine {
 // Run runs a procedure against a ledger in the given context.
 func (vm *VirtualMachine) Run(ctx Context, proc Procedure, ledger state.Ledger) error {
 
-	st := state.NewState(ledger,
+	opts := []state.StateOption{
 		state.WithMaxKeySizeAllowed(ctx.MaxStateKeySize),
 		state.WithMaxValueSizeAllowed(ctx.MaxStateValueSize),
-		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize))
+		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize),
+	}
+
+	if tx, ok := proc.(*TransactionProcedure); ok && ctx.TransactionTraceEnabled {
+		tx.Trace = state.NewTrace()
+		opts = append(opts, state.WithTrace(tx.Trace))
+	}
+
+	st := state.NewState(ledger, opts...)
 
 	err := proc.Run(vm, ctx, st)
 	if err != nil {
 		return err
 	}
+
+	if tx, ok := proc.(*TransactionProcedure); ok && tx.deferred != nil {
+		// the changes are written once the deferred fees are deducted
+		return nil
+	}
+
 	return st.Commit()
 }
 
@@ -61,20 +75,47 @@ func (vm *VirtualMachine) GetAccount(ctx Context, address flow.Address, ledger s
 	return account, nil
 }
 
+// GetAccountStorage returns the storage used by an account and its storage capacity, in bytes.
+func (vm *VirtualMachine) GetAccountStorage(ctx Context, address flow.Address, ledger state.Ledger) (*AccountStorage, error) {
+	st := state.NewState(ledger,
+		state.WithMaxKeySizeAllowed(ctx.MaxStateKeySize),
+		state.WithMaxValueSizeAllowed(ctx.MaxStateValueSize),
+		state.WithMaxInteractionSizeAllowed(ctx.MaxStateInteractionSize))
+
+	storage, err := getAccountStorage(vm, ctx, st, address)
+	if err != nil {
+		return nil, err
+	}
+	err = st.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return storage, nil
+}
+
 // invokeMetaTransaction invokes a meta transaction inside the context of an outer transaction.
 //
 // Errors that occur in a meta transaction are propagated as a single error that can be
 // captured by the Cadence runtime and eventually disambiguated by the parent context.
+//
+// The meta transaction is atomic: if it fails, all its changes are rolled back.
 func (vm *VirtualMachine) invokeMetaTransaction(ctx Context, tx *TransactionProcedure, st *state.State) error {
+	checkpoint := st.Checkpoint()
+
 	invocator := NewTransactionInvocator(zerolog.Nop())
 	err := invocator.Process(vm, ctx, tx, st)
-	if err != nil {
-		return err
+	if err == nil && tx.Err != nil {
+		err = tx.Err
 	}
 
-	if tx.Err != nil {
-		return tx.Err
+	if err != nil {
+		rollbackErr := st.RollbackTo(checkpoint)
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
 	}
 
-	return nil
+	return st.ReleaseCheckpoint(checkpoint)
 }
@@ -1369,3 +1369,224 @@ func TestEventLimits(t *testing.T) {
 		assert.NoError(t, tx.Err)
 	})
 }
+
+func TestBlockContext_ExecuteTransaction_Trace(t *testing.T) {
+	rt := runtime.NewInterpreterRuntime()
+
+	chain := flow.Testnet.Chain()
+
+	vm := fvm.New(rt)
+
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+
+	newTransaction := func(sequenceNumber uint64) *flow.TransactionBody {
+		txBody := flow.NewTransactionBody().
+			SetScript(createAccountScript).
+			AddAuthorizer(chain.ServiceAddress())
+
+		err := testutil.SignTransactionAsServiceAccount(txBody, sequenceNumber, chain)
+		require.NoError(t, err)
+
+		return txBody
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+		tx := fvm.Transaction(newTransaction(0), 0)
+
+		err := vm.Run(ctx, tx, ledger)
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+
+		assert.Nil(t, tx.Trace)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+		tracingCtx := fvm.NewContextFromParent(ctx, fvm.WithTransactionTrace(true))
+
+		tx := fvm.Transaction(newTransaction(0), 0)
+
+		err := vm.Run(tracingCtx, tx, ledger)
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+
+		require.NotNil(t, tx.Trace)
+		require.Len(t, tx.Events, 1)
+
+		operations := make(map[state.TraceOperation]int)
+		var events []flow.Event
+		for _, entry := range tx.Trace.Entries {
+			operations[entry.Operation]++
+			// events of meta transactions, e.g. the fee deduction, are traced as well
+			if entry.Operation == state.TraceEvent && entry.Event.TransactionID == tx.ID {
+				events = append(events, *entry.Event)
+			}
+		}
+
+		assert.Greater(t, operations[state.TraceGet], 0)
+		assert.Greater(t, operations[state.TraceSet], 0)
+		assert.Equal(t, tx.Events, events)
+	})
+}
+
+type failingTransactionProcessor struct{}
+
+func (failingTransactionProcessor) Process(
+	_ *fvm.VirtualMachine,
+	_ fvm.Context,
+	proc *fvm.TransactionProcedure,
+	_ *state.State,
+) error {
+	return &fvm.StorageCapacityExceededError{Address: proc.Transaction.Payer}
+}
+
+func TestBlockContext_ExecuteTransaction_Rollback(t *testing.T) {
+	rt := runtime.NewInterpreterRuntime()
+
+	chain := flow.MonotonicEmulator.Chain()
+
+	vm := fvm.New(rt)
+
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+
+	// a processor following the transaction body fails
+	processors := make([]fvm.TransactionProcessor, 0, len(ctx.TransactionProcessors)+1)
+	processors = append(processors, ctx.TransactionProcessors...)
+	processors = append(processors, failingTransactionProcessor{})
+
+	failingCtx := fvm.NewContextFromParent(ctx, fvm.WithTransactionProcessors(processors...))
+
+	newTransaction := func(sequenceNumber uint64) *flow.TransactionBody {
+		txBody := flow.NewTransactionBody().
+			SetScript(createAccountScript).
+			AddAuthorizer(chain.ServiceAddress())
+
+		err := testutil.SignTransactionAsServiceAccount(txBody, sequenceNumber, chain)
+		require.NoError(t, err)
+
+		return txBody
+	}
+
+	ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+	tx := fvm.Transaction(newTransaction(0), 0)
+
+	err := vm.Run(failingCtx, tx, ledger)
+	require.NoError(t, err)
+	require.Error(t, tx.Err)
+
+	// changes of the transaction body are rolled back
+	_, err = vm.GetAccount(ctx, flow.HexToAddress("05"), ledger)
+	assert.Equal(t, fvm.ErrAccountNotFound, err)
+
+	// sequence number increment is kept
+	serviceAccount, err := vm.GetAccount(ctx, chain.ServiceAddress(), ledger)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), serviceAccount.Keys[0].SeqNumber)
+
+	// the transaction body succeeds with the next sequence number
+	tx = fvm.Transaction(newTransaction(1), 1)
+
+	err = vm.Run(ctx, tx, ledger)
+	require.NoError(t, err)
+	require.NoError(t, tx.Err)
+
+	_, err = vm.GetAccount(ctx, flow.HexToAddress("05"), ledger)
+	assert.NoError(t, err)
+}
+
+func TestBlockContext_Metering(t *testing.T) {
+	rt := runtime.NewInterpreterRuntime()
+
+	chain := flow.Testnet.Chain()
+
+	vm := fvm.New(rt)
+
+	ctx := fvm.NewContext(zerolog.Nop(), fvm.WithChain(chain))
+
+	runTransaction := func(ctx fvm.Context, gasLimit uint64) *fvm.TransactionProcedure {
+		txBody := flow.NewTransactionBody().
+			SetScript(createAccountScript).
+			SetGasLimit(gasLimit).
+			AddAuthorizer(chain.ServiceAddress())
+
+		err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+		require.NoError(t, err)
+
+		ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+		tx := fvm.Transaction(txBody, 0)
+
+		err = vm.Run(ctx, tx, ledger)
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	t.Run("operations", func(t *testing.T) {
+		unmetered := runTransaction(ctx, 10_000)
+		require.NoError(t, unmetered.Err)
+
+		meteredCtx := fvm.NewContextFromParent(ctx, fvm.WithMeteringTable(fvm.MeteringTable{
+			fvm.MeteringCreateAccount: 1_000,
+		}))
+
+		metered := runTransaction(meteredCtx, 10_000)
+		require.NoError(t, metered.Err)
+
+		assert.Equal(t, unmetered.GasUsed+1_000, metered.GasUsed)
+	})
+
+	t.Run("bytes written", func(t *testing.T) {
+		meteredCtx := fvm.NewContextFromParent(ctx, fvm.WithMeteringTable(fvm.MeteringTable{
+			fvm.MeteringBytesWritten: 1,
+		}))
+
+		unmetered := runTransaction(ctx, 0)
+		require.NoError(t, unmetered.Err)
+
+		metered := runTransaction(meteredCtx, 0)
+		require.NoError(t, metered.Err)
+
+		assert.Greater(t, metered.GasUsed, unmetered.GasUsed)
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		meteredCtx := fvm.NewContextFromParent(ctx, fvm.WithMeteringTable(fvm.MeteringTable{
+			fvm.MeteringCreateAccount: 1_000,
+		}))
+
+		tx := runTransaction(meteredCtx, 999)
+		require.Error(t, tx.Err)
+		assert.Equal(t, (&fvm.ComputationLimitExceededError{}).Code(), tx.Err.Code())
+	})
+
+	t.Run("script", func(t *testing.T) {
+		code := []byte(fmt.Sprintf(`
+			pub fun main(): UInt64 {
+				return getAccount(0x%s).storageCapacity
+			}
+		`, chain.ServiceAddress()))
+
+		meteredCtx := fvm.NewContextFromParent(ctx, fvm.WithMeteringTable(fvm.MeteringTable{
+			fvm.MeteringGetStorageCapacity: 7,
+		}))
+
+		ledger := testutil.RootBootstrappedLedger(vm, ctx)
+
+		unmetered := fvm.Script(code)
+		err := vm.Run(ctx, unmetered, ledger)
+		require.NoError(t, err)
+		require.NoError(t, unmetered.Err)
+
+		metered := fvm.Script(code)
+		err = vm.Run(meteredCtx, metered, ledger)
+		require.NoError(t, err)
+		require.NoError(t, metered.Err)
+
+		assert.Equal(t, unmetered.GasUsed+7, metered.GasUsed)
+	})
+}
@@ -0,0 +1,97 @@
+package fvm
+
+import (
+	"github.com/onflow/flow-go/fvm/state"
+)
+
+// A MeteringOperation is an operation of the host environment that can be charged computation.
+type MeteringOperation string
+
+const (
+	MeteringGetValue                  MeteringOperation = "get_value"
+	MeteringSetValue                  MeteringOperation = "set_value"
+	MeteringCreateAccount             MeteringOperation = "create_account"
+	MeteringAddAccountKey             MeteringOperation = "add_account_key"
+	MeteringRemoveAccountKey          MeteringOperation = "remove_account_key"
+	MeteringUpdateAccountContractCode MeteringOperation = "update_account_contract_code"
+	MeteringRemoveAccountContractCode MeteringOperation = "remove_account_contract_code"
+	MeteringGetAccountBalance         MeteringOperation = "get_account_balance"
+	MeteringGetStorageCapacity        MeteringOperation = "get_storage_capacity"
+	MeteringVerifySignature           MeteringOperation = "verify_signature"
+	MeteringHash                      MeteringOperation = "hash"
+	MeteringEmitEvent                 MeteringOperation = "emit_event"
+	MeteringGenerateUUID              MeteringOperation = "generate_uuid"
+	MeteringGetBlockAtHeight          MeteringOperation = "get_block_at_height"
+
+	// MeteringBytesRead is charged per byte of the values read from the state
+	MeteringBytesRead MeteringOperation = "bytes_read"
+	// MeteringBytesWritten is charged per byte of the values written to the state
+	MeteringBytesWritten MeteringOperation = "bytes_written"
+)
+
+// A MeteringTable defines the computation charged for host environment operations,
+// in addition to the computation reported by Cadence.
+//
+// Operations are charged their weight per call, except MeteringBytesRead and MeteringBytesWritten,
+// which are charged their weight per byte. Operations that are not in the table are not charged.
+type MeteringTable map[MeteringOperation]uint64
+
+// computationMeter meters the computation used by host environment operations
+// according to a metering table.
+type computationMeter struct {
+	table MeteringTable
+	st    *state.State
+	// bytes read from and written to the state before the meter was created
+	initialBytesRead    uint64
+	initialBytesWritten uint64
+	// computation used by operations charged per call
+	operationsUsed uint64
+}
+
+func newComputationMeter(table MeteringTable, st *state.State) *computationMeter {
+	return &computationMeter{
+		table:               table,
+		st:                  st,
+		initialBytesRead:    st.BytesRead(),
+		initialBytesWritten: st.BytesWritten(),
+	}
+}
+
+// meter charges a call of the operation,
+// and checks that the computation used does not exceed the limit.
+func (m *computationMeter) meter(operation MeteringOperation, limit uint64) error {
+	m.operationsUsed += m.table[operation]
+	return m.check(limit)
+}
+
+// check checks that the computation used does not exceed the limit.
+// Like in the runtime, a limit of zero is ignored.
+func (m *computationMeter) check(limit uint64) error {
+	if limit == 0 {
+		return nil
+	}
+
+	used := m.computationUsed()
+	if used > limit {
+		return &ComputationLimitExceededError{
+			Used:  used,
+			Limit: limit,
+		}
+	}
+	return nil
+}
+
+// computationUsed returns the computation used by operations,
+// including the values read from and written to the state since the meter was created.
+func (m *computationMeter) computationUsed() uint64 {
+	if len(m.table) == 0 {
+		return 0
+	}
+
+	bytesRead := m.st.BytesRead() - m.initialBytesRead
+	bytesWritten := m.st.BytesWritten() - m.initialBytesWritten
+
+	return m.operationsUsed +
+		bytesRead*m.table[MeteringBytesRead] +
+		bytesWritten*m.table[MeteringBytesWritten]
+}
@@ -9,18 +9,33 @@ import (
 // A MetricsCollector accumulates performance metrics reported by the Cadence runtime.
 //
 // A single collector instance will sum all reported values. For example, the "parsed" field will be
-// incremented each time a program is parsed.
+// incremented each time a program is parsed. The values are also broken down by the location
+// of the program they were reported for.
 type MetricsCollector struct {
 	parsed       time.Duration
 	checked      time.Duration
 	interpreted  time.Duration
 	valueEncoded time.Duration
 	valueDecoded time.Duration
+	programs     map[common.LocationID]*ProgramMetrics
+}
+
+// ProgramMetrics are the performance metrics reported for the program at a location.
+//
+// The check duration of a program includes the check durations of the programs it imports.
+type ProgramMetrics struct {
+	Parsed      time.Duration
+	Checked     time.Duration
+	Interpreted time.Duration
+	// Imports are the locations of the contracts imported at the top level of a transaction or script
+	Imports []common.LocationID
 }
 
 // NewMetricsCollectors returns a new runtime metrics collector.
 func NewMetricsCollector() *MetricsCollector {
-	return &MetricsCollector{}
+	return &MetricsCollector{
+		programs: make(map[common.LocationID]*ProgramMetrics),
+	}
 }
 
 func (m *MetricsCollector) Parsed() time.Duration       { return m.parsed }
@@ -29,6 +44,45 @@ func (m *MetricsCollector) Interpreted() time.Duration  { return m.interpreted }
 func (m *MetricsCollector) ValueEncoded() time.Duration { return m.valueEncoded }
 func (m *MetricsCollector) ValueDecoded() time.Duration { return m.valueDecoded }
 
+// Program returns the metrics reported for the program at the location.
+func (m *MetricsCollector) Program(location common.LocationID) ProgramMetrics {
+	program, ok := m.programs[location]
+	if !ok {
+		return ProgramMetrics{}
+	}
+	return *program
+}
+
+// Programs returns the metrics reported for all programs, by location.
+func (m *MetricsCollector) Programs() map[common.LocationID]ProgramMetrics {
+	programs := make(map[common.LocationID]ProgramMetrics, len(m.programs))
+	for location, program := range m.programs {
+		programs[location] = *program
+	}
+	return programs
+}
+
+func (m *MetricsCollector) program(location common.Location) *ProgramMetrics {
+	if m.programs == nil {
+		m.programs = make(map[common.LocationID]*ProgramMetrics)
+	}
+
+	id := location.ID()
+	program, ok := m.programs[id]
+	if !ok {
+		program = &ProgramMetrics{}
+		m.programs[id] = program
+	}
+	return program
+}
+
+// programImports records the contracts imported at the top level of the program at the location.
+func (m *MetricsCollector) programImports(location common.Location, imports []common.LocationID) {
+	if m != nil {
+		m.program(location).Imports = imports
+	}
+}
+
 type metricsCollector struct {
 	*MetricsCollector
 }
@@ -36,18 +90,21 @@ type metricsCollector struct {
 func (m metricsCollector) ProgramParsed(location common.Location, duration time.Duration) {
 	if m.MetricsCollector != nil {
 		m.parsed += duration
+		m.program(location).Parsed += duration
 	}
 }
 
 func (m metricsCollector) ProgramChecked(location common.Location, duration time.Duration) {
 	if m.MetricsCollector != nil {
 		m.checked += duration
+		m.program(location).Checked += duration
 	}
 }
 
 func (m metricsCollector) ProgramInterpreted(location common.Location, duration time.Duration) {
 	if m.MetricsCollector != nil {
 		m.interpreted += duration
+		m.program(location).Interpreted += duration
 	}
 }
 
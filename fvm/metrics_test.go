@@ -0,0 +1,70 @@
+package fvm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestMetricsCollector_Programs(t *testing.T) {
+	t.Run("transaction imports and programs are recorded", newVMTest().
+		run(func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, ledger state.Ledger) {
+			txBody := flow.NewTransactionBody().
+				SetScript([]byte(fmt.Sprintf(`
+					import FungibleToken from 0x%s
+					import FlowToken from 0x%s
+
+					transaction {
+						prepare(signer: AuthAccount) {
+							signer.borrow<&FlowToken.Vault>(from: /storage/flowTokenVault)!
+						}
+					}
+				`, fvm.FungibleTokenAddress(chain), fvm.FlowTokenAddress(chain)))).
+				AddAuthorizer(chain.ServiceAddress())
+
+			err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+			require.NoError(t, err)
+
+			metrics := fvm.NewMetricsCollector()
+			txCtx := fvm.NewContextFromParent(ctx, fvm.WithMetricsCollector(metrics))
+
+			tx := fvm.Transaction(txBody, 0)
+			err = vm.Run(txCtx, tx, ledger)
+			require.NoError(t, err)
+			require.NoError(t, tx.Err)
+
+			txID := txBody.ID()
+			fungibleToken := common.AddressLocation{
+				Address: common.BytesToAddress(fvm.FungibleTokenAddress(chain).Bytes()),
+				Name:    "FungibleToken",
+			}.ID()
+			flowToken := common.AddressLocation{
+				Address: common.BytesToAddress(fvm.FlowTokenAddress(chain).Bytes()),
+				Name:    "FlowToken",
+			}.ID()
+
+			program := metrics.Program(common.TransactionLocation(txID[:]).ID())
+			assert.Equal(t, []common.LocationID{fungibleToken, flowToken}, program.Imports)
+			assert.Greater(t, int64(program.Parsed), int64(0))
+			assert.Greater(t, int64(program.Checked), int64(0))
+			assert.Greater(t, int64(program.Interpreted), int64(0))
+
+			// imported programs may have been parsed before, but are checked on every use
+			assert.Greater(t, int64(metrics.Program(flowToken).Checked), int64(0))
+
+			// the per-program durations add up to the totals
+			var parsed int64
+			for _, program := range metrics.Programs() {
+				parsed += int64(program.Parsed)
+			}
+			assert.Equal(t, int64(metrics.Parsed()), parsed)
+		}))
+}
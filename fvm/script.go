@@ -93,9 +93,15 @@ func (i ScriptInvocator) Process(
 		return err
 	}
 
+	err = env.checkComputationLimit()
+	if err != nil {
+		return err
+	}
+
 	proc.Value = value
 	proc.Logs = env.getLogs()
 	proc.Events = env.Events()
+	proc.GasUsed = env.getGasUsed()
 
 	return nil
 }
@@ -47,3 +47,12 @@ type LedgerFailure struct {
 func (e *LedgerFailure) Error() string {
 	return fmt.Sprintf("ledger returns unsuccessful: %s", e.err.Error())
 }
+
+// An InvalidCheckpointError indicates that a savepoint does not exist, or has already been discarded
+type InvalidCheckpointError struct {
+	ID int
+}
+
+func (e *InvalidCheckpointError) Error() string {
+	return fmt.Sprintf("savepoint %d does not exist", e.ID)
+}
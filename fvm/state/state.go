@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"sort"
 	"sync"
 
@@ -28,9 +29,19 @@ type State struct {
 	updatedAddresses      map[flow.Address]struct{}
 	readCache             map[string]payload
 	interactionUsed       uint64
+	bytesRead             uint64
+	bytesWritten          uint64
 	maxKeySizeAllowed     uint64
 	maxValueSizeAllowed   uint64
 	maxInteractionAllowed uint64
+	trace                 *Trace
+	// savepoints are the snapshots of the pending changes created by Checkpoint, in order
+	savepoints []savepoint
+}
+
+type savepoint struct {
+	draft            map[string]payload
+	updatedAddresses map[flow.Address]struct{}
 }
 
 func defaultState(ledger Ledger) *State {
@@ -79,10 +90,33 @@ func WithMaxInteractionSizeAllowed(limit uint64) func(st *State) *State {
 	}
 }
 
+// WithTrace records all register accesses and emitted events in the given trace
+func WithTrace(trace *Trace) func(st *State) *State {
+	return func(st *State) *State {
+		st.trace = trace
+		return st
+	}
+}
+
 func (s *State) Get(owner, controller, key string) (flow.RegisterValue, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	value, err := s.get(owner, controller, key)
+	if err != nil {
+		return value, err
+	}
+
+	s.bytesRead += uint64(len(value))
+
+	if s.trace != nil {
+		s.trace.recordAccess(TraceGet, owner, controller, key, value)
+	}
+
+	return value, nil
+}
+
+func (s *State) get(owner, controller, key string) (flow.RegisterValue, error) {
 	if err := s.checkSize(owner, controller, key, []byte{}); err != nil {
 		return nil, err
 	}
@@ -133,6 +167,21 @@ func (s *State) Set(owner, controller, key string, value flow.RegisterValue) err
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	err := s.set(owner, controller, key, value)
+	if err != nil {
+		return err
+	}
+
+	s.bytesWritten += uint64(len(value))
+
+	if s.trace != nil {
+		s.trace.recordAccess(TraceSet, owner, controller, key, value)
+	}
+
+	return nil
+}
+
+func (s *State) set(owner, controller, key string, value flow.RegisterValue) error {
 	if err := s.checkSize(owner, controller, key, value); err != nil {
 		return err
 	}
@@ -146,13 +195,45 @@ func (s *State) Set(owner, controller, key string, value flow.RegisterValue) err
 }
 
 func (s *State) Touch(owner, controller, key string) error {
-	_, err := s.Get(owner, controller, key)
-	return err
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err := s.get(owner, controller, key)
+	if err != nil {
+		return err
+	}
+
+	if s.trace != nil {
+		s.trace.recordAccess(TraceTouch, owner, controller, key, nil)
+	}
+
+	return nil
 }
 
 func (s *State) Delete(owner, controller, key string) error {
-	err := s.Set(owner, controller, key, nil)
-	return err
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	err := s.set(owner, controller, key, nil)
+	if err != nil {
+		return err
+	}
+
+	if s.trace != nil {
+		s.trace.recordAccess(TraceDelete, owner, controller, key, nil)
+	}
+
+	return nil
+}
+
+// RecordEvent adds an emitted event to the trace of the state, if it is traced
+func (s *State) RecordEvent(event flow.Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.trace != nil {
+		s.trace.recordEvent(event)
+	}
 }
 
 func (s *State) Commit() error {
@@ -195,6 +276,7 @@ func (s *State) Commit() error {
 	// reset draft
 	s.draft = make(map[string]payload)
 	s.updatedAddresses = make(map[flow.Address]struct{})
+	s.savepoints = nil
 
 	return nil
 }
@@ -205,6 +287,69 @@ func (s *State) Rollback() error {
 
 	s.draft = make(map[string]payload)
 	s.updatedAddresses = make(map[flow.Address]struct{})
+	s.savepoints = nil
+	return nil
+}
+
+// Checkpoint creates a savepoint of the pending changes and returns its ID.
+//
+// Savepoints can be nested. Committing or rolling back all pending changes discards all savepoints.
+func (s *State) Checkpoint() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	draft := make(map[string]payload, len(s.draft))
+	for k, p := range s.draft {
+		draft[k] = p
+	}
+
+	updatedAddresses := make(map[flow.Address]struct{}, len(s.updatedAddresses))
+	for address := range s.updatedAddresses {
+		updatedAddresses[address] = struct{}{}
+	}
+
+	s.savepoints = append(s.savepoints, savepoint{
+		draft:            draft,
+		updatedAddresses: updatedAddresses,
+	})
+
+	return len(s.savepoints) - 1
+}
+
+// RollbackTo discards all changes made since the given savepoint was created,
+// and discards the savepoint and all savepoints created after it.
+//
+// The interaction used is not reverted: reads made since the savepoint have been performed
+// against the ledger and remain cached, and discarded changes were never accounted,
+// as changes are only accounted when committed.
+func (s *State) RollbackTo(id int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if id < 0 || id >= len(s.savepoints) {
+		return &InvalidCheckpointError{ID: id}
+	}
+
+	sp := s.savepoints[id]
+	s.draft = sp.draft
+	s.updatedAddresses = sp.updatedAddresses
+	s.savepoints = s.savepoints[:id]
+
+	return nil
+}
+
+// ReleaseCheckpoint keeps all changes made since the given savepoint was created,
+// and discards the savepoint and all savepoints created after it.
+func (s *State) ReleaseCheckpoint(id int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if id < 0 || id >= len(s.savepoints) {
+		return &InvalidCheckpointError{ID: id}
+	}
+
+	s.savepoints = s.savepoints[:id]
+
 	return nil
 }
 
@@ -212,11 +357,20 @@ func (s *State) Ledger() Ledger {
 	return s.ledger
 }
 
+// UpdatedAddresses returns the addresses of the accounts updated on the state, in ascending order.
+//
+// The order is deterministic, as the registers of the accounts are read in this order,
+// and the reads are part of the execution of the transaction.
 func (s *State) UpdatedAddresses() []flow.Address {
 	addresses := make([]flow.Address, 0, len(s.updatedAddresses))
 	for k := range s.updatedAddresses {
 		addresses = append(addresses, k)
 	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
 	return addresses
 }
 
@@ -226,6 +380,26 @@ func (s *State) InteractionUsed() uint64 {
 	return s.interactionUsed
 }
 
+// BytesRead returns the total size of the values returned by Get.
+//
+// Unlike the interaction used, it includes values read from pending changes and from the read cache,
+// and it is not reverted when changes are rolled back.
+func (s *State) BytesRead() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.bytesRead
+}
+
+// BytesWritten returns the total size of the values passed to Set.
+//
+// Unlike the interaction used, it includes values that are not committed yet,
+// and it is not reverted when changes are rolled back.
+func (s *State) BytesWritten() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.bytesWritten
+}
+
 func (s *State) updateInteraction(owner, controller, key string, oldValue, newValue flow.RegisterValue) error {
 	keySize := uint64(len(owner) + len(controller) + len(key))
 	oldValueSize := uint64(len(oldValue))
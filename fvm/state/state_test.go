@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
 )
 
 func TestState_DraftFunctionality(t *testing.T) {
@@ -127,3 +128,209 @@ func TestState_MaxInteraction(t *testing.T) {
 	_, err = st.Get("3", "4", "5")
 	require.Error(t, err)
 }
+
+func TestState_Trace(t *testing.T) {
+	ledger := state.NewMapLedger()
+	err := ledger.Set("address", "controller", "key1", []byte("A"))
+	require.NoError(t, err)
+
+	trace := state.NewTrace()
+	st := state.NewState(ledger, state.WithTrace(trace))
+
+	v, err := st.Get("address", "controller", "key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("A"), v)
+
+	err = st.Set("address", "controller", "key2", []byte("B"))
+	require.NoError(t, err)
+
+	err = st.Touch("address", "controller", "key3")
+	require.NoError(t, err)
+
+	event := flow.Event{Type: "test", EventIndex: 0}
+	st.RecordEvent(event)
+
+	err = st.Delete("address", "controller", "key1")
+	require.NoError(t, err)
+
+	// reading from draft
+	v, err = st.Get("address", "controller", "key2")
+	require.NoError(t, err)
+	require.Equal(t, []byte("B"), v)
+
+	// committing doesn't add any entries
+	err = st.Commit()
+	require.NoError(t, err)
+
+	require.Equal(t, []state.TraceEntry{
+		{Operation: state.TraceGet, Owner: "address", Controller: "controller", Key: "key1", Value: []byte("A")},
+		{Operation: state.TraceSet, Owner: "address", Controller: "controller", Key: "key2", Value: []byte("B")},
+		{Operation: state.TraceTouch, Owner: "address", Controller: "controller", Key: "key3"},
+		{Operation: state.TraceEvent, Event: &event},
+		{Operation: state.TraceDelete, Owner: "address", Controller: "controller", Key: "key1"},
+		{Operation: state.TraceGet, Owner: "address", Controller: "controller", Key: "key2", Value: []byte("B")},
+	}, trace.Entries)
+
+	t.Run("untraced state", func(t *testing.T) {
+		st := state.NewState(state.NewMapLedger())
+		err := st.Set("address", "controller", "key", []byte("A"))
+		require.NoError(t, err)
+		st.RecordEvent(event)
+	})
+}
+
+func TestState_Checkpoint(t *testing.T) {
+	address1 := flow.HexToAddress("01")
+	address2 := flow.HexToAddress("02")
+	owner1 := string(address1.Bytes())
+	owner2 := string(address2.Bytes())
+
+	ledger := state.NewMapLedger()
+	err := ledger.Set(owner1, "controller", "key", []byte("A"))
+	require.NoError(t, err)
+
+	st := state.NewState(ledger)
+
+	err = st.Set(owner1, "controller", "key", []byte("B"))
+	require.NoError(t, err)
+
+	outer := st.Checkpoint()
+
+	err = st.Set(owner1, "controller", "key", []byte("C"))
+	require.NoError(t, err)
+
+	inner := st.Checkpoint()
+	require.Greater(t, inner, outer)
+
+	err = st.Set(owner2, "controller", "key", []byte("D"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []flow.Address{address1, address2}, st.UpdatedAddresses())
+
+	// read - interaction of reading from the ledger is accounted
+	_, err = st.Get(owner2, "controller", "other")
+	require.NoError(t, err)
+	interactionUsed := st.InteractionUsed()
+	require.Equal(t, uint64(len(owner2)+len("controller")+len("other")), interactionUsed)
+
+	// rollback to inner savepoint
+	err = st.RollbackTo(inner)
+	require.NoError(t, err)
+
+	v, err := st.Get(owner2, "controller", "key")
+	require.NoError(t, err)
+	require.Empty(t, v)
+
+	v, err = st.Get(owner1, "controller", "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("C"), v)
+
+	require.Equal(t, []flow.Address{address1}, st.UpdatedAddresses())
+
+	// reads made since the savepoint are still accounted, and remain cached
+	_, err = st.Get(owner2, "controller", "other")
+	require.NoError(t, err)
+	require.Equal(t, interactionUsed+uint64(len(owner2)+len("controller")+len("key")), st.InteractionUsed())
+
+	// inner savepoint has been discarded
+	err = st.RollbackTo(inner)
+	require.Error(t, err)
+
+	// rollback to outer savepoint
+	err = st.RollbackTo(outer)
+	require.NoError(t, err)
+
+	v, err = st.Get(owner1, "controller", "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("B"), v)
+
+	// release keeps changes
+	released := st.Checkpoint()
+	err = st.Set(owner1, "controller", "key", []byte("E"))
+	require.NoError(t, err)
+
+	err = st.ReleaseCheckpoint(released)
+	require.NoError(t, err)
+
+	err = st.ReleaseCheckpoint(released)
+	require.Error(t, err)
+
+	v, err = st.Get(owner1, "controller", "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("E"), v)
+
+	// commit discards all savepoints
+	discarded := st.Checkpoint()
+
+	err = st.Commit()
+	require.NoError(t, err)
+
+	err = st.RollbackTo(discarded)
+	require.Error(t, err)
+
+	v, err = ledger.Get(owner1, "controller", "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("E"), v)
+
+	v, err = ledger.Get(owner2, "controller", "key")
+	require.NoError(t, err)
+	require.Empty(t, v)
+}
+
+func TestState_BytesReadWritten(t *testing.T) {
+	ledger := state.NewMapLedger()
+	err := ledger.Set("address", "controller", "key1", []byte("AB"))
+	require.NoError(t, err)
+
+	st := state.NewState(ledger)
+
+	// read from ledger
+	_, err = st.Get("address", "controller", "key1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), st.BytesRead())
+
+	// read from read cache
+	_, err = st.Get("address", "controller", "key1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), st.BytesRead())
+
+	err = st.Set("address", "controller", "key2", []byte("CDE"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), st.BytesWritten())
+
+	// read from draft
+	_, err = st.Get("address", "controller", "key2")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), st.BytesRead())
+
+	// rollback doesn't revert the bytes read and written
+	err = st.Rollback()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), st.BytesRead())
+	require.Equal(t, uint64(3), st.BytesWritten())
+}
+
+func TestState_UpdatedAddresses(t *testing.T) {
+	st := state.NewState(state.NewMapLedger())
+
+	addresses := []flow.Address{
+		flow.HexToAddress("03"),
+		flow.HexToAddress("0100"),
+		flow.HexToAddress("02"),
+		flow.HexToAddress("01"),
+	}
+	for _, address := range addresses {
+		err := st.Set(string(address.Bytes()), "controller", "key", []byte("A"))
+		require.NoError(t, err)
+	}
+
+	// the addresses are ordered, so the accounts are always visited in the same order
+	expected := []flow.Address{
+		flow.HexToAddress("01"),
+		flow.HexToAddress("02"),
+		flow.HexToAddress("03"),
+		flow.HexToAddress("0100"),
+	}
+	for i := 0; i < 10; i++ {
+		require.Equal(t, expected, st.UpdatedAddresses())
+	}
+}
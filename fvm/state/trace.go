@@ -0,0 +1,69 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TraceOperation is the operation of a trace entry
+type TraceOperation string
+
+const (
+	TraceGet    TraceOperation = "get"
+	TraceSet    TraceOperation = "set"
+	TraceTouch  TraceOperation = "touch"
+	TraceDelete TraceOperation = "delete"
+	TraceEvent  TraceOperation = "event"
+)
+
+// TraceEntry is a single register access or emitted event.
+//
+// Value is the value read by a get, or the value written by a set.
+// Event is only set for emitted events.
+type TraceEntry struct {
+	Operation  TraceOperation
+	Owner      string
+	Controller string
+	Key        string
+	Value      flow.RegisterValue
+	Event      *flow.Event
+}
+
+func (e TraceEntry) String() string {
+	switch e.Operation {
+	case TraceEvent:
+		return fmt.Sprintf("%s %s #%d %s", e.Operation, e.Event.Type, e.Event.EventIndex, e.Event.Payload)
+	case TraceGet, TraceSet:
+		return fmt.Sprintf("%s %x/%x/%x = %x", e.Operation, e.Owner, e.Controller, e.Key, e.Value)
+	default:
+		return fmt.Sprintf("%s %x/%x/%x", e.Operation, e.Owner, e.Controller, e.Key)
+	}
+}
+
+// Trace records all register accesses of a state and the events emitted, in order.
+type Trace struct {
+	Entries []TraceEntry
+}
+
+// NewTrace creates an empty trace
+func NewTrace() *Trace {
+	return &Trace{}
+}
+
+func (t *Trace) recordAccess(operation TraceOperation, owner, controller, key string, value flow.RegisterValue) {
+	t.Entries = append(t.Entries, TraceEntry{
+		Operation:  operation,
+		Owner:      owner,
+		Controller: controller,
+		Key:        key,
+		Value:      value,
+	})
+}
+
+func (t *Trace) recordEvent(event flow.Event) {
+	t.Entries = append(t.Entries, TraceEntry{
+		Operation: TraceEvent,
+		Event:     &event,
+	})
+}
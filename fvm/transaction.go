@@ -33,13 +33,108 @@ type TransactionProcedure struct {
 	Events        []flow.Event
 	ServiceEvents []flow.Event
 	// TODO: report gas consumption: https://github.com/dapperlabs/flow-go/issues/4139
+	// GasUsed is the computation reported by Cadence,
+	// and the computation charged for host environment operations by the metering table of the context
 	GasUsed uint64
 	Err     Error
+	// Trace is only recorded if transaction tracing is enabled in the context
+	Trace *state.Trace
+
+	// deferred is the run of the transaction waiting for its fee to be deducted, if the fee was deferred
+	deferred *transactionRun
 }
 
+// Run runs the transaction processors of the context in order, stopping at the first failure.
+//
+// If the transaction body or a processor following it fails, only the changes made since the invocation
+// of the body are rolled back, and the changes of the preceding processors, e.g. the sequence number
+// increment, are kept. The fee deductors following the body are then run on the rolled back state,
+// so that failed transactions are charged too. If a processor preceding the body fails, all changes are rolled back.
+//
+// If the fees are deferred in the context, the run stops before the first fee deductor following the body,
+// and is completed by DeductDeferredFees.
 func (proc *TransactionProcedure) Run(vm *VirtualMachine, ctx Context, st *state.State) error {
-	for _, p := range ctx.TransactionProcessors {
-		err := p.Process(vm, ctx, proc, st)
+	run := &transactionRun{
+		vm:             vm,
+		ctx:            ctx,
+		st:             st,
+		bodyIndex:      -1,
+		bodyCheckpoint: -1,
+	}
+
+	if !ctx.TransactionFeesDeferred {
+		return run.finish(proc)
+	}
+
+	err := run.runUntil(proc, feeDeductorIndex(ctx))
+	if err != nil {
+		return err
+	}
+
+	proc.deferred = run
+	return nil
+}
+
+// DeductDeferredFees completes the run of a transaction whose fees were deferred, running the fee deductors
+// and the processors following them, and writes the changes of the transaction to the ledger it was run against.
+// It does nothing if the fees of the transaction weren't deferred.
+//
+// The registers the transaction didn't read yet are read from the ledger when the fees are deducted,
+// so the fees are deducted from the latest state of the ledger.
+func (proc *TransactionProcedure) DeductDeferredFees() error {
+	run := proc.deferred
+	if run == nil {
+		return nil
+	}
+	proc.deferred = nil
+
+	err := run.finish(proc)
+	if err != nil {
+		return err
+	}
+
+	return run.st.Commit()
+}
+
+// feeDeductorIndex returns the index of the first fee deductor following the transaction body
+// in the processors of the context, or the number of processors if there is none.
+func feeDeductorIndex(ctx Context) int {
+	body := false
+	for i, p := range ctx.TransactionProcessors {
+		switch p.(type) {
+		case *TransactionInvocator:
+			body = true
+		case *TransactionFeeDeductor:
+			if body {
+				return i
+			}
+		}
+	}
+	return len(ctx.TransactionProcessors)
+}
+
+// transactionRun is the progress of running the transaction processors of a context.
+type transactionRun struct {
+	vm             *VirtualMachine
+	ctx            Context
+	st             *state.State
+	next           int // index of the next processor to run
+	bodyIndex      int
+	bodyCheckpoint int
+	failed         bool // the body or a processor following it failed, and the fees are still to be deducted
+}
+
+// runUntil runs the processors up to the one at the given index, excluding it, stopping at the first failure.
+func (r *transactionRun) runUntil(proc *TransactionProcedure, until int) error {
+	for ; r.next < until; r.next++ {
+		p := r.ctx.TransactionProcessors[r.next]
+
+		if _, ok := p.(*TransactionInvocator); ok && r.bodyCheckpoint < 0 {
+			r.bodyIndex = r.next
+			r.bodyCheckpoint = r.st.Checkpoint()
+		}
+
+		err := p.Process(r.vm, r.ctx, proc, r.st)
 		vmErr, fatalErr := handleError(err)
 		if fatalErr != nil {
 			return fatalErr
@@ -47,7 +142,58 @@ func (proc *TransactionProcedure) Run(vm *VirtualMachine, ctx Context, st *state
 
 		if vmErr != nil {
 			proc.Err = vmErr
-			return nil
+			r.next = len(r.ctx.TransactionProcessors)
+
+			if r.bodyCheckpoint < 0 {
+				return r.st.Rollback()
+			}
+
+			r.failed = true
+			return r.st.RollbackTo(r.bodyCheckpoint)
+		}
+	}
+
+	return nil
+}
+
+// finish runs the remaining processors, and the fee deductors if the transaction failed.
+func (r *transactionRun) finish(proc *TransactionProcedure) error {
+	err := r.runUntil(proc, len(r.ctx.TransactionProcessors))
+	if err != nil {
+		return err
+	}
+
+	if r.failed {
+		return r.deductFeesOfFailedTransaction(proc)
+	}
+	return nil
+}
+
+// deductFeesOfFailedTransaction runs the fee deductors following the transaction body on the rolled back state,
+// including a failed fee deductor, e.g. if the body spent the balance the fee is paid with. As the transaction
+// already failed, a failing fee deduction is rolled back and not reported.
+func (r *transactionRun) deductFeesOfFailedTransaction(proc *TransactionProcedure) error {
+	for i := r.bodyIndex + 1; i < len(r.ctx.TransactionProcessors); i++ {
+		d, ok := r.ctx.TransactionProcessors[i].(*TransactionFeeDeductor)
+		if !ok {
+			continue
+		}
+
+		checkpoint := r.st.Checkpoint()
+
+		err := d.Process(r.vm, r.ctx, proc, r.st)
+		vmErr, fatalErr := handleError(err)
+		if fatalErr != nil {
+			return fatalErr
+		}
+
+		if vmErr != nil {
+			err = r.st.RollbackTo(checkpoint)
+		} else {
+			err = r.st.ReleaseCheckpoint(checkpoint)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
@@ -89,11 +235,19 @@ func (i *TransactionInvocator) Process(
 		},
 	)
 
+	// the computation used is also reported for failed transactions, which are charged for it too
+	proc.GasUsed = env.getGasUsed()
+
 	if err != nil {
 		i.safetyErrorCheck(err)
 		return err
 	}
 
+	err = env.checkComputationLimit()
+	if err != nil {
+		return err
+	}
+
 	i.logger.Info().Str("txHash", proc.ID.String()).Msgf("(%d) ledger interactions used by transaction", st.InteractionUsed())
 
 	proc.Events = env.getEvents()
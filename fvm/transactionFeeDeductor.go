@@ -1,10 +1,16 @@
 package fvm
 
 import (
+	"github.com/onflow/cadence"
+
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
 )
 
+// TransactionFeeDeductor deducts the fee computed by the fee calculator of the context from the payer.
+//
+// It has to follow the transaction invocator, so that the computation used and the storage delta
+// of the transaction are known.
 type TransactionFeeDeductor struct{}
 
 func NewTransactionFeeDeductor() *TransactionFeeDeductor {
@@ -17,19 +23,34 @@ func (d *TransactionFeeDeductor) Process(
 	proc *TransactionProcedure,
 	st *state.State,
 ) error {
-	return d.deductFees(vm, ctx, proc.Transaction, st)
+	params, err := transactionFeeParameters(proc, st)
+	if err != nil {
+		return err
+	}
+
+	fee, err := feeCalculator(ctx).TransactionFee(vm, ctx, st, params)
+	if err != nil {
+		return err
+	}
+
+	return d.deductFees(vm, ctx, proc.Transaction, fee, st)
 }
 
 func (d *TransactionFeeDeductor) deductFees(
 	vm *VirtualMachine,
 	ctx Context,
 	tx *flow.TransactionBody,
+	fee cadence.UFix64,
 	st *state.State,
 ) error {
+	if fee == 0 {
+		return nil
+	}
+
+	deductTx, err := deductTransactionFeeTransaction(tx.Payer, ctx.Chain.ServiceAddress(), FlowFeesAddress(ctx.Chain), fee)
+	if err != nil {
+		return err
+	}
 
-	return vm.invokeMetaTransaction(
-		ctx,
-		deductTransactionFeeTransaction(tx.Payer, ctx.Chain.ServiceAddress()),
-		st,
-	)
+	return vm.invokeMetaTransaction(ctx, deductTx, st)
 }
@@ -2,7 +2,9 @@
 package encoding
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/utils"
@@ -44,12 +46,14 @@ const (
 	TypeUpdate
 	// TypeTrieUpdate - type for trie update
 	TypeTrieUpdate
+	// TypeCompactBatchProof - type for BatchProofs with deduplicated interims
+	TypeCompactBatchProof
 	// this is used to flag types from the future
 	typeUnsuported
 )
 
 func (e Type) String() string {
-	return [...]string{"Unknown", "State", "KeyPart", "Key", "Value", "Path", "Payload", "Proof", "BatchProof", "Query", "Update", "Trie Update"}[e]
+	return [...]string{"Unknown", "State", "KeyPart", "Key", "Value", "Path", "Payload", "Proof", "BatchProof", "Query", "Update", "Trie Update", "Compact BatchProof"}[e]
 }
 
 // CheckVersion extracts encoding bytes from a raw encoded message
@@ -726,13 +730,18 @@ func encodeTrieBatchProof(bp *ledger.TrieBatchProof) []byte {
 	return buffer
 }
 
-// DecodeTrieBatchProof constructs a batch proof from an encoded byte slice
+// DecodeTrieBatchProof constructs a batch proof from an encoded byte slice.
+// It decodes batch proofs encoded by both EncodeTrieBatchProof and EncodeCompactTrieBatchProof.
 func DecodeTrieBatchProof(encodedBatchProof []byte) (*ledger.TrieBatchProof, error) {
 	// check the enc dec version
 	rest, _, err := CheckVersion(encodedBatchProof)
 	if err != nil {
 		return nil, fmt.Errorf("error decoding batch proof: %w", err)
 	}
+	// compact batch proofs are decoded into regular batch proofs
+	if len(rest) > 0 && rest[0] == TypeCompactBatchProof {
+		return DecodeCompactTrieBatchProof(encodedBatchProof)
+	}
 	// check the encoding type
 	rest, err = CheckType(rest, TypeBatchProof)
 	if err != nil {
@@ -779,3 +788,290 @@ func decodeTrieBatchProof(inp []byte) (*ledger.TrieBatchProof, error) {
 	}
 	return bp, nil
 }
+
+// EncodeCompactTrieBatchProof encodes a batch proof into a byte slice, storing each interim shared by
+// several proofs only once.
+//
+// Proofs are stored sorted by path, and every proof only stores the interims, flags and path bytes
+// following the prefix it shares with the previous proof. Proofs of paths sharing a prefix share the
+// interims of the trie nodes along that prefix, so the interims close to the root are stored once for
+// the whole batch instead of once per proof. Decoding restores the proofs in their original order.
+func EncodeCompactTrieBatchProof(bp *ledger.TrieBatchProof) []byte {
+	if bp == nil {
+		return []byte{}
+	}
+	// encode version
+	buffer := utils.AppendUint16([]byte{}, Version)
+
+	// encode compact batch proof entity type
+	buffer = utils.AppendUint8(buffer, TypeCompactBatchProof)
+	// encode compact batch proof content
+	buffer = append(buffer, encodeCompactTrieBatchProof(bp)...)
+
+	return buffer
+}
+
+// compact proof header flags
+const (
+	compactProofInclusion = 1 << 7 // set if the proof is an inclusion proof
+	compactProofDuplicate = 1 << 6 // set if the proof equals the previous proof, nothing else is stored
+)
+
+func encodeCompactTrieBatchProof(bp *ledger.TrieBatchProof) []byte {
+	order := make([]int, len(bp.Proofs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return bytes.Compare(bp.Proofs[order[i]].Path, bp.Proofs[order[j]].Path) < 0
+	})
+
+	buffer := make([]byte, 0)
+	// encode number of proofs
+	buffer = utils.AppendUint32(buffer, uint32(len(bp.Proofs)))
+
+	prev := ledger.NewTrieProof()
+	for n, i := range order {
+		p := bp.Proofs[i]
+
+		// encode original index of the proof in the batch
+		buffer = utils.AppendUint32(buffer, uint32(i))
+
+		if n > 0 && p.Equals(prev) {
+			buffer = utils.AppendUint8(buffer, compactProofDuplicate)
+			continue
+		}
+
+		header := uint8(0)
+		if p.Inclusion {
+			header |= compactProofInclusion
+		}
+		buffer = utils.AppendUint8(buffer, header)
+
+		// steps are encoded as a single byte
+		buffer = utils.AppendUint8(buffer, p.Steps)
+
+		// flags size, size of the prefix shared with the previous proof and the remaining flags
+		sharedFlags := commonPrefixLength(prev.Flags, p.Flags)
+		buffer = utils.AppendUint8(buffer, uint8(len(p.Flags)))
+		buffer = utils.AppendUint8(buffer, uint8(sharedFlags))
+		buffer = append(buffer, p.Flags[sharedFlags:]...)
+
+		// path size, size of the prefix shared with the previous proof and the remaining path
+		sharedPath := commonPrefixLength(prev.Path, p.Path)
+		buffer = utils.AppendUint16(buffer, uint16(p.Path.Size()))
+		buffer = utils.AppendUint16(buffer, uint16(sharedPath))
+		buffer = append(buffer, p.Path[sharedPath:]...)
+
+		// encoded payload size and content
+		encPayload := encodePayload(p.Payload)
+		buffer = utils.AppendUint64(buffer, uint64(len(encPayload)))
+		buffer = append(buffer, encPayload...)
+
+		// number of interims, number of interims shared with the previous proof and the remaining interims
+		sharedInterims := 0
+		for sharedInterims < len(prev.Interims) && sharedInterims < len(p.Interims) &&
+			bytes.Equal(prev.Interims[sharedInterims], p.Interims[sharedInterims]) {
+			sharedInterims++
+		}
+		buffer = utils.AppendUint16(buffer, uint16(len(p.Interims)))
+		buffer = utils.AppendUint16(buffer, uint16(sharedInterims))
+		for _, inter := range p.Interims[sharedInterims:] {
+			buffer = utils.AppendUint16(buffer, uint16(len(inter)))
+			buffer = append(buffer, inter...)
+		}
+
+		prev = p
+	}
+
+	return buffer
+}
+
+// commonPrefixLength returns the length of the longest common prefix of a and b
+func commonPrefixLength(a, b []byte) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// DecodeCompactTrieBatchProof constructs a batch proof from a byte slice encoded by EncodeCompactTrieBatchProof
+func DecodeCompactTrieBatchProof(encodedBatchProof []byte) (*ledger.TrieBatchProof, error) {
+	// check the enc dec version
+	rest, _, err := CheckVersion(encodedBatchProof)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding compact batch proof: %w", err)
+	}
+	// check the encoding type
+	rest, err = CheckType(rest, TypeCompactBatchProof)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding compact batch proof: %w", err)
+	}
+
+	// decode the compact batch proof content
+	bp, err := decodeCompactTrieBatchProof(rest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding compact batch proof: %w", err)
+	}
+	return bp, nil
+}
+
+func decodeCompactTrieBatchProof(inp []byte) (*ledger.TrieBatchProof, error) {
+	// number of proofs
+	numOfProofs, rest, err := utils.ReadUint32(inp)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding compact batch proof (content): %w", err)
+	}
+	// every proof takes at least 5 bytes, don't allocate proofs for corrupted counts
+	if uint64(numOfProofs) > uint64(len(rest))/5 {
+		return nil, fmt.Errorf("error decoding compact batch proof (content): %d proofs exceed the data size", numOfProofs)
+	}
+
+	bp := &ledger.TrieBatchProof{Proofs: make([]*ledger.TrieProof, numOfProofs)}
+
+	var prev *ledger.TrieProof
+	for n := 0; n < int(numOfProofs); n++ {
+		var index uint32
+		index, rest, err = utils.ReadUint32(rest)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding compact batch proof (content): %w", err)
+		}
+		if index >= numOfProofs || bp.Proofs[index] != nil {
+			return nil, fmt.Errorf("error decoding compact batch proof (content): invalid proof index %d", index)
+		}
+
+		var p *ledger.TrieProof
+		p, rest, err = decodeCompactTrieProof(rest, prev)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding compact batch proof (content): %w", err)
+		}
+		bp.Proofs[index] = p
+		prev = p
+	}
+
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("error decoding compact batch proof (content): %d trailing bytes", len(rest))
+	}
+
+	return bp, nil
+}
+
+// decodeCompactTrieProof decodes a single proof of a compact batch proof, following the previous proof
+func decodeCompactTrieProof(inp []byte, prev *ledger.TrieProof) (*ledger.TrieProof, []byte, error) {
+	header, rest, err := utils.ReadUint8(inp)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+
+	if header&compactProofDuplicate != 0 {
+		if prev == nil {
+			return nil, rest, fmt.Errorf("error decoding proof: first proof cannot be a duplicate")
+		}
+		return prev, rest, nil
+	}
+
+	if prev == nil {
+		prev = ledger.NewTrieProof()
+	}
+
+	pInst := ledger.NewTrieProof()
+	pInst.Inclusion = header&compactProofInclusion != 0
+
+	// read steps
+	pInst.Steps, rest, err = utils.ReadUint8(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+
+	// read flags
+	flagsSize, rest, err := utils.ReadUint8(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	sharedFlags, rest, err := utils.ReadUint8(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	if int(sharedFlags) > len(prev.Flags) || sharedFlags > flagsSize {
+		return nil, rest, fmt.Errorf("error decoding proof: invalid shared flags size %d", sharedFlags)
+	}
+	flags, rest, err := utils.ReadSlice(rest, int(flagsSize-sharedFlags))
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	pInst.Flags = make([]byte, 0, flagsSize)
+	pInst.Flags = append(pInst.Flags, prev.Flags[:sharedFlags]...)
+	pInst.Flags = append(pInst.Flags, flags...)
+
+	// read path
+	pathSize, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	sharedPath, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	if int(sharedPath) > len(prev.Path) || sharedPath > pathSize {
+		return nil, rest, fmt.Errorf("error decoding proof: invalid shared path size %d", sharedPath)
+	}
+	path, rest, err := utils.ReadSlice(rest, int(pathSize-sharedPath))
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	pInst.Path = make([]byte, 0, pathSize)
+	pInst.Path = append(pInst.Path, prev.Path[:sharedPath]...)
+	pInst.Path = append(pInst.Path, path...)
+
+	// read payload
+	encPayloadSize, rest, err := utils.ReadUint64(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	if encPayloadSize > uint64(len(rest)) {
+		return nil, rest, fmt.Errorf("error decoding proof: payload size %d exceeds the data size", encPayloadSize)
+	}
+	encPayload, rest, err := utils.ReadSlice(rest, int(encPayloadSize))
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	pInst.Payload, err = decodePayload(encPayload)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+
+	// read interims
+	interimsLen, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	sharedInterims, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+	}
+	if int(sharedInterims) > len(prev.Interims) || sharedInterims > interimsLen {
+		return nil, rest, fmt.Errorf("error decoding proof: invalid shared interims count %d", sharedInterims)
+	}
+	pInst.Interims = make([][]byte, 0, interimsLen)
+	// interims are never modified, so they are shared with the previous proof
+	pInst.Interims = append(pInst.Interims, prev.Interims[:sharedInterims]...)
+
+	var interimSize uint16
+	var interim []byte
+
+	for i := sharedInterims; i < interimsLen; i++ {
+		interimSize, rest, err = utils.ReadUint16(rest)
+		if err != nil {
+			return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+		}
+
+		interim, rest, err = utils.ReadSlice(rest, int(interimSize))
+		if err != nil {
+			return nil, rest, fmt.Errorf("error decoding proof: %w", err)
+		}
+		pInst.Interims = append(pInst.Interims, interim)
+	}
+
+	return pInst, rest, nil
+}
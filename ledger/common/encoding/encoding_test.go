@@ -89,6 +89,34 @@ func Test_BatchProofEncodingDecoding(t *testing.T) {
 	require.True(t, newbp.Equals(bp))
 }
 
+// Test_CompactBatchProofEncodingDecoding tests encoding decoding functionality of a compact batch proof
+func Test_CompactBatchProofEncodingDecoding(t *testing.T) {
+	bp, _ := utils.TrieBatchProofFixture()
+	// duplicated and unsorted proofs are restored in the original order
+	bp.Proofs = []*ledger.TrieProof{bp.Proofs[1], bp.Proofs[0], bp.Proofs[1]}
+
+	encoded := encoding.EncodeCompactTrieBatchProof(bp)
+	newbp, err := encoding.DecodeCompactTrieBatchProof(encoded)
+	require.NoError(t, err)
+	require.True(t, newbp.Equals(bp))
+
+	// regular batch proof decoding accepts compact batch proofs as well
+	newbp, err = encoding.DecodeTrieBatchProof(encoded)
+	require.NoError(t, err)
+	require.True(t, newbp.Equals(bp))
+
+	// but not the other way around
+	_, err = encoding.DecodeCompactTrieBatchProof(encoding.EncodeTrieBatchProof(bp))
+	require.Error(t, err)
+
+	t.Run("truncated", func(t *testing.T) {
+		for i := 0; i < len(encoded); i++ {
+			_, err := encoding.DecodeCompactTrieBatchProof(encoded[:i])
+			require.Error(t, err)
+		}
+	})
+}
+
 // Test_TrieUpdateEncodingDecoding tests encoding decoding functionality of a trie update
 func Test_TrieUpdateEncodingDecoding(t *testing.T) {
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 
 	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/common/utils"
 )
 
@@ -13,6 +14,9 @@ import (
 // hash from the leaf to the root and comparing the rootHash
 func VerifyTrieProof(p *ledger.TrieProof, expectedState ledger.State) bool {
 	treeHeight := 8 * len(p.Path)
+	if treeHeight >= len(defaultHashes) { // paths longer than supported can only come from malformed proofs
+		return false
+	}
 	leafHeight := treeHeight - int(p.Steps)             // p.Steps is the number of edges we are traversing until we hit the compactified leaf.
 	if !(0 <= leafHeight && leafHeight <= treeHeight) { // sanity check
 		return false
@@ -65,3 +69,26 @@ func VerifyTrieBatchProof(bp *ledger.TrieBatchProof, expectedState ledger.State)
 	}
 	return true
 }
+
+// VerifyEncodedTrieBatchProof verifies an encoded batch proof (either a regular or a compact batch proof)
+// against the expected state, without constructing a partial trie from it.
+// Proofs of duplicated keys in compact batch proofs are only verified once.
+// It returns false if the proof cannot be decoded.
+func VerifyEncodedTrieBatchProof(encodedProof []byte, expectedState ledger.State) bool {
+	bp, err := encoding.DecodeTrieBatchProof(encodedProof)
+	if err != nil {
+		return false
+	}
+
+	verified := make(map[*ledger.TrieProof]struct{}, len(bp.Proofs))
+	for _, p := range bp.Proofs {
+		if _, ok := verified[p]; ok {
+			continue
+		}
+		if !VerifyTrieProof(p, expectedState) {
+			return false
+		}
+		verified[p] = struct{}{}
+	}
+	return true
+}
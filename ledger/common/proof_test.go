@@ -1,12 +1,17 @@
 package common_test
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common"
+	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/module/metrics"
 )
 
 // Test_ProofVerify tests proof verification
@@ -20,3 +25,121 @@ func Test_TrieBatchProofVerify(t *testing.T) {
 	bp, sc := utils.TrieBatchProofFixture()
 	require.True(t, common.VerifyTrieBatchProof(bp, sc))
 }
+
+// Test_EncodedTrieBatchProofVerify tests verification of encoded (regular and compact) batch proofs
+func Test_EncodedTrieBatchProofVerify(t *testing.T) {
+	bp, sc := utils.TrieBatchProofFixture()
+	require.True(t, common.VerifyEncodedTrieBatchProof(encoding.EncodeTrieBatchProof(bp), sc))
+	require.True(t, common.VerifyEncodedTrieBatchProof(encoding.EncodeCompactTrieBatchProof(bp), sc))
+
+	bp.Proofs[1].Payload = utils.LightPayload8('C', 'D')
+	require.False(t, common.VerifyEncodedTrieBatchProof(encoding.EncodeTrieBatchProof(bp), sc))
+	require.False(t, common.VerifyEncodedTrieBatchProof(encoding.EncodeCompactTrieBatchProof(bp), sc))
+
+	require.False(t, common.VerifyEncodedTrieBatchProof([]byte{1, 2, 3}, sc))
+}
+
+// Test_CompactTrieBatchProofSize tests that compact batch proofs of many keys are smaller than regular ones
+func Test_CompactTrieBatchProofSize(t *testing.T) {
+	bp, rootHash := randomTrieBatchProof(t, 1, 2000, 300, 30)
+
+	regular := encoding.EncodeTrieBatchProof(bp)
+	compact := encoding.EncodeCompactTrieBatchProof(bp)
+	require.Less(t, len(compact), len(regular)/2)
+
+	require.True(t, common.VerifyEncodedTrieBatchProof(compact, rootHash))
+}
+
+// randomTrieBatchProof creates a trie with the given number of registers, and returns the proofs of
+// reading the given number of existing and missing registers (in random order, including duplicates).
+func randomTrieBatchProof(t testing.TB, seed int64, registers int, reads int, missingReads int) (*ledger.TrieBatchProof, ledger.State) {
+	rng := rand.New(rand.NewSource(seed))
+
+	randomPath := func() ledger.Path {
+		path := make([]byte, 32)
+		_, _ = rng.Read(path)
+		return path
+	}
+
+	forest, err := mtrie.NewForest(32, "", 10, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	paths := make([]ledger.Path, registers)
+	payloads := make([]*ledger.Payload, registers)
+	for i := range paths {
+		paths[i] = randomPath()
+		payloads[i] = utils.LightPayload(uint16(rng.Intn(1<<16)), uint16(rng.Intn(1<<16)))
+	}
+
+	rootHash, err := forest.Update(&ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: paths, Payloads: payloads})
+	require.NoError(t, err)
+
+	readPaths := make([]ledger.Path, 0, reads+missingReads)
+	for i := 0; i < reads && registers > 0; i++ {
+		readPaths = append(readPaths, paths[rng.Intn(registers)])
+	}
+	for i := 0; i < missingReads; i++ {
+		readPaths = append(readPaths, randomPath())
+	}
+
+	bp, err := forest.Proofs(&ledger.TrieRead{RootHash: rootHash, Paths: readPaths})
+	require.NoError(t, err)
+
+	return bp, ledger.State(rootHash)
+}
+
+// FuzzCompactTrieBatchProof checks that compact batch proofs of random tries decode into the
+// original per-key proofs, and verify exactly like them.
+func FuzzCompactTrieBatchProof(f *testing.F) {
+	f.Add(int64(0), uint16(0), uint8(0), uint8(1))
+	f.Add(int64(1), uint16(1), uint8(1), uint8(0))
+	f.Add(int64(2), uint16(100), uint8(20), uint8(5))
+	f.Add(int64(3), uint16(1000), uint8(255), uint8(255))
+
+	f.Fuzz(func(t *testing.T, seed int64, registers uint16, reads uint8, missingReads uint8) {
+		bp, rootHash := randomTrieBatchProof(t, seed, int(registers%2000), int(reads), int(missingReads))
+
+		compact := encoding.EncodeCompactTrieBatchProof(bp)
+		decoded, err := encoding.DecodeCompactTrieBatchProof(compact)
+		require.NoError(t, err)
+		require.True(t, decoded.Equals(bp))
+
+		require.True(t, common.VerifyTrieBatchProof(bp, rootHash))
+		require.True(t, common.VerifyEncodedTrieBatchProof(compact, rootHash))
+
+		otherState := ledger.State(utils.RootHashFixture())
+		require.Equal(t, common.VerifyTrieBatchProof(bp, otherState), common.VerifyEncodedTrieBatchProof(compact, otherState))
+	})
+}
+
+// FuzzVerifyEncodedTrieBatchProof checks that verifying arbitrarily modified compact batch proofs
+// never panics, and agrees with the verification of the per-key proofs they decode into.
+func FuzzVerifyEncodedTrieBatchProof(f *testing.F) {
+	for seed := int64(0); seed < 4; seed++ {
+		bp, _ := randomTrieBatchProof(f, seed, 50, 10, 3)
+		f.Add(seed, encoding.EncodeCompactTrieBatchProof(bp), uint16(0), byte(0))
+		f.Add(seed, encoding.EncodeCompactTrieBatchProof(bp), uint16(seed*100), byte(1<<seed))
+	}
+	f.Add(int64(0), []byte{}, uint16(0), byte(0))
+
+	f.Fuzz(func(t *testing.T, seed int64, encoded []byte, position uint16, mask byte) {
+		if len(encoded) > 0 {
+			encoded[int(position)%len(encoded)] ^= mask
+		}
+
+		_, rootHash := randomTrieBatchProof(t, seed, 50, 0, 0)
+
+		decoded, err := encoding.DecodeTrieBatchProof(encoded)
+		if err != nil {
+			require.False(t, common.VerifyEncodedTrieBatchProof(encoded, rootHash))
+			return
+		}
+
+		require.Equal(t, common.VerifyTrieBatchProof(decoded, rootHash), common.VerifyEncodedTrieBatchProof(encoded, rootHash))
+
+		// decoded proofs encode into an equivalent compact batch proof
+		redecoded, err := encoding.DecodeCompactTrieBatchProof(encoding.EncodeCompactTrieBatchProof(decoded))
+		require.NoError(t, err)
+		require.True(t, redecoded.Equals(decoded))
+	})
+}
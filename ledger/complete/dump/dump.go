@@ -0,0 +1,248 @@
+package dump
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/common/utils"
+)
+
+// A payload dump is a portable export of all payloads of a single ledger state.
+//
+// File format (all integers are Big Endian):
+//   * header
+//     * 2-bytes magic bytes
+//     * 2-bytes version
+//     * 1-byte path finder version used to compute the paths of the payloads
+//     * 2-bytes length of the state commitment, followed by the state commitment
+//   * payload records, in strictly ascending order of their paths
+//     * 1-byte record tag (recordPayload)
+//     * 4-bytes length of the encoded payload, followed by the payload (see encoding.EncodePayload)
+//   * footer
+//     * 1-byte record tag (recordEnd)
+//     * 8-bytes number of payload records
+//     * 4-bytes CRC32 checksum of everything before the checksum
+
+const MagicBytes uint16 = 0x2138
+
+// VersionV1 is the first version of the payload dump format
+const VersionV1 uint16 = 0x01
+
+const (
+	recordEnd     byte = 0
+	recordPayload byte = 1
+)
+
+var crc32Table = crc32.MakeTable(crc32.Castagnoli)
+
+// Header describes the state exported in a payload dump
+type Header struct {
+	Version           uint16
+	PathFinderVersion uint8
+	StateCommitment   ledger.State
+}
+
+// Writer writes a payload dump. Payloads have to be written in strictly ascending order of their paths.
+// Close has to be called to finish the dump, it doesn't close the underlying writer.
+type Writer struct {
+	writer            io.Writer
+	crc               hash.Hash32
+	pathFinderVersion uint8
+	lastPath          ledger.Path
+	count             uint64
+}
+
+// NewWriter writes the header of a payload dump of the given state and returns the writer for its payloads
+func NewWriter(w io.Writer, stateCommitment ledger.State, pathFinderVersion uint8) (*Writer, error) {
+	crc := crc32.New(crc32Table)
+	writer := &Writer{
+		writer:            io.MultiWriter(w, crc),
+		crc:               crc,
+		pathFinderVersion: pathFinderVersion,
+	}
+
+	header := make([]byte, 0, 2+2+1+2+len(stateCommitment))
+	header = utils.AppendUint16(header, MagicBytes)
+	header = utils.AppendUint16(header, VersionV1)
+	header = utils.AppendUint8(header, pathFinderVersion)
+	header = utils.AppendShortData(header, stateCommitment)
+
+	_, err := writer.writer.Write(header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot write payload dump header: %w", err)
+	}
+
+	return writer, nil
+}
+
+// Write appends the payload to the dump
+func (w *Writer) Write(payload *ledger.Payload) error {
+	path, err := pathfinder.KeyToPath(payload.Key, w.pathFinderVersion)
+	if err != nil {
+		return fmt.Errorf("cannot compute path of payload: %w", err)
+	}
+	if w.lastPath != nil && bytes.Compare(w.lastPath, path) >= 0 {
+		return fmt.Errorf("payloads are not sorted by path: %x written after %x", path, w.lastPath)
+	}
+
+	record := []byte{recordPayload}
+	record = utils.AppendLongData(record, encoding.EncodePayload(payload))
+
+	_, err = w.writer.Write(record)
+	if err != nil {
+		return fmt.Errorf("cannot write payload record %d: %w", w.count, err)
+	}
+
+	w.lastPath = path
+	w.count++
+	return nil
+}
+
+// Close writes the footer of the dump
+func (w *Writer) Close() error {
+	footer := make([]byte, 0, 1+8)
+	footer = append(footer, recordEnd)
+	footer = utils.AppendUint64(footer, w.count)
+
+	_, err := w.writer.Write(footer)
+	if err != nil {
+		return fmt.Errorf("cannot write payload dump footer: %w", err)
+	}
+
+	// the checksum itself is not part of the checksum
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, w.crc.Sum32())
+	_, err = w.writer.Write(checksum)
+	if err != nil {
+		return fmt.Errorf("cannot write payload dump checksum: %w", err)
+	}
+
+	return nil
+}
+
+// Reader reads a payload dump, verifying the order of the payloads, their count and the checksum
+type Reader struct {
+	reader   io.Reader
+	crc      hash.Hash32
+	header   *Header
+	lastPath ledger.Path
+	count    uint64
+	done     bool
+}
+
+// NewReader reads the header of a payload dump and returns the reader for its payloads
+func NewReader(r io.Reader) (*Reader, error) {
+	crc := crc32.New(crc32Table)
+	reader := &Reader{
+		reader: io.TeeReader(r, crc),
+		crc:    crc,
+	}
+
+	buf, err := utils.ReadFromBuffer(reader.reader, 2+2+1)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read payload dump header: %w", err)
+	}
+
+	magic, buf, _ := utils.ReadUint16(buf)
+	if magic != MagicBytes {
+		return nil, fmt.Errorf("wrong magic bytes of payload dump %x, expected %x", magic, MagicBytes)
+	}
+
+	version, buf, _ := utils.ReadUint16(buf)
+	if version != VersionV1 {
+		return nil, fmt.Errorf("unsupported payload dump version %d", version)
+	}
+
+	pathFinderVersion, _, _ := utils.ReadUint8(buf)
+
+	stateCommitment, err := utils.ReadShortDataFromReader(reader.reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read state commitment of payload dump: %w", err)
+	}
+
+	reader.header = &Header{
+		Version:           version,
+		PathFinderVersion: pathFinderVersion,
+		StateCommitment:   stateCommitment,
+	}
+
+	return reader, nil
+}
+
+// Header returns the header of the dump
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// Next returns the next payload of the dump and its path.
+// It returns io.EOF after the last payload, once the payload count and the checksum of the dump have been verified.
+func (r *Reader) Next() (ledger.Path, *ledger.Payload, error) {
+	if r.done {
+		return nil, nil, io.EOF
+	}
+
+	tag, err := utils.ReadFromBuffer(r.reader, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read record %d: %w", r.count, err)
+	}
+
+	switch tag[0] {
+	case recordPayload:
+		encodedPayload, err := utils.ReadLongDataFromReader(r.reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read payload record %d: %w", r.count, err)
+		}
+		payload, err := encoding.DecodePayload(encodedPayload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot decode payload record %d: %w", r.count, err)
+		}
+		path, err := pathfinder.KeyToPath(payload.Key, r.header.PathFinderVersion)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot compute path of payload record %d: %w", r.count, err)
+		}
+		if r.lastPath != nil && bytes.Compare(r.lastPath, path) >= 0 {
+			return nil, nil, fmt.Errorf("payload record %d is not sorted by path: %x after %x", r.count, path, r.lastPath)
+		}
+		r.lastPath = path
+		r.count++
+		return path, payload, nil
+
+	case recordEnd:
+		r.done = true
+		return nil, nil, r.readFooter()
+
+	default:
+		return nil, nil, fmt.Errorf("unknown record tag %d of record %d", tag[0], r.count)
+	}
+}
+
+func (r *Reader) readFooter() error {
+	buf, err := utils.ReadFromBuffer(r.reader, 8)
+	if err != nil {
+		return fmt.Errorf("cannot read payload count: %w", err)
+	}
+	count, _, _ := utils.ReadUint64(buf)
+	if count != r.count {
+		return fmt.Errorf("payload count mismatch: dump contains %d payloads, footer says %d", r.count, count)
+	}
+
+	calculatedCrc32 := r.crc.Sum32()
+
+	buf, err = utils.ReadFromBuffer(r.reader, 4)
+	if err != nil {
+		return fmt.Errorf("cannot read checksum: %w", err)
+	}
+	readCrc32, _, _ := utils.ReadUint32(buf)
+	if readCrc32 != calculatedCrc32 {
+		return fmt.Errorf("checksum mismatch: payload dump is corrupted (read %x, calculated %x)", readCrc32, calculatedCrc32)
+	}
+
+	return io.EOF
+}
@@ -0,0 +1,173 @@
+package dump_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/dump"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+const pathFinderVersion = 1
+
+// sortedPayloads returns random payloads sorted by path, and the state commitment of the trie containing them
+func sortedPayloads(t *testing.T, n int) ([]ledger.Payload, ledger.State) {
+	keys := utils.RandomUniqueKeys(n, 2, 1, 10)
+	values := utils.RandomValues(n, 1, 100)
+
+	payloads := make([]ledger.Payload, n)
+	for i := range keys {
+		payloads[i] = *ledger.NewPayload(keys[i], values[i])
+	}
+
+	paths, err := pathfinder.PathsFromPayloads(payloads, pathFinderVersion)
+	require.NoError(t, err)
+
+	sort.Sort(byPath{paths, payloads})
+
+	emptyTrie, err := trie.NewEmptyMTrie(pathfinder.PathByteSize)
+	require.NoError(t, err)
+	tr, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, paths, payloads)
+	require.NoError(t, err)
+
+	return payloads, tr.RootHash()
+}
+
+type byPath struct {
+	paths    []ledger.Path
+	payloads []ledger.Payload
+}
+
+func (b byPath) Len() int           { return len(b.paths) }
+func (b byPath) Less(i, j int) bool { return bytes.Compare(b.paths[i], b.paths[j]) < 0 }
+func (b byPath) Swap(i, j int) {
+	b.paths[i], b.paths[j] = b.paths[j], b.paths[i]
+	b.payloads[i], b.payloads[j] = b.payloads[j], b.payloads[i]
+}
+
+func writeDump(t *testing.T, payloads []ledger.Payload, state ledger.State) []byte {
+	var buf bytes.Buffer
+	writer, err := dump.NewWriter(&buf, state, pathFinderVersion)
+	require.NoError(t, err)
+	for i := range payloads {
+		require.NoError(t, writer.Write(&payloads[i]))
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func Test_PayloadDump(t *testing.T) {
+
+	t.Run("write and read", func(t *testing.T) {
+		payloads, state := sortedPayloads(t, 100)
+		data := writeDump(t, payloads, state)
+
+		reader, err := dump.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, dump.VersionV1, reader.Header().Version)
+		require.Equal(t, uint8(pathFinderVersion), reader.Header().PathFinderVersion)
+		require.Equal(t, state, reader.Header().StateCommitment)
+
+		for i := range payloads {
+			path, payload, err := reader.Next()
+			require.NoError(t, err)
+			require.True(t, payloads[i].Equals(payload))
+
+			expectedPath, err := pathfinder.KeyToPath(payloads[i].Key, pathFinderVersion)
+			require.NoError(t, err)
+			require.Equal(t, expectedPath, path)
+		}
+
+		_, _, err = reader.Next()
+		require.Equal(t, io.EOF, err)
+	})
+
+	t.Run("empty dump", func(t *testing.T) {
+		state := ledger.State(trie.EmptyTrieRootHash(pathfinder.PathByteSize))
+		data := writeDump(t, nil, state)
+
+		reader, err := dump.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		tr, err := dump.BuildTrie(reader, zerolog.Nop())
+		require.NoError(t, err)
+		require.Equal(t, []byte(state), tr.RootHash())
+	})
+
+	t.Run("unsorted payloads are rejected", func(t *testing.T) {
+		payloads, state := sortedPayloads(t, 2)
+
+		writer, err := dump.NewWriter(&bytes.Buffer{}, state, pathFinderVersion)
+		require.NoError(t, err)
+		require.NoError(t, writer.Write(&payloads[1]))
+		require.Error(t, writer.Write(&payloads[0]))
+	})
+
+	t.Run("corrupted dump is detected", func(t *testing.T) {
+		payloads, state := sortedPayloads(t, 10)
+		data := writeDump(t, payloads, state)
+
+		// flip a bit in the value of the last payload, so the dump can still be decoded
+		data[len(data)-4-8-1-1] ^= 0x01
+
+		reader, err := dump.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		_, err = dump.BuildTrie(reader, zerolog.Nop())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum mismatch")
+	})
+
+	t.Run("truncated dump is detected", func(t *testing.T) {
+		payloads, state := sortedPayloads(t, 10)
+		data := writeDump(t, payloads, state)
+
+		reader, err := dump.NewReader(bytes.NewReader(data[:len(data)/2]))
+		require.NoError(t, err)
+		_, err = dump.BuildTrie(reader, zerolog.Nop())
+		require.Error(t, err)
+	})
+}
+
+func Test_ImportRootCheckpoint(t *testing.T) {
+
+	t.Run("root checkpoint is created", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			payloads, state := sortedPayloads(t, 100)
+			data := writeDump(t, payloads, state)
+
+			importedState, err := dump.ImportRootCheckpoint(bytes.NewReader(data), dir, zerolog.Nop())
+			require.NoError(t, err)
+			require.Equal(t, state, importedState)
+
+			forestSequencing, err := wal.LoadCheckpoint(path.Join(dir, wal.RootCheckpointFilename))
+			require.NoError(t, err)
+			require.Len(t, forestSequencing.Tries, 1)
+			require.Equal(t, []byte(state), forestSequencing.Tries[0].RootHash)
+		})
+	})
+
+	t.Run("mismatching state commitment", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			payloads, _ := sortedPayloads(t, 100)
+			data := writeDump(t, payloads, ledger.State(unittest.StateCommitmentFixture()))
+
+			_, err := dump.ImportRootCheckpoint(bytes.NewReader(data), dir, zerolog.Nop())
+			require.Error(t, err)
+
+			files, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+			require.Empty(t, files)
+		})
+	})
+}
@@ -0,0 +1,122 @@
+package dump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+)
+
+// importBatchSize is the number of payloads added to the trie at once while importing a dump
+const importBatchSize = 100000
+
+// BuildTrie reads all payloads of the dump and builds the trie containing them.
+// Payloads are added in batches, so only the trie and a single batch are held in memory.
+// It fails if the root hash of the trie doesn't match the state commitment of the dump.
+func BuildTrie(r *Reader, log zerolog.Logger) (*trie.MTrie, error) {
+	t, err := trie.NewEmptyMTrie(pathfinder.PathByteSize)
+	if err != nil {
+		return nil, fmt.Errorf("constructing empty trie failed: %w", err)
+	}
+
+	paths := make([]ledger.Path, 0, importBatchSize)
+	payloads := make([]ledger.Payload, 0, importBatchSize)
+	count := 0
+
+	addBatch := func() error {
+		if len(paths) == 0 {
+			return nil
+		}
+		t, err = trie.NewTrieWithUpdatedRegisters(t, paths, payloads)
+		if err != nil {
+			return fmt.Errorf("constructing updated trie failed: %w", err)
+		}
+		count += len(paths)
+		log.Info().Int("payloads", count).Msg("payloads imported")
+		paths = paths[:0]
+		payloads = payloads[:0]
+		return nil
+	}
+
+	for {
+		path, payload, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read payload dump: %w", err)
+		}
+
+		paths = append(paths, path)
+		payloads = append(payloads, *payload)
+
+		if len(paths) == importBatchSize {
+			err = addBatch()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = addBatch()
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(t.RootHash(), r.Header().StateCommitment) {
+		return nil, fmt.Errorf("root hash of the imported trie %x doesn't match the state commitment of the dump %x",
+			t.RootHash(), []byte(r.Header().StateCommitment))
+	}
+
+	return t, nil
+}
+
+// ImportRootCheckpoint builds the trie of the payload dump and stores it as the root checkpoint in the given directory,
+// which can be used to bootstrap a new ledger. It returns the state commitment of the imported state.
+// No checkpoint is written if the root hash of the trie doesn't match the state commitment of the dump.
+func ImportRootCheckpoint(r io.Reader, outputDir string, log zerolog.Logger) (ledger.State, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Hex("state_commitment", reader.Header().StateCommitment).
+		Uint8("path_finder_version", reader.Header().PathFinderVersion).
+		Msg("importing payload dump")
+
+	t, err := BuildTrie(reader, log)
+	if err != nil {
+		return nil, err
+	}
+
+	flatTrie, err := flattener.FlattenTrie(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten the trie: %w", err)
+	}
+
+	writer, err := wal.CreateCheckpointWriterForFile(outputDir, wal.RootCheckpointFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a checkpoint writer: %w", err)
+	}
+
+	err = wal.StoreCheckpoint(flatTrie.ToFlattenedForestWithASingleTrie(), writer)
+	if err != nil {
+		// closing the writer would rename the incomplete temporary file to the root checkpoint
+		return nil, fmt.Errorf("failed to store the checkpoint: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close the checkpoint: %w", err)
+	}
+
+	return t.RootHash(), nil
+}
@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,16 +17,25 @@ import (
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/dump"
 	"github.com/onflow/flow-go/ledger/complete/mtrie"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/nodestore"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/ledger/complete/wal"
 	"github.com/onflow/flow-go/module"
+	modulemetrics "github.com/onflow/flow-go/module/metrics"
 )
 
 const DefaultCacheSize = 1000
 const DefaultPathFinderVersion = 1
 
+// RestoredStatesCapacity is the number of restored states held in memory, in addition to the states of the forest
+const RestoredStatesCapacity = 10
+
+// NodeStoreDirName is the name of the sub-directory (of the ledger directory) holding the on-disk node store
+const NodeStoreDirName = "nodes"
+
 // Ledger (complete) is a fast memory-efficient fork-aware thread-safe trie-based key/value storage.
 // Ledger holds an array of registers (key-value pairs) and keeps tracks of changes over a limited time.
 // Each register is referenced by an ID (key) and holds a value (byte slice).
@@ -32,18 +43,24 @@ const DefaultPathFinderVersion = 1
 // Every update to the Ledger creates a new state which captures the state of the storage.
 // Under the hood, it uses binary Merkle tries to generate inclusion and non-inclusion proofs.
 // Ledger is fork-aware which means any update can be applied at any previous state which forms a tree of tries (forest).
-// The forest is in memory but all changes (e.g. register updates) are captured inside write-ahead-logs for crash recovery reasons.
+// The forest is in memory (or paged to an on-disk node store, see NewLedgerWithNodeStore) but all changes
+// (e.g. register updates) are captured inside write-ahead-logs for crash recovery reasons.
 // In order to limit the memory usage and maintain the performance storage only keeps a limited number of
 // tries and purge the old ones (LRU-based); in other words, Ledger is not designed to be used
 // for archival usage but make it possible for other software components to reconstruct very old tries using write-ahead logs.
 type Ledger struct {
 	forest  *mtrie.Forest
 	wal     *wal.LedgerWAL
+	// restored holds the states restored from checkpoints and the WAL, separately from the forest,
+	// so restoring an old state doesn't evict a recent one from the forest.
+	restored *mtrie.Forest
 	metrics module.LedgerMetrics
 	logger  zerolog.Logger
 	// disk size reading can be time consuming, so limit how often its read
 	diskUpdateLimiter *time.Ticker
 	pathFinderVersion uint8
+	nodeStore         *nodestore.Store // only set for disk-backed ledgers
+	restoreLock       sync.Mutex       // only one state is restored from the WAL at a time
 }
 
 // NewLedger creates a new in-memory trie-backed ledger storage with persistence.
@@ -54,14 +71,63 @@ func NewLedger(dbDir string,
 	reg prometheus.Registerer,
 	pathFinderVer uint8) (*Ledger, error) {
 
+	return newLedger(dbDir, capacity, nil, metrics, log, reg, pathFinderVer)
+}
+
+// NewLedgerWithNodeStore creates a new disk-backed trie ledger storage with persistence.
+// In contrast to NewLedger, the trie nodes are held in an on-disk node store (inside
+// the ledger directory) and only the nodeCacheSize most recently used nodes are kept in memory.
+// Ledger semantics and state commitments are identical to the in-memory ledger.
+func NewLedgerWithNodeStore(dbDir string,
+	capacity int,
+	nodeCacheSize int,
+	metrics module.LedgerMetrics,
+	log zerolog.Logger,
+	reg prometheus.Registerer,
+	pathFinderVer uint8) (*Ledger, error) {
+
+	nodeStore, err := nodestore.NewStore(filepath.Join(dbDir, NodeStoreDirName), nodeCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create node store: %w", err)
+	}
+
+	return newLedger(dbDir, capacity, nodeStore, metrics, log, reg, pathFinderVer)
+}
+
+func newLedger(dbDir string,
+	capacity int,
+	nodeStore *nodestore.Store,
+	metrics module.LedgerMetrics,
+	log zerolog.Logger,
+	reg prometheus.Registerer,
+	pathFinderVer uint8) (*Ledger, error) {
+
 	w, err := wal.NewWAL(log, reg, dbDir, capacity, pathfinder.PathByteSize, wal.SegmentSize)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create LedgerWAL: %w", err)
 	}
 
-	forest, err := mtrie.NewForest(pathfinder.PathByteSize, dbDir, capacity, metrics, func(evictedTrie *trie.MTrie) error {
+	onTreeEvicted := func(evictedTrie *trie.MTrie) error {
 		return w.RecordDelete(evictedTrie.RootHash())
-	})
+	}
+
+	// restored states are not part of the WAL, hence their eviction isn't recorded
+	onRestoredTreeEvicted := func(evictedTrie *trie.MTrie) error {
+		return nil
+	}
+
+	var forest, restored *mtrie.Forest
+	if nodeStore != nil {
+		forest, err = mtrie.NewForestWithNodeStore(pathfinder.PathByteSize, dbDir, capacity, nodeStore, metrics, onTreeEvicted)
+		if err == nil {
+			restored, err = mtrie.NewForestWithNodeStore(pathfinder.PathByteSize, dbDir, RestoredStatesCapacity, nodeStore, &modulemetrics.NoopCollector{}, onRestoredTreeEvicted)
+		}
+	} else {
+		forest, err = mtrie.NewForest(pathfinder.PathByteSize, dbDir, capacity, metrics, onTreeEvicted)
+		if err == nil {
+			restored, err = mtrie.NewForest(pathfinder.PathByteSize, dbDir, RestoredStatesCapacity, &modulemetrics.NoopCollector{}, onRestoredTreeEvicted)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("cannot create forest: %w", err)
 	}
@@ -70,11 +136,13 @@ func NewLedger(dbDir string,
 
 	storage := &Ledger{
 		forest:            forest,
+		restored:          restored,
 		wal:               w,
 		metrics:           metrics,
 		logger:            logger,
 		diskUpdateLimiter: time.NewTicker(5 * time.Second),
 		pathFinderVersion: pathFinderVer,
+		nodeStore:         nodeStore,
 	}
 
 	// pause records to prevent double logging trie removals
@@ -103,9 +171,9 @@ func (l *Ledger) Ready() <-chan struct{} {
 }
 
 // Done implements interface module.ReadyDoneAware
-// it closes all the open write-ahead log files.
+// it closes all the open write-ahead log files (and the node store, if any).
 func (l *Ledger) Done() <-chan struct{} {
-	_ = l.wal.Close()
+	l.CloseStorage()
 	done := make(chan struct{})
 	close(done)
 	return done
@@ -125,8 +193,12 @@ func (l *Ledger) Get(query *ledger.Query) (values []ledger.Value, err error) {
 		return nil, err
 	}
 	trieRead := &ledger.TrieRead{RootHash: ledger.RootHash(query.State()), Paths: paths}
-	payloads, err := l.forest.Read(trieRead)
+	forest := l.forestOf(trieRead.RootHash)
+	payloads, err := forest.Read(trieRead)
 	if err != nil {
+		if !forest.HasTrie(trieRead.RootHash) {
+			return nil, ledger.NewErrStatePruned(query.State())
+		}
 		return nil, err
 	}
 	values, err = pathfinder.PayloadsToValues(payloads)
@@ -213,8 +285,12 @@ func (l *Ledger) Prove(query *ledger.Query) (proof ledger.Proof, err error) {
 	}
 
 	trieRead := &ledger.TrieRead{RootHash: ledger.RootHash(query.State()), Paths: paths}
-	batchProof, err := l.forest.Proofs(trieRead)
+	forest := l.forestOf(trieRead.RootHash)
+	batchProof, err := forest.Proofs(trieRead)
 	if err != nil {
+		if !forest.HasTrie(trieRead.RootHash) {
+			return nil, ledger.NewErrStatePruned(query.State())
+		}
 		return nil, fmt.Errorf("could not get proofs: %w", err)
 	}
 
@@ -230,6 +306,9 @@ func (l *Ledger) Prove(query *ledger.Query) (proof ledger.Proof, err error) {
 // CloseStorage closes the DB
 func (l *Ledger) CloseStorage() {
 	_ = l.wal.Close()
+	if l.nodeStore != nil {
+		_ = l.nodeStore.Close()
+	}
 }
 
 // MemSize return the amount of memory used by ledger
@@ -248,6 +327,74 @@ func (l *Ledger) ForestSize() int {
 	return l.forest.Size()
 }
 
+// SetCodec sets the codec used to compress WAL records and checkpoints written from now on.
+// WAL segments and checkpoints written with any codec remain readable.
+func (l *Ledger) SetCodec(codec wal.Codec) {
+	l.wal.SetCodec(codec)
+}
+
+// SetCheckpointParts enables or disables splitting checkpoints written from now on into parts, which are loaded in
+// parallel. Checkpoints split into parts can't be read by binaries without support for them.
+func (l *Ledger) SetCheckpointParts(enabled bool) {
+	l.wal.SetCheckpointParts(enabled)
+}
+
+// HasState returns true if the trie of the given state is held in memory
+func (l *Ledger) HasState(state ledger.State) bool {
+	rootHash := ledger.RootHash(state)
+	return l.forest.HasTrie(rootHash) || l.restored.HasTrie(rootHash)
+}
+
+// forestOf returns the forest holding the trie with the given root hash. It returns the main
+// forest if neither holds it.
+func (l *Ledger) forestOf(rootHash ledger.RootHash) *mtrie.Forest {
+	if !l.forest.HasTrie(rootHash) && l.restored.HasTrie(rootHash) {
+		return l.restored
+	}
+	return l.forest
+}
+
+// RestoreState restores the trie of the given state from checkpoints and the WAL, if it has been evicted from memory.
+// Restoring replays (parts of) the WAL and can take a long time, so it should only be used for states retained by a
+// retention policy. It returns a ledger.ErrStatePruned if the state cannot be restored.
+// Restored states are held apart from the forest, for reading only: they don't evict states from the forest,
+// and only the RestoredStatesCapacity most recently used restored states are kept.
+func (l *Ledger) RestoreState(state ledger.State) error {
+	if l.HasState(state) {
+		return nil
+	}
+
+	l.restoreLock.Lock()
+	defer l.restoreLock.Unlock()
+
+	// the state might have been restored while waiting for the lock
+	if l.HasState(state) {
+		return nil
+	}
+
+	start := time.Now()
+
+	restored, found, err := l.wal.RestoreTrie(ledger.RootHash(state))
+	if err != nil {
+		return fmt.Errorf("cannot restore state %s: %w", state, err)
+	}
+	if !found {
+		return ledger.NewErrStatePruned(state)
+	}
+
+	err = l.restored.AddTrie(restored)
+	if err != nil {
+		return fmt.Errorf("cannot add restored trie of state %s: %w", state, err)
+	}
+
+	l.logger.Info().
+		Hex("state", state).
+		Dur("duration", time.Since(start)).
+		Msg("state restored from checkpoints and WAL")
+
+	return nil
+}
+
 // Checkpointer returns a checkpointer instance
 func (l *Ledger) Checkpointer() (*wal.Checkpointer, error) {
 	checkpointer, err := l.wal.NewCheckpointer()
@@ -281,7 +428,10 @@ func (l *Ledger) ExportCheckpointAt(state ledger.State,
 	// l.logger.Info().Msg("Trie is valid.")
 
 	// get all payloads
-	payloads := t.AllPayloads()
+	payloads, err := t.AllPayloads()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get payloads of the trie: %w", err)
+	}
 	payloadSize := len(payloads)
 
 	// migrate payloads
@@ -347,6 +497,55 @@ func (l *Ledger) ExportCheckpointAt(state ledger.State,
 	return newTrie.RootHash(), nil
 }
 
+// ExportPayloads writes all payloads of the given state as a portable payload dump (see package dump),
+// which can be imported to bootstrap a new ledger with dump.ImportRootCheckpoint.
+func (l *Ledger) ExportPayloads(state ledger.State, w io.Writer) error {
+	rootHash := ledger.RootHash(state)
+	t, err := l.forestOf(rootHash).GetTrie(rootHash)
+	if err != nil {
+		return fmt.Errorf("cannot find the trie of state %s: %w", state, err)
+	}
+
+	writer, err := dump.NewWriter(w, state, l.pathFinderVersion)
+	if err != nil {
+		return err
+	}
+
+	// payloads are returned in ascending path order
+	payloads, err := t.AllPayloads()
+	if err != nil {
+		return fmt.Errorf("cannot get payloads of state %s: %w", state, err)
+	}
+	for i := range payloads {
+		err = writer.Write(&payloads[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// Diff streams the registers which were added, removed or modified between
+// the `from` and `to` states to fn, in ascending path order.
+// Sub-tries shared between both states are skipped by comparing their hashes.
+// Diffing stops at the first error returned by fn.
+func (l *Ledger) Diff(from, to ledger.State, fn func(diff *ledger.PayloadDiff) error) error {
+	fromRootHash := ledger.RootHash(from)
+	fromTrie, err := l.forestOf(fromRootHash).GetTrie(fromRootHash)
+	if err != nil {
+		return fmt.Errorf("cannot find the trie of state %s: %w", from, err)
+	}
+
+	toRootHash := ledger.RootHash(to)
+	toTrie, err := l.forestOf(toRootHash).GetTrie(toRootHash)
+	if err != nil {
+		return fmt.Errorf("cannot find the trie of state %s: %w", to, err)
+	}
+
+	return trie.Diff(fromTrie, toTrie, fn)
+}
+
 // DumpTrieAsJSON export trie at specific state as a jsonl file, each line is json encode of a payload
 func (l *Ledger) DumpTrieAsJSON(state ledger.State, outputFilePath string) error {
 	fmt.Println(ledger.RootHash(state))
@@ -2,7 +2,10 @@ package complete_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math/rand"
 	"testing"
 	"time"
@@ -14,8 +17,11 @@ import (
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common"
 	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
 	"github.com/onflow/flow-go/ledger/common/utils"
 	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/dump"
+	"github.com/onflow/flow-go/ledger/complete/wal"
 	"github.com/onflow/flow-go/ledger/partial/ptrie"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -256,6 +262,189 @@ func Test_WAL(t *testing.T) {
 	})
 }
 
+func Test_WALCompression(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		led, err := complete.NewLedger(dir, 100, &metrics.NoopCollector{}, zerolog.Nop(), nil, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		state := led.InitialState()
+		var updates []*ledger.Update
+
+		// first half of the updates is recorded uncompressed, second half compressed
+		for i := 0; i < 10; i++ {
+			if i == 5 {
+				led.SetCodec(wal.CodecSnappy)
+			}
+
+			keys := utils.RandomUniqueKeys(10, 2, 1, 10)
+			values := utils.RandomValues(10, 1, 1024)
+			update, err := ledger.NewUpdate(state, keys, values)
+			require.NoError(t, err)
+			state, err = led.Set(update)
+			require.NoError(t, err)
+
+			updates = append(updates, update)
+		}
+
+		<-led.Done()
+
+		led2, err := complete.NewLedger(dir, 100, &metrics.NoopCollector{}, zerolog.Nop(), nil, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		for _, update := range updates {
+			query, err := ledger.NewQuery(state, update.Keys())
+			require.NoError(t, err)
+			values, err := led2.Get(query)
+			require.NoError(t, err)
+			for i, value := range update.Values() {
+				require.True(t, value.Equals(values[i]))
+			}
+		}
+
+		<-led2.Done()
+	})
+}
+
+func TestLedgerWithNodeStore(t *testing.T) {
+	numInsPerStep := 10
+	keyNumberOfParts := 3
+	keyPartMinByteSize := 1
+	keyPartMaxByteSize := 20
+	valueMaxByteSize := 64
+	steps := 10
+	metricsCollector := &metrics.NoopCollector{}
+	logger := zerolog.Logger{}
+
+	unittest.RunWithTempDir(t, func(memDir string) {
+		unittest.RunWithTempDir(t, func(diskDir string) {
+
+			memLed, err := complete.NewLedger(memDir, 100, metricsCollector, logger, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			// a tiny node cache forces nodes to be loaded from disk
+			diskLed, err := complete.NewLedgerWithNodeStore(diskDir, 100, 10, metricsCollector, logger, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			require.Equal(t, memLed.InitialState(), diskLed.InitialState())
+
+			state := memLed.InitialState()
+			states := make([]ledger.State, 0, steps)
+			allKeys := make([]ledger.Key, 0)
+			for i := 0; i < steps; i++ {
+				keys := utils.RandomUniqueKeys(numInsPerStep, keyNumberOfParts, keyPartMinByteSize, keyPartMaxByteSize)
+				values := utils.RandomValues(numInsPerStep, 1, valueMaxByteSize)
+				allKeys = append(allKeys, keys...)
+
+				update, err := ledger.NewUpdate(state, keys, values)
+				require.NoError(t, err)
+
+				memState, err := memLed.Set(update)
+				require.NoError(t, err)
+				diskState, err := diskLed.Set(update)
+				require.NoError(t, err)
+				require.Equal(t, memState, diskState)
+
+				state = memState
+				states = append(states, state)
+			}
+
+			for _, s := range states {
+				query, err := ledger.NewQuery(s, allKeys)
+				require.NoError(t, err)
+
+				memValues, err := memLed.Get(query)
+				require.NoError(t, err)
+				diskValues, err := diskLed.Get(query)
+				require.NoError(t, err)
+				require.Equal(t, memValues, diskValues)
+
+				memProof, err := memLed.Prove(query)
+				require.NoError(t, err)
+				diskProof, err := diskLed.Prove(query)
+				require.NoError(t, err)
+				require.True(t, memProof.Equals(diskProof))
+			}
+
+			<-diskLed.Done()
+
+			// restarting replays the WAL into the disk-backed forest
+			diskLed2, err := complete.NewLedgerWithNodeStore(diskDir, 100, 10, metricsCollector, logger, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			query, err := ledger.NewQuery(state, allKeys)
+			require.NoError(t, err)
+			memValues, err := memLed.Get(query)
+			require.NoError(t, err)
+			diskValues, err := diskLed2.Get(query)
+			require.NoError(t, err)
+			require.Equal(t, memValues, diskValues)
+
+			<-diskLed2.Done()
+			<-memLed.Done()
+		})
+	})
+}
+
+func TestLedger_Diff(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dbDir string) {
+
+		led, err := complete.NewLedger(dbDir, 100, &metrics.NoopCollector{}, zerolog.Logger{}, nil, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		keys := utils.RandomUniqueKeys(30, 2, 1, 10)
+		values := utils.RandomValues(30, 1, 32)
+
+		update, err := ledger.NewUpdate(led.InitialState(), keys[:20], values[:20])
+		require.NoError(t, err)
+		state1, err := led.Set(update)
+		require.NoError(t, err)
+
+		// remove keys[0:5], modify keys[5:10], add keys[20:30]
+		updatedKeys := append(make([]ledger.Key, 0), keys[0:10]...)
+		updatedValues := append(make([]ledger.Value, 5), utils.RandomValues(5, 33, 64)...)
+		updatedKeys = append(updatedKeys, keys[20:]...)
+		updatedValues = append(updatedValues, values[20:]...)
+
+		update, err = ledger.NewUpdate(state1, updatedKeys, updatedValues)
+		require.NoError(t, err)
+		state2, err := led.Set(update)
+		require.NoError(t, err)
+
+		t.Run("diff between states", func(t *testing.T) {
+			counts := make(map[ledger.PayloadDiffType]int)
+			err := led.Diff(state1, state2, func(diff *ledger.PayloadDiff) error {
+				counts[diff.Type]++
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, 10, counts[ledger.PayloadAdded])
+			require.Equal(t, 5, counts[ledger.PayloadRemoved])
+			require.Equal(t, 5, counts[ledger.PayloadModified])
+		})
+
+		t.Run("diff from initial state contains all registers", func(t *testing.T) {
+			added := make([]ledger.Key, 0)
+			err := led.Diff(led.InitialState(), state1, func(diff *ledger.PayloadDiff) error {
+				require.Equal(t, ledger.PayloadAdded, diff.Type)
+				added = append(added, diff.After.Key)
+				return nil
+			})
+			require.NoError(t, err)
+			require.ElementsMatch(t, keys[:20], added)
+		})
+
+		t.Run("unknown state", func(t *testing.T) {
+			err := led.Diff(state1, ledger.State([]byte{1, 2, 3}), func(diff *ledger.PayloadDiff) error {
+				return nil
+			})
+			require.Error(t, err)
+		})
+
+		<-led.Done()
+	})
+}
+
 func TestLedgerFunctionality(t *testing.T) {
 	rand.Seed(time.Now().UnixNano())
 	// You can manually increase this for more coverage
@@ -468,6 +657,178 @@ func Test_ExportCheckpointAt(t *testing.T) {
 	})
 }
 
+func TestLedger_RestoreState(t *testing.T) {
+
+	// updates the ledger count times, returning the states, keys and values of each update
+	update := func(t *testing.T, led *complete.Ledger, state ledger.State, count int) ([]ledger.State, [][]ledger.Key, [][]ledger.Value) {
+		states := make([]ledger.State, 0, count)
+		keys := make([][]ledger.Key, 0, count)
+		values := make([][]ledger.Value, 0, count)
+		for i := 0; i < count; i++ {
+			k := utils.RandomUniqueKeys(5, 2, 1, 10)
+			v := utils.RandomValues(5, 1, 32)
+			u, err := ledger.NewUpdate(state, k, v)
+			require.NoError(t, err)
+			state, err = led.Set(u)
+			require.NoError(t, err)
+			states = append(states, state)
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+		return states, keys, values
+	}
+
+	t.Run("evicted states are pruned and can be restored", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dbDir string) {
+			led, err := complete.NewLedger(dbDir, 5, &metrics.NoopCollector{}, zerolog.Logger{}, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			states, keys, values := update(t, led, led.InitialState(), 20)
+
+			require.False(t, led.HasState(states[2]))
+			require.True(t, led.HasState(states[19]))
+
+			q, err := ledger.NewQuery(states[2], keys[2])
+			require.NoError(t, err)
+
+			_, err = led.Get(q)
+			require.True(t, errors.Is(err, ledger.ErrStatePruned{}))
+
+			_, err = led.Prove(q)
+			require.True(t, errors.Is(err, ledger.ErrStatePruned{}))
+
+			err = led.RestoreState(states[2])
+			require.NoError(t, err)
+			require.True(t, led.HasState(states[2]))
+
+			retValues, err := led.Get(q)
+			require.NoError(t, err)
+			require.Equal(t, values[2], retValues)
+
+			_, err = led.Prove(q)
+			require.NoError(t, err)
+
+			// restored states can be exported and diffed
+			err = led.ExportPayloads(states[2], ioutil.Discard)
+			require.NoError(t, err)
+
+			diffs := 0
+			err = led.Diff(states[2], states[19], func(*ledger.PayloadDiff) error {
+				diffs++
+				return nil
+			})
+			require.NoError(t, err)
+			require.NotZero(t, diffs)
+
+			// restoring doesn't evict the recent states
+			for i := 15; i < 20; i++ {
+				require.True(t, led.HasState(states[i]))
+			}
+
+			// only a limited number of restored states is kept, states[2] was restored first
+			for i := 3; i < 3+complete.RestoredStatesCapacity; i++ {
+				require.NoError(t, led.RestoreState(states[i]))
+			}
+			require.False(t, led.HasState(states[2]))
+			require.True(t, led.HasState(states[3]))
+			for i := 15; i < 20; i++ {
+				require.True(t, led.HasState(states[i]))
+			}
+
+			// states which have never existed can't be restored
+			err = led.RestoreState(ledger.State(utils.RootHashFixture()))
+			require.True(t, errors.Is(err, ledger.ErrStatePruned{}))
+
+			<-led.Done()
+		})
+	})
+
+	t.Run("states are restored from checkpoints", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dbDir string) {
+			led, err := complete.NewLedger(dbDir, 5, &metrics.NoopCollector{}, zerolog.Logger{}, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			states, keys, values := update(t, led, led.InitialState(), 10)
+			<-led.Done()
+
+			// checkpoint all segments, the checkpoint contains the last 5 states
+			w, err := wal.NewWAL(zerolog.Nop(), nil, dbDir, 5, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+			checkpointer, err := w.NewCheckpointer()
+			require.NoError(t, err)
+			_, to, err := checkpointer.NotCheckpointedSegments()
+			require.NoError(t, err)
+			err = checkpointer.Checkpoint(to, func() (io.WriteCloser, error) {
+				return checkpointer.CheckpointWriter(to)
+			})
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			led, err = complete.NewLedger(dbDir, 5, &metrics.NoopCollector{}, zerolog.Logger{}, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			newStates, newKeys, newValues := update(t, led, states[9], 10)
+			states = append(states, newStates...)
+			keys = append(keys, newKeys...)
+			values = append(values, newValues...)
+
+			// states[7] is part of the checkpoint, states[2] is only restored from the segments before it
+			for _, i := range []int{7, 2, 12} {
+				require.False(t, led.HasState(states[i]))
+
+				err = led.RestoreState(states[i])
+				require.NoError(t, err)
+
+				q, err := ledger.NewQuery(states[i], keys[i])
+				require.NoError(t, err)
+				retValues, err := led.Get(q)
+				require.NoError(t, err)
+				require.Equal(t, values[i], retValues)
+			}
+
+			<-led.Done()
+		})
+	})
+}
+
+func Test_ExportPayloads(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dbDir string) {
+		unittest.RunWithTempDir(t, func(dir2 string) {
+
+			led, err := complete.NewLedger(dbDir, 100, &metrics.NoopCollector{}, zerolog.Logger{}, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			keys := utils.RandomUniqueKeys(100, 2, 1, 10)
+			values := utils.RandomValues(100, 1, 1024)
+			u, err := ledger.NewUpdate(led.InitialState(), keys, values)
+			require.NoError(t, err)
+
+			state, err := led.Set(u)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = led.ExportPayloads(state, &buf)
+			require.NoError(t, err)
+			<-led.Done()
+
+			importedState, err := dump.ImportRootCheckpoint(&buf, dir2, zerolog.Nop())
+			require.NoError(t, err)
+			require.Equal(t, state, importedState)
+
+			led2, err := complete.NewLedger(dir2, 100, &metrics.NoopCollector{}, zerolog.Logger{}, nil, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			q, err := ledger.NewQuery(state, keys)
+			require.NoError(t, err)
+
+			retValues, err := led2.Get(q)
+			require.NoError(t, err)
+			require.Equal(t, values, retValues)
+			<-led2.Done()
+		})
+	})
+}
+
 func valuesMatches(expected []ledger.Value, got []ledger.Value) bool {
 	if len(expected) != len(got) {
 		return false
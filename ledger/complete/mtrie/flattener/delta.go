@@ -0,0 +1,115 @@
+package flattener
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+)
+
+// FlattenedForestDelta represents a Forest as a flattened data structure relative to a base
+// FlattenedForest (e.g. a previous checkpoint). It only holds the nodes which are not part of the base:
+//   * Nodes continue the base's node indices, i.e. the node Nodes[i] has index BaseNodeCount+1+i.
+//     Nodes reference their children by index, which can either point into the base or into the delta.
+//   * Tries contains all tries of the forest, each referencing their respective root node by index
+//     (in the base or in the delta).
+// The nodes of the delta satisfy the Descendents-First-Relationship (see FlattenedForest),
+// hence appending them to the base's nodes yields a valid sequence of nodes.
+type FlattenedForestDelta struct {
+	BaseNodeCount uint64 // number of nodes in the base, excluding the 0th nil element
+	Nodes         []*StorableNode
+	Tries         []*StorableTrie
+}
+
+// FlattenForestDelta returns the FlattenedForestDelta of the forest relative to the given base nodes,
+// which are the nodes of a previously flattened forest (including the 0th nil element).
+// Nodes are identified by hash; subtries which are already part of the base are not descended into.
+func FlattenForestDelta(f *mtrie.Forest, baseNodes []*StorableNode) (*FlattenedForestDelta, error) {
+	if len(baseNodes) == 0 {
+		return nil, fmt.Errorf("base nodes must at least contain the 0th nil element")
+	}
+
+	tries, err := f.GetTries()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get cached tries root hashes: %w", err)
+	}
+
+	allNodes := make(node2indexMap, len(baseNodes))
+	allNodes[""] = 0 // 0th element is nil
+	for i := 1; i < len(baseNodes); i++ {
+		allNodes[string(baseNodes[i].HashValue)] = uint64(i)
+	}
+
+	delta := &FlattenedForestDelta{
+		BaseNodeCount: uint64(len(baseNodes) - 1),
+		Nodes:         make([]*StorableNode, 0),
+		Tries:         make([]*StorableTrie, 0, len(tries)),
+	}
+
+	for _, t := range tries {
+		err := delta.addNodes(t.RootNode(), allNodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to flatten trie %s: %w", t.StringRootHash(), err)
+		}
+		storableTrie, err := toStorableTrie(t, allNodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct storable trie: %w", err)
+		}
+		delta.Tries = append(delta.Tries, storableTrie)
+	}
+
+	return delta, nil
+}
+
+// addNodes appends all nodes of the subtrie, which are not yet indexed, to the delta.
+// Children are added before their parent, which maintains the Descendents-First-Relationship.
+func (d *FlattenedForestDelta) addNodes(n *node.Node, allNodes node2indexMap) error {
+	if n == nil {
+		return nil
+	}
+	if _, has := allNodes[string(n.Hash())]; has {
+		return nil
+	}
+
+	lChild, err := n.LeftChild()
+	if err != nil {
+		return err
+	}
+	err = d.addNodes(lChild, allNodes)
+	if err != nil {
+		return err
+	}
+	rChild, err := n.RightChild()
+	if err != nil {
+		return err
+	}
+	err = d.addNodes(rChild, allNodes)
+	if err != nil {
+		return err
+	}
+
+	allNodes[string(n.Hash())] = d.BaseNodeCount + uint64(len(d.Nodes)) + 1
+	storableNode, err := toStorableNode(n, allNodes)
+	if err != nil {
+		return fmt.Errorf("failed to construct storable node: %w", err)
+	}
+	d.Nodes = append(d.Nodes, storableNode)
+	return nil
+}
+
+// ApplyTo returns the FlattenedForest resulting from applying the delta to the given base.
+// The nodes of the base are shared with the result, the tries are the delta's tries.
+func (d *FlattenedForestDelta) ApplyTo(base *FlattenedForest) (*FlattenedForest, error) {
+	if uint64(len(base.Nodes)) != d.BaseNodeCount+1 {
+		return nil, fmt.Errorf("delta expects a base with %d nodes, but base has %d nodes", d.BaseNodeCount, len(base.Nodes)-1)
+	}
+
+	nodes := make([]*StorableNode, 0, len(base.Nodes)+len(d.Nodes))
+	nodes = append(nodes, base.Nodes...)
+	nodes = append(nodes, d.Nodes...)
+
+	return &FlattenedForest{
+		Nodes: nodes,
+		Tries: d.Tries,
+	}, nil
+}
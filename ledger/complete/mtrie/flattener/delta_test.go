@@ -0,0 +1,85 @@
+package flattener_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+func TestForestDeltaApply(t *testing.T) {
+	pathByteSize := 1
+	dir, err := ioutil.TempDir("", "test-mtrie-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	metricsCollector := &metrics.NoopCollector{}
+	mForest, err := mtrie.NewForest(pathByteSize, dir, 5, metricsCollector, nil)
+	require.NoError(t, err)
+	rootHash := mForest.GetEmptyRootHash()
+
+	p1 := utils.OneBytePath(1)
+	v1 := utils.LightPayload8('A', 'a')
+	p2 := utils.OneBytePath(2)
+	v2 := utils.LightPayload8('B', 'b')
+	p3 := utils.OneBytePath(130)
+	v3 := utils.LightPayload8('C', 'c')
+
+	paths := []ledger.Path{p1, p2, p3}
+	payloads := []*ledger.Payload{v1, v2, v3}
+
+	update := &ledger.TrieUpdate{RootHash: rootHash, Paths: paths, Payloads: payloads}
+	rootHash, err = mForest.Update(update)
+	require.NoError(t, err)
+
+	base, err := flattener.FlattenForest(mForest)
+	require.NoError(t, err)
+
+	p4 := utils.OneBytePath(131)
+	v4 := utils.LightPayload8('D', 'd')
+	update = &ledger.TrieUpdate{RootHash: rootHash, Paths: []ledger.Path{p4}, Payloads: []*ledger.Payload{v4}}
+	rootHash, err = mForest.Update(update)
+	require.NoError(t, err)
+
+	delta, err := flattener.FlattenForestDelta(mForest, base.Nodes)
+	require.NoError(t, err)
+
+	// only nodes on the path to the new leaf are part of the delta
+	require.Equal(t, uint64(len(base.Nodes)-1), delta.BaseNodeCount)
+	require.Less(t, len(delta.Nodes), len(base.Nodes)-1)
+	require.Len(t, delta.Tries, 3)
+
+	t.Run("applied delta rebuilds forest", func(t *testing.T) {
+		forestSequencing, err := delta.ApplyTo(base)
+		require.NoError(t, err)
+
+		newForest, err := mtrie.NewForest(pathByteSize, dir, 5, metricsCollector, nil)
+		require.NoError(t, err)
+
+		rebuiltTries, err := flattener.RebuildTries(forestSequencing)
+		require.NoError(t, err)
+		err = newForest.AddTries(rebuiltTries)
+		require.NoError(t, err)
+
+		read := &ledger.TrieRead{RootHash: rootHash, Paths: []ledger.Path{p1, p2, p3, p4}}
+		retPayloads, err := mForest.Read(read)
+		require.NoError(t, err)
+		newRetPayloads, err := newForest.Read(read)
+		require.NoError(t, err)
+		for i := range read.Paths {
+			require.True(t, retPayloads[i].Equals(newRetPayloads[i]))
+		}
+	})
+
+	t.Run("delta cannot be applied to a different base", func(t *testing.T) {
+		_, err := delta.ApplyTo(&flattener.FlattenedForest{Nodes: base.Nodes[:1]})
+		require.Error(t, err)
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"runtime"
+	"sync"
 
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/encoding"
@@ -29,8 +31,10 @@ type FlattenedForest struct {
 	Tries []*StorableTrie
 }
 
-// node2indexMap maps a node pointer to the node index in the serialization
-type node2indexMap map[*node.Node]uint64
+// node2indexMap maps a node hash to the node index in the serialization.
+// Nodes are identified by hash rather than by pointer, as paged nodes
+// might be re-instantiated when loaded from a node store.
+type node2indexMap map[string]uint64
 
 // FlattenForest returns forest FlattenedForest, which contains all nodes and tries of the Forest.
 func FlattenForest(f *mtrie.Forest) (*FlattenedForest, error) {
@@ -44,15 +48,16 @@ func FlattenForest(f *mtrie.Forest) (*FlattenedForest, error) {
 
 	// assign unique value to every node
 	allNodes := make(node2indexMap)
-	allNodes[nil] = 0 // 0th element is nil
+	allNodes[""] = 0 // 0th element is nil
 
 	counter := uint64(1) // start from 1, as 0 marks nil
 	for _, t := range tries {
-		for itr := NewNodeIterator(t); itr.Next(); {
+		itr := NewNodeIterator(t)
+		for itr.Next() {
 			n := itr.Value()
 			// if node not in map
-			if _, has := allNodes[n]; !has {
-				allNodes[n] = counter
+			if _, has := allNodes[string(n.Hash())]; !has {
+				allNodes[string(n.Hash())] = counter
 				counter++
 				storableNode, err := toStorableNode(n, allNodes)
 				if err != nil {
@@ -61,6 +66,9 @@ func FlattenForest(f *mtrie.Forest) (*FlattenedForest, error) {
 				storableNodes = append(storableNodes, storableNode)
 			}
 		}
+		if err := itr.Err(); err != nil {
+			return nil, fmt.Errorf("failed to iterate trie nodes: %w", err)
+		}
 		//fix root nodes indices
 		// since we indexed all nodes, root must be present
 		storableTrie, err := toStorableTrie(t, allNodes)
@@ -77,13 +85,13 @@ func FlattenForest(f *mtrie.Forest) (*FlattenedForest, error) {
 }
 
 func toStorableNode(node *node.Node, indexForNode node2indexMap) (*StorableNode, error) {
-	leftIndex, found := indexForNode[node.LeftChild()]
+	leftIndex, found := indexForNode[string(node.LeftChildHash())]
 	if !found {
-		return nil, fmt.Errorf("internal error: missing node with hash %s", hex.EncodeToString(node.LeftChild().Hash()))
+		return nil, fmt.Errorf("internal error: missing node with hash %s", hex.EncodeToString(node.LeftChildHash()))
 	}
-	rightIndex, found := indexForNode[node.RightChild()]
+	rightIndex, found := indexForNode[string(node.RightChildHash())]
 	if !found {
-		return nil, fmt.Errorf("internal error: missing node with hash %s", hex.EncodeToString(node.RightChild().Hash()))
+		return nil, fmt.Errorf("internal error: missing node with hash %s", hex.EncodeToString(node.RightChildHash()))
 	}
 
 	storableNode := &StorableNode{
@@ -100,7 +108,7 @@ func toStorableNode(node *node.Node, indexForNode node2indexMap) (*StorableNode,
 }
 
 func toStorableTrie(mtrie *trie.MTrie, indexForNode node2indexMap) (*StorableTrie, error) {
-	rootIndex, found := indexForNode[mtrie.RootNode()]
+	rootIndex, found := indexForNode[string(mtrie.RootHash())]
 	if !found {
 		return nil, fmt.Errorf("internal error: missing node with hash %s", hex.EncodeToString(mtrie.RootNode().Hash()))
 	}
@@ -137,6 +145,11 @@ func RebuildTries(flatForest *FlattenedForest) ([]*trie.MTrie, error) {
 // RebuildNodes generates a list of Nodes from a sequence of StorableNodes.
 // The sequence must obey the DESCENDANTS-FIRST-RELATIONSHIP
 func RebuildNodes(storableNodes []*StorableNode) ([]*node.Node, error) {
+	payloads, err := decodePayloads(storableNodes)
+	if err != nil {
+		return nil, err
+	}
+
 	nodes := make([]*node.Node, 0, len(storableNodes))
 	for i, snode := range storableNodes {
 		if snode == nil {
@@ -149,11 +162,7 @@ func RebuildNodes(storableNodes []*StorableNode) ([]*node.Node, error) {
 
 		if len(snode.Path) > 0 {
 			path := ledger.Path(snode.Path)
-			payload, err := encoding.DecodePayload(snode.EncPayload)
-			if err != nil {
-				return nil, fmt.Errorf("failed to decode a payload for an storableNode %w", err)
-			}
-			node := node.NewNode(int(snode.Height), nodes[snode.LIndex], nodes[snode.RIndex], path, payload, snode.HashValue, snode.MaxDepth, snode.RegCount)
+			node := node.NewNode(int(snode.Height), nodes[snode.LIndex], nodes[snode.RIndex], path, payloads[i], snode.HashValue, snode.MaxDepth, snode.RegCount)
 			nodes = append(nodes, node)
 			continue
 		}
@@ -162,3 +171,51 @@ func RebuildNodes(storableNodes []*StorableNode) ([]*node.Node, error) {
 	}
 	return nodes, nil
 }
+
+// decodePayloads decodes the payloads of all leaf nodes concurrently.
+// Linking the nodes has to follow the DESCENDANTS-FIRST-RELATIONSHIP, but decoding
+// the payloads, which is the bulk of the work, does not depend on the order.
+func decodePayloads(storableNodes []*StorableNode) ([]*ledger.Payload, error) {
+	payloads := make([]*ledger.Payload, len(storableNodes))
+
+	workers := runtime.NumCPU()
+	chunkSize := (len(storableNodes) + workers - 1) / workers
+
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunkSize
+		end := start + chunkSize
+		if end > len(storableNodes) {
+			end = len(storableNodes)
+		}
+		if start >= end {
+			break
+		}
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				snode := storableNodes[i]
+				if snode == nil || len(snode.Path) == 0 {
+					continue
+				}
+				payload, err := encoding.DecodePayload(snode.EncPayload)
+				if err != nil {
+					errs[w] = fmt.Errorf("failed to decode a payload for an storableNode %w", err)
+					return
+				}
+				payloads[i] = payload
+			}
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return payloads, nil
+}
@@ -1,6 +1,8 @@
 package flattener
 
 import (
+	"bytes"
+
 	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 )
@@ -52,6 +54,9 @@ type NodeIterator struct {
 	// This has the advantage, that we gracefully handle tries whose root node is nil.
 	unprocessedRoot *node.Node
 	stack           []*node.Node
+
+	// err is the error of loading a paged node, which ends the iteration
+	err error
 }
 
 // NewNodeIterator returns a node NodeIterator, which iterates through all nodes
@@ -74,12 +79,19 @@ func NewNodeIterator(mTrie *trie.MTrie) *NodeIterator {
 	return i
 }
 
+// Next advances the iterator to the next node and returns true if there is one.
+// It returns false once all nodes were iterated, or if a paged node couldn't be
+// loaded, in which case Err returns the error.
 func (i *NodeIterator) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
 	if i.unprocessedRoot != nil {
 		// initial call to Next() for a non-empty trie
-		i.dig(i.unprocessedRoot)
+		i.err = i.dig(i.unprocessedRoot)
 		i.unprocessedRoot = nil
-		return true
+		return i.err == nil
 	}
 
 	// the current head of the stack, `n`, has been recalled
@@ -90,15 +102,29 @@ func (i *NodeIterator) Next() bool {
 		// Before we can recall `p`, we need to dig into the parent's right child, if we haven't
 		// done so already. As we decent into the left child with priority, the only case where
 		// we still need to dig into the right child is, if n is p's left child.
+		// Note: children of paged nodes are loaded on demand and might be re-instantiated,
+		// hence we identify the child by its hash rather than by its pointer.
 		parent := i.peek()
-		if parent.LeftChild() == n {
-			i.dig(parent.RightChild())
+		if bytes.Equal(parent.LeftChildHash(), n.Hash()) {
+			rChild, err := parent.RightChild()
+			if err == nil {
+				err = i.dig(rChild)
+			}
+			if err != nil {
+				i.err = err
+				return false
+			}
 		}
 		return true
 	}
 	return false // as len(i.stack) == 0, i.e. there are no more elements to recall
 }
 
+// Err returns the error which ended the iteration, if any.
+func (i *NodeIterator) Err() error {
+	return i.err
+}
+
 func (i *NodeIterator) Value() *node.Node {
 	if len(i.stack) == 0 {
 		return nil
@@ -117,20 +143,28 @@ func (i *NodeIterator) peek() *node.Node {
 	return i.stack[len(i.stack)-1]
 }
 
-func (i *NodeIterator) dig(n *node.Node) {
+func (i *NodeIterator) dig(n *node.Node) error {
 	if n == nil {
-		return
+		return nil
 	}
 	for {
 		i.stack = append(i.stack, n)
-		if lChild := n.LeftChild(); lChild != nil {
+		lChild, err := n.LeftChild()
+		if err != nil {
+			return err
+		}
+		if lChild != nil {
 			n = lChild
 			continue
 		}
-		if rChild := n.RightChild(); rChild != nil {
+		rChild, err := n.RightChild()
+		if err != nil {
+			return err
+		}
+		if rChild != nil {
 			n = rChild
 			continue
 		}
-		return
+		return nil
 	}
 }
@@ -63,15 +63,24 @@ func TestPopulatedTrie(t *testing.T) {
 
 	require.True(t, itr.Next())
 	p_parent := itr.Value()
-	require.Equal(t, p1_leaf, p_parent.LeftChild())
-	require.Equal(t, p2_leaf, p_parent.RightChild())
+	lChild, err := p_parent.LeftChild()
+	require.NoError(t, err)
+	require.Equal(t, p1_leaf, lChild)
+	rChild, err := p_parent.RightChild()
+	require.NoError(t, err)
+	require.Equal(t, p2_leaf, rChild)
 
 	require.True(t, itr.Next())
 	root := itr.Value()
 	require.Equal(t, testTrie.RootNode(), root)
-	require.Equal(t, p_parent, root.LeftChild())
-	require.True(t, nil == root.RightChild())
+	lChild, err = root.LeftChild()
+	require.NoError(t, err)
+	require.Equal(t, p_parent, lChild)
+	rChild, err = root.RightChild()
+	require.NoError(t, err)
+	require.True(t, nil == rChild)
 
 	require.False(t, itr.Next())
+	require.NoError(t, itr.Err())
 	require.True(t, nil == itr.Value())
 }
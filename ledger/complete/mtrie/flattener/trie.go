@@ -3,7 +3,6 @@ package flattener
 import (
 	"fmt"
 
-	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 )
 
@@ -28,15 +27,16 @@ func FlattenTrie(trie *trie.MTrie) (*FlattenedTrie, error) {
 	storableNodes := []*StorableNode{nil} // 0th element is nil
 
 	// assign unique value to every node
-	allNodes := make(map[*node.Node]uint64)
-	allNodes[nil] = 0 // 0th element is nil
+	allNodes := make(node2indexMap)
+	allNodes[""] = 0 // 0th element is nil
 
 	counter := uint64(1) // start from 1, as 0 marks nil
-	for itr := NewNodeIterator(trie); itr.Next(); {
+	itr := NewNodeIterator(trie)
+	for itr.Next() {
 		n := itr.Value()
 		// if node not in map
-		if _, has := allNodes[n]; !has {
-			allNodes[n] = counter
+		if _, has := allNodes[string(n.Hash())]; !has {
+			allNodes[string(n.Hash())] = counter
 			counter++
 			storableNode, err := toStorableNode(n, allNodes)
 			if err != nil {
@@ -45,6 +45,9 @@ func FlattenTrie(trie *trie.MTrie) (*FlattenedTrie, error) {
 			storableNodes = append(storableNodes, storableNode)
 		}
 	}
+	if err := itr.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate trie nodes: %w", err)
+	}
 	// fix root nodes indices
 	// since we indexed all nodes, root must be present
 	storableTrie, err := toStorableTrie(trie, allNodes)
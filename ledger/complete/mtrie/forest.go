@@ -10,6 +10,7 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/nodestore"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/utils/io"
@@ -36,6 +37,10 @@ type Forest struct {
 	onTreeEvicted  func(tree *trie.MTrie) error
 	pathByteSize   int // length [bytes] of register path
 	metrics        module.LedgerMetrics
+	// nodeStore (optional) persists the nodes of all tries added to the forest.
+	// If set, the forest only holds paged tries, whose nodes are loaded from
+	// the node store on demand.
+	nodeStore *nodestore.Store
 }
 
 // NewForest returns a new instance of memory forest.
@@ -46,6 +51,24 @@ type Forest struct {
 // Make sure you chose a sufficiently large forestCapacity, such that, when reaching the capacity, the
 // Least Recently Used trie will never be needed again.
 func NewForest(pathByteSize int, trieStorageDir string, forestCapacity int, metrics module.LedgerMetrics, onTreeEvicted func(tree *trie.MTrie) error) (*Forest, error) {
+	return newForest(pathByteSize, trieStorageDir, forestCapacity, nil, metrics, onTreeEvicted)
+}
+
+// NewForestWithNodeStore returns a new instance of a disk-backed forest.
+//
+// Every trie added to the forest has its nodes persisted in the given node store and is replaced
+// by a paged trie, whose nodes are loaded from the store on demand. Hence, only the hot nodes
+// kept by the node store's cache are held in memory, independently of the size of the tries.
+// Reads, updates and proofs as well as the resulting root hashes are identical to the in-memory forest.
+// The same CAUTION on forestCapacity applies as for NewForest.
+func NewForestWithNodeStore(pathByteSize int, trieStorageDir string, forestCapacity int, nodeStore *nodestore.Store, metrics module.LedgerMetrics, onTreeEvicted func(tree *trie.MTrie) error) (*Forest, error) {
+	if nodeStore == nil {
+		return nil, errors.New("node store must not be nil")
+	}
+	return newForest(pathByteSize, trieStorageDir, forestCapacity, nodeStore, metrics, onTreeEvicted)
+}
+
+func newForest(pathByteSize int, trieStorageDir string, forestCapacity int, nodeStore *nodestore.Store, metrics module.LedgerMetrics, onTreeEvicted func(tree *trie.MTrie) error) (*Forest, error) {
 	// init LRU cache as a SHORTCUT for a usage-related storage eviction policy
 	var cache *lru.Cache
 	var err error
@@ -75,6 +98,7 @@ func NewForest(pathByteSize int, trieStorageDir string, forestCapacity int, metr
 		onTreeEvicted:  onTreeEvicted,
 		pathByteSize:   pathByteSize,
 		metrics:        metrics,
+		nodeStore:      nodeStore,
 	}
 
 	// add empty roothash
@@ -319,6 +343,12 @@ func (f *Forest) GetTrie(rootHash ledger.RootHash) (*trie.MTrie, error) {
 	return nil, fmt.Errorf("trie with the given rootHash [%v] not found", encRootHash)
 }
 
+// HasTrie returns true if the trie with the given rootHash is in the forest.
+// In contrast to GetTrie, it doesn't mark the trie as recently used.
+func (f *Forest) HasTrie(rootHash ledger.RootHash) bool {
+	return f.tries.Contains(hex.EncodeToString(rootHash))
+}
+
 // GetTries returns list of currently cached tree root hashes
 func (f *Forest) GetTries() ([]*trie.MTrie, error) {
 	// ToDo needs concurrency safety
@@ -364,6 +394,17 @@ func (f *Forest) AddTrie(newTrie *trie.MTrie) error {
 		}
 		return fmt.Errorf("forest already contains a tree with same root hash but other properties")
 	}
+
+	if f.nodeStore != nil {
+		pagedRoot, err := f.nodeStore.StoreTrie(newTrie.RootNode())
+		if err != nil {
+			return fmt.Errorf("cannot persist trie nodes: %w", err)
+		}
+		newTrie, err = trie.NewMTrie(pagedRoot)
+		if err != nil {
+			return fmt.Errorf("cannot construct paged trie: %w", err)
+		}
+	}
 	f.tries.Add(hashString, newTrie)
 	f.metrics.ForestNumberOfTrees(uint64(f.tries.Len()))
 
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/onflow/flow-go/ledger/common"
 	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/nodestore"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/ledger/partial/ptrie"
 	"github.com/onflow/flow-go/module/metrics"
@@ -986,6 +988,80 @@ func TestRandomUpdateReadProof(t *testing.T) {
 	}
 }
 
+// TestForestWithNodeStore applies the same random updates to an in-memory forest and to a
+// disk-backed forest and verifies that root hashes, reads and proofs are identical
+func TestForestWithNodeStore(t *testing.T) {
+	pathByteSize := 2 // path size of 16 bits
+	rep := 20
+	maxNumPathsPerStep := 20
+	rand.Seed(time.Now().UnixNano())
+	dir, err := ioutil.TempDir("", "test-mtrie-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // clean up
+
+	memForest, err := NewForest(pathByteSize, dir, 100, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+
+	// a tiny cache forces most nodes to be loaded from disk
+	nodeStore, err := nodestore.NewStore(filepath.Join(dir, "nodes"), 10)
+	require.NoError(t, err)
+	defer nodeStore.Close()
+
+	diskForest, err := NewForestWithNodeStore(pathByteSize, dir, 100, nodeStore, &metrics.NoopCollector{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, memForest.GetEmptyRootHash(), diskForest.GetEmptyRootHash())
+
+	roots := []ledger.RootHash{memForest.GetEmptyRootHash()}
+	allPaths := make([]ledger.Path, 0)
+	for e := 0; e < rep; e++ {
+		paths := utils.RandomPathsRandLen(maxNumPathsPerStep, pathByteSize)
+		payloads := utils.RandomPayloads(len(paths), 2, 10)
+		allPaths = append(allPaths, paths...)
+
+		// fork from a random previous state
+		parent := roots[rand.Intn(len(roots))]
+		update := &ledger.TrieUpdate{RootHash: parent, Paths: paths, Payloads: payloads}
+
+		memRoot, err := memForest.Update(update)
+		require.NoError(t, err)
+		diskRoot, err := diskForest.Update(update)
+		require.NoError(t, err)
+		require.Equal(t, memRoot, diskRoot)
+		roots = append(roots, memRoot)
+
+		diskTrie, err := diskForest.GetTrie(diskRoot)
+		require.NoError(t, err)
+		require.True(t, diskTrie.RootNode().IsPaged())
+	}
+
+	for _, root := range roots {
+		read := &ledger.TrieRead{RootHash: root, Paths: allPaths}
+
+		memPayloads, err := memForest.Read(read)
+		require.NoError(t, err)
+		diskPayloads, err := diskForest.Read(read)
+		require.NoError(t, err)
+		for i := range memPayloads {
+			require.True(t, memPayloads[i].Equals(diskPayloads[i]))
+		}
+
+		memProofs, err := memForest.Proofs(read)
+		require.NoError(t, err)
+		diskProofs, err := diskForest.Proofs(read)
+		require.NoError(t, err)
+		require.True(t, memProofs.Equals(diskProofs))
+		require.True(t, common.VerifyTrieBatchProof(diskProofs, ledger.State(root)))
+
+		memTrie, err := memForest.GetTrie(root)
+		require.NoError(t, err)
+		diskTrie, err := diskForest.GetTrie(root)
+		require.NoError(t, err)
+		require.Equal(t, memTrie.AllocatedRegCount(), diskTrie.AllocatedRegCount())
+		require.Equal(t, memTrie.MaxDepth(), diskTrie.MaxDepth())
+		require.True(t, diskTrie.IsAValidTrie())
+	}
+}
+
 // TestProofGenerationInclusion tests that inclusion proofs generated by a Trie pass verification
 func TestProofGenerationInclusion(t *testing.T) {
 	pathByteSize := 2 // path size of 16 bits
@@ -27,6 +27,11 @@ import (
 //
 // Nodes are supposed to be used in READ-ONLY fashion. However,
 // for performance reasons, we not not copy read.
+//
+// A node can also be PAGED: instead of holding pointers to its children, it only
+// holds the children's hashes and loads the children on demand through a Resolver
+// (e.g. an on-disk node store). Paged nodes are indistinguishable from in-memory
+// nodes through the node's API.
 // TODO: optimized data structures might be able to reduce memory consumption
 type Node struct {
 	lChild    *Node           // Left Child
@@ -37,6 +42,15 @@ type Node struct {
 	hashValue []byte          // hash value of node (cached)
 	maxDepth  uint16          // captures the longest path from this node to compacted leafs in the subtree
 	regCount  uint64          // number of registers allocated in the subtree
+	lHash     []byte          // hash of the left child (paged nodes only)
+	rHash     []byte          // hash of the right child (paged nodes only)
+	resolver  Resolver        // resolves the children by hash (paged nodes only)
+}
+
+// Resolver loads paged nodes by their hash value.
+type Resolver interface {
+	// Resolve returns the node with the given hash value.
+	Resolve(hash []byte) (*Node, error)
 }
 
 // NewNode creates a new Node.
@@ -72,6 +86,34 @@ func NewNode(height int,
 	return n
 }
 
+// NewPagedNode creates a new paged Node, whose children are referenced by
+// their hash values and loaded on demand through the given resolver.
+// A nil child hash means the respective child is nil.
+// UNCHECKED requirement: combination of values must conform to
+// a valid node type (see documentation of `Node` for details)
+func NewPagedNode(height int,
+	lHash,
+	rHash []byte,
+	path ledger.Path,
+	payload *ledger.Payload,
+	hashValue []byte,
+	maxDepth uint16,
+	regCount uint64,
+	resolver Resolver) *Node {
+
+	return &Node{
+		height:    height,
+		path:      path,
+		payload:   payload,
+		hashValue: hashValue,
+		maxDepth:  maxDepth,
+		regCount:  regCount,
+		lHash:     lHash,
+		rHash:     rHash,
+		resolver:  resolver,
+	}
+}
+
 // NewEmptyTreeRoot creates a compact leaf Node
 // UNCHECKED requirement: height must be non-negative
 func NewEmptyTreeRoot(height int) *Node {
@@ -143,7 +185,8 @@ func NewInterimNode(height int, lchild, rchild *Node) *Node {
 // computeHash computes the hashValue for the given Node
 // we kept it this way to stay compatible with the previous versions
 func (n *Node) computeHash() []byte {
-	if n.lChild == nil && n.rChild == nil {
+	lHash, rHash := n.LeftChildHash(), n.RightChildHash()
+	if lHash == nil && rHash == nil {
 		// both ROOT NODE and LEAF NODE have n.lChild == n.rChild == nil
 		if n.payload != nil {
 			// LEAF node: defined by key-value pair
@@ -155,24 +198,34 @@ func (n *Node) computeHash() []byte {
 
 	// this is an INTERIOR node at least one of lChild or rChild is not nil.
 	h1 := common.GetDefaultHashForHeight(n.height - 1)
-	if n.lChild != nil {
-		h1 = n.lChild.Hash()
+	if lHash != nil {
+		h1 = lHash
 	}
 	h2 := common.GetDefaultHashForHeight(n.height - 1)
-	if n.rChild != nil {
-		h2 = n.rChild.Hash()
+	if rHash != nil {
+		h2 = rHash
 	}
 	return common.HashInterNode(h1, h2)
 }
 
+// VerifyCachedHash verifies the cached hash values of the node and of its subtrie.
+// A subtrie whose paged nodes cannot be loaded can't be verified.
 func (n *Node) VerifyCachedHash() bool {
-	if n.lChild != nil {
-		if !n.lChild.VerifyCachedHash() {
+	lChild, err := n.LeftChild()
+	if err != nil {
+		return false
+	}
+	if lChild != nil {
+		if !lChild.VerifyCachedHash() {
 			return false
 		}
 	}
-	if n.rChild != nil {
-		if !n.rChild.VerifyCachedHash() {
+	rChild, err := n.RightChild()
+	if err != nil {
+		return false
+	}
+	if rChild != nil {
+		if !rChild.VerifyCachedHash() {
 			return false
 		}
 	}
@@ -208,13 +261,60 @@ func (n *Node) Payload() *ledger.Payload { return n.payload }
 
 // LeftChild returns the the Node's left child.
 // Only INTERIOR nodes have children.
+// For paged nodes, the child is loaded through the node's resolver, which
+// errors if the child cannot be loaded from the node store.
 // Do NOT MODIFY returned Node!
-func (n *Node) LeftChild() *Node { return n.lChild }
+func (n *Node) LeftChild() (*Node, error) {
+	if n.lHash == nil {
+		return n.lChild, nil
+	}
+	return n.resolve(n.lHash)
+}
 
 // RightChild returns the the Node's right child.
 // Only INTERIOR nodes have children.
+// For paged nodes, the child is loaded through the node's resolver, which
+// errors if the child cannot be loaded from the node store.
 // Do NOT MODIFY returned Node!
-func (n *Node) RightChild() *Node { return n.rChild }
+func (n *Node) RightChild() (*Node, error) {
+	if n.rHash == nil {
+		return n.rChild, nil
+	}
+	return n.resolve(n.rHash)
+}
+
+// LeftChildHash returns the hash of the Node's left child or nil if there
+// is no left child. In contrast to LeftChild, it never loads paged nodes.
+// Do NOT MODIFY returned slice!
+func (n *Node) LeftChildHash() []byte {
+	if n.lChild != nil {
+		return n.lChild.Hash()
+	}
+	return n.lHash
+}
+
+// RightChildHash returns the hash of the Node's right child or nil if there
+// is no right child. In contrast to RightChild, it never loads paged nodes.
+// Do NOT MODIFY returned slice!
+func (n *Node) RightChildHash() []byte {
+	if n.rChild != nil {
+		return n.rChild.Hash()
+	}
+	return n.rHash
+}
+
+// IsPaged returns true if and only if the Node's children are loaded on demand
+// through a resolver, i.e. the node has been read from a node store.
+func (n *Node) IsPaged() bool { return n.resolver != nil }
+
+// resolve loads a paged child node.
+func (n *Node) resolve(hash []byte) (*Node, error) {
+	child, err := n.resolver.Resolve(hash)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve paged node %x: %w", hash, err)
+	}
+	return child, nil
+}
 
 // IsLeaf returns true if and only if Node is a LEAF.
 func (n *Node) IsLeaf() bool {
@@ -225,12 +325,16 @@ func (n *Node) IsLeaf() bool {
 // FmtStr provides formatted string representation of the Node and sub tree
 func (n *Node) FmtStr(prefix string, subpath string) string {
 	right := ""
-	if n.rChild != nil {
-		right = fmt.Sprintf("\n%v", n.rChild.FmtStr(prefix+"\t", subpath+"1"))
+	if rChild, err := n.RightChild(); err != nil {
+		right = fmt.Sprintf("\n%v%v", prefix+"\t", err)
+	} else if rChild != nil {
+		right = fmt.Sprintf("\n%v", rChild.FmtStr(prefix+"\t", subpath+"1"))
 	}
 	left := ""
-	if n.lChild != nil {
-		left = fmt.Sprintf("\n%v", n.lChild.FmtStr(prefix+"\t", subpath+"0"))
+	if lChild, err := n.LeftChild(); err != nil {
+		left = fmt.Sprintf("\n%v%v", prefix+"\t", err)
+	} else if lChild != nil {
+		left = fmt.Sprintf("\n%v", lChild.FmtStr(prefix+"\t", subpath+"0"))
 	}
 	payloadSize := 0
 	if n.payload != nil {
@@ -242,20 +346,34 @@ func (n *Node) FmtStr(prefix string, subpath string) string {
 }
 
 // AllPayloads returns the payload of this node and all payloads of the subtrie
-func (n *Node) AllPayloads() []ledger.Payload {
+func (n *Node) AllPayloads() ([]ledger.Payload, error) {
 	payloads := make([]ledger.Payload, 0)
 	if n.IsLeaf() {
 		payloads = append(payloads, *n.Payload())
 	}
 
-	if lChild := n.LeftChild(); lChild != nil {
-		cp := lChild.AllPayloads()
+	lChild, err := n.LeftChild()
+	if err != nil {
+		return nil, err
+	}
+	if lChild != nil {
+		cp, err := lChild.AllPayloads()
+		if err != nil {
+			return nil, err
+		}
 		payloads = append(payloads, cp...)
 	}
 
-	if rChild := n.RightChild(); rChild != nil {
-		cp := rChild.AllPayloads()
+	rChild, err := n.RightChild()
+	if err != nil {
+		return nil, err
+	}
+	if rChild != nil {
+		cp, err := rChild.AllPayloads()
+		if err != nil {
+			return nil, err
+		}
 		payloads = append(payloads, cp...)
 	}
-	return payloads
+	return payloads, nil
 }
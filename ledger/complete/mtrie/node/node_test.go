@@ -120,7 +120,9 @@ func Test_AllPayloads(t *testing.T) {
 	n3 := node.NewLeaf(path, payload, 0)
 	n4 := node.NewInterimNode(1, n1, n2)
 	n5 := node.NewInterimNode(1, n4, n3)
-	require.Equal(t, len(n5.AllPayloads()), 3)
+	payloads, err := n5.AllPayloads()
+	require.NoError(t, err)
+	require.Equal(t, len(payloads), 3)
 }
 
 func Test_VerifyCachedHash(t *testing.T) {
@@ -0,0 +1,119 @@
+package nodestore
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+)
+
+const encodingDecodingVersion = uint16(0)
+
+// encodeNode encodes a node for the node store.
+// Children are referenced by their hash values, the node's own hash
+// value is not part of the encoding, as it is used as the storage key.
+func encodeNode(n *node.Node) []byte {
+	lHash := n.LeftChildHash()
+	rHash := n.RightChildHash()
+	encPayload := encoding.EncodePayload(n.Payload())
+
+	length := 2 + 2 + 2 + 8 + 2 + len(lHash) + 2 + len(rHash) + 2 + len(n.Path()) + 4 + len(encPayload)
+	buf := make([]byte, 0, length)
+
+	// 2-bytes encoding version
+	buf = utils.AppendUint16(buf, encodingDecodingVersion)
+
+	// 2-bytes Big Endian uint16 height
+	buf = utils.AppendUint16(buf, uint16(n.Height()))
+
+	// 2-bytes Big Endian maxDepth
+	buf = utils.AppendUint16(buf, n.MaxDepth())
+
+	// 8-bytes Big Endian regCount
+	buf = utils.AppendUint64(buf, n.RegCount())
+
+	// 2-bytes Big Endian uint16 length and n-bytes hash of the left child
+	buf = utils.AppendShortData(buf, lHash)
+
+	// 2-bytes Big Endian uint16 length and n-bytes hash of the right child
+	buf = utils.AppendShortData(buf, rHash)
+
+	// 2-bytes Big Endian uint16 encoded path length and n-bytes encoded path
+	buf = utils.AppendShortData(buf, n.Path())
+
+	// 4-bytes Big Endian uint32 encoded payload length and n-bytes encoded payload
+	buf = utils.AppendLongData(buf, encPayload)
+
+	return buf
+}
+
+// decodeNode decodes a node read from the node store into a paged node,
+// whose children are resolved through the given resolver.
+func decodeNode(hash []byte, data []byte, resolver node.Resolver) (*node.Node, error) {
+	version, rest, err := utils.ReadUint16(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding stored node: %w", err)
+	}
+	if version > encodingDecodingVersion {
+		return nil, fmt.Errorf("error decoding stored node: unsuported version %d > %d", version, encodingDecodingVersion)
+	}
+
+	height, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding stored node: %w", err)
+	}
+
+	maxDepth, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding stored node: %w", err)
+	}
+
+	regCount, rest, err := utils.ReadUint64(rest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding stored node: %w", err)
+	}
+
+	lHash, rest, err := utils.ReadShortData(rest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read left child hash: %w", err)
+	}
+
+	rHash, rest, err := utils.ReadShortData(rest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read right child hash: %w", err)
+	}
+
+	path, rest, err := utils.ReadShortData(rest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read path data: %w", err)
+	}
+
+	encPayload, _, err := utils.ReadLongData(rest)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read payload data: %w", err)
+	}
+
+	payload, err := encoding.DecodePayload(encPayload)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode payload: %w", err)
+	}
+
+	return node.NewPagedNode(int(height),
+		nilIfEmpty(lHash),
+		nilIfEmpty(rHash),
+		ledger.Path(nilIfEmpty(path)),
+		payload,
+		hash,
+		maxDepth,
+		regCount,
+		resolver), nil
+}
+
+func nilIfEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
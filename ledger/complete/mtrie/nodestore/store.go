@@ -0,0 +1,166 @@
+package nodestore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+)
+
+// DefaultCacheSize is the default number of hot nodes kept in memory by the node store
+const DefaultCacheSize = 1000000
+
+// ErrNodeNotFound is returned when a node is not present in the node store
+var ErrNodeNotFound = errors.New("node not found in node store")
+
+// Store is an on-disk store of mtrie nodes, keyed by node hash.
+// Nodes read from the store are PAGED nodes: they reference their children by hash
+// and load them on demand through the store. A LRU cache keeps the recently used
+// (hot) nodes in memory, all other nodes are only held on disk.
+//
+// Nodes are content-addressed, hence subtries shared between tries are only stored once.
+// TODO: nodes which are not referenced by any trie anymore are never removed from the store
+type Store struct {
+	db    *badger.DB
+	cache *lru.Cache
+}
+
+// NewStore opens (or creates) a node store in the given directory,
+// keeping up to cacheSize nodes in memory.
+func NewStore(dir string, cacheSize int) (*Store, error) {
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create node cache: %w", err)
+	}
+
+	opts := badger.
+		DefaultOptions(dir).
+		WithKeepL0InMemory(true).
+		WithLogger(nil)
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open node store: %w", err)
+	}
+
+	return &Store{
+		db:    db,
+		cache: cache,
+	}, nil
+}
+
+// Resolve returns the (paged) node with the given hash value.
+// It implements node.Resolver.
+func (s *Store) Resolve(hash []byte) (*node.Node, error) {
+	if cached, ok := s.cache.Get(string(hash)); ok {
+		return cached.(*node.Node), nil
+	}
+
+	var n *node.Node
+	err := s.db.View(func(tx *badger.Txn) error {
+		item, err := tx.Get(hash)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return ErrNodeNotFound
+		}
+		if err != nil {
+			return err
+		}
+		// the decoded node references the slices of its encoding, hence it must not be
+		// decoded from the value buffer, which is only valid during the transaction
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		n, err = decodeNode(hash, val, s)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot load node %x: %w", hash, err)
+	}
+
+	s.cache.Add(string(hash), n)
+
+	return n, nil
+}
+
+// StoreTrie persists all nodes of the subtrie rooted at the given node, which are not
+// yet in the store, and returns the paged equivalent of the root node.
+// Paged nodes are already persisted, hence subtries with a paged root are skipped.
+// Once the returned root is used instead of the given one, the in-memory nodes
+// can be garbage collected.
+func (s *Store) StoreTrie(root *node.Node) (*node.Node, error) {
+	if root.IsPaged() {
+		return root, nil
+	}
+
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	err := s.storeNodes(batch, root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot store trie nodes: %w", err)
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return nil, fmt.Errorf("cannot flush trie nodes: %w", err)
+	}
+
+	paged := s.toPaged(root)
+	s.cache.Add(string(paged.Hash()), paged)
+
+	return paged, nil
+}
+
+// storeNodes adds all in-memory nodes of the given subtrie to the write batch
+func (s *Store) storeNodes(batch *badger.WriteBatch, n *node.Node) error {
+	if n == nil || n.IsPaged() {
+		return nil
+	}
+
+	lChild, err := n.LeftChild()
+	if err != nil {
+		return err
+	}
+	err = s.storeNodes(batch, lChild)
+	if err != nil {
+		return err
+	}
+
+	rChild, err := n.RightChild()
+	if err != nil {
+		return err
+	}
+	err = s.storeNodes(batch, rChild)
+	if err != nil {
+		return err
+	}
+
+	return batch.Set(n.Hash(), encodeNode(n))
+}
+
+// toPaged returns a paged copy of the given in-memory node
+func (s *Store) toPaged(n *node.Node) *node.Node {
+	return node.NewPagedNode(n.Height(),
+		n.LeftChildHash(),
+		n.RightChildHash(),
+		n.Path(),
+		n.Payload(),
+		n.Hash(),
+		n.MaxDepth(),
+		n.RegCount(),
+		s)
+}
+
+// Size returns the number of hot nodes currently kept in memory
+func (s *Store) Size() int {
+	return s.cache.Len()
+}
+
+// Close closes the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,154 @@
+package nodestore_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/nodestore"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+func TestStoreAndResolveTrie(t *testing.T) {
+	pathByteSize := 2
+	dir, err := ioutil.TempDir("", "test-nodestore-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	paths := utils.RandomPaths(100, pathByteSize)
+	sort.Slice(paths, func(i, j int) bool {
+		return bytes.Compare(paths[i], paths[j]) < 0
+	})
+	payloads := make([]ledger.Payload, 0, len(paths))
+	for _, p := range utils.RandomPayloads(len(paths), 2, 10) {
+		payloads = append(payloads, *p)
+	}
+
+	emptyTrie, err := trie.NewEmptyMTrie(pathByteSize)
+	require.NoError(t, err)
+	memTrie, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, paths, payloads)
+	require.NoError(t, err)
+
+	// a tiny cache forces nodes to be loaded from disk
+	store, err := nodestore.NewStore(dir, 3)
+	require.NoError(t, err)
+
+	pagedRoot, err := store.StoreTrie(memTrie.RootNode())
+	require.NoError(t, err)
+	require.True(t, pagedRoot.IsPaged())
+	require.Equal(t, memTrie.RootHash(), pagedRoot.Hash())
+	require.Equal(t, memTrie.AllocatedRegCount(), pagedRoot.RegCount())
+	require.Equal(t, memTrie.MaxDepth(), pagedRoot.MaxDepth())
+
+	pagedTrie, err := trie.NewMTrie(pagedRoot)
+	require.NoError(t, err)
+	require.True(t, pagedTrie.IsAValidTrie())
+
+	retPayloads, err := pagedTrie.UnsafeRead(paths)
+	require.NoError(t, err)
+	for i := range paths {
+		require.True(t, retPayloads[i].Equals(&payloads[i]))
+	}
+
+	// storing a paged trie is a no-op
+	samePagedRoot, err := store.StoreTrie(pagedRoot)
+	require.NoError(t, err)
+	require.Equal(t, pagedRoot, samePagedRoot)
+
+	require.NoError(t, store.Close())
+
+	t.Run("nodes survive reopening the store", func(t *testing.T) {
+		store, err := nodestore.NewStore(dir, 3)
+		require.NoError(t, err)
+		defer store.Close()
+
+		root, err := store.Resolve(memTrie.RootHash())
+		require.NoError(t, err)
+		reopenedTrie, err := trie.NewMTrie(root)
+		require.NoError(t, err)
+
+		retPayloads, err := reopenedTrie.UnsafeRead(paths)
+		require.NoError(t, err)
+		for i := range paths {
+			require.True(t, retPayloads[i].Equals(&payloads[i]))
+		}
+	})
+
+	t.Run("resolved nodes outlive the read", func(t *testing.T) {
+		// a cache holding all nodes, so the resolved nodes are reused by the later reads
+		store, err := nodestore.NewStore(dir, 1000)
+		require.NoError(t, err)
+		defer store.Close()
+
+		root, err := store.Resolve(memTrie.RootHash())
+		require.NoError(t, err)
+		cachedTrie, err := trie.NewMTrie(root)
+		require.NoError(t, err)
+		_, err = cachedTrie.UnsafeRead(paths)
+		require.NoError(t, err)
+
+		// reading and writing other nodes reuses the buffers of the database
+		otherPaths := utils.RandomPaths(1000, pathByteSize)
+		sort.Slice(otherPaths, func(i, j int) bool {
+			return bytes.Compare(otherPaths[i], otherPaths[j]) < 0
+		})
+		otherPayloads := make([]ledger.Payload, 0, len(otherPaths))
+		for _, p := range utils.RandomPayloads(len(otherPaths), 2, 10) {
+			otherPayloads = append(otherPayloads, *p)
+		}
+		otherTrie, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, otherPaths, otherPayloads)
+		require.NoError(t, err)
+		_, err = store.StoreTrie(otherTrie.RootNode())
+		require.NoError(t, err)
+
+		require.True(t, cachedTrie.IsAValidTrie())
+		retPayloads, err := cachedTrie.UnsafeRead(paths)
+		require.NoError(t, err)
+		for i := range paths {
+			require.True(t, retPayloads[i].Equals(&payloads[i]))
+		}
+	})
+
+	t.Run("missing child", func(t *testing.T) {
+		emptyDir, err := ioutil.TempDir("", "test-nodestore-")
+		require.NoError(t, err)
+		defer os.RemoveAll(emptyDir)
+
+		store, err := nodestore.NewStore(dir, 3)
+		require.NoError(t, err)
+		root, err := store.Resolve(memTrie.RootHash())
+		require.NoError(t, err)
+		require.NoError(t, store.Close())
+
+		// the root is paged, but its children are not in the store it is copied to
+		emptyStore, err := nodestore.NewStore(emptyDir, 3)
+		require.NoError(t, err)
+		defer emptyStore.Close()
+		orphan := node.NewPagedNode(root.Height(), root.LeftChildHash(), root.RightChildHash(), root.Path(),
+			root.Payload(), root.Hash(), root.MaxDepth(), root.RegCount(), emptyStore)
+		orphanTrie, err := trie.NewMTrie(orphan)
+		require.NoError(t, err)
+
+		_, err = orphanTrie.UnsafeRead(paths)
+		require.ErrorIs(t, err, nodestore.ErrNodeNotFound)
+		_, err = orphanTrie.AllPayloads()
+		require.ErrorIs(t, err, nodestore.ErrNodeNotFound)
+		require.False(t, orphanTrie.IsAValidTrie())
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		store, err := nodestore.NewStore(dir, 3)
+		require.NoError(t, err)
+		defer store.Close()
+
+		_, err = store.Resolve([]byte{1, 2, 3})
+		require.ErrorIs(t, err, nodestore.ErrNodeNotFound)
+	})
+}
@@ -0,0 +1,154 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+)
+
+// Diff walks the tries `from` and `to` and calls fn for every register which was
+// added, removed or modified in `to` compared to `from`, in ascending path order.
+// Sub-tries shared between both tries (i.e. with identical hashes) are skipped
+// without being traversed, so the cost is proportional to the size of the change.
+// Walking stops at the first error returned by fn.
+func Diff(from, to *MTrie, fn func(diff *ledger.PayloadDiff) error) error {
+	if from.PathLength() != to.PathLength() {
+		return fmt.Errorf("cannot diff tries with different path lengths %d and %d", from.PathLength(), to.PathLength())
+	}
+	return diffNodes(from.root, to.root, fn)
+}
+
+// diffNodes diffs the sub-tries rooted at the two nodes, which are at the same height.
+// Either node might be nil, representing an empty sub-trie.
+func diffNodes(from, to *node.Node, fn func(diff *ledger.PayloadDiff) error) error {
+	if from == nil && to == nil {
+		return nil
+	}
+	if from != nil && to != nil && bytes.Equal(from.Hash(), to.Hash()) {
+		return nil
+	}
+
+	// descend in lockstep as long as both are interim nodes
+	if from != nil && to != nil && !from.IsLeaf() && !to.IsLeaf() {
+		fromLeft, err := from.LeftChild()
+		if err != nil {
+			return err
+		}
+		toLeft, err := to.LeftChild()
+		if err != nil {
+			return err
+		}
+		err = diffNodes(fromLeft, toLeft, fn)
+		if err != nil {
+			return err
+		}
+		fromRight, err := from.RightChild()
+		if err != nil {
+			return err
+		}
+		toRight, err := to.RightChild()
+		if err != nil {
+			return err
+		}
+		return diffNodes(fromRight, toRight, fn)
+	}
+
+	// at least one side is empty or a (compact) leaf, holding at most a single register.
+	// Hence, all registers of the other side are compared against this single one.
+	if from == nil || from.IsLeaf() {
+		return diffLeaf(from, to, true, fn)
+	}
+	return diffLeaf(to, from, false, fn)
+}
+
+// diffLeaf diffs the single leaf (or nil) against all leaves of the other sub-trie.
+// isFrom states whether the leaf belongs to the `from` trie.
+func diffLeaf(leaf *node.Node, other *node.Node, isFrom bool, fn func(diff *ledger.PayloadDiff) error) error {
+	emit := func(leafPayload, otherPayload *ledger.Payload, path ledger.Path) error {
+		if isFrom {
+			return emitDiff(path, leafPayload, otherPayload, fn)
+		}
+		return emitDiff(path, otherPayload, leafPayload, fn)
+	}
+
+	pending := leaf
+	err := walkLeaves(other, func(n *node.Node) error {
+		if pending != nil {
+			cmp := bytes.Compare(pending.Path(), n.Path())
+			if cmp < 0 {
+				err := emit(pending.Payload(), nil, pending.Path())
+				if err != nil {
+					return err
+				}
+				pending = nil
+			} else if cmp == 0 {
+				err := emit(pending.Payload(), n.Payload(), n.Path())
+				pending = nil
+				return err
+			}
+		}
+		return emit(nil, n.Payload(), n.Path())
+	})
+	if err != nil {
+		return err
+	}
+
+	if pending != nil {
+		return emit(pending.Payload(), nil, pending.Path())
+	}
+	return nil
+}
+
+// emitDiff calls fn with the change between the two payloads of the same path, if there is any
+func emitDiff(path ledger.Path, before, after *ledger.Payload, fn func(diff *ledger.PayloadDiff) error) error {
+	if !registerExists(before) {
+		before = nil
+	}
+	if !registerExists(after) {
+		after = nil
+	}
+
+	switch {
+	case before == nil && after == nil:
+		return nil
+	case before == nil:
+		return fn(&ledger.PayloadDiff{Type: ledger.PayloadAdded, Path: path, After: after})
+	case after == nil:
+		return fn(&ledger.PayloadDiff{Type: ledger.PayloadRemoved, Path: path, Before: before})
+	case !before.Equals(after):
+		return fn(&ledger.PayloadDiff{Type: ledger.PayloadModified, Path: path, Before: before, After: after})
+	default:
+		return nil
+	}
+}
+
+// registerExists returns true if the payload holds a value, as
+// registers are removed by setting an empty value
+func registerExists(payload *ledger.Payload) bool {
+	return payload != nil && len(payload.Value) > 0
+}
+
+// walkLeaves calls fn for every leaf of the sub-trie, in ascending path order
+func walkLeaves(n *node.Node, fn func(leaf *node.Node) error) error {
+	if n == nil {
+		return nil
+	}
+	if n.IsLeaf() {
+		return fn(n)
+	}
+	lChild, err := n.LeftChild()
+	if err != nil {
+		return err
+	}
+	err = walkLeaves(lChild, fn)
+	if err != nil {
+		return err
+	}
+	rChild, err := n.RightChild()
+	if err != nil {
+		return err
+	}
+	return walkLeaves(rChild, fn)
+}
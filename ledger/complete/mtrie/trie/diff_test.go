@@ -0,0 +1,172 @@
+package trie_test
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+func collectDiffs(t *testing.T, from, to *trie.MTrie) []*ledger.PayloadDiff {
+	diffs := make([]*ledger.PayloadDiff, 0)
+	err := trie.Diff(from, to, func(diff *ledger.PayloadDiff) error {
+		diffs = append(diffs, diff)
+		return nil
+	})
+	require.NoError(t, err)
+	return diffs
+}
+
+// Test_DiffIdenticalTries tests that there are no differences between identical tries
+func Test_DiffIdenticalTries(t *testing.T) {
+	emptyTrie, err := trie.NewEmptyMTrie(ReferenceImplPathByteSize)
+	require.NoError(t, err)
+
+	require.Empty(t, collectDiffs(t, emptyTrie, emptyTrie))
+
+	paths := utils.RandomPaths(100, ReferenceImplPathByteSize)
+	payloads := make([]ledger.Payload, 0, len(paths))
+	for _, payload := range utils.RandomPayloads(len(paths), 1, 20) {
+		payloads = append(payloads, *payload)
+	}
+	populatedTrie, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, paths, payloads)
+	require.NoError(t, err)
+
+	require.Empty(t, collectDiffs(t, populatedTrie, populatedTrie))
+}
+
+// Test_DiffCompactLeaves tests diffs where a compact leaf is compared against a sub-trie
+func Test_DiffCompactLeaves(t *testing.T) {
+	emptyTrie, err := trie.NewEmptyMTrie(ReferenceImplPathByteSize)
+	require.NoError(t, err)
+
+	p1 := utils.TwoBytesPath(0)
+	p2 := utils.TwoBytesPath(1)
+	v1 := utils.LightPayload(1, 1)
+	v2 := utils.LightPayload(2, 2)
+	v1Modified := utils.LightPayload(1, 3)
+
+	// single compact leaf of p1 at the root's left child
+	trie1, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, []ledger.Path{p1}, []ledger.Payload{*v1})
+	require.NoError(t, err)
+
+	// p1 and p2 expanded down to the leaves
+	trie2, err := trie.NewTrieWithUpdatedRegisters(trie1, []ledger.Path{p1, p2}, []ledger.Payload{*v1Modified, *v2})
+	require.NoError(t, err)
+
+	diffs := collectDiffs(t, trie1, trie2)
+	require.Len(t, diffs, 2)
+	require.Equal(t, ledger.PayloadModified, diffs[0].Type)
+	require.Equal(t, p1, diffs[0].Path)
+	require.True(t, v1.Equals(diffs[0].Before))
+	require.True(t, v1Modified.Equals(diffs[0].After))
+	require.Equal(t, ledger.PayloadAdded, diffs[1].Type)
+	require.Equal(t, p2, diffs[1].Path)
+	require.Nil(t, diffs[1].Before)
+	require.True(t, v2.Equals(diffs[1].After))
+
+	// reversed direction
+	diffs = collectDiffs(t, trie2, trie1)
+	require.Len(t, diffs, 2)
+	require.Equal(t, ledger.PayloadModified, diffs[0].Type)
+	require.True(t, v1Modified.Equals(diffs[0].Before))
+	require.True(t, v1.Equals(diffs[0].After))
+	require.Equal(t, ledger.PayloadRemoved, diffs[1].Type)
+	require.True(t, v2.Equals(diffs[1].Before))
+	require.Nil(t, diffs[1].After)
+}
+
+// Test_DiffRandomUpdates compares the diff of randomly updated tries against
+// the changes computed from the updates themselves
+func Test_DiffRandomUpdates(t *testing.T) {
+	pathByteSize := 4
+	emptyTrie, err := trie.NewEmptyMTrie(pathByteSize)
+	require.NoError(t, err)
+
+	allPaths := utils.RandomPaths(1000, pathByteSize)
+	basePaths := allPaths[:700]
+	state := make(map[string]*ledger.Payload)
+
+	basePayloads := make([]ledger.Payload, 0, len(basePaths))
+	for i, payload := range utils.RandomPayloads(len(basePaths), 1, 20) {
+		basePayloads = append(basePayloads, *payload)
+		state[string(basePaths[i])] = payload
+	}
+	baseTrie, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, basePaths, basePayloads)
+	require.NoError(t, err)
+
+	// remove 100, modify 100, keep 100 unchanged (with an identical write) and add 300 registers
+	expected := make(map[string]*ledger.PayloadDiff)
+	updatedPaths := make([]ledger.Path, 0)
+	updatedPayloads := make([]ledger.Payload, 0)
+	for i, path := range allPaths[400:] {
+		before := state[string(path)]
+		var after *ledger.Payload
+		switch {
+		case i < 100:
+			after = ledger.NewPayload(before.Key, nil)
+			expected[string(path)] = &ledger.PayloadDiff{Type: ledger.PayloadRemoved, Path: path, Before: before}
+		case i < 200:
+			after = utils.RandomPayload(1, 20)
+			expected[string(path)] = &ledger.PayloadDiff{Type: ledger.PayloadModified, Path: path, Before: before, After: after}
+		case i < 300:
+			after = before
+		default:
+			after = utils.RandomPayload(1, 20)
+			expected[string(path)] = &ledger.PayloadDiff{Type: ledger.PayloadAdded, Path: path, After: after}
+		}
+		updatedPaths = append(updatedPaths, path)
+		updatedPayloads = append(updatedPayloads, *after)
+	}
+	updatedTrie, err := trie.NewTrieWithUpdatedRegisters(baseTrie, updatedPaths, updatedPayloads)
+	require.NoError(t, err)
+
+	diffs := collectDiffs(t, baseTrie, updatedTrie)
+	require.Len(t, diffs, len(expected))
+
+	require.True(t, sort.SliceIsSorted(diffs, func(i, j int) bool {
+		return bytes.Compare(diffs[i].Path, diffs[j].Path) < 0
+	}))
+
+	for _, diff := range diffs {
+		expectedDiff, ok := expected[string(diff.Path)]
+		require.True(t, ok)
+		require.Equal(t, expectedDiff.Type, diff.Type)
+		if expectedDiff.Before == nil {
+			require.Nil(t, diff.Before)
+		} else {
+			require.True(t, expectedDiff.Before.Equals(diff.Before))
+		}
+		if expectedDiff.After == nil {
+			require.Nil(t, diff.After)
+		} else {
+			require.True(t, expectedDiff.After.Equals(diff.After))
+		}
+	}
+
+	t.Run("diff stops at first error", func(t *testing.T) {
+		expectedErr := errors.New("expected")
+		calls := 0
+		err := trie.Diff(baseTrie, updatedTrie, func(diff *ledger.PayloadDiff) error {
+			calls++
+			return expectedErr
+		})
+		require.True(t, errors.Is(err, expectedErr))
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("different path lengths cannot be diffed", func(t *testing.T) {
+		otherTrie, err := trie.NewEmptyMTrie(pathByteSize + 1)
+		require.NoError(t, err)
+		err = trie.Diff(baseTrie, otherTrie, func(diff *ledger.PayloadDiff) error {
+			return nil
+		})
+		require.Error(t, err)
+	})
+}
@@ -138,7 +138,11 @@ func (mt *MTrie) read(head *node.Node, paths []ledger.Path) ([]*ledger.Payload,
 	// TODO make this parallel
 	payloads := make([]*ledger.Payload, 0)
 	if len(lpaths) > 0 {
-		p, err := mt.read(head.LeftChild(), lpaths)
+		lChild, err := head.LeftChild()
+		if err != nil {
+			return nil, err
+		}
+		p, err := mt.read(lChild, lpaths)
 		if err != nil {
 			return nil, err
 		}
@@ -146,7 +150,11 @@ func (mt *MTrie) read(head *node.Node, paths []ledger.Path) ([]*ledger.Payload,
 	}
 
 	if len(rpaths) > 0 {
-		p, err := mt.read(head.RightChild(), rpaths)
+		rChild, err := head.RightChild()
+		if err != nil {
+			return nil, err
+		}
+		p, err := mt.read(rChild, rpaths)
 		if err != nil {
 			return nil, err
 		}
@@ -216,6 +224,15 @@ func update(treeHeight int, nodeHeight int, parentNode *node.Node, paths []ledge
 		return nil, fmt.Errorf("error spliting payloads by path: %w", err)
 	}
 
+	parentLChild, err := parentNode.LeftChild()
+	if err != nil {
+		return nil, err
+	}
+	parentRChild, err := parentNode.RightChild()
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO [runtime optimization]: do not branch if either lpayload or rpayload is empty
 	var lChild, rChild *node.Node
 	var lErr, rErr error
@@ -223,9 +240,9 @@ func update(treeHeight int, nodeHeight int, parentNode *node.Node, paths []ledge
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		lChild, lErr = update(treeHeight, nodeHeight-1, parentNode.LeftChild(), lpaths, lpayloads)
+		lChild, lErr = update(treeHeight, nodeHeight-1, parentLChild, lpaths, lpayloads)
 	}()
-	rChild, rErr = update(treeHeight, nodeHeight-1, parentNode.RightChild(), rpaths, rpayloads)
+	rChild, rErr = update(treeHeight, nodeHeight-1, parentRChild, rpaths, rpayloads)
 	wg.Wait()
 	if lErr != nil || rErr != nil {
 		var merr *multierror.Error
@@ -334,8 +351,17 @@ func (mt *MTrie) proofs(head *node.Node, paths []ledger.Path, proofs []*ledger.T
 		return fmt.Errorf("proof generation failed, path split error: %w", err)
 	}
 
+	lChild, err := head.LeftChild()
+	if err != nil {
+		return err
+	}
+	rChild, err := head.RightChild()
+	if err != nil {
+		return err
+	}
+
 	if len(lpaths) > 0 {
-		if rChild := head.RightChild(); rChild != nil {
+		if rChild != nil {
 			nodeHash := rChild.Hash()
 			isDef := bytes.Equal(nodeHash, common.GetDefaultHashForHeight(rChild.Height()))
 			if !isDef { // in proofs, we only provide non-default value hashes
@@ -348,14 +374,14 @@ func (mt *MTrie) proofs(head *node.Node, paths []ledger.Path, proofs []*ledger.T
 				}
 			}
 		}
-		err := mt.proofs(head.LeftChild(), lpaths, lproofs)
+		err := mt.proofs(lChild, lpaths, lproofs)
 		if err != nil {
 			return err
 		}
 	}
 
 	if len(rpaths) > 0 {
-		if lChild := head.LeftChild(); lChild != nil {
+		if lChild != nil {
 			nodeHash := lChild.Hash()
 			isDef := bytes.Equal(nodeHash, common.GetDefaultHashForHeight(lChild.Height()))
 			if !isDef { // in proofs, we only provide non-default value hashes
@@ -368,7 +394,7 @@ func (mt *MTrie) proofs(head *node.Node, paths []ledger.Path, proofs []*ledger.T
 				}
 			}
 		}
-		err := mt.proofs(head.RightChild(), rpaths, rproofs)
+		err := mt.proofs(rChild, rpaths, rproofs)
 		if err != nil {
 			return err
 		}
@@ -408,14 +434,22 @@ func (mt *MTrie) dumpAsJSON(n *node.Node, encoder *json.Encoder) error {
 		}
 	}
 
-	if lChild := n.LeftChild(); lChild != nil {
+	lChild, err := n.LeftChild()
+	if err != nil {
+		return err
+	}
+	if lChild != nil {
 		err := mt.dumpAsJSON(lChild, encoder)
 		if err != nil {
 			return err
 		}
 	}
 
-	if rChild := n.RightChild(); rChild != nil {
+	rChild, err := n.RightChild()
+	if err != nil {
+		return err
+	}
+	if rChild != nil {
 		err := mt.dumpAsJSON(rChild, encoder)
 		if err != nil {
 			return err
@@ -430,7 +464,7 @@ func EmptyTrieRootHash(pathByteSize int) []byte {
 }
 
 // AllPayloads returns all payloads
-func (mt *MTrie) AllPayloads() []ledger.Payload {
+func (mt *MTrie) AllPayloads() ([]ledger.Payload, error) {
 	return mt.root.AllPayloads()
 }
 
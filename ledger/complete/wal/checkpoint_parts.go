@@ -0,0 +1,188 @@
+package wal
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+)
+
+// checkpointMaxParts is the maximum number of parts the node stream of a checkpoint is split into.
+// It bounds the parallelism available when loading a checkpoint, independently of the machine
+// the checkpoint was created on.
+const checkpointMaxParts = 64
+
+// checkpointPartCount returns the number of parts to split the given number of nodes into
+func checkpointPartCount(nodeCount uint64) uint16 {
+	if nodeCount < checkpointMaxParts {
+		return uint16(nodeCount)
+	}
+	return checkpointMaxParts
+}
+
+// writeNodeParts writes the nodes split into partCount parts. Every part is independently decodable:
+//   * 8-bytes Big Endian uint64 number of nodes in the part
+//   * 8-bytes Big Endian uint64 byte length of the encoded (and compressed) nodes
+//   * encoded nodes, compressed with the given codec
+//   * 4-bytes Big Endian uint32 CRC32 checksum of the encoded (and compressed) nodes
+func writeNodeParts(writer io.Writer, nodes []*flattener.StorableNode, partCount uint16, codec Codec) error {
+	if partCount == 0 {
+		return nil
+	}
+
+	partSize := (len(nodes) + int(partCount) - 1) / int(partCount)
+
+	buf := &bytes.Buffer{}
+	header := make([]byte, 8+8)
+	crc32buf := make([]byte, 4)
+
+	for part := 0; part < int(partCount); part++ {
+		start := part * partSize
+		end := start + partSize
+		if start > len(nodes) {
+			start = len(nodes)
+		}
+		if end > len(nodes) {
+			end = len(nodes)
+		}
+
+		buf.Reset()
+		for _, storableNode := range nodes[start:end] {
+			buf.Write(flattener.EncodeStorableNode(storableNode))
+		}
+
+		data, err := codec.compress(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("cannot compress node data of part %d: %w", part, err)
+		}
+
+		pos := writeUint64(header, 0, uint64(end-start))
+		writeUint64(header, pos, uint64(len(data)))
+		_, err = writer.Write(header)
+		if err != nil {
+			return fmt.Errorf("cannot write header of part %d: %w", part, err)
+		}
+
+		_, err = writer.Write(data)
+		if err != nil {
+			return fmt.Errorf("error while writing node data of part %d: %w", part, err)
+		}
+
+		writeUint32(crc32buf, 0, crc32.Checksum(data, crc32Table))
+		_, err = writer.Write(crc32buf)
+		if err != nil {
+			return fmt.Errorf("cannot write crc32 of part %d: %w", part, err)
+		}
+	}
+
+	return nil
+}
+
+// readNodeParts reads partCount parts written by writeNodeParts into nodes, starting at index 1
+// (0th element is nil). Parts are read sequentially from the reader, but verified, decompressed
+// and decoded concurrently on all available cores.
+func readNodeParts(reader io.Reader, nodes []*flattener.StorableNode, partCount uint16, codec Codec) error {
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	setErr := func(err error) {
+		errMu.Lock()
+		defer errMu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	failed := func() bool {
+		errMu.Lock()
+		defer errMu.Unlock()
+		return firstErr != nil
+	}
+
+	// limit the number of parts held in memory, which are read but not yet decoded
+	workers := make(chan struct{}, runtime.NumCPU())
+
+	header := make([]byte, 8+8+4)
+	next := uint64(1)
+
+	for part := 0; part < int(partCount) && !failed(); part++ {
+		_, err := io.ReadFull(reader, header[:8+8])
+		if err != nil {
+			setErr(fmt.Errorf("cannot read header of part %d: %w", part, err))
+			break
+		}
+		partNodeCount, pos := readUint64(header, 0)
+		partLength, _ := readUint64(header, pos)
+
+		if partNodeCount > uint64(len(nodes))-next {
+			setErr(fmt.Errorf("part %d contains %d nodes, exceeding total node count %d", part, partNodeCount, len(nodes)-1))
+			break
+		}
+
+		// copy instead of allocating partLength upfront, as it might be corrupted
+		data := &bytes.Buffer{}
+		_, err = io.CopyN(data, reader, int64(partLength))
+		if err != nil {
+			setErr(fmt.Errorf("cannot read node data of part %d: %w", part, err))
+			break
+		}
+
+		_, err = io.ReadFull(reader, header[8+8:])
+		if err != nil {
+			setErr(fmt.Errorf("cannot read crc32 of part %d: %w", part, err))
+			break
+		}
+		readCrc32, _ := readUint32(header, 8+8)
+
+		start := next
+		next += partNodeCount
+
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(part int, start uint64, count uint64, data []byte, readCrc32 uint32) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			calculatedCrc32 := crc32.Checksum(data, crc32Table)
+			if calculatedCrc32 != readCrc32 {
+				setErr(fmt.Errorf("checkpoint part %d checksum failed! File contains %x but read data checksums to %x", part, readCrc32, calculatedCrc32))
+				return
+			}
+
+			data, err := codec.decompress(data)
+			if err != nil {
+				setErr(fmt.Errorf("cannot decompress node data of part %d: %w", part, err))
+				return
+			}
+
+			partReader := bytes.NewReader(data)
+			for i := start; i < start+count; i++ {
+				storableNode, err := flattener.ReadStorableNode(partReader)
+				if err != nil {
+					setErr(fmt.Errorf("cannot read storable node %d: %w", i, err))
+					return
+				}
+				nodes[i] = storableNode
+			}
+		}(part, start, partNodeCount, data.Bytes(), readCrc32)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if next != uint64(len(nodes)) {
+		return fmt.Errorf("checkpoint parts contain %d nodes, expected %d", next-1, len(nodes)-1)
+	}
+
+	return nil
+}
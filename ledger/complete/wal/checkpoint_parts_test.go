@@ -0,0 +1,214 @@
+package wal
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func randomFlattenedForest(nodeCount int) *flattener.FlattenedForest {
+	nodes := make([]*flattener.StorableNode, nodeCount+1)
+	for i := 1; i <= nodeCount; i++ {
+		nodes[i] = &flattener.StorableNode{
+			LIndex:     uint64(i - 1),
+			Height:     uint16(i),
+			MaxDepth:   uint16(i % 7),
+			RegCount:   uint64(i),
+			Path:       []byte{byte(i), byte(i >> 8)},
+			EncPayload: []byte{byte(i), 1, 2, 3},
+			HashValue:  []byte{byte(i), byte(i >> 8), 0xff},
+		}
+	}
+	return &flattener.FlattenedForest{
+		Nodes: nodes,
+		Tries: []*flattener.StorableTrie{
+			{RootIndex: uint64(nodeCount), RootHash: []byte{1, 2, 3}},
+		},
+	}
+}
+
+// storeCheckpointV3 writes the checkpoint in the sequential version 3 format
+func storeCheckpointV3(t *testing.T, forestSequencing *flattener.FlattenedForest) []byte {
+	buffer := &bytes.Buffer{}
+	crc32Writer := NewCRC32Writer(buffer)
+
+	header := make([]byte, 4+8+2)
+	pos := writeUint16(header, 0, MagicBytes)
+	pos = writeUint16(header, pos, VersionV3)
+	pos = writeUint64(header, pos, uint64(len(forestSequencing.Nodes)-1))
+	writeUint16(header, pos, uint16(len(forestSequencing.Tries)))
+	_, err := crc32Writer.Write(header)
+	require.NoError(t, err)
+
+	for _, storableNode := range forestSequencing.Nodes[1:] {
+		_, err = crc32Writer.Write(flattener.EncodeStorableNode(storableNode))
+		require.NoError(t, err)
+	}
+	for _, storableTrie := range forestSequencing.Tries {
+		_, err = crc32Writer.Write(flattener.EncodeStorableTrie(storableTrie))
+		require.NoError(t, err)
+	}
+
+	crc32buf := make([]byte, 4)
+	writeUint32(crc32buf, 0, crc32Writer.Crc32())
+	buffer.Write(crc32buf)
+
+	return buffer.Bytes()
+}
+
+func Test_CheckpointParts(t *testing.T) {
+
+	for _, nodeCount := range []int{0, 1, checkpointMaxParts - 1, checkpointMaxParts, 1000} {
+		forestSequencing := randomFlattenedForest(nodeCount)
+
+		buffer := &bytes.Buffer{}
+		err := StoreCompressedCheckpoint(forestSequencing, CodecNone, buffer)
+		require.NoError(t, err)
+
+		read, err := ReadCheckpoint(buffer)
+		require.NoError(t, err)
+		require.Equal(t, forestSequencing, read, "node count %d", nodeCount)
+	}
+
+	t.Run("version 3 checkpoints are still readable", func(t *testing.T) {
+		forestSequencing := randomFlattenedForest(100)
+
+		read, err := ReadCheckpoint(bytes.NewReader(storeCheckpointV3(t, forestSequencing)))
+		require.NoError(t, err)
+		require.Equal(t, forestSequencing, read)
+	})
+
+	t.Run("checkpoints are written in version 3 unless split into parts", func(t *testing.T) {
+		forestSequencing := randomFlattenedForest(100)
+
+		buffer := &bytes.Buffer{}
+		err := StoreCheckpoint(forestSequencing, buffer)
+		require.NoError(t, err)
+		require.Equal(t, storeCheckpointV3(t, forestSequencing), buffer.Bytes())
+	})
+
+	t.Run("delta checkpoints", func(t *testing.T) {
+		forestSequencing := randomFlattenedForest(1000)
+		delta := &flattener.FlattenedForestDelta{
+			BaseNodeCount: 10,
+			Nodes:         forestSequencing.Nodes[1:],
+			Tries:         forestSequencing.Tries,
+		}
+
+		plain := &bytes.Buffer{}
+		err := StoreDeltaCheckpoint(delta, 7, plain)
+		require.NoError(t, err)
+
+		for _, codec := range []Codec{CodecNone, CodecSnappy} {
+			buffer := &bytes.Buffer{}
+			err := StoreCompressedDeltaCheckpoint(delta, 7, codec, buffer)
+			require.NoError(t, err)
+			if codec == CodecSnappy {
+				require.Less(t, buffer.Len(), plain.Len())
+			}
+
+			_, read, parent, err := readCheckpoint(bytes.NewReader(buffer.Bytes()))
+			require.NoError(t, err)
+			require.Equal(t, 7, parent)
+			require.Equal(t, delta, read)
+		}
+
+		_, read, parent, err := readCheckpoint(plain)
+		require.NoError(t, err)
+		require.Equal(t, 7, parent)
+		require.Equal(t, delta, read)
+	})
+
+	t.Run("corrupted part is detected", func(t *testing.T) {
+		forestSequencing := randomFlattenedForest(1000)
+
+		buffer := &bytes.Buffer{}
+		err := StoreCompressedCheckpoint(forestSequencing, CodecNone, buffer)
+		require.NoError(t, err)
+
+		data := buffer.Bytes()
+		// node 500 is somewhere in the middle of the parts
+		index := bytes.Index(data, forestSequencing.Nodes[500].EncPayload)
+		require.Greater(t, index, 0)
+		data[index+1]++
+
+		_, err = ReadCheckpoint(bytes.NewReader(data))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum")
+	})
+
+	t.Run("truncated checkpoint is detected", func(t *testing.T) {
+		forestSequencing := randomFlattenedForest(1000)
+
+		buffer := &bytes.Buffer{}
+		err := StoreCompressedCheckpoint(forestSequencing, CodecNone, buffer)
+		require.NoError(t, err)
+
+		_, err = ReadCheckpoint(bytes.NewReader(buffer.Bytes()[:buffer.Len()/2]))
+		require.Error(t, err)
+	})
+
+	t.Run("compressed checkpoints", func(t *testing.T) {
+		forestSequencing := randomFlattenedForest(1000)
+
+		plain := &bytes.Buffer{}
+		err := StoreCompressedCheckpoint(forestSequencing, CodecNone, plain)
+		require.NoError(t, err)
+
+		compressed := &bytes.Buffer{}
+		err = StoreCompressedCheckpoint(forestSequencing, CodecSnappy, compressed)
+		require.NoError(t, err)
+		require.Less(t, compressed.Len(), plain.Len())
+
+		read, err := ReadCheckpoint(bytes.NewReader(compressed.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, forestSequencing, read)
+
+		// corrupting compressed data is detected as well
+		data := compressed.Bytes()
+		data[len(data)/2]++
+		_, err = ReadCheckpoint(bytes.NewReader(data))
+		require.Error(t, err)
+	})
+
+	t.Run("checkpoint header", func(t *testing.T) {
+		forestSequencing := randomFlattenedForest(100)
+
+		unittest.RunWithTempDir(t, func(dir string) {
+			for _, codec := range []Codec{CodecNone, CodecSnappy} {
+				writer, err := CreateCheckpointWriterForFile(dir, codec.String())
+				require.NoError(t, err)
+				err = StoreCompressedCheckpoint(forestSequencing, codec, writer)
+				require.NoError(t, err)
+				require.NoError(t, writer.Close())
+
+				header, err := ReadCheckpointHeader(filepath.Join(dir, codec.String()))
+				require.NoError(t, err)
+				require.Equal(t, &CheckpointHeader{
+					Version:   VersionV5,
+					Codec:     codec,
+					Parent:    -1,
+					NodeCount: 100,
+					TrieCount: 1,
+				}, header)
+
+				read, err := LoadCheckpoint(filepath.Join(dir, codec.String()))
+				require.NoError(t, err)
+				require.Equal(t, forestSequencing, read)
+			}
+		})
+	})
+
+	t.Run("only version 5 checkpoints can be compressed", func(t *testing.T) {
+		data := storeCheckpointV3(t, randomFlattenedForest(10))
+		writeUint16(data, 2, uint16(CodecSnappy)<<versionCodecShift|VersionV3)
+
+		_, err := ReadCheckpoint(bytes.NewReader(data))
+		require.Error(t, err)
+	})
+}
@@ -29,6 +29,24 @@ const VersionV1 uint16 = 0x01
 // Version 3 contains a file checksum for detecting corrupted checkpoint files.
 const VersionV3 uint16 = 0x03
 
+// VersionDeltaV4 is an incremental (delta) checkpoint. It only contains the nodes created since
+// its parent checkpoint and can only be loaded together with the chain of its parent checkpoints.
+// Like version 3, it contains a file checksum.
+const VersionDeltaV4 uint16 = 0x04
+
+// VersionV5 splits the nodes into independently decodable parts, each with its own checksum,
+// so they can be decoded in parallel. Like version 3, it contains a file checksum.
+// Versions 5 and 6 are only written if enabled, as they can't be read by binaries only supporting versions up to 4.
+const VersionV5 uint16 = 0x05
+
+// VersionDeltaV6 is a delta checkpoint like version 4, with the nodes split into parts like version 5.
+const VersionDeltaV6 uint16 = 0x06
+
+// The high byte of the version of VersionV5 and VersionDeltaV6 checkpoints holds the Codec used to compress the node parts.
+// Checkpoints written before codecs were introduced have it set to 0 - uncompressed.
+const versionMask uint16 = 0x00ff
+const versionCodecShift = 8
+
 const RootCheckpointFilename = "root.checkpoint"
 
 type Checkpointer struct {
@@ -36,14 +54,21 @@ type Checkpointer struct {
 	wal            *LedgerWAL
 	keyByteSize    int
 	forestCapacity int
+	codec          Codec
+	parts          bool
 }
 
+// NewCheckpointer returns a Checkpointer for the given WAL. Checkpoints are split into parts (versions 5 and 6)
+// if enabled for the WAL, or if they are compressed with the WAL's codec, which requires parts.
+// Otherwise, checkpoints are written in the formats readable by all binaries (versions 3 and 4).
 func NewCheckpointer(wal *LedgerWAL, keyByteSize int, forestCapacity int) *Checkpointer {
 	return &Checkpointer{
 		dir:            wal.wal.Dir(),
 		wal:            wal,
 		keyByteSize:    keyByteSize,
 		forestCapacity: forestCapacity,
+		codec:          wal.codec,
+		parts:          wal.checkpointParts || wal.codec != CodecNone,
 	}
 }
 
@@ -197,9 +222,94 @@ func (c *Checkpointer) Checkpoint(to int, targetWriter func() (io.WriteCloser, e
 	}
 	defer writer.Close()
 
-	err = StoreCheckpoint(forestSequencing, writer)
+	if c.parts {
+		return StoreCompressedCheckpoint(forestSequencing, c.codec, writer)
+	}
+	return StoreCheckpoint(forestSequencing, writer)
+}
+
+// CheckpointDelta creates a new delta checkpoint stopping at given segment.
+// The delta checkpoint only contains the nodes created since the latest checkpoint, which
+// becomes its parent. Loading the delta checkpoint requires the chain of its parent checkpoints.
+func (c *Checkpointer) CheckpointDelta(to int, targetWriter func() (io.WriteCloser, error)) error {
+
+	_, notCheckpointedTo, err := c.NotCheckpointedSegments()
+	if err != nil {
+		return fmt.Errorf("cannot get not checkpointed segments: %w", err)
+	}
+
+	parent, err := c.LatestCheckpoint()
+	if err != nil {
+		return fmt.Errorf("cannot get latest checkpoint: %w", err)
+	}
+
+	if parent == to {
+		return nil //nothing to do
+	}
+
+	if parent == -1 {
+		return fmt.Errorf("no parent checkpoint for delta checkpoint to %d", to)
+	}
+
+	if parent > to {
+		return fmt.Errorf("cannot create delta checkpoint to %d on top of later checkpoint %d", to, parent)
+	}
+
+	if notCheckpointedTo < to {
+		return fmt.Errorf("no segments to checkpoint to %d, latests not checkpointed segment: %d", to, notCheckpointedTo)
+	}
+
+	base, err := c.LoadCheckpoint(parent)
+	if err != nil {
+		return fmt.Errorf("cannot load parent checkpoint %d: %w", parent, err)
+	}
+
+	forest, err := mtrie.NewForest(c.keyByteSize, c.dir, c.forestCapacity, &metrics.NoopCollector{}, func(evictedTrie *trie.MTrie) error {
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create Forest: %w", err)
+	}
+
+	tries, err := flattener.RebuildTries(base)
+	if err != nil {
+		return fmt.Errorf("cannot rebuild tries of parent checkpoint: %w", err)
+	}
+	err = forest.AddTries(tries)
+	if err != nil {
+		return fmt.Errorf("cannot add tries of parent checkpoint: %w", err)
+	}
+
+	err = c.wal.replay(parent+1, to,
+		func(forestSequencing *flattener.FlattenedForest) error {
+			return fmt.Errorf("unexpected checkpoint while replaying segments %d to %d", parent+1, to)
+		},
+		func(update *ledger.TrieUpdate) error {
+			_, err := forest.Update(update)
+			return err
+		}, func(rootHash ledger.RootHash) error {
+			return nil
+		}, false)
 
-	return err
+	if err != nil {
+		return fmt.Errorf("cannot replay WAL: %w", err)
+	}
+
+	delta, err := flattener.FlattenForestDelta(forest, base.Nodes)
+	if err != nil {
+		return fmt.Errorf("cannot get storables: %w", err)
+	}
+
+	writer, err := targetWriter()
+	if err != nil {
+		return fmt.Errorf("cannot generate writer: %w", err)
+	}
+	defer writer.Close()
+
+	if c.parts {
+		return StoreCompressedDeltaCheckpoint(delta, parent, c.codec, writer)
+	}
+	return StoreDeltaCheckpoint(delta, parent, writer)
 }
 
 func NumberToFilenamePart(n int) string {
@@ -242,6 +352,7 @@ func CreateCheckpointWriterForFile(dir, filename string) (io.WriteCloser, error)
 }
 
 // StoreCheckpoint writes the given checkpoint to disk, and also append with a CRC32 file checksum for integrity check.
+// The checkpoint is written in version 3, which is readable by all binaries.
 func StoreCheckpoint(forestSequencing *flattener.FlattenedForest, writer io.Writer) error {
 	storableNodes := forestSequencing.Nodes
 	storableTries := forestSequencing.Tries
@@ -268,9 +379,107 @@ func StoreCheckpoint(forestSequencing *flattener.FlattenedForest, writer io.Writ
 		}
 	}
 
+	return writeTriesAndChecksum(crc32Writer, storableTries, writer)
+}
+
+// StoreCompressedCheckpoint writes the given checkpoint split into parts (version 5), compressing the parts with
+// the given codec. The parts are decoded in parallel when loading the checkpoint, but binaries only supporting
+// versions up to 4 can't read it.
+func StoreCompressedCheckpoint(forestSequencing *flattener.FlattenedForest, codec Codec, writer io.Writer) error {
+	storableNodes := forestSequencing.Nodes
+	storableTries := forestSequencing.Tries
+	nodeCount := uint64(len(storableNodes) - 1) // -1 to account for 0 node meaning nil
+	partCount := checkpointPartCount(nodeCount)
+	header := make([]byte, 4+8+2+2)
+
+	crc32Writer := NewCRC32Writer(writer)
+
+	pos := writeUint16(header, 0, MagicBytes)
+	pos = writeUint16(header, pos, uint16(codec)<<versionCodecShift|VersionV5)
+	pos = writeUint64(header, pos, nodeCount)
+	pos = writeUint16(header, pos, uint16(len(storableTries)))
+	writeUint16(header, pos, partCount)
+
+	_, err := crc32Writer.Write(header)
+	if err != nil {
+		return fmt.Errorf("cannot write checkpoint header: %w", err)
+	}
+
+	// 0 element = nil, we don't need to store it
+	err = writeNodeParts(crc32Writer, storableNodes[1:], partCount, codec)
+	if err != nil {
+		return fmt.Errorf("cannot write nodes: %w", err)
+	}
+
+	return writeTriesAndChecksum(crc32Writer, storableTries, writer)
+}
+
+// StoreDeltaCheckpoint writes the given delta checkpoint to disk, and also append with a CRC32 file checksum for integrity check.
+// The parent is the number of the checkpoint the delta is relative to.
+func StoreDeltaCheckpoint(delta *flattener.FlattenedForestDelta, parent int, writer io.Writer) error {
+	header := make([]byte, 4+8+8+8+2)
+
+	crc32Writer := NewCRC32Writer(writer)
+
+	pos := writeUint16(header, 0, MagicBytes)
+	pos = writeUint16(header, pos, VersionDeltaV4)
+	pos = writeUint64(header, pos, uint64(parent))
+	pos = writeUint64(header, pos, delta.BaseNodeCount)
+	pos = writeUint64(header, pos, uint64(len(delta.Nodes)))
+	writeUint16(header, pos, uint16(len(delta.Tries)))
+
+	_, err := crc32Writer.Write(header)
+	if err != nil {
+		return fmt.Errorf("cannot write checkpoint header: %w", err)
+	}
+
+	for _, storableNode := range delta.Nodes {
+		bytes := flattener.EncodeStorableNode(storableNode)
+		_, err = crc32Writer.Write(bytes)
+		if err != nil {
+			return fmt.Errorf("error while writing node date: %w", err)
+		}
+	}
+
+	return writeTriesAndChecksum(crc32Writer, delta.Tries, writer)
+}
+
+// StoreCompressedDeltaCheckpoint writes the given delta checkpoint like StoreDeltaCheckpoint, with the nodes split
+// into parts compressed with the given codec (version 6). Like version 5, it can't be read by binaries only
+// supporting versions up to 4.
+func StoreCompressedDeltaCheckpoint(delta *flattener.FlattenedForestDelta, parent int, codec Codec, writer io.Writer) error {
+	nodeCount := uint64(len(delta.Nodes))
+	partCount := checkpointPartCount(nodeCount)
+	header := make([]byte, 4+8+8+8+2+2)
+
+	crc32Writer := NewCRC32Writer(writer)
+
+	pos := writeUint16(header, 0, MagicBytes)
+	pos = writeUint16(header, pos, uint16(codec)<<versionCodecShift|VersionDeltaV6)
+	pos = writeUint64(header, pos, uint64(parent))
+	pos = writeUint64(header, pos, delta.BaseNodeCount)
+	pos = writeUint64(header, pos, nodeCount)
+	pos = writeUint16(header, pos, uint16(len(delta.Tries)))
+	writeUint16(header, pos, partCount)
+
+	_, err := crc32Writer.Write(header)
+	if err != nil {
+		return fmt.Errorf("cannot write checkpoint header: %w", err)
+	}
+
+	err = writeNodeParts(crc32Writer, delta.Nodes, partCount, codec)
+	if err != nil {
+		return fmt.Errorf("cannot write nodes: %w", err)
+	}
+
+	return writeTriesAndChecksum(crc32Writer, delta.Tries, writer)
+}
+
+// writeTriesAndChecksum writes the tries of a checkpoint, followed by the CRC32 checksum of the whole file
+func writeTriesAndChecksum(crc32Writer *Crc32Writer, storableTries []*flattener.StorableTrie, writer io.Writer) error {
 	for _, storableTrie := range storableTries {
 		bytes := flattener.EncodeStorableTrie(storableTrie)
-		_, err = crc32Writer.Write(bytes)
+		_, err := crc32Writer.Write(bytes)
 		if err != nil {
 			return fmt.Errorf("error while writing trie date: %w", err)
 		}
@@ -280,7 +489,7 @@ func StoreCheckpoint(forestSequencing *flattener.FlattenedForest, writer io.Writ
 	crc32buf := make([]byte, 4)
 	writeUint32(crc32buf, 0, crc32Writer.Crc32())
 
-	_, err = writer.Write(crc32buf)
+	_, err := writer.Write(crc32buf)
 	if err != nil {
 		return fmt.Errorf("cannot write crc32: %w", err)
 	}
@@ -312,6 +521,82 @@ func (c *Checkpointer) RemoveCheckpoint(checkpoint int) error {
 	return os.Remove(path.Join(c.dir, NumberToFilename(checkpoint)))
 }
 
+// CheckpointParent returns the number of the parent checkpoint of a delta checkpoint,
+// or -1 if the given checkpoint is a full checkpoint.
+func (c *Checkpointer) CheckpointParent(checkpoint int) (int, error) {
+	header, err := ReadCheckpointHeader(path.Join(c.dir, NumberToFilename(checkpoint)))
+	if err != nil {
+		return -1, fmt.Errorf("cannot read header of checkpoint %d: %w", checkpoint, err)
+	}
+	return header.Parent, nil
+}
+
+// CheckpointHeader describes a checkpoint file, as recorded in its header
+type CheckpointHeader struct {
+	Version   uint16 // format version, without the codec
+	Codec     Codec  // codec used to compress the nodes
+	Parent    int    // number of the parent checkpoint of a delta checkpoint, -1 for full checkpoints
+	NodeCount uint64 // number of nodes stored in the file (only new nodes for delta checkpoints)
+	TrieCount uint16
+}
+
+// ReadCheckpointHeader reads the header of the given checkpoint file, without loading the checkpoint
+func ReadCheckpointHeader(filepath string) (*CheckpointHeader, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open checkpoint file %s: %w", filepath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, 4)
+	_, err = io.ReadFull(file, header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+
+	magicBytes, pos := readUint16(header, 0)
+	fullVersion, _ := readUint16(header, pos)
+	if magicBytes != MagicBytes {
+		return nil, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magicBytes, MagicBytes)
+	}
+	version, codec, err := splitVersion(fullVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := -1
+	if isDeltaVersion(version) {
+		deltaHeader := make([]byte, 8+8)
+		_, err = io.ReadFull(file, deltaHeader)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read delta header bytes: %w", err)
+		}
+		parentNumber, _ := readUint64(deltaHeader, 0)
+		parent = int(parentNumber)
+	}
+
+	countsHeader := make([]byte, 8+2)
+	_, err = io.ReadFull(file, countsHeader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+	nodeCount, pos := readUint64(countsHeader, 0)
+	trieCount, _ := readUint16(countsHeader, pos)
+
+	return &CheckpointHeader{
+		Version:   version,
+		Codec:     codec,
+		Parent:    parent,
+		NodeCount: nodeCount,
+		TrieCount: trieCount,
+	}, nil
+}
+
+// LoadCheckpoint loads the checkpoint from the given file.
+// Delta checkpoints are loaded together with the chain of their parent checkpoints,
+// which must reside in the same directory.
 func LoadCheckpoint(filepath string) (*flattener.FlattenedForest, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
@@ -321,78 +606,170 @@ func LoadCheckpoint(filepath string) (*flattener.FlattenedForest, error) {
 		_ = file.Close()
 	}()
 
-	return ReadCheckpoint(file)
+	forest, delta, parent, err := readCheckpoint(file)
+	if err != nil {
+		return nil, err
+	}
+	if delta == nil {
+		return forest, nil
+	}
+
+	base, err := LoadCheckpoint(path.Join(path.Dir(filepath), NumberToFilename(parent)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot load parent checkpoint %d: %w", parent, err)
+	}
+
+	return delta.ApplyTo(base)
 }
 
+// ReadCheckpoint reads a full checkpoint.
+// Delta checkpoints cannot be read without their parents, use LoadCheckpoint instead.
 func ReadCheckpoint(r io.Reader) (*flattener.FlattenedForest, error) {
+	forest, delta, parent, err := readCheckpoint(r)
+	if err != nil {
+		return nil, err
+	}
+	if delta != nil {
+		return nil, fmt.Errorf("cannot read delta checkpoint without its parent checkpoint %d", parent)
+	}
+	return forest, nil
+}
+
+// readCheckpoint reads either a full checkpoint or a delta checkpoint (together with the number of its parent checkpoint)
+func readCheckpoint(r io.Reader) (*flattener.FlattenedForest, *flattener.FlattenedForestDelta, int, error) {
 
 	var bufReader io.Reader = bufio.NewReader(r)
 	crcReader := NewCRC32Reader(bufReader)
 	var reader io.Reader = crcReader
 
-	header := make([]byte, 4+8+2)
+	header := make([]byte, 4)
 
 	_, err := io.ReadFull(reader, header)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read header bytes: %w", err)
+		return nil, nil, -1, fmt.Errorf("cannot read header bytes: %w", err)
 	}
 
 	magicBytes, pos := readUint16(header, 0)
-	version, pos := readUint16(header, pos)
-	nodesCount, pos := readUint64(header, pos)
-	triesCount, _ := readUint16(header, pos)
+	fullVersion, _ := readUint16(header, pos)
 
 	if magicBytes != MagicBytes {
-		return nil, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magicBytes, MagicBytes)
+		return nil, nil, -1, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magicBytes, MagicBytes)
 	}
-	if version != VersionV1 && version != VersionV3 {
-		return nil, fmt.Errorf("unsupported file version %x ", version)
+	version, codec, err := splitVersion(fullVersion)
+	if err != nil {
+		return nil, nil, -1, err
 	}
 
-	if version != VersionV3 {
+	if version == VersionV1 {
 		reader = bufReader //switch back to plain reader
 	}
 
+	parent := -1
+	baseNodeCount := uint64(0)
+	if isDeltaVersion(version) {
+		deltaHeader := make([]byte, 8+8)
+		_, err := io.ReadFull(reader, deltaHeader)
+		if err != nil {
+			return nil, nil, -1, fmt.Errorf("cannot read delta header bytes: %w", err)
+		}
+		parentNumber, pos := readUint64(deltaHeader, 0)
+		baseNodeCount, _ = readUint64(deltaHeader, pos)
+		parent = int(parentNumber)
+	}
+
+	countsHeader := make([]byte, 8+2)
+	_, err = io.ReadFull(reader, countsHeader)
+	if err != nil {
+		return nil, nil, -1, fmt.Errorf("cannot read header bytes: %w", err)
+	}
+	nodesCount, pos := readUint64(countsHeader, 0)
+	triesCount, _ := readUint16(countsHeader, pos)
+
 	nodes := make([]*flattener.StorableNode, nodesCount+1) //+1 for 0 index meaning nil
 	tries := make([]*flattener.StorableTrie, triesCount)
 
-	for i := uint64(1); i <= nodesCount; i++ {
-		storableNode, err := flattener.ReadStorableNode(reader)
+	if version == VersionV5 || version == VersionDeltaV6 {
+		partsHeader := make([]byte, 2)
+		_, err = io.ReadFull(reader, partsHeader)
+		if err != nil {
+			return nil, nil, -1, fmt.Errorf("cannot read parts header bytes: %w", err)
+		}
+		partCount, _ := readUint16(partsHeader, 0)
+
+		err = readNodeParts(reader, nodes, partCount, codec)
 		if err != nil {
-			return nil, fmt.Errorf("cannot read storable node %d: %w", i, err)
+			return nil, nil, -1, fmt.Errorf("cannot read storable nodes: %w", err)
+		}
+	} else {
+		for i := uint64(1); i <= nodesCount; i++ {
+			storableNode, err := flattener.ReadStorableNode(reader)
+			if err != nil {
+				return nil, nil, -1, fmt.Errorf("cannot read storable node %d: %w", i, err)
+			}
+			nodes[i] = storableNode
 		}
-		nodes[i] = storableNode
 	}
 
 	// TODO version ?
 	for i := uint16(0); i < triesCount; i++ {
 		storableTrie, err := flattener.ReadStorableTrie(reader)
 		if err != nil {
-			return nil, fmt.Errorf("cannot read storable trie %d: %w", i, err)
+			return nil, nil, -1, fmt.Errorf("cannot read storable trie %d: %w", i, err)
 		}
 		tries[i] = storableTrie
 	}
 
-	if version == VersionV3 {
+	if version != VersionV1 {
 		crc32buf := make([]byte, 4)
-		_, err := bufReader.Read(crc32buf)
+		_, err := io.ReadFull(bufReader, crc32buf)
 		if err != nil {
-			return nil, fmt.Errorf("error while reading CRC32 checksum: %w", err)
+			return nil, nil, -1, fmt.Errorf("error while reading CRC32 checksum: %w", err)
 		}
 		readCrc32, _ := readUint32(crc32buf, 0)
 
 		calculatedCrc32 := crcReader.Crc32()
 
 		if calculatedCrc32 != readCrc32 {
-			return nil, fmt.Errorf("checkpoint checksum failed! File contains %x but read data checksums to %x", readCrc32, calculatedCrc32)
+			return nil, nil, -1, fmt.Errorf("checkpoint checksum failed! File contains %x but read data checksums to %x", readCrc32, calculatedCrc32)
 		}
 	}
 
+	if isDeltaVersion(version) {
+		return nil, &flattener.FlattenedForestDelta{
+			BaseNodeCount: baseNodeCount,
+			Nodes:         nodes[1:],
+			Tries:         tries,
+		}, parent, nil
+	}
+
 	return &flattener.FlattenedForest{
 		Nodes: nodes,
 		Tries: tries,
-	}, nil
+	}, nil, -1, nil
+
+}
+
+// splitVersion splits the version read from a checkpoint file into the format version and the codec
+func splitVersion(fullVersion uint16) (uint16, Codec, error) {
+	version := fullVersion & versionMask
+	codec := Codec(fullVersion >> versionCodecShift)
+
+	if version != VersionV1 && version != VersionV3 && version != VersionDeltaV4 && version != VersionV5 && version != VersionDeltaV6 {
+		return 0, CodecNone, fmt.Errorf("unsupported file version %x ", fullVersion)
+	}
+	if codec != CodecNone && version != VersionV5 && version != VersionDeltaV6 {
+		return 0, CodecNone, fmt.Errorf("unsupported file version %x, version %x cannot be compressed", fullVersion, version)
+	}
+	if codec != CodecNone && codec != CodecSnappy {
+		return 0, CodecNone, fmt.Errorf("unsupported file version %x, unknown codec %d", fullVersion, codec)
+	}
+
+	return version, codec, nil
+}
 
+// isDeltaVersion returns true if the given version is a delta checkpoint version
+func isDeltaVersion(version uint16) bool {
+	return version == VersionDeltaV4 || version == VersionDeltaV6
 }
 
 func writeUint16(buffer []byte, location int, value uint16) int {
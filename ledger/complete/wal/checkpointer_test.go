@@ -503,3 +503,196 @@ func loadIntoForest(forest *mtrie.Forest, forestSequencing *flattener.FlattenedF
 	}
 	return nil
 }
+
+func Test_DeltaCheckpointing(t *testing.T) {
+
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		f, err := mtrie.NewForest(pathByteSize, dir, size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+		require.NoError(t, err)
+
+		var rootHash = f.GetEmptyRootHash()
+
+		//saved data after updates
+		savedData := make(map[string]map[string]*ledger.Payload)
+
+		wal, err := realWAL.NewWAL(zerolog.Nop(), nil, dir, size*10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+
+		for i := 0; i < size; i++ {
+
+			keys := utils.RandomUniqueKeys(numInsPerStep, keyNumberOfParts, 1600, 1600)
+			values := utils.RandomValues(numInsPerStep, valueMaxByteSize/2, valueMaxByteSize)
+			update, err := ledger.NewUpdate(rootHash, keys, values)
+			require.NoError(t, err)
+
+			trieUpdate, err := pathfinder.UpdateToTrieUpdate(update, pathFinderVersion)
+			require.NoError(t, err)
+
+			err = wal.RecordUpdate(trieUpdate)
+			require.NoError(t, err)
+
+			rootHash, err = f.Update(trieUpdate)
+			require.NoError(t, err)
+
+			data := make(map[string]*ledger.Payload, len(trieUpdate.Paths))
+			for j, path := range trieUpdate.Paths {
+				data[string(path)] = trieUpdate.Payloads[j]
+			}
+
+			savedData[string(rootHash)] = data
+		}
+
+		require.FileExists(t, path.Join(dir, "00000010")) //make sure we have enough segments saved
+
+		checkpointer, err := wal.NewCheckpointer()
+		require.NoError(t, err)
+
+		fullCheckpointFile := path.Join(dir, "full.checkpoint")
+
+		t.Run("delta checkpoint requires a parent checkpoint", func(t *testing.T) {
+			err := checkpointer.CheckpointDelta(3, func() (io.WriteCloser, error) {
+				return checkpointer.CheckpointWriter(3)
+			})
+			require.Error(t, err)
+			require.NoFileExists(t, path.Join(dir, "checkpoint.00000003"))
+		})
+
+		t.Run("create full checkpoint and chain of delta checkpoints", func(t *testing.T) {
+			err := checkpointer.Checkpoint(3, func() (io.WriteCloser, error) {
+				return checkpointer.CheckpointWriter(3)
+			})
+			require.NoError(t, err)
+
+			// full checkpoint to compare the chain against, stored outside of the checkpoint sequence
+			err = checkpointer.Checkpoint(9, func() (io.WriteCloser, error) {
+				return os.Create(fullCheckpointFile)
+			})
+			require.NoError(t, err)
+
+			for _, checkpoint := range []int{6, 9} {
+				checkpoint := checkpoint
+				err = checkpointer.CheckpointDelta(checkpoint, func() (io.WriteCloser, error) {
+					return checkpointer.CheckpointWriter(checkpoint)
+				})
+				require.NoError(t, err)
+			}
+
+			parent, err := checkpointer.CheckpointParent(3)
+			require.NoError(t, err)
+			require.Equal(t, -1, parent)
+
+			parent, err = checkpointer.CheckpointParent(6)
+			require.NoError(t, err)
+			require.Equal(t, 3, parent)
+
+			parent, err = checkpointer.CheckpointParent(9)
+			require.NoError(t, err)
+			require.Equal(t, 6, parent)
+
+			// without parts enabled, checkpoints are readable by all binaries
+			for checkpoint, version := range map[int]uint16{3: realWAL.VersionV3, 6: realWAL.VersionDeltaV4, 9: realWAL.VersionDeltaV4} {
+				header, err := realWAL.ReadCheckpointHeader(path.Join(dir, realWAL.NumberToFilename(checkpoint)))
+				require.NoError(t, err)
+				require.Equal(t, version, header.Version)
+			}
+		})
+
+		t.Run("delta checkpoint cannot be read without its parents", func(t *testing.T) {
+			file, err := os.Open(path.Join(dir, "checkpoint.00000009"))
+			require.NoError(t, err)
+			defer file.Close()
+
+			_, err = realWAL.ReadCheckpoint(file)
+			require.Error(t, err)
+		})
+
+		t.Run("delta checkpoint chain contains all data", func(t *testing.T) {
+			forestSequencing, err := checkpointer.LoadCheckpoint(9)
+			require.NoError(t, err)
+
+			f2, err := mtrie.NewForest(pathByteSize, dir, size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+			require.NoError(t, err)
+
+			err = loadIntoForest(f2, forestSequencing)
+			require.NoError(t, err)
+
+			// full checkpoint of the same segments must contain the same tries
+			fullForestSequencing, err := realWAL.LoadCheckpoint(fullCheckpointFile)
+			require.NoError(t, err)
+			require.Len(t, forestSequencing.Tries, len(fullForestSequencing.Tries))
+
+			for _, storableTrie := range fullForestSequencing.Tries {
+				_, err := f2.GetTrie(storableTrie.RootHash)
+				require.NoError(t, err)
+			}
+
+			checked := 0
+			for rootHash, data := range savedData {
+
+				// last updates are not part of the checkpoint
+				if _, err := f2.GetTrie(ledger.RootHash([]byte(rootHash))); err != nil {
+					continue
+				}
+				checked++
+
+				paths := make([]ledger.Path, 0, len(data))
+				for pathString := range data {
+					paths = append(paths, []byte(pathString))
+				}
+
+				payloads, err := f2.Read(&ledger.TrieRead{RootHash: ledger.RootHash([]byte(rootHash)), Paths: paths})
+				require.NoError(t, err)
+
+				for i := range paths {
+					require.True(t, data[string(paths[i])].Equals(payloads[i]))
+				}
+			}
+			require.Greater(t, checked, 0)
+		})
+
+		t.Run("delta checkpoints split into parts", func(t *testing.T) {
+			expected, err := checkpointer.LoadCheckpoint(9)
+			require.NoError(t, err)
+
+			require.NoError(t, checkpointer.RemoveCheckpoint(9))
+			require.NoError(t, checkpointer.RemoveCheckpoint(6))
+
+			wal.SetCheckpointParts(true)
+			defer wal.SetCheckpointParts(false)
+			partsCheckpointer, err := wal.NewCheckpointer()
+			require.NoError(t, err)
+
+			for _, checkpoint := range []int{6, 9} {
+				checkpoint := checkpoint
+				err = partsCheckpointer.CheckpointDelta(checkpoint, func() (io.WriteCloser, error) {
+					return partsCheckpointer.CheckpointWriter(checkpoint)
+				})
+				require.NoError(t, err)
+
+				header, err := realWAL.ReadCheckpointHeader(path.Join(dir, realWAL.NumberToFilename(checkpoint)))
+				require.NoError(t, err)
+				require.Equal(t, realWAL.VersionDeltaV6, header.Version)
+			}
+
+			// the parent of the delta checkpoints is still in version 3
+			forestSequencing, err := partsCheckpointer.LoadCheckpoint(9)
+			require.NoError(t, err)
+			require.Equal(t, expected, forestSequencing)
+		})
+
+		t.Run("missing parent checkpoint fails loading", func(t *testing.T) {
+			err := os.Rename(path.Join(dir, "checkpoint.00000006"), path.Join(dir, "checkpoint.backup"))
+			require.NoError(t, err)
+
+			_, err = checkpointer.LoadCheckpoint(9)
+			require.Error(t, err)
+
+			err = os.Rename(path.Join(dir, "checkpoint.backup"), path.Join(dir, "checkpoint.00000006"))
+			require.NoError(t, err)
+		})
+
+		err = wal.Close()
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,71 @@
+package wal
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+// Codec is a compression codec applied to WAL records and checkpoint node parts.
+// The codec used is recorded alongside the data (in the operation byte of WAL records and in the
+// version of checkpoint files), so files written with different codecs can be read side by side.
+type Codec uint8
+
+const (
+	// CodecNone stores data uncompressed, as all files written before codecs were introduced
+	CodecNone Codec = 0
+	// CodecSnappy compresses data with snappy
+	CodecSnappy Codec = 1
+)
+
+// maxCodec is the highest codec which fits into the operation byte of a WAL record
+const maxCodec = 0x0f
+
+func (c Codec) String() string {
+	switch c {
+	case CodecNone:
+		return "none"
+	case CodecSnappy:
+		return "snappy"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// ParseCodec returns the codec with the given name, as returned by Codec.String
+func ParseCodec(name string) (Codec, error) {
+	switch name {
+	case "", "none":
+		return CodecNone, nil
+	case "snappy":
+		return CodecSnappy, nil
+	default:
+		return CodecNone, fmt.Errorf("unknown compression codec: %s", name)
+	}
+}
+
+func (c Codec) compress(data []byte) ([]byte, error) {
+	switch c {
+	case CodecNone:
+		return data, nil
+	case CodecSnappy:
+		return snappy.Encode(nil, data), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %d", uint8(c))
+	}
+}
+
+func (c Codec) decompress(data []byte) ([]byte, error) {
+	switch c {
+	case CodecNone:
+		return data, nil
+	case CodecSnappy:
+		decoded, err := snappy.Decode(nil, data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress snappy data: %w", err)
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %d", uint8(c))
+	}
+}
@@ -16,9 +16,15 @@ type Compactor struct {
 	interval           time.Duration
 	checkpointDistance uint
 	checkpointsToKeep  uint
+	maxDeltas          uint
 }
 
-func NewCompactor(checkpointer *Checkpointer, interval time.Duration, checkpointDistance uint, checkpointsToKeep uint) *Compactor {
+// NewCompactor creates a new Compactor which creates a checkpoint every checkpointDistance segments
+// and keeps the latest checkpointsToKeep checkpoints (0 to keep all).
+// If maxDeltas is positive, up to maxDeltas incremental (delta) checkpoints are created on top of a
+// full checkpoint before the chain is consolidated into a new full checkpoint.
+// If maxDeltas is 0, only full checkpoints are created.
+func NewCompactor(checkpointer *Checkpointer, interval time.Duration, checkpointDistance uint, checkpointsToKeep uint, maxDeltas uint) *Compactor {
 	if checkpointDistance < 1 {
 		checkpointDistance = 1
 	}
@@ -29,6 +35,7 @@ func NewCompactor(checkpointer *Checkpointer, interval time.Duration, checkpoint
 		interval:           interval,
 		checkpointDistance: checkpointDistance,
 		checkpointsToKeep:  checkpointsToKeep,
+		maxDeltas:          maxDeltas,
 	}
 }
 
@@ -103,9 +110,20 @@ func (c *Compactor) createCheckpoints() error {
 		checkpointNumber := to - 1
 		fmt.Printf("checkpointing to %d\n", checkpointNumber)
 
-		err = c.checkpointer.Checkpoint(checkpointNumber, func() (io.WriteCloser, error) {
+		delta, err := c.shouldCreateDelta()
+		if err != nil {
+			return fmt.Errorf("cannot determine checkpoint type: %w", err)
+		}
+
+		writer := func() (io.WriteCloser, error) {
 			return c.checkpointer.CheckpointWriter(checkpointNumber)
-		})
+		}
+
+		if delta {
+			err = c.checkpointer.CheckpointDelta(checkpointNumber, writer)
+		} else {
+			err = c.checkpointer.Checkpoint(checkpointNumber, writer)
+		}
 		if err != nil {
 			return fmt.Errorf("error creating checkpoint (%d): %w", checkpointNumber, err)
 		}
@@ -113,6 +131,46 @@ func (c *Compactor) createCheckpoints() error {
 	return nil
 }
 
+// shouldCreateDelta returns true if the next checkpoint should be a delta checkpoint,
+// i.e. if there is a latest checkpoint with less than maxDeltas delta checkpoints in its chain.
+func (c *Compactor) shouldCreateDelta() (bool, error) {
+	if c.maxDeltas == 0 {
+		return false, nil
+	}
+
+	latest, err := c.checkpointer.LatestCheckpoint()
+	if err != nil {
+		return false, fmt.Errorf("cannot get latest checkpoint: %w", err)
+	}
+	if latest == -1 {
+		return false, nil
+	}
+
+	chain, err := c.checkpointChain(latest)
+	if err != nil {
+		return false, err
+	}
+
+	// chain contains the full checkpoint as well
+	return uint(len(chain)-1) < c.maxDeltas, nil
+}
+
+// checkpointChain returns the given checkpoint followed by all its ancestors, down to the full checkpoint
+func (c *Compactor) checkpointChain(checkpoint int) ([]int, error) {
+	chain := []int{checkpoint}
+	for {
+		parent, err := c.checkpointer.CheckpointParent(checkpoint)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get parent of checkpoint %d: %w", checkpoint, err)
+		}
+		if parent == -1 {
+			return chain, nil
+		}
+		chain = append(chain, parent)
+		checkpoint = parent
+	}
+}
+
 func (c *Compactor) cleanupCheckpoints() error {
 	// don't bother listing checkpoints if we keep them all
 	if c.checkpointsToKeep == 0 {
@@ -125,7 +183,22 @@ func (c *Compactor) cleanupCheckpoints() error {
 	if len(checkpoints) > int(c.checkpointsToKeep) {
 		checkpointsToRemove := checkpoints[:len(checkpoints)-int(c.checkpointsToKeep)] // if condition guarantees this never fails
 
+		// delta checkpoints cannot be loaded without their ancestors, so keep those as well
+		required := make(map[int]struct{})
+		for _, checkpoint := range checkpoints[len(checkpoints)-int(c.checkpointsToKeep):] {
+			chain, err := c.checkpointChain(checkpoint)
+			if err != nil {
+				return err
+			}
+			for _, ancestor := range chain {
+				required[ancestor] = struct{}{}
+			}
+		}
+
 		for _, checkpoint := range checkpointsToRemove {
+			if _, ok := required[checkpoint]; ok {
+				continue
+			}
 			err := c.checkpointer.RemoveCheckpoint(checkpoint)
 			if err != nil {
 				return fmt.Errorf("cannot remove checkpoint %d: %w", checkpoint, err)
@@ -51,7 +51,7 @@ func Test_Compactor(t *testing.T) {
 			checkpointer, err := wal.NewCheckpointer()
 			require.NoError(t, err)
 
-			compactor := NewCompactor(checkpointer, 100*time.Millisecond, checkpointDistance, 1) //keep only latest checkpoint
+			compactor := NewCompactor(checkpointer, 100*time.Millisecond, checkpointDistance, 1, 0) //keep only latest checkpoint
 
 			// Run Compactor in background.
 			<-compactor.Ready()
@@ -217,7 +217,7 @@ func Test_Compactor_checkpointInterval(t *testing.T) {
 			checkpointer, err := wal.NewCheckpointer()
 			require.NoError(t, err)
 
-			compactor := NewCompactor(checkpointer, 100*time.Millisecond, checkpointDistance, 2)
+			compactor := NewCompactor(checkpointer, 100*time.Millisecond, checkpointDistance, 2, 0)
 
 			// Generate the tree and create WAL
 			for i := 0; i < size; i++ {
@@ -299,6 +299,90 @@ func Test_Compactor_checkpointInterval(t *testing.T) {
 	})
 }
 
+func Test_Compactor_deltaCheckpoints(t *testing.T) {
+
+	numInsPerStep := 2
+	pathByteSize := 4
+	minPayloadByteSize := 100
+	maxPayloadByteSize := 2 << 16
+	size := 20
+	metricsCollector := &metrics.NoopCollector{}
+	checkpointDistance := uint(3) // there should be 3 WAL not checkpointed
+
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		f, err := mtrie.NewForest(4, dir, size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+		require.NoError(t, err)
+
+		var rootHash = f.GetEmptyRootHash()
+
+		t.Run("Compactor creates delta checkpoints", func(t *testing.T) {
+
+			wal, err := NewWAL(zerolog.Nop(), nil, dir, size*10, 4, 32*1024)
+			require.NoError(t, err)
+
+			checkpointer, err := wal.NewCheckpointer()
+			require.NoError(t, err)
+
+			// at most 2 delta checkpoints on top of a full checkpoint, keep only latest checkpoint
+			compactor := NewCompactor(checkpointer, 100*time.Millisecond, checkpointDistance, 1, 2)
+
+			// Generate the tree and create WAL
+			for i := 0; i < size; i++ {
+
+				paths := utils.RandomPaths(numInsPerStep, pathByteSize)
+				payloads := utils.RandomPayloads(numInsPerStep, minPayloadByteSize, maxPayloadByteSize)
+
+				update := &ledger.TrieUpdate{RootHash: rootHash, Paths: paths, Payloads: payloads}
+
+				err = wal.RecordUpdate(update)
+				require.NoError(t, err)
+
+				rootHash, err = f.Update(update)
+				require.NoError(t, err)
+
+				require.FileExists(t, path.Join(dir, NumberToFilenamePart(i)))
+
+				// run checkpoint creation after every file
+				err = compactor.createCheckpoints()
+				require.NoError(t, err)
+			}
+
+			// full checkpoint, followed by 2 deltas and consolidation into a new full checkpoint
+			expectedParents := map[int]int{3: -1, 7: 3, 11: 7, 15: -1, 19: 15}
+
+			checkpoints, err := checkpointer.Checkpoints()
+			require.NoError(t, err)
+			require.Equal(t, []int{3, 7, 11, 15, 19}, checkpoints)
+
+			for checkpoint, expectedParent := range expectedParents {
+				parent, err := checkpointer.CheckpointParent(checkpoint)
+				require.NoError(t, err)
+				require.Equal(t, expectedParent, parent)
+			}
+
+			// expect latest checkpoint and its parent to be kept
+			err = compactor.cleanupCheckpoints()
+			require.NoError(t, err)
+
+			checkpoints, err = checkpointer.Checkpoints()
+			require.NoError(t, err)
+			require.Equal(t, []int{15, 19}, checkpoints)
+
+			forestSequencing, err := checkpointer.LoadCheckpoint(19)
+			require.NoError(t, err)
+
+			f2, err := mtrie.NewForest(4, dir, size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+			require.NoError(t, err)
+			err = loadIntoForest(f2, forestSequencing)
+			require.NoError(t, err)
+
+			err = wal.Close()
+			require.NoError(t, err)
+		})
+	})
+}
+
 func loadIntoForest(forest *mtrie.Forest, forestSequencing *flattener.FlattenedForest) error {
 	tries, err := flattener.RebuildTries(forestSequencing)
 	if err != nil {
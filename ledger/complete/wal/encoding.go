@@ -13,6 +13,11 @@ type WALOperation uint8
 const WALUpdate WALOperation = 1
 const WALDelete WALOperation = 2
 
+// walOperationMask selects the operation type from the first byte of a record,
+// the remaining high bits hold the Codec used to compress the rest of the record.
+const walOperationMask = 0x0f
+const walCodecShift = 4
+
 /*
 The LedgerWAL update record uses two operations so far - an update which must include all keys and values, and deletion
 which only needs a root tree state commitment.
@@ -22,6 +27,9 @@ Every record has:
 
 1 byte Operation Type | 2 bytes Big Endian uint16 length of state commitment | state commitment data
 
+The lower 4 bits of the first byte hold the operation type, the upper 4 bits the compression Codec
+of the remainder of the record (0 - uncompressed, for records written before codecs were introduced).
+
 If OP = WALUpdate, then it follow with:
 
 4 bytes Big Endian uint32 - total number of key/value pairs | 2 bytes Big Endian uint16 - length of key (keys are the same length)
@@ -51,19 +59,58 @@ func EncodeDelete(rootHash ledger.RootHash) []byte {
 	return buf
 }
 
+// CompressRecord compresses an encoded record (except for its operation byte) with the given codec
+func CompressRecord(data []byte, codec Codec) ([]byte, error) {
+	if codec == CodecNone {
+		return data, nil
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("cannot compress empty record")
+	}
+	if codec > maxCodec {
+		return nil, fmt.Errorf("codec %d cannot be recorded in WAL records", codec)
+	}
+	if data[0]>>walCodecShift != 0 {
+		return nil, fmt.Errorf("record is already compressed")
+	}
+
+	compressed, err := codec.compress(data[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, len(compressed)+1)
+	buf = append(buf, byte(codec)<<walCodecShift|data[0])
+	buf = append(buf, compressed...)
+	return buf, nil
+}
+
 func Decode(data []byte) (operation WALOperation, rootHash ledger.RootHash, update *ledger.TrieUpdate, err error) {
-	if len(data) < 4 { // 1 byte op + 2 size + actual data = 4 minimum
+	if len(data) < 2 {
+		err = fmt.Errorf("data corrupted, too short to represent operation - hexencoded data: %x", data)
+		return
+	}
+
+	codec := Codec(data[0] >> walCodecShift)
+	operation = WALOperation(data[0] & walOperationMask)
+
+	record, err := codec.decompress(data[1:])
+	if err != nil {
+		err = fmt.Errorf("cannot decompress record: %w", err)
+		return
+	}
+
+	if len(record) < 3 { // 1 byte op + 2 size + actual data = 4 minimum
 		err = fmt.Errorf("data corrupted, too short to represent operation - hexencoded data: %x", data)
 		return
 	}
 
-	operation = WALOperation(data[0])
 	switch operation {
 	case WALUpdate:
-		update, err = encoding.DecodeTrieUpdate(data[1:])
+		update, err = encoding.DecodeTrieUpdate(record)
 		return
 	case WALDelete:
-		rootHash, _, err = utils.ReadShortData(data[1:])
+		rootHash, _, err = utils.ReadShortData(record)
 		if err != nil {
 			err = fmt.Errorf("cannot read state commitment: %w", err)
 		}
@@ -43,6 +43,26 @@ func TestUpdate(t *testing.T) {
 		assert.Nil(t, stateCommitment)
 		assert.Equal(t, update, up)
 	})
+
+	t.Run("decode compressed", func(t *testing.T) {
+		data, err := realWAL.CompressRecord(realWAL.EncodeUpdate(update), realWAL.CodecSnappy)
+		require.NoError(t, err)
+		assert.NotEqual(t, expected, data)
+
+		operation, stateCommitment, up, err := realWAL.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, realWAL.WALUpdate, operation)
+		assert.Nil(t, stateCommitment)
+		assert.Equal(t, update, up)
+	})
+
+	t.Run("corrupted compressed record", func(t *testing.T) {
+		data, err := realWAL.CompressRecord(realWAL.EncodeUpdate(update), realWAL.CodecSnappy)
+		require.NoError(t, err)
+
+		_, _, _, err = realWAL.Decode(data[:len(data)-3])
+		require.Error(t, err)
+	})
 }
 
 func TestDelete(t *testing.T) {
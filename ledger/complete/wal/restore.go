@@ -0,0 +1,136 @@
+package wal
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+// errTrieRestored stops replaying the WAL once the restored trie has been created
+var errTrieRestored = errors.New("trie restored")
+
+// RestoreTrie recreates the trie with the given root hash from checkpoints and the WAL, for example after it
+// has been evicted from the forest. Checkpoints are tried from the newest to the oldest: each checkpoint is
+// loaded into a temporary forest and the segments up to the next newer checkpoint are replayed on top of it,
+// until the trie is found. Finally the root checkpoint (or the empty trie) and the segments up to the oldest
+// checkpoint are tried, if the WAL starts at segment 0.
+// It returns false if the trie cannot be restored, for example because it has never been created.
+func (w *LedgerWAL) RestoreTrie(rootHash ledger.RootHash) (*trie.MTrie, bool, error) {
+	from, to, err := w.Segments()
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot list segments: %w", err)
+	}
+
+	checkpointer, err := w.NewCheckpointer()
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot create checkpointer: %w", err)
+	}
+
+	checkpoints, err := checkpointer.Checkpoints()
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot get list of checkpoints: %w", err)
+	}
+
+	// last is the last segment not covered by a newer (loadable) checkpoint
+	last := to
+	for i := len(checkpoints) - 1; i >= 0; i-- {
+		checkpoint := checkpoints[i]
+		if checkpoint > to {
+			continue
+		}
+		// segments between the checkpoint and the first segment are missing
+		if checkpoint < from-1 {
+			break
+		}
+
+		forestSequencing, err := checkpointer.LoadCheckpoint(checkpoint)
+		if err != nil {
+			w.log.Warn().Int("checkpoint", checkpoint).Err(err).Msg("checkpoint loading failed")
+			continue
+		}
+
+		restored, found, err := w.restoreTrieFrom(forestSequencing, checkpoint+1, last, rootHash)
+		if err != nil || found {
+			return restored, found, err
+		}
+		last = checkpoint
+	}
+
+	if from != 0 {
+		return nil, false, nil
+	}
+
+	// replaying from segment 0 loads the root checkpoint, if there is one
+	return w.restoreTrieFrom(nil, 0, last, rootHash)
+}
+
+// restoreTrieFrom replays the segments from first to last on top of the given checkpoint,
+// until the trie with the given root hash is created.
+func (w *LedgerWAL) restoreTrieFrom(forestSequencing *flattener.FlattenedForest, first, last int, rootHash ledger.RootHash) (*trie.MTrie, bool, error) {
+	forest, err := mtrie.NewForest(w.pathByteSize, w.wal.Dir(), w.forestCapacity, &metrics.NoopCollector{}, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot create forest: %w", err)
+	}
+
+	addTries := func(forestSequencing *flattener.FlattenedForest) error {
+		tries, err := flattener.RebuildTries(forestSequencing)
+		if err != nil {
+			return fmt.Errorf("rebuilding forest from sequenced nodes failed: %w", err)
+		}
+		return forest.AddTries(tries)
+	}
+
+	if forestSequencing != nil {
+		err = addTries(forestSequencing)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	restored, err := forest.GetTrie(rootHash)
+	if err == nil {
+		return restored, true, nil
+	}
+
+	if first > last {
+		return nil, false, nil
+	}
+
+	err = w.replay(first, last,
+		addTries,
+		func(update *ledger.TrieUpdate) error {
+			newRootHash, err := forest.Update(update)
+			if err != nil {
+				// the parent trie might not be part of the checkpoint, the trie can then be
+				// restored from an older checkpoint
+				return nil
+			}
+			if newRootHash.Equals(rootHash) {
+				restored, err = forest.GetTrie(newRootHash)
+				if err != nil {
+					return err
+				}
+				return errTrieRestored
+			}
+			return nil
+		},
+		func(ledger.RootHash) error {
+			// deleted tries are kept, the restored trie might be one of them
+			return nil
+		},
+		false,
+	)
+	if errors.Is(err, errTrieRestored) {
+		return restored, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot replay segments %d to %d: %w", first, last, err)
+	}
+
+	return nil, false, nil
+}
@@ -0,0 +1,306 @@
+package wal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	prometheusWAL "github.com/m4ksio/wal/wal"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+// CheckpointVerification is the result of verifying a single checkpoint file
+type CheckpointVerification struct {
+	Checkpoint int    // number of the checkpoint, -1 for the root checkpoint
+	File       string // path of the checkpoint file
+	Err        error  // nil if the checkpoint is valid
+}
+
+// VerifyCheckpoints reads every checkpoint file (including the root checkpoint) and verifies its checksums.
+// Delta checkpoints are verified on their own, without loading their parents.
+func (c *Checkpointer) VerifyCheckpoints() ([]CheckpointVerification, error) {
+	checkpoints, err := c.Checkpoints()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list checkpoints: %w", err)
+	}
+
+	hasRootCheckpoint, err := c.HasRootCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("cannot check root checkpoint existence: %w", err)
+	}
+
+	var verifications []CheckpointVerification
+	if hasRootCheckpoint {
+		file := path.Join(c.dir, RootCheckpointFilename)
+		verifications = append(verifications, CheckpointVerification{
+			Checkpoint: -1,
+			File:       file,
+			Err:        verifyCheckpointFile(file),
+		})
+	}
+
+	for _, checkpoint := range checkpoints {
+		file := path.Join(c.dir, NumberToFilename(checkpoint))
+		verifications = append(verifications, CheckpointVerification{
+			Checkpoint: checkpoint,
+			File:       file,
+			Err:        verifyCheckpointFile(file),
+		})
+	}
+
+	return verifications, nil
+}
+
+// verifyCheckpointFile reads the whole checkpoint file, which verifies its checksums and node encoding
+func verifyCheckpointFile(filepath string) error {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return fmt.Errorf("cannot open checkpoint file %s: %w", filepath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, _, _, err = readCheckpoint(file)
+	return err
+}
+
+// WALVerification is the result of verifying the records of a LedgerWAL
+type WALVerification struct {
+	// Checkpoint is the checkpoint the root hashes have been recomputed from,
+	// -1 if they have been recomputed from the root checkpoint or the empty trie
+	Checkpoint int
+	// RecomputedFrom is the first segment whose root hashes have been recomputed,
+	// -1 if no checkpoint was available to recompute the root hashes from
+	RecomputedFrom int
+	// Records is the number of valid records
+	Records int
+	// RootHash is the root hash of the trie created by the last valid update
+	RootHash ledger.RootHash
+	// Segment is the segment containing the first bad record, -1 if all records are valid
+	Segment int
+	// Offset is the offset in Segment at which the first bad record ends or the corruption has been detected.
+	// All records ending before Offset are valid.
+	Offset int64
+	// Err describes why the first bad record is invalid, nil if all records are valid
+	Err error
+}
+
+// Verify reads and decodes every record of the WAL, stopping at the first bad record.
+// Root hashes are recomputed by applying the updates recorded after the latest loadable checkpoint
+// (or the root checkpoint) on top of it, the same way the WAL is replayed on startup. This detects
+// updates of tries which don't exist in the recomputed state.
+// Returned errors are unexpected errors, bad records are reported in the verification.
+func (w *LedgerWAL) Verify() (*WALVerification, error) {
+	from, to, err := w.Segments()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list segments: %w", err)
+	}
+
+	checkpointer, err := w.NewCheckpointer()
+	if err != nil {
+		return nil, fmt.Errorf("cannot create checkpointer: %w", err)
+	}
+
+	forest, err := mtrie.NewForest(w.pathByteSize, w.wal.Dir(), w.forestCapacity, &metrics.NoopCollector{}, func(evictedTrie *trie.MTrie) error {
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create forest: %w", err)
+	}
+
+	result := &WALVerification{
+		Checkpoint:     -1,
+		RecomputedFrom: -1,
+		RootHash:       forest.GetEmptyRootHash(),
+		Segment:        -1,
+	}
+
+	checkpoint, err := w.loadVerificationBase(checkpointer, forest, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint >= 0 {
+		result.Checkpoint = checkpoint
+		result.RecomputedFrom = checkpoint + 1
+	} else if from == 0 {
+		result.RecomputedFrom = 0
+	}
+
+	sr, err := prometheusWAL.NewSegmentsRangeReader(prometheusWAL.SegmentRange{
+		Dir:   w.wal.Dir(),
+		First: from,
+		Last:  to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create segment reader: %w", err)
+	}
+	defer sr.Close()
+
+	reader := prometheusWAL.NewReader(sr)
+
+	for reader.Next() {
+		// the reader reuses the record buffer, while the forest keeps the decoded payloads
+		data := make([]byte, len(reader.Record()))
+		copy(data, reader.Record())
+
+		operation, rootHash, update, err := Decode(data)
+		if err != nil {
+			result.fail(reader.Segment(), reader.Offset(), fmt.Errorf("cannot decode record: %w", err))
+			return result, nil
+		}
+
+		if result.RecomputedFrom >= 0 && reader.Segment() >= result.RecomputedFrom {
+			switch operation {
+			case WALUpdate:
+				newRootHash, err := forest.Update(update)
+				if err != nil {
+					result.fail(reader.Segment(), reader.Offset(), fmt.Errorf("cannot apply update of trie %s: %w", update.RootHash, err))
+					return result, nil
+				}
+				result.RootHash = newRootHash
+			case WALDelete:
+				forest.RemoveTrie(rootHash)
+			}
+		}
+
+		result.Records++
+	}
+
+	err = reader.Err()
+	if err != nil {
+		var corruption *prometheusWAL.CorruptionErr
+		if errors.As(err, &corruption) && corruption.Segment >= 0 {
+			result.fail(corruption.Segment, corruption.Offset, corruption.Err)
+		} else {
+			result.fail(reader.Segment(), reader.Offset(), err)
+		}
+	}
+
+	return result, nil
+}
+
+func (v *WALVerification) fail(segment int, offset int64, err error) {
+	v.Segment = segment
+	v.Offset = offset
+	v.Err = err
+}
+
+// loadVerificationBase loads the state the root hashes of the given segments are recomputed from into the forest,
+// the same way the state is loaded on startup. It returns the number of the loaded checkpoint, or -1 if the root
+// checkpoint (or no checkpoint) has been loaded.
+func (w *LedgerWAL) loadVerificationBase(checkpointer *Checkpointer, forest *mtrie.Forest, from, to int) (int, error) {
+	addTries := func(forestSequencing *flattener.FlattenedForest) error {
+		tries, err := flattener.RebuildTries(forestSequencing)
+		if err != nil {
+			return fmt.Errorf("rebuilding forest from sequenced nodes failed: %w", err)
+		}
+		return forest.AddTries(tries)
+	}
+
+	allCheckpoints, err := checkpointer.Checkpoints()
+	if err != nil {
+		return -1, fmt.Errorf("cannot get list of checkpoints: %w", err)
+	}
+
+	if len(allCheckpoints) > 0 {
+		availableCheckpoints := getPossibleCheckpoints(allCheckpoints, from-1, to)
+		for i := len(availableCheckpoints) - 1; i >= 0; i-- {
+			forestSequencing, err := checkpointer.LoadCheckpoint(availableCheckpoints[i])
+			if err != nil {
+				w.log.Warn().Int("checkpoint", availableCheckpoints[i]).Err(err).Msg("checkpoint loading failed")
+				continue
+			}
+			err = addTries(forestSequencing)
+			if err != nil {
+				return -1, fmt.Errorf("cannot add tries of checkpoint %d: %w", availableCheckpoints[i], err)
+			}
+			return availableCheckpoints[i], nil
+		}
+	}
+
+	if from != 0 {
+		return -1, nil
+	}
+
+	hasRootCheckpoint, err := checkpointer.HasRootCheckpoint()
+	if err != nil {
+		return -1, fmt.Errorf("cannot check root checkpoint existence: %w", err)
+	}
+	if hasRootCheckpoint {
+		forestSequencing, err := checkpointer.LoadRootCheckpoint()
+		if err != nil {
+			return -1, fmt.Errorf("cannot load root checkpoint: %w", err)
+		}
+		err = addTries(forestSequencing)
+		if err != nil {
+			return -1, fmt.Errorf("cannot add tries of root checkpoint: %w", err)
+		}
+	}
+
+	return -1, nil
+}
+
+// Repair truncates the WAL at the first bad record found by Verify, keeping all records before it.
+// Checkpoints newer than the checkpoint the verification started from cannot be loaded or refer to
+// truncated segments, so they are removed. Finally a new checkpoint is created from the last consistent
+// state. It returns the number of the new checkpoint.
+func (w *LedgerWAL) Repair(verification *WALVerification) (int, error) {
+	if verification.Err == nil {
+		return -1, fmt.Errorf("WAL has no bad records to repair")
+	}
+	if verification.RecomputedFrom < 0 {
+		return -1, fmt.Errorf("no checkpoint available to create a new checkpoint from")
+	}
+	if verification.Segment < verification.RecomputedFrom {
+		return -1, fmt.Errorf("bad record in segment %d is already covered by checkpoint %d and is not replayed, "+
+			"truncating the WAL would discard valid records", verification.Segment, verification.Checkpoint)
+	}
+
+	checkpointer, err := w.NewCheckpointer()
+	if err != nil {
+		return -1, fmt.Errorf("cannot create checkpointer: %w", err)
+	}
+
+	checkpoints, err := checkpointer.Checkpoints()
+	if err != nil {
+		return -1, fmt.Errorf("cannot list checkpoints: %w", err)
+	}
+	for _, checkpoint := range checkpoints {
+		if checkpoint <= verification.Checkpoint {
+			continue
+		}
+		w.log.Warn().Int("checkpoint", checkpoint).Msg("removing checkpoint newer than the last consistent state")
+		err = checkpointer.RemoveCheckpoint(checkpoint)
+		if err != nil {
+			return -1, fmt.Errorf("cannot remove checkpoint %d: %w", checkpoint, err)
+		}
+	}
+
+	// the WAL keeps all records ending before the offset of the corruption, and removes all later segments
+	err = w.wal.Repair(&prometheusWAL.CorruptionErr{
+		Dir:     w.wal.Dir(),
+		Segment: verification.Segment,
+		Offset:  verification.Offset,
+		Err:     verification.Err,
+	})
+	if err != nil {
+		return -1, fmt.Errorf("cannot truncate WAL: %w", err)
+	}
+
+	err = checkpointer.Checkpoint(verification.Segment, func() (io.WriteCloser, error) {
+		return checkpointer.CheckpointWriter(verification.Segment)
+	})
+	if err != nil {
+		return -1, fmt.Errorf("cannot create checkpoint %d: %w", verification.Segment, err)
+	}
+
+	return verification.Segment, nil
+}
@@ -0,0 +1,177 @@
+package wal
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// recordUpdates records count updates building on each other and returns the root hashes of the created tries
+func recordUpdates(t *testing.T, wal *LedgerWAL, dir string, count int) []ledger.RootHash {
+	f, err := mtrie.NewForest(4, dir, 100, &metrics.NoopCollector{}, func(tree *trie.MTrie) error { return nil })
+	require.NoError(t, err)
+
+	rootHash := f.GetEmptyRootHash()
+	rootHashes := make([]ledger.RootHash, 0, count)
+
+	for i := 0; i < count; i++ {
+		paths := utils.RandomPaths(2, 4)
+		payloads := utils.RandomPayloads(2, 4*1024, 8*1024)
+		update := &ledger.TrieUpdate{RootHash: rootHash, Paths: paths, Payloads: payloads}
+
+		err = wal.RecordUpdate(update)
+		require.NoError(t, err)
+
+		rootHash, err = f.Update(update)
+		require.NoError(t, err)
+		rootHashes = append(rootHashes, rootHash)
+	}
+
+	return rootHashes
+}
+
+func Test_Verify(t *testing.T) {
+
+	t.Run("corrupted segment is detected and repaired", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			wal, err := NewWAL(zerolog.Nop(), nil, dir, 100, 4, 32*1024)
+			require.NoError(t, err)
+
+			rootHashes := recordUpdates(t, wal, dir, 20)
+			require.NoError(t, wal.Close())
+
+			wal, err = NewWAL(zerolog.Nop(), nil, dir, 100, 4, 32*1024)
+			require.NoError(t, err)
+
+			verification, err := wal.Verify()
+			require.NoError(t, err)
+			require.NoError(t, verification.Err)
+			require.Equal(t, 20, verification.Records)
+			require.Equal(t, rootHashes[19], verification.RootHash)
+			require.Equal(t, 0, verification.RecomputedFrom)
+			require.NoError(t, wal.Close())
+
+			// corrupt a record in the middle of the WAL
+			corrupted := 3
+			segmentFile := path.Join(dir, NumberToFilenamePart(corrupted))
+			data, err := ioutil.ReadFile(segmentFile)
+			require.NoError(t, err)
+			require.NotEmpty(t, data)
+			data[len(data)/2]++
+			require.NoError(t, ioutil.WriteFile(segmentFile, data, 0644))
+
+			wal, err = NewWAL(zerolog.Nop(), nil, dir, 100, 4, 32*1024)
+			require.NoError(t, err)
+
+			verification, err = wal.Verify()
+			require.NoError(t, err)
+			require.Error(t, verification.Err)
+			require.Equal(t, corrupted, verification.Segment)
+			require.Less(t, verification.Records, 20)
+			require.Greater(t, verification.Offset, int64(0))
+
+			lastGood := verification.RootHash
+			if verification.Records > 0 {
+				require.Equal(t, rootHashes[verification.Records-1], lastGood)
+			}
+
+			checkpoint, err := wal.Repair(verification)
+			require.NoError(t, err)
+			require.Equal(t, corrupted, checkpoint)
+			require.NoError(t, wal.Close())
+
+			// the WAL is consistent again, and the new checkpoint contains the last good state
+			wal, err = NewWAL(zerolog.Nop(), nil, dir, 100, 4, 32*1024)
+			require.NoError(t, err)
+
+			verification, err = wal.Verify()
+			require.NoError(t, err)
+			require.NoError(t, verification.Err)
+			require.Equal(t, corrupted, verification.Checkpoint)
+
+			checkpointer, err := wal.NewCheckpointer()
+			require.NoError(t, err)
+			forestSequencing, err := checkpointer.LoadCheckpoint(corrupted)
+			require.NoError(t, err)
+
+			found := false
+			for _, storableTrie := range forestSequencing.Tries {
+				if lastGood.Equals(storableTrie.RootHash) {
+					found = true
+				}
+			}
+			require.True(t, found, "checkpoint does not contain the last consistent trie")
+
+			require.NoError(t, wal.Close())
+		})
+	})
+
+	t.Run("update of unknown trie is detected", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			wal, err := NewWAL(zerolog.Nop(), nil, dir, 100, 4, 32*1024)
+			require.NoError(t, err)
+
+			rootHashes := recordUpdates(t, wal, dir, 3)
+
+			update := &ledger.TrieUpdate{
+				RootHash: ledger.RootHash([]byte{1, 2, 3}),
+				Paths:    utils.RandomPaths(1, 4),
+				Payloads: utils.RandomPayloads(1, 10, 20),
+			}
+			err = wal.RecordUpdate(update)
+			require.NoError(t, err)
+
+			verification, err := wal.Verify()
+			require.NoError(t, err)
+			require.Error(t, verification.Err)
+			require.Equal(t, 3, verification.Records)
+			require.Equal(t, rootHashes[2], verification.RootHash)
+
+			require.NoError(t, wal.Close())
+		})
+	})
+
+	t.Run("corrupted checkpoints are detected", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			wal, err := NewWAL(zerolog.Nop(), nil, dir, 100, 4, 32*1024)
+			require.NoError(t, err)
+
+			checkpointer, err := wal.NewCheckpointer()
+			require.NoError(t, err)
+
+			forestSequencing := randomFlattenedForest(100)
+			for _, checkpoint := range []int{1, 2} {
+				writer, err := checkpointer.CheckpointWriter(checkpoint)
+				require.NoError(t, err)
+				require.NoError(t, StoreCheckpoint(forestSequencing, writer))
+				require.NoError(t, writer.Close())
+			}
+
+			checkpointFile := path.Join(dir, NumberToFilename(2))
+			data, err := ioutil.ReadFile(checkpointFile)
+			require.NoError(t, err)
+			data[len(data)/2]++
+			require.NoError(t, ioutil.WriteFile(checkpointFile, data, 0644))
+
+			verifications, err := checkpointer.VerifyCheckpoints()
+			require.NoError(t, err)
+			require.Len(t, verifications, 2)
+			require.Equal(t, 1, verifications[0].Checkpoint)
+			require.NoError(t, verifications[0].Err)
+			require.Equal(t, 2, verifications[1].Checkpoint)
+			require.Error(t, verifications[1].Err)
+
+			require.NoError(t, wal.Close())
+		})
+	})
+}
@@ -16,11 +16,14 @@ import (
 const SegmentSize = 32 * 1024 * 1024
 
 type LedgerWAL struct {
-	wal            *prometheusWAL.WAL
-	paused         bool
-	forestCapacity int
-	pathByteSize   int
-	log            zerolog.Logger
+	wal             *prometheusWAL.WAL
+	paused          bool
+	forestCapacity  int
+	pathByteSize    int
+	codec           Codec
+	checkpointParts bool
+	log             zerolog.Logger
+	readOnly        bool
 }
 
 // TODO use real logger and metrics, but that would require passing them to Trie storage
@@ -38,11 +41,45 @@ func NewWAL(logger zerolog.Logger, reg prometheus.Registerer, dir string, forest
 	}, nil
 }
 
+// OpenReadOnly opens an existing WAL for reading only, for example to restore tries offline.
+// Unlike NewWAL, it doesn't create a new segment, and no records are written.
+func OpenReadOnly(logger zerolog.Logger, dir string, forestCapacity int, pathByteSize int) (*LedgerWAL, error) {
+	w, err := prometheusWAL.Open(logger, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &LedgerWAL{
+		wal:            w,
+		paused:         true,
+		forestCapacity: forestCapacity,
+		pathByteSize:   pathByteSize,
+		log:            logger,
+		readOnly:       true,
+	}, nil
+}
+
+// SetCodec sets the codec used to compress update records and checkpoints written from now on.
+// Data written with any codec can always be read back, regardless of the codec set.
+// Compressed records and checkpoints can't be read by binaries without support for compression.
+func (w *LedgerWAL) SetCodec(codec Codec) {
+	w.codec = codec
+}
+
+// SetCheckpointParts enables or disables splitting checkpoints written from now on into parts, which are
+// loaded in parallel. Such checkpoints can't be read by binaries only supporting checkpoint versions up to 4,
+// hence enabling it is a one-way upgrade.
+func (w *LedgerWAL) SetCheckpointParts(enabled bool) {
+	w.checkpointParts = enabled
+}
+
 func (w *LedgerWAL) PauseRecord() {
 	w.paused = true
 }
 
 func (w *LedgerWAL) UnpauseRecord() {
+	if w.readOnly {
+		return
+	}
 	w.paused = false
 }
 
@@ -51,9 +88,12 @@ func (w *LedgerWAL) RecordUpdate(update *ledger.TrieUpdate) error {
 		return nil
 	}
 
-	bytes := EncodeUpdate(update)
+	bytes, err := CompressRecord(EncodeUpdate(update), w.codec)
+	if err != nil {
+		return fmt.Errorf("error while compressing update for LedgerWAL: %w", err)
+	}
 
-	_, err := w.wal.Log(bytes)
+	_, err = w.wal.Log(bytes)
 
 	if err != nil {
 		return fmt.Errorf("error while recording update in LedgerWAL: %w", err)
@@ -228,30 +268,35 @@ func (w *LedgerWAL) replay(
 
 	defer sr.Close()
 
-	for reader.Next() {
-		record := reader.Record()
-		operation, rootHash, update, err := Decode(record)
-		if err != nil {
-			return fmt.Errorf("cannot decode LedgerWAL record: %w", err)
+	// records are read and decoded concurrently with applying the previous ones
+	records := make(chan replayedRecord, replayBufferSize)
+	done := make(chan struct{})
+	go decodeRecords(reader, records, done)
+
+	defer func() {
+		// stop decoding and wait for it to finish before closing the segments reader
+		close(done)
+		for range records {
+		}
+	}()
+
+	for record := range records {
+		if record.err != nil {
+			return record.err
 		}
 
-		switch operation {
+		switch record.operation {
 		case WALUpdate:
-			err = updateFn(update)
+			err = updateFn(record.update)
 			if err != nil {
 				return fmt.Errorf("error while processing LedgerWAL update: %w", err)
 			}
 		case WALDelete:
-			err = deleteFn(rootHash)
+			err = deleteFn(record.rootHash)
 			if err != nil {
 				return fmt.Errorf("error while processing LedgerWAL deletion: %w", err)
 			}
 		}
-
-		err = reader.Err()
-		if err != nil {
-			return fmt.Errorf("cannot read LedgerWAL: %w", err)
-		}
 	}
 
 	w.log.Debug().Msgf("finished replaying WAL from %d to %d", from, to)
@@ -259,6 +304,53 @@ func (w *LedgerWAL) replay(
 	return nil
 }
 
+// replayBufferSize is the number of decoded records buffered ahead of applying them
+const replayBufferSize = 64
+
+// replayedRecord is a decoded LedgerWAL record, or an error which ends the replay
+type replayedRecord struct {
+	operation WALOperation
+	rootHash  ledger.RootHash
+	update    *ledger.TrieUpdate
+	err       error
+}
+
+// decodeRecords reads and decodes all records and sends them to the records channel,
+// which is closed when all records have been sent, an error occurred or done is closed.
+func decodeRecords(reader *prometheusWAL.Reader, records chan<- replayedRecord, done <-chan struct{}) {
+	defer close(records)
+
+	send := func(record replayedRecord) bool {
+		select {
+		case records <- record:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	for reader.Next() {
+		// the reader reuses the record buffer, while decoded data might still refer to it
+		data := make([]byte, len(reader.Record()))
+		copy(data, reader.Record())
+
+		operation, rootHash, update, err := Decode(data)
+		if err != nil {
+			send(replayedRecord{err: fmt.Errorf("cannot decode LedgerWAL record: %w", err)})
+			return
+		}
+
+		if !send(replayedRecord{operation: operation, rootHash: rootHash, update: update}) {
+			return
+		}
+	}
+
+	err := reader.Err()
+	if err != nil {
+		send(replayedRecord{err: fmt.Errorf("cannot read LedgerWAL: %w", err)})
+	}
+}
+
 func getPossibleCheckpoints(allCheckpoints []int, from, to int) []int {
 	// list of checkpoints is sorted
 	indexFrom := sort.SearchInts(allCheckpoints, from)
@@ -288,5 +380,8 @@ func (w *LedgerWAL) NewCheckpointer() (*Checkpointer, error) {
 }
 
 func (w *LedgerWAL) Close() error {
+	if w.readOnly {
+		return nil
+	}
 	return w.wal.Close()
 }
@@ -1,6 +1,7 @@
 package wal
 
 import (
+	"io/ioutil"
 	"testing"
 
 	"github.com/rs/zerolog"
@@ -37,6 +38,42 @@ func RunWithWALCheckpointerWithFiles(t *testing.T, names ...interface{}) {
 	})
 }
 
+func Test_OpenReadOnly(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		wal, err := NewWAL(zerolog.Nop(), nil, dir, 100, 4, 32*1024)
+		require.NoError(t, err)
+
+		rootHashes := recordUpdates(t, wal, dir, 10)
+		require.NoError(t, wal.Close())
+
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+
+		wal, err = OpenReadOnly(zerolog.Nop(), dir, 100, 4)
+		require.NoError(t, err)
+
+		restored, found, err := wal.RestoreTrie(rootHashes[4])
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, []byte(rootHashes[4]), restored.RootHash())
+
+		// records are not written
+		wal.UnpauseRecord()
+		err = wal.RecordDelete(rootHashes[0])
+		require.NoError(t, err)
+		require.NoError(t, wal.Close())
+
+		filesAfter, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, filesAfter, len(files))
+		for i, file := range files {
+			require.Equal(t, file.Name(), filesAfter[i].Name())
+			require.Equal(t, file.Size(), filesAfter[i].Size())
+			require.Equal(t, file.ModTime(), filesAfter[i].ModTime())
+		}
+	})
+}
+
 func Test_emptyDir(t *testing.T) {
 	RunWithWALCheckpointerWithFiles(t, func(t *testing.T, wal *LedgerWAL, checkpointer *Checkpointer) {
 		latestCheckpoint, err := checkpointer.LatestCheckpoint()
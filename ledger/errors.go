@@ -1,5 +1,7 @@
 package ledger
 
+import "fmt"
+
 // ErrLedgerConstruction is returned upon a failure in ledger creation steps
 type ErrLedgerConstruction struct {
 	Err error
@@ -40,6 +42,27 @@ func (e ErrMissingKeys) Is(other error) bool {
 	return ok
 }
 
+// ErrStatePruned is returned when a state is not available anymore, as its trie
+// has been evicted from memory and it is not retained by the retention policy
+type ErrStatePruned struct {
+	State State
+}
+
+func (e ErrStatePruned) Error() string {
+	return fmt.Sprintf("state %s has been pruned", e.State)
+}
+
+// Is returns true if the type of errors are the same
+func (e ErrStatePruned) Is(other error) bool {
+	_, ok := other.(ErrStatePruned)
+	return ok
+}
+
+// NewErrStatePruned constructs a new state pruned error
+func NewErrStatePruned(state State) *ErrStatePruned {
+	return &ErrStatePruned{State: state}
+}
+
 // TODO add more errors
 // ErrorFetchQuery
 // ErrorCommitChanges
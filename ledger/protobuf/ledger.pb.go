@@ -0,0 +1,647 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: ledger.proto
+
+package ledger
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type KeyPart struct {
+	Type                 uint32   `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyPart) Reset()         { *m = KeyPart{} }
+func (m *KeyPart) String() string { return proto.CompactTextString(m) }
+func (*KeyPart) ProtoMessage()    {}
+func (*KeyPart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{0}
+}
+
+func (m *KeyPart) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyPart.Unmarshal(m, b)
+}
+func (m *KeyPart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyPart.Marshal(b, m, deterministic)
+}
+func (m *KeyPart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyPart.Merge(m, src)
+}
+func (m *KeyPart) XXX_Size() int {
+	return xxx_messageInfo_KeyPart.Size(m)
+}
+func (m *KeyPart) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyPart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyPart proto.InternalMessageInfo
+
+func (m *KeyPart) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *KeyPart) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type Key struct {
+	Parts                []*KeyPart `protobuf:"bytes,1,rep,name=parts,proto3" json:"parts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Key) Reset()         { *m = Key{} }
+func (m *Key) String() string { return proto.CompactTextString(m) }
+func (*Key) ProtoMessage()    {}
+func (*Key) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{1}
+}
+
+func (m *Key) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Key.Unmarshal(m, b)
+}
+func (m *Key) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Key.Marshal(b, m, deterministic)
+}
+func (m *Key) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Key.Merge(m, src)
+}
+func (m *Key) XXX_Size() int {
+	return xxx_messageInfo_Key.Size(m)
+}
+func (m *Key) XXX_DiscardUnknown() {
+	xxx_messageInfo_Key.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Key proto.InternalMessageInfo
+
+func (m *Key) GetParts() []*KeyPart {
+	if m != nil {
+		return m.Parts
+	}
+	return nil
+}
+
+type InitialStateRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InitialStateRequest) Reset()         { *m = InitialStateRequest{} }
+func (m *InitialStateRequest) String() string { return proto.CompactTextString(m) }
+func (*InitialStateRequest) ProtoMessage()    {}
+func (*InitialStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{2}
+}
+
+func (m *InitialStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InitialStateRequest.Unmarshal(m, b)
+}
+func (m *InitialStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InitialStateRequest.Marshal(b, m, deterministic)
+}
+func (m *InitialStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InitialStateRequest.Merge(m, src)
+}
+func (m *InitialStateRequest) XXX_Size() int {
+	return xxx_messageInfo_InitialStateRequest.Size(m)
+}
+func (m *InitialStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InitialStateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InitialStateRequest proto.InternalMessageInfo
+
+type StateResponse struct {
+	State                []byte   `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateResponse) Reset()         { *m = StateResponse{} }
+func (m *StateResponse) String() string { return proto.CompactTextString(m) }
+func (*StateResponse) ProtoMessage()    {}
+func (*StateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{3}
+}
+
+func (m *StateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateResponse.Unmarshal(m, b)
+}
+func (m *StateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateResponse.Marshal(b, m, deterministic)
+}
+func (m *StateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateResponse.Merge(m, src)
+}
+func (m *StateResponse) XXX_Size() int {
+	return xxx_messageInfo_StateResponse.Size(m)
+}
+func (m *StateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateResponse proto.InternalMessageInfo
+
+func (m *StateResponse) GetState() []byte {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+type GetRequest struct {
+	State                []byte   `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Keys                 []*Key   `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRequest) Reset()         { *m = GetRequest{} }
+func (m *GetRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRequest) ProtoMessage()    {}
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{4}
+}
+
+func (m *GetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
+}
+func (m *GetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRequest.Merge(m, src)
+}
+func (m *GetRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRequest.Size(m)
+}
+func (m *GetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRequest proto.InternalMessageInfo
+
+func (m *GetRequest) GetState() []byte {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (m *GetRequest) GetKeys() []*Key {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type GetResponse struct {
+	// values are empty if nil, otherwise prefixed with a 0x01 byte
+	Values               [][]byte `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetResponse) Reset()         { *m = GetResponse{} }
+func (m *GetResponse) String() string { return proto.CompactTextString(m) }
+func (*GetResponse) ProtoMessage()    {}
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{5}
+}
+
+func (m *GetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
+}
+func (m *GetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetResponse.Marshal(b, m, deterministic)
+}
+func (m *GetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetResponse.Merge(m, src)
+}
+func (m *GetResponse) XXX_Size() int {
+	return xxx_messageInfo_GetResponse.Size(m)
+}
+func (m *GetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetResponse proto.InternalMessageInfo
+
+func (m *GetResponse) GetValues() [][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type SetRequest struct {
+	State []byte `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Keys  []*Key `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	// values are empty if nil, otherwise prefixed with a 0x01 byte
+	Values               [][]byte `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetRequest) Reset()         { *m = SetRequest{} }
+func (m *SetRequest) String() string { return proto.CompactTextString(m) }
+func (*SetRequest) ProtoMessage()    {}
+func (*SetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{6}
+}
+
+func (m *SetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRequest.Unmarshal(m, b)
+}
+func (m *SetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRequest.Marshal(b, m, deterministic)
+}
+func (m *SetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRequest.Merge(m, src)
+}
+func (m *SetRequest) XXX_Size() int {
+	return xxx_messageInfo_SetRequest.Size(m)
+}
+func (m *SetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRequest proto.InternalMessageInfo
+
+func (m *SetRequest) GetState() []byte {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (m *SetRequest) GetKeys() []*Key {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *SetRequest) GetValues() [][]byte {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type ProveRequest struct {
+	State                []byte   `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Keys                 []*Key   `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProveRequest) Reset()         { *m = ProveRequest{} }
+func (m *ProveRequest) String() string { return proto.CompactTextString(m) }
+func (*ProveRequest) ProtoMessage()    {}
+func (*ProveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{7}
+}
+
+func (m *ProveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProveRequest.Unmarshal(m, b)
+}
+func (m *ProveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProveRequest.Marshal(b, m, deterministic)
+}
+func (m *ProveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProveRequest.Merge(m, src)
+}
+func (m *ProveRequest) XXX_Size() int {
+	return xxx_messageInfo_ProveRequest.Size(m)
+}
+func (m *ProveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProveRequest proto.InternalMessageInfo
+
+func (m *ProveRequest) GetState() []byte {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+func (m *ProveRequest) GetKeys() []*Key {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type ProofResponse struct {
+	Proof                []byte   `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProofResponse) Reset()         { *m = ProofResponse{} }
+func (m *ProofResponse) String() string { return proto.CompactTextString(m) }
+func (*ProofResponse) ProtoMessage()    {}
+func (*ProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_63585974d4c6a2c4, []int{8}
+}
+
+func (m *ProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProofResponse.Unmarshal(m, b)
+}
+func (m *ProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProofResponse.Marshal(b, m, deterministic)
+}
+func (m *ProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProofResponse.Merge(m, src)
+}
+func (m *ProofResponse) XXX_Size() int {
+	return xxx_messageInfo_ProofResponse.Size(m)
+}
+func (m *ProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProofResponse proto.InternalMessageInfo
+
+func (m *ProofResponse) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*KeyPart)(nil), "ledger.KeyPart")
+	proto.RegisterType((*Key)(nil), "ledger.Key")
+	proto.RegisterType((*InitialStateRequest)(nil), "ledger.InitialStateRequest")
+	proto.RegisterType((*StateResponse)(nil), "ledger.StateResponse")
+	proto.RegisterType((*GetRequest)(nil), "ledger.GetRequest")
+	proto.RegisterType((*GetResponse)(nil), "ledger.GetResponse")
+	proto.RegisterType((*SetRequest)(nil), "ledger.SetRequest")
+	proto.RegisterType((*ProveRequest)(nil), "ledger.ProveRequest")
+	proto.RegisterType((*ProofResponse)(nil), "ledger.ProofResponse")
+}
+
+func init() { proto.RegisterFile("ledger.proto", fileDescriptor_63585974d4c6a2c4) }
+
+var fileDescriptor_63585974d4c6a2c4 = []byte{
+	// 355 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x52, 0x41, 0x6b, 0xf2, 0x40,
+	0x10, 0x25, 0x46, 0xfd, 0x60, 0x4c, 0xf8, 0x60, 0xd5, 0x8f, 0xe0, 0x77, 0xa8, 0x2c, 0x08, 0x1e,
+	0xda, 0x44, 0xb4, 0xb7, 0xde, 0x2c, 0x45, 0x8a, 0x3d, 0xc8, 0xe6, 0xd6, 0x9e, 0xa2, 0x1d, 0x6d,
+	0x68, 0xea, 0xa6, 0x9b, 0x8d, 0x25, 0xbf, 0xb9, 0x7f, 0xa2, 0xb8, 0xbb, 0xc1, 0x04, 0xec, 0xc9,
+	0x8b, 0xf8, 0xe6, 0xcd, 0xbc, 0x37, 0xfb, 0x26, 0xe0, 0x24, 0xf8, 0xba, 0x43, 0xe1, 0xa7, 0x82,
+	0x4b, 0x4e, 0xda, 0x1a, 0xd1, 0x19, 0xfc, 0x59, 0x62, 0xb1, 0x8a, 0x84, 0x24, 0x04, 0x9a, 0xb2,
+	0x48, 0xd1, 0xb3, 0x86, 0xd6, 0xd8, 0x65, 0xea, 0x3f, 0xe9, 0x41, 0xeb, 0x10, 0x25, 0x39, 0x7a,
+	0x8d, 0xa1, 0x35, 0x76, 0x98, 0x06, 0xf4, 0x1a, 0xec, 0x25, 0x16, 0x64, 0x04, 0xad, 0x34, 0x12,
+	0x32, 0xf3, 0xac, 0xa1, 0x3d, 0xee, 0x4c, 0xff, 0xfa, 0xc6, 0xc1, 0x08, 0x32, 0xcd, 0xd2, 0x3e,
+	0x74, 0x1f, 0xf7, 0xb1, 0x8c, 0xa3, 0x24, 0x94, 0x91, 0x44, 0x86, 0x9f, 0x39, 0x66, 0x92, 0x8e,
+	0xc0, 0x35, 0x38, 0x4b, 0xf9, 0x3e, 0x53, 0x5e, 0xd9, 0xb1, 0xa0, 0x16, 0x70, 0x98, 0x06, 0xf4,
+	0x1e, 0x60, 0x81, 0xd2, 0x0c, 0x9d, 0xef, 0x21, 0x57, 0xd0, 0x7c, 0xc7, 0x22, 0xf3, 0x1a, 0x6a,
+	0x8f, 0x4e, 0x65, 0x0f, 0xa6, 0x08, 0x3a, 0x82, 0x8e, 0x12, 0x31, 0x4e, 0xff, 0xa0, 0xad, 0x1e,
+	0xa2, 0x37, 0x77, 0x98, 0x41, 0xf4, 0x05, 0x20, 0xbc, 0xd4, 0xab, 0x22, 0x6e, 0xd7, 0xc4, 0x1f,
+	0xc0, 0x59, 0x09, 0x7e, 0xc0, 0x8b, 0x9f, 0xe2, 0xae, 0x04, 0xe7, 0xdb, 0x6a, 0x6c, 0xe9, 0xb1,
+	0x50, 0xea, 0x28, 0x30, 0xfd, 0xb6, 0xc0, 0x7d, 0x52, 0xb3, 0x21, 0x8a, 0x43, 0xbc, 0x41, 0x32,
+	0x07, 0xa7, 0x7a, 0x06, 0xf2, 0xbf, 0xd4, 0x3e, 0x73, 0x9c, 0x41, 0xbf, 0x24, 0xeb, 0x27, 0xf2,
+	0xc1, 0x5e, 0xa0, 0x24, 0xa4, 0x64, 0x4f, 0x97, 0x19, 0x74, 0x6b, 0x35, 0xd3, 0x3f, 0x01, 0x3b,
+	0xac, 0xf6, 0x9f, 0xd2, 0xfd, 0xcd, 0xe1, 0x16, 0x5a, 0x2a, 0x25, 0xd2, 0x2b, 0xf9, 0x6a, 0x68,
+	0xa7, 0xa9, 0x5a, 0x06, 0xf3, 0xe9, 0xf3, 0x64, 0x17, 0xcb, 0xb7, 0x7c, 0xed, 0x6f, 0xf8, 0x47,
+	0xc0, 0xf7, 0xdb, 0x84, 0x7f, 0x05, 0xc7, 0x9f, 0x9b, 0x1d, 0x0f, 0xf4, 0x44, 0xa0, 0xbe, 0xfb,
+	0x75, 0xbe, 0xbd, 0xd3, 0x78, 0xdd, 0x56, 0x85, 0xd9, 0xcf, 0x00, 0xef, 0xb7, 0x42, 0xac, 0x18,
+	0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// LedgerServiceClient is the client API for LedgerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LedgerServiceClient interface {
+	// InitialState returns the state of an empty ledger
+	InitialState(ctx context.Context, in *InitialStateRequest, opts ...grpc.CallOption) (*StateResponse, error)
+	// Get returns the values of the given keys at the given state
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	// Set updates the given keys with the given values on top of the given state
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*StateResponse, error)
+	// Prove returns a batch proof of the given keys at the given state
+	Prove(ctx context.Context, in *ProveRequest, opts ...grpc.CallOption) (*ProofResponse, error)
+}
+
+type ledgerServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLedgerServiceClient(cc *grpc.ClientConn) LedgerServiceClient {
+	return &ledgerServiceClient{cc}
+}
+
+func (c *ledgerServiceClient) InitialState(ctx context.Context, in *InitialStateRequest, opts ...grpc.CallOption) (*StateResponse, error) {
+	out := new(StateResponse)
+	err := c.cc.Invoke(ctx, "/ledger.LedgerService/InitialState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/ledger.LedgerService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerServiceClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*StateResponse, error) {
+	out := new(StateResponse)
+	err := c.cc.Invoke(ctx, "/ledger.LedgerService/Set", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerServiceClient) Prove(ctx context.Context, in *ProveRequest, opts ...grpc.CallOption) (*ProofResponse, error) {
+	out := new(ProofResponse)
+	err := c.cc.Invoke(ctx, "/ledger.LedgerService/Prove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LedgerServiceServer is the server API for LedgerService service.
+type LedgerServiceServer interface {
+	// InitialState returns the state of an empty ledger
+	InitialState(context.Context, *InitialStateRequest) (*StateResponse, error)
+	// Get returns the values of the given keys at the given state
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	// Set updates the given keys with the given values on top of the given state
+	Set(context.Context, *SetRequest) (*StateResponse, error)
+	// Prove returns a batch proof of the given keys at the given state
+	Prove(context.Context, *ProveRequest) (*ProofResponse, error)
+}
+
+// UnimplementedLedgerServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedLedgerServiceServer struct {
+}
+
+func (*UnimplementedLedgerServiceServer) InitialState(ctx context.Context, req *InitialStateRequest) (*StateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InitialState not implemented")
+}
+func (*UnimplementedLedgerServiceServer) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedLedgerServiceServer) Set(ctx context.Context, req *SetRequest) (*StateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (*UnimplementedLedgerServiceServer) Prove(ctx context.Context, req *ProveRequest) (*ProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Prove not implemented")
+}
+
+func RegisterLedgerServiceServer(s *grpc.Server, srv LedgerServiceServer) {
+	s.RegisterService(&_LedgerService_serviceDesc, srv)
+}
+
+func _LedgerService_InitialState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitialStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServiceServer).InitialState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.LedgerService/InitialState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServiceServer).InitialState(ctx, req.(*InitialStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LedgerService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.LedgerService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LedgerService_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServiceServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.LedgerService/Set",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServiceServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LedgerService_Prove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServiceServer).Prove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ledger.LedgerService/Prove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServiceServer).Prove(ctx, req.(*ProveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _LedgerService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ledger.LedgerService",
+	HandlerType: (*LedgerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "InitialState",
+			Handler:    _LedgerService_InitialState_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _LedgerService_Get_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _LedgerService_Set_Handler,
+		},
+		{
+			MethodName: "Prove",
+			Handler:    _LedgerService_Prove_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ledger.proto",
+}
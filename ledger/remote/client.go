@@ -0,0 +1,174 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/ledger"
+	ledgerpb "github.com/onflow/flow-go/ledger/protobuf"
+	grpcutils "github.com/onflow/flow-go/utils/grpc"
+)
+
+// DefaultTimeout is the default timeout of the calls to the ledger server.
+const DefaultTimeout = time.Minute
+
+// Client is a ledger.Ledger backed by a ledger served by a Server in another process.
+//
+// Calls wait for the connection to the server to become ready until they time out, so the
+// client survives short restarts of the server (and vice versa).
+type Client struct {
+	conn         *grpc.ClientConn
+	client       ledgerpb.LedgerServiceClient
+	timeout      time.Duration
+	initialState ledger.State
+}
+
+var _ ledger.Ledger = &Client{}
+
+// NewClient connects to the ledger server at the given address.
+// It blocks until the initial state of the ledger has been retrieved from the server, or the call timed out.
+func NewClient(addr string, maxMsgSize int, timeout time.Duration) (*Client, error) {
+	if maxMsgSize == 0 {
+		maxMsgSize = grpcutils.DefaultMaxMsgSize
+	}
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	conn, err := grpc.Dial(addr,
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(maxMsgSize),
+			grpc.MaxCallSendMsgSize(maxMsgSize),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to ledger server at %s: %w", addr, err)
+	}
+
+	c, err := newClient(conn, timeout)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func newClient(conn *grpc.ClientConn, timeout time.Duration) (*Client, error) {
+	client := ledgerpb.NewLedgerServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res, err := client.InitialState(ctx, &ledgerpb.InitialStateRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, fmt.Errorf("could not get initial state from ledger server: %w", err)
+	}
+
+	return &Client{
+		conn:         conn,
+		client:       client,
+		timeout:      timeout,
+		initialState: res.GetState(),
+	}, nil
+}
+
+// Ready implements interface module.ReadyDoneAware.
+// The client is ready once constructed.
+func (c *Client) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+// Done implements interface module.ReadyDoneAware.
+// It closes the connection to the server, the remote ledger keeps running.
+func (c *Client) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		_ = c.conn.Close()
+		close(done)
+	}()
+	return done
+}
+
+// InitialState returns the state of an empty ledger
+func (c *Client) InitialState() ledger.State {
+	return c.initialState
+}
+
+// Get reads the values of the given keys at the given state
+// it returns the values in the same order as the given keys
+func (c *Client) Get(query *ledger.Query) ([]ledger.Value, error) {
+	req := &ledgerpb.GetRequest{
+		State: query.State(),
+		Keys:  keysToMessages(query.Keys()),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	res, err := c.client.Get(ctx, req, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, fmt.Errorf("could not read values from ledger server: %w", callError(err, query.State()))
+	}
+
+	values, err := messagesToValues(res.GetValues())
+	if err != nil {
+		return nil, fmt.Errorf("could not decode values from ledger server: %w", err)
+	}
+
+	return values, nil
+}
+
+// Set updates the ledger given an update
+// it returns the state after the update
+func (c *Client) Set(update *ledger.Update) (ledger.State, error) {
+	req := &ledgerpb.SetRequest{
+		State:  update.State(),
+		Keys:   keysToMessages(update.Keys()),
+		Values: valuesToMessages(update.Values()),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	res, err := c.client.Set(ctx, req, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, fmt.Errorf("could not update values on ledger server: %w", callError(err, update.State()))
+	}
+
+	return res.GetState(), nil
+}
+
+// Prove provides proofs for the given keys at the given state
+func (c *Client) Prove(query *ledger.Query) (ledger.Proof, error) {
+	req := &ledgerpb.ProveRequest{
+		State: query.State(),
+		Keys:  keysToMessages(query.Keys()),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	res, err := c.client.Prove(ctx, req, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, fmt.Errorf("could not get proofs from ledger server: %w", callError(err, query.State()))
+	}
+
+	return res.GetProof(), nil
+}
+
+// callError converts the error of a call to the ledger server, returning a ledger.ErrStatePruned if the server
+// doesn't have the given state anymore.
+func callError(err error, state ledger.State) error {
+	if status.Code(err) == codes.NotFound {
+		return ledger.NewErrStatePruned(state)
+	}
+	return err
+}
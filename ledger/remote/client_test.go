@@ -0,0 +1,141 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete"
+	ledgerpb "github.com/onflow/flow-go/ledger/protobuf"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// withRemoteLedger runs f with a complete ledger and a client connected to it through an in-memory gRPC connection
+func withRemoteLedger(t *testing.T, f func(local *complete.Ledger, client *Client)) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		local, err := complete.NewLedger(dir, 100, &metrics.NoopCollector{}, zerolog.Nop(), nil, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		listener := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer()
+		ledgerpb.RegisterLedgerServiceServer(server, NewService(local))
+		go func() {
+			_ = server.Serve(listener)
+		}()
+
+		conn, err := grpc.DialContext(context.Background(), "bufnet",
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+				return listener.Dial()
+			}),
+			grpc.WithInsecure(),
+		)
+		require.NoError(t, err)
+
+		client, err := newClient(conn, DefaultTimeout)
+		require.NoError(t, err)
+
+		f(local, client)
+
+		<-client.Done()
+		server.Stop()
+		<-local.Done()
+	})
+}
+
+func TestClient(t *testing.T) {
+	withRemoteLedger(t, func(local *complete.Ledger, client *Client) {
+
+		require.Equal(t, local.InitialState(), client.InitialState())
+
+		keys := utils.RandomUniqueKeys(10, 2, 1, 10)
+		values := utils.RandomValues(10, 1, 32)
+		// empty values are transferred as well
+		values[0] = ledger.Value{}
+
+		update, err := ledger.NewUpdate(client.InitialState(), keys, values)
+		require.NoError(t, err)
+
+		state, err := client.Set(update)
+		require.NoError(t, err)
+
+		localState, err := local.Set(update)
+		require.NoError(t, err)
+		require.Equal(t, localState, state)
+
+		query, err := ledger.NewQuery(state, keys)
+		require.NoError(t, err)
+
+		retValues, err := client.Get(query)
+		require.NoError(t, err)
+		require.Len(t, retValues, len(values))
+		for i, value := range values {
+			require.True(t, value.Equals(retValues[i]))
+		}
+
+		proof, err := client.Prove(query)
+		require.NoError(t, err)
+		localProof, err := local.Prove(query)
+		require.NoError(t, err)
+		require.True(t, localProof.Equals(proof))
+
+		t.Run("unknown state", func(t *testing.T) {
+			query, err := ledger.NewQuery(ledger.State([]byte{1, 2, 3}), keys)
+			require.NoError(t, err)
+
+			_, err = client.Get(query)
+			require.True(t, errors.Is(err, ledger.ErrStatePruned{}))
+
+			_, err = client.Prove(query)
+			require.True(t, errors.Is(err, ledger.ErrStatePruned{}))
+		})
+	})
+}
+
+func TestValuesEncoding(t *testing.T) {
+	values := []ledger.Value{nil, {}, {0}, {1, 2, 3}}
+
+	decoded, err := messagesToValues(valuesToMessages(values))
+	require.NoError(t, err)
+	require.Len(t, decoded, len(values))
+
+	// nil and empty values are kept apart
+	require.Nil(t, decoded[0])
+	require.NotNil(t, decoded[1])
+	require.Empty(t, decoded[1])
+	for i := 2; i < len(values); i++ {
+		require.Equal(t, values[i], decoded[i])
+	}
+
+	_, err = messagesToValues([][]byte{{2, 1}})
+	require.Error(t, err)
+}
+
+func TestClientTimeout(t *testing.T) {
+	// the server is unreachable, so calls wait for the connection to become ready until they time out
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return nil, fmt.Errorf("unreachable")
+		}),
+		grpc.WithInsecure(),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	start := time.Now()
+	_, err = newClient(conn, 100*time.Millisecond)
+	require.Equal(t, codes.DeadlineExceeded, status.Code(errors.Unwrap(err)))
+	require.Less(t, int64(time.Since(start)), int64(10*time.Second))
+}
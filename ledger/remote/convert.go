@@ -0,0 +1,70 @@
+package remote
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/onflow/flow-go/ledger"
+	ledgerpb "github.com/onflow/flow-go/ledger/protobuf"
+)
+
+func keysToMessages(keys []ledger.Key) []*ledgerpb.Key {
+	messages := make([]*ledgerpb.Key, len(keys))
+	for i, key := range keys {
+		parts := make([]*ledgerpb.KeyPart, len(key.KeyParts))
+		for j, part := range key.KeyParts {
+			parts[j] = &ledgerpb.KeyPart{
+				Type:  uint32(part.Type),
+				Value: part.Value,
+			}
+		}
+		messages[i] = &ledgerpb.Key{Parts: parts}
+	}
+	return messages
+}
+
+func messagesToKeys(messages []*ledgerpb.Key) ([]ledger.Key, error) {
+	keys := make([]ledger.Key, len(messages))
+	for i, message := range messages {
+		parts := make([]ledger.KeyPart, len(message.GetParts()))
+		for j, part := range message.GetParts() {
+			if part.GetType() > math.MaxUint16 {
+				return nil, fmt.Errorf("invalid type %d of key part %d of key %d", part.GetType(), j, i)
+			}
+			parts[j] = ledger.NewKeyPart(uint16(part.GetType()), part.GetValue())
+		}
+		keys[i] = ledger.NewKey(parts)
+	}
+	return keys, nil
+}
+
+// valuePresent prefixes the encoding of non-nil values. Protobuf doesn't distinguish nil from empty bytes, so a nil
+// value is encoded as empty bytes and any other value, including an empty one, as valuePresent followed by the value.
+const valuePresent byte = 1
+
+func valuesToMessages(values []ledger.Value) [][]byte {
+	messages := make([][]byte, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		message := make([]byte, 0, 1+len(value))
+		message = append(message, valuePresent)
+		messages[i] = append(message, value...)
+	}
+	return messages
+}
+
+func messagesToValues(messages [][]byte) ([]ledger.Value, error) {
+	values := make([]ledger.Value, len(messages))
+	for i, message := range messages {
+		if len(message) == 0 {
+			continue
+		}
+		if message[0] != valuePresent {
+			return nil, fmt.Errorf("invalid encoding of value %d", i)
+		}
+		values[i] = message[1:]
+	}
+	return values, nil
+}
@@ -0,0 +1,80 @@
+package remote
+
+import (
+	"net"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/ledger"
+	ledgerpb "github.com/onflow/flow-go/ledger/protobuf"
+	grpcutils "github.com/onflow/flow-go/utils/grpc"
+)
+
+// Config defines the configurable options for the ledger gRPC server.
+type Config struct {
+	ListenAddr string
+	MaxMsgSize int // In bytes
+}
+
+// Server serves a ledger over gRPC, so it can be used from other processes
+// through a Client.
+type Server struct {
+	unit   *engine.Unit
+	log    zerolog.Logger
+	server *grpc.Server // the gRPC server
+	config Config
+}
+
+// NewServer returns a new ledger gRPC server for the given ledger.
+// The ledger's lifecycle is not managed by the server.
+func NewServer(log zerolog.Logger, config Config, ledger ledger.Ledger) *Server {
+	log = log.With().Str("component", "ledger_server").Logger()
+
+	if config.MaxMsgSize == 0 {
+		config.MaxMsgSize = grpcutils.DefaultMaxMsgSize
+	}
+
+	s := &Server{
+		unit: engine.NewUnit(),
+		log:  log,
+		server: grpc.NewServer(
+			grpc.MaxRecvMsgSize(config.MaxMsgSize),
+			grpc.MaxSendMsgSize(config.MaxMsgSize),
+		),
+		config: config,
+	}
+
+	ledgerpb.RegisterLedgerServiceServer(s.server, NewService(ledger))
+
+	return s
+}
+
+// Ready returns a ready channel that is closed once the server has started.
+func (s *Server) Ready() <-chan struct{} {
+	s.unit.Launch(s.serve)
+	return s.unit.Ready()
+}
+
+// Done returns a done channel that is closed once the server has fully stopped.
+// It stops the gRPC server gracefully, waiting for pending requests to finish.
+func (s *Server) Done() <-chan struct{} {
+	return s.unit.Done(s.server.GracefulStop)
+}
+
+// serve starts the gRPC server.
+func (s *Server) serve() {
+	s.log.Info().Msgf("starting server on address %s", s.config.ListenAddr)
+
+	l, err := net.Listen("tcp", s.config.ListenAddr)
+	if err != nil {
+		s.log.Err(err).Msg("failed to start server")
+		return
+	}
+
+	err = s.server.Serve(l)
+	if err != nil {
+		s.log.Err(err).Msg("fatal error in server")
+	}
+}
@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/ledger"
+	ledgerpb "github.com/onflow/flow-go/ledger/protobuf"
+)
+
+// Service implements the gRPC ledger service on top of a ledger.Ledger
+type Service struct {
+	ledger ledger.Ledger
+}
+
+var _ ledgerpb.LedgerServiceServer = &Service{}
+
+// NewService returns a new gRPC ledger service serving the given ledger
+func NewService(ledger ledger.Ledger) *Service {
+	return &Service{
+		ledger: ledger,
+	}
+}
+
+// InitialState returns the state of an empty ledger
+func (s *Service) InitialState(_ context.Context, _ *ledgerpb.InitialStateRequest) (*ledgerpb.StateResponse, error) {
+	return &ledgerpb.StateResponse{
+		State: s.ledger.InitialState(),
+	}, nil
+}
+
+// Get returns the values of the given keys at the given state
+func (s *Service) Get(_ context.Context, req *ledgerpb.GetRequest) (*ledgerpb.GetResponse, error) {
+	keys, err := messagesToKeys(req.GetKeys())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query, err := ledger.NewQuery(req.GetState(), keys)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	values, err := s.ledger.Get(query)
+	if err != nil {
+		return nil, ledgerError(err, "failed to read values")
+	}
+
+	return &ledgerpb.GetResponse{
+		Values: valuesToMessages(values),
+	}, nil
+}
+
+// Set updates the given keys with the given values on top of the given state
+func (s *Service) Set(_ context.Context, req *ledgerpb.SetRequest) (*ledgerpb.StateResponse, error) {
+	keys, err := messagesToKeys(req.GetKeys())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	values, err := messagesToValues(req.GetValues())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	update, err := ledger.NewUpdate(req.GetState(), keys, values)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	newState, err := s.ledger.Set(update)
+	if err != nil {
+		return nil, ledgerError(err, "failed to update values")
+	}
+
+	return &ledgerpb.StateResponse{
+		State: newState,
+	}, nil
+}
+
+// Prove returns a batch proof of the given keys at the given state
+func (s *Service) Prove(_ context.Context, req *ledgerpb.ProveRequest) (*ledgerpb.ProofResponse, error) {
+	keys, err := messagesToKeys(req.GetKeys())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query, err := ledger.NewQuery(req.GetState(), keys)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	proof, err := s.ledger.Prove(query)
+	if err != nil {
+		return nil, ledgerError(err, "failed to generate proofs")
+	}
+
+	return &ledgerpb.ProofResponse{
+		Proof: proof,
+	}, nil
+}
+
+// ledgerError converts an error of the ledger to a gRPC status error. Pruned states are reported with codes.NotFound,
+// so that clients can return a ledger.ErrStatePruned as well.
+func ledgerError(err error, msg string) error {
+	if errors.Is(err, ledger.ErrStatePruned{}) {
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
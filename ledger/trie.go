@@ -177,6 +177,45 @@ func EmptyPayload() *Payload {
 	return &Payload{}
 }
 
+// PayloadDiffType is the kind of change of a register between two tries
+type PayloadDiffType uint8
+
+const (
+	// PayloadAdded marks a register which only exists in the later trie
+	PayloadAdded PayloadDiffType = iota
+	// PayloadRemoved marks a register which only exists in the earlier trie
+	PayloadRemoved
+	// PayloadModified marks a register which exists in both tries with different payloads
+	PayloadModified
+)
+
+func (t PayloadDiffType) String() string {
+	switch t {
+	case PayloadAdded:
+		return "added"
+	case PayloadRemoved:
+		return "removed"
+	case PayloadModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// PayloadDiff captures the change of a single register between two tries.
+// A register with an empty value is considered to not exist, hence
+// Before is nil for added registers and After is nil for removed registers.
+type PayloadDiff struct {
+	Type   PayloadDiffType
+	Path   Path
+	Before *Payload
+	After  *Payload
+}
+
+func (d *PayloadDiff) String() string {
+	return fmt.Sprintf("%s %s", d.Type, d.Path)
+}
+
 // TrieProof includes all the information needed to walk
 // through a trie branch from an specific leaf node (key)
 // up to the root of the trie.
@@ -132,30 +132,50 @@ func (l *linearCodeImpl) chain() ChainID {
 	return l.chainID
 }
 
+// A TransactionFeeSchedule defines how the fees of transactions are computed on a chain.
+//
+// If Fixed is set, transactions are charged the fixed transaction fee set in the service account.
+// Otherwise, transactions are charged the given fees (in units of 1e-8 FLOW) per unit of inclusion effort,
+// per unit of computation used and per byte of storage added.
+type TransactionFeeSchedule struct {
+	Fixed              bool
+	InclusionEffortFee uint64
+	ComputationFee     uint64
+	StorageFee         uint64
+}
+
+// FixedTransactionFeeSchedule charges transactions the fixed transaction fee set in the service account.
+var FixedTransactionFeeSchedule = TransactionFeeSchedule{Fixed: true}
+
 type addressedChain struct {
 	chainImpl
+	feeSchedule TransactionFeeSchedule
 }
 
 var mainnet = &addressedChain{
 	chainImpl: &linearCodeImpl{
 		chainID: Mainnet,
 	},
+	feeSchedule: FixedTransactionFeeSchedule,
 }
 
 var testnet = &addressedChain{
 	chainImpl: &linearCodeImpl{
 		chainID: Testnet,
 	},
+	feeSchedule: FixedTransactionFeeSchedule,
 }
 
 var emulator = &addressedChain{
 	chainImpl: &linearCodeImpl{
 		chainID: Emulator,
 	},
+	feeSchedule: FixedTransactionFeeSchedule,
 }
 
 var monotonicEmulator = &addressedChain{
-	chainImpl: &monotonicImpl{},
+	chainImpl:   &monotonicImpl{},
+	feeSchedule: FixedTransactionFeeSchedule,
 }
 
 // Chain returns the Chain corresponding to the string input
@@ -186,6 +206,7 @@ type Chain interface {
 	BytesToAddressGenerator(b []byte) AddressGenerator
 	IsValid(Address) bool
 	IndexFromAddress(address Address) (uint64, error)
+	TransactionFeeSchedule() TransactionFeeSchedule
 	String() string
 	// required for tests
 	zeroAddress() Address
@@ -213,6 +234,11 @@ func (id *addressedChain) ServiceAddress() Address {
 	return address
 }
 
+// TransactionFeeSchedule returns the schedule the fees of transactions are computed with on the chain.
+func (id *addressedChain) TransactionFeeSchedule() TransactionFeeSchedule {
+	return id.feeSchedule
+}
+
 // zeroAddress returns the "zero address" (account that no one owns).
 func (id *addressedChain) zeroAddress() Address {
 	// returned error is guaranteed to be nil
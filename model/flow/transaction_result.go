@@ -11,6 +11,9 @@ type TransactionResult struct {
 	TransactionID Identifier
 	// ErrorMessage contains the error message of any error that may have occurred when the transaction was executed
 	ErrorMessage string
+	// ComputationUsed is the computation used by the transaction, including the computation
+	// charged for host environment operations
+	ComputationUsed uint64
 }
 
 // String returns the string representation of this error.
@@ -54,3 +54,29 @@ func (b *ExecutionStateDelta) Height() uint64 {
 func (b *ExecutionStateDelta) ParentID() flow.Identifier {
 	return b.Block.Header.ParentID
 }
+
+// ExecutionRegisterDelta contains the registers updated by an executed block, which execution nodes push to the
+// access nodes indexing the registers of the execution state.
+type ExecutionRegisterDelta struct {
+	BlockID   flow.Identifier
+	Height    uint64
+	EndState  flow.StateCommitment
+	Registers flow.RegisterEntries
+}
+
+// ID returns the identifier of the register delta, which is the hash of its content, so the register deltas sent by
+// execution nodes are only the same if they agree on the registers updated by the block.
+func (d *ExecutionRegisterDelta) ID() flow.Identifier {
+	return flow.MakeID(d)
+}
+
+func (d *ExecutionRegisterDelta) Checksum() flow.Identifier {
+	return flow.MakeID(d)
+}
+
+// ExecutionRegisterDeltaRequest represents a request for the register delta of an executed block, sent by access
+// nodes which lost the register delta pushed by the execution nodes.
+type ExecutionRegisterDeltaRequest struct {
+	BlockID flow.Identifier
+	Nonce   uint64 // so that we aren't deduplicated by the network layer
+}
@@ -0,0 +1,33 @@
+package mempool
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+)
+
+// RegisterDeltas represents a concurrency-safe memory pool for the register deltas of executed blocks. A block can
+// have several register deltas, one per content the execution nodes sent.
+type RegisterDeltas interface {
+
+	// Add will add the given register delta to the memory pool. It will return
+	// false if it was already in the mempool.
+	Add(delta *messages.ExecutionRegisterDelta) bool
+
+	// Rem will remove the register delta with the given ID from the memory
+	// pool; it will return true if the register delta was known and removed.
+	Rem(deltaID flow.Identifier) bool
+
+	// ByID retrieve the register delta with the given ID from the memory
+	// pool. It will return false if it was not found in the mempool.
+	ByID(deltaID flow.Identifier) (*messages.ExecutionRegisterDelta, bool)
+
+	// Size will return the current size of the memory pool.
+	Size() uint
+
+	// All will retrieve all register deltas that are currently in the memory
+	// pool as a slice.
+	All() []*messages.ExecutionRegisterDelta
+
+	// RegisterEjectionCallbacks adds the provided OnEjection callbacks
+	RegisterEjectionCallbacks(callbacks ...OnEjection)
+}
@@ -0,0 +1,51 @@
+package stdmap
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+)
+
+// RegisterDeltas implements the register deltas memory pool of the access
+// node, used to buffer the register deltas received from execution nodes
+// until their blocks are finalized and their results are trusted.
+type RegisterDeltas struct {
+	*Backend
+}
+
+// NewRegisterDeltas creates a new memory pool for register deltas.
+func NewRegisterDeltas(limit uint) (*RegisterDeltas, error) {
+	d := &RegisterDeltas{
+		Backend: NewBackend(WithLimit(limit)),
+	}
+	return d, nil
+}
+
+// Add adds a register delta to the mempool, keyed by the hash of its content.
+func (d *RegisterDeltas) Add(delta *messages.ExecutionRegisterDelta) bool {
+	return d.Backend.Add(delta)
+}
+
+// Rem will remove the register delta with the given ID.
+func (d *RegisterDeltas) Rem(deltaID flow.Identifier) bool {
+	return d.Backend.Rem(deltaID)
+}
+
+// ByID returns the register delta with the given ID.
+func (d *RegisterDeltas) ByID(deltaID flow.Identifier) (*messages.ExecutionRegisterDelta, bool) {
+	entity, exists := d.Backend.ByID(deltaID)
+	if !exists {
+		return nil, false
+	}
+	delta := entity.(*messages.ExecutionRegisterDelta)
+	return delta, true
+}
+
+// All will return all register deltas in the memory pool.
+func (d *RegisterDeltas) All() []*messages.ExecutionRegisterDelta {
+	entities := d.Backend.All()
+	deltas := make([]*messages.ExecutionRegisterDelta, 0, len(entities))
+	for _, entity := range entities {
+		deltas = append(deltas, entity.(*messages.ExecutionRegisterDelta))
+	}
+	return deltas
+}
@@ -286,9 +286,24 @@ type ExecutionMetrics interface {
 	// ExecutionGasUsedPerBlock reports gas used per block
 	ExecutionGasUsedPerBlock(gas uint64)
 
+	// ExecutionComputationUsedPerTransaction reports computation used per transaction,
+	// including the computation charged for host environment operations
+	ExecutionComputationUsedPerTransaction(computation uint64)
+
 	// ExecutionStateReadsPerBlock reports number of state access/read operations per block
 	ExecutionStateReadsPerBlock(reads uint64)
 
+	// ExecutionContractParsed reports the time spent parsing a contract imported at the top level of a transaction
+	ExecutionContractParsed(contract string, dur time.Duration)
+
+	// ExecutionContractChecked reports the time spent checking a contract imported at the top level of a transaction,
+	// including checking the contracts it imports
+	ExecutionContractChecked(contract string, dur time.Duration)
+
+	// ExecutionTransactionExecuted reports the execution time of a transaction and the contracts it imports
+	// at the top level, for the report of the slowest transactions
+	ExecutionTransactionExecuted(txID flow.Identifier, contracts []string, dur time.Duration)
+
 	// ExecutionStorageStateCommitment reports the storage size of a state commitment in bytes
 	ExecutionStorageStateCommitment(bytes int64)
 
@@ -327,6 +342,18 @@ type TransactionMetrics interface {
 	TransactionSubmissionFailed()
 }
 
+type RegisterIndexMetrics interface {
+	// RegisterDeltaEjected is called when a register delta is ejected from the memory pool before being indexed
+	RegisterDeltaEjected()
+
+	// RegisterDeltaRequested is called when a register delta missing for the next height to index is requested
+	// from the execution nodes
+	RegisterDeltaRequested()
+
+	// RegistersIndexed reports the latest height whose registers were indexed
+	RegistersIndexed(height uint64)
+}
+
 type PingMetrics interface {
 	// NodeReachable tracks the node availability of the node and reports it as 1 if the node was successfully pinged, 0
 	// otherwise. The nodeInfo provides additional information about the node such as the name of the node operator
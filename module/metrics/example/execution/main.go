@@ -26,9 +26,14 @@ func main() {
 			*metrics.ExecutionCollector
 			*metrics.NetworkCollector
 		}{
-			HotstuffCollector:  metrics.NewHotstuffCollector("some_chain_id"),
-			ExecutionCollector: metrics.NewExecutionCollector(tracer, prometheus.DefaultRegisterer),
-			NetworkCollector:   metrics.NewNetworkCollector(),
+			HotstuffCollector: metrics.NewHotstuffCollector("some_chain_id"),
+			ExecutionCollector: metrics.NewExecutionCollector(
+				tracer,
+				prometheus.DefaultRegisterer,
+				metrics.NewSlowestTransactions(metrics.DefaultSlowestTransactionsSize, metrics.DefaultSlowestTransactionsWindow),
+				nil,
+			),
+			NetworkCollector: metrics.NewNetworkCollector(),
 		}
 		diskTotal := rand.Int63n(1024 ^ 3)
 		for i := 0; i < 1000; i++ {
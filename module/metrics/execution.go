@@ -20,6 +20,7 @@ const (
 type ExecutionCollector struct {
 	tracer                           *trace.OpenTracer
 	gasUsedPerBlock                  prometheus.Histogram
+	computationUsedPerTransaction    prometheus.Histogram
 	stateReadsPerBlock               prometheus.Histogram
 	totalExecutedTransactionsCounter prometheus.Counter
 	lastExecutedBlockHeightGauge     prometheus.Gauge
@@ -46,12 +47,29 @@ type ExecutionCollector struct {
 	transactionParseTime             prometheus.Histogram
 	transactionCheckTime             prometheus.Histogram
 	transactionInterpretTime         prometheus.Histogram
+	contractParseTime                *prometheus.HistogramVec
+	contractCheckTime                *prometheus.HistogramVec
+	slowestTransactions              *SlowestTransactions
+	contractLabels                   map[string]struct{}
 	totalChunkDataPackRequests       prometheus.Counter
 	stateSyncActive                  prometheus.Gauge
 	executionStateDiskUsage          prometheus.Gauge
 }
 
-func NewExecutionCollector(tracer *trace.OpenTracer, registerer prometheus.Registerer) *ExecutionCollector {
+// NewExecutionCollector creates a collector of execution metrics, recording the executed transactions
+// to the given slowest transactions report. The runtime metrics of contracts are labelled with their location
+// only for the given contracts, e.g. the core contracts, and with "other" for all other contracts.
+func NewExecutionCollector(
+	tracer *trace.OpenTracer,
+	registerer prometheus.Registerer,
+	slowestTransactions *SlowestTransactions,
+	contracts []string,
+) *ExecutionCollector {
+
+	contractLabels := make(map[string]struct{}, len(contracts))
+	for _, contract := range contracts {
+		contractLabels[contract] = struct{}{}
+	}
 
 	forestApproxMemorySize := prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: namespaceExecution,
@@ -204,6 +222,20 @@ func NewExecutionCollector(tracer *trace.OpenTracer, registerer prometheus.Regis
 		Help:      "the interpretation time for a transaction in nanoseconds",
 	})
 
+	contractParseTime := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemRuntime,
+		Name:      "contract_parse_time_nanoseconds",
+		Help:      "the parse time for a contract imported at the top level of a transaction in nanoseconds",
+	}, []string{LabelContract})
+
+	contractCheckTime := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemRuntime,
+		Name:      "contract_check_time_nanoseconds",
+		Help:      "the checking time for a contract imported at the top level of a transaction, including its imports, in nanoseconds",
+	}, []string{LabelContract})
+
 	totalChunkDataPackRequests := prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: namespaceExecution,
 		Subsystem: subsystemProvider,
@@ -232,6 +264,8 @@ func NewExecutionCollector(tracer *trace.OpenTracer, registerer prometheus.Regis
 	registerer.MustRegister(transactionParseTime)
 	registerer.MustRegister(transactionCheckTime)
 	registerer.MustRegister(transactionInterpretTime)
+	registerer.MustRegister(contractParseTime)
+	registerer.MustRegister(contractCheckTime)
 	registerer.MustRegister(totalChunkDataPackRequests)
 
 	ec := &ExecutionCollector{
@@ -258,6 +292,10 @@ func NewExecutionCollector(tracer *trace.OpenTracer, registerer prometheus.Regis
 		transactionParseTime:       transactionParseTime,
 		transactionCheckTime:       transactionCheckTime,
 		transactionInterpretTime:   transactionInterpretTime,
+		contractParseTime:          contractParseTime,
+		contractCheckTime:          contractCheckTime,
+		slowestTransactions:        slowestTransactions,
+		contractLabels:             contractLabels,
 		totalChunkDataPackRequests: totalChunkDataPackRequests,
 
 		gasUsedPerBlock: promauto.NewHistogram(prometheus.HistogramOpts{
@@ -268,6 +306,14 @@ func NewExecutionCollector(tracer *trace.OpenTracer, registerer prometheus.Regis
 			Help:      "the gas used per block",
 		}),
 
+		computationUsedPerTransaction: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceExecution,
+			Subsystem: subsystemRuntime,
+			Buckets:   prometheus.ExponentialBuckets(10, 10, 6),
+			Name:      "computation_used_per_transaction",
+			Help:      "the computation used per transaction, including the computation charged for host environment operations",
+		}),
+
 		stateReadsPerBlock: promauto.NewHistogram(prometheus.HistogramOpts{
 			Namespace: namespaceExecution,
 			Subsystem: subsystemRuntime,
@@ -339,6 +385,12 @@ func (ec *ExecutionCollector) ExecutionGasUsedPerBlock(gas uint64) {
 	ec.gasUsedPerBlock.Observe(float64(gas))
 }
 
+// ExecutionComputationUsedPerTransaction reports computation used per transaction,
+// including the computation charged for host environment operations
+func (ec *ExecutionCollector) ExecutionComputationUsedPerTransaction(computation uint64) {
+	ec.computationUsedPerTransaction.Observe(float64(computation))
+}
+
 // ExecutionStateReadsPerBlock reports number of state access/read operations per block
 func (ec *ExecutionCollector) ExecutionStateReadsPerBlock(reads uint64) {
 	ec.stateReadsPerBlock.Observe(float64(reads))
@@ -467,6 +519,44 @@ func (ec *ExecutionCollector) TransactionInterpreted(dur time.Duration) {
 	ec.transactionInterpretTime.Observe(float64(dur))
 }
 
+// ExecutionContractParsed reports the time spent parsing a contract imported at the top level of a transaction.
+// Nothing is observed if the contract was not parsed, e.g. as its program was cached.
+func (ec *ExecutionCollector) ExecutionContractParsed(contract string, dur time.Duration) {
+	if dur == 0 {
+		return
+	}
+	ec.contractParseTime.WithLabelValues(ec.contractLabel(contract)).Observe(float64(dur))
+}
+
+// ExecutionContractChecked reports the time spent checking a contract imported at the top level of a transaction,
+// including checking the contracts it imports. Nothing is observed if the contract was not checked.
+func (ec *ExecutionCollector) ExecutionContractChecked(contract string, dur time.Duration) {
+	if dur == 0 {
+		return
+	}
+	ec.contractCheckTime.WithLabelValues(ec.contractLabel(contract)).Observe(float64(dur))
+}
+
+// contractLabel returns the label of a contract, which is its location if it is one of the labelled contracts,
+// so that the number of label values is bounded.
+func (ec *ExecutionCollector) contractLabel(contract string) string {
+	if _, ok := ec.contractLabels[contract]; ok {
+		return contract
+	}
+	return LabelContractOther
+}
+
+// ExecutionTransactionExecuted reports the execution time of a transaction and the contracts it imports
+// at the top level, for the report of the slowest transactions
+func (ec *ExecutionCollector) ExecutionTransactionExecuted(txID flow.Identifier, contracts []string, dur time.Duration) {
+	ec.slowestTransactions.Add(SlowTransaction{
+		TransactionID: txID,
+		Contracts:     contracts,
+		Duration:      dur,
+		ExecutedAt:    time.Now(),
+	})
+}
+
 // ChunkDataPackRequested is executed every time a chunk data pack request is arrived at execution node.
 // It increases the request counter by one.
 func (ec *ExecutionCollector) ChunkDataPackRequested() {
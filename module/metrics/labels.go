@@ -10,12 +10,18 @@ const (
 	LabelNodeRole = "noderole"
 	LabelNodeInfo = "nodeinfo"
 	LabelPriority = "priority"
+	LabelContract = "contract"
 )
 
 const (
 	ChannelOneToOne = "OneToOne"
 )
 
+const (
+	// LabelContractOther is the label value of the contracts that are not labelled with their location
+	LabelContractOther = "other"
+)
+
 const (
 	// collection
 	EngineProposal               = "proposal"
@@ -31,6 +31,7 @@ const (
 const (
 	subsystemTransactionTiming     = "transaction_timing"
 	subsystemTransactionSubmission = "transaction_submission"
+	subsystemRegisterIndex         = "register_index"
 )
 
 // Collection subsystem
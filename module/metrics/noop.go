@@ -82,7 +82,11 @@ func (nc *NoopCollector) LogVerifiableChunkSize(size float64)
 func (nc *NoopCollector) StartBlockReceivedToExecuted(blockID flow.Identifier)                   {}
 func (nc *NoopCollector) FinishBlockReceivedToExecuted(blockID flow.Identifier)                  {}
 func (nc *NoopCollector) ExecutionGasUsedPerBlock(gas uint64)                                    {}
+func (nc *NoopCollector) ExecutionComputationUsedPerTransaction(computation uint64)              {}
 func (nc *NoopCollector) ExecutionStateReadsPerBlock(reads uint64)                               {}
+func (nc *NoopCollector) ExecutionContractParsed(contract string, dur time.Duration)             {}
+func (nc *NoopCollector) ExecutionContractChecked(contract string, dur time.Duration)            {}
+func (nc *NoopCollector) ExecutionTransactionExecuted(flow.Identifier, []string, time.Duration)  {}
 func (nc *NoopCollector) ExecutionStateStorageDiskTotal(bytes int64)                             {}
 func (nc *NoopCollector) ExecutionStorageStateCommitment(bytes int64)                            {}
 func (nc *NoopCollector) ExecutionLastExecutedBlockHeight(height uint64)                         {}
@@ -113,6 +117,9 @@ func (nc *NoopCollector) TransactionFinalized(txID flow.Identifier, when time.Ti
 func (nc *NoopCollector) TransactionExecuted(txID flow.Identifier, when time.Time)               {}
 func (nc *NoopCollector) TransactionExpired(txID flow.Identifier)                                {}
 func (nc *NoopCollector) TransactionSubmissionFailed()                                           {}
+func (nc *NoopCollector) RegisterDeltaEjected()                                                  {}
+func (nc *NoopCollector) RegisterDeltaRequested()                                                {}
+func (nc *NoopCollector) RegistersIndexed(height uint64)                                         {}
 func (nc *NoopCollector) ChunkDataPackRequested()                                                {}
 func (nc *NoopCollector) ExecutionSync(syncing bool)                                             {}
 func (nc *NoopCollector) DiskSize(uint64)                                                        {}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type RegisterIndexCollector struct {
+	deltasEjected   prometheus.Counter
+	deltasRequested prometheus.Counter
+	indexedHeight   prometheus.Gauge
+}
+
+func NewRegisterIndexCollector() *RegisterIndexCollector {
+	rc := &RegisterIndexCollector{
+		deltasEjected: promauto.NewCounter(prometheus.CounterOpts{
+			Name:      "register_deltas_ejected_total",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemRegisterIndex,
+			Help:      "the number of register deltas ejected from the memory pool before being indexed",
+		}),
+		deltasRequested: promauto.NewCounter(prometheus.CounterOpts{
+			Name:      "register_deltas_requested_total",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemRegisterIndex,
+			Help:      "the number of register deltas requested from the execution nodes as they were lost or ejected",
+		}),
+		indexedHeight: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "indexed_height",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemRegisterIndex,
+			Help:      "the latest height whose registers were indexed",
+		}),
+	}
+
+	return rc
+}
+
+func (rc *RegisterIndexCollector) RegisterDeltaEjected() {
+	rc.deltasEjected.Inc()
+}
+
+func (rc *RegisterIndexCollector) RegisterDeltaRequested() {
+	rc.deltasRequested.Inc()
+}
+
+func (rc *RegisterIndexCollector) RegistersIndexed(height uint64) {
+	rc.indexedHeight.Set(float64(height))
+}
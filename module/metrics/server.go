@@ -15,11 +15,12 @@ import (
 // Server is the http server that will be serving the /metrics request for prometheus
 type Server struct {
 	server *http.Server
+	mux    *http.ServeMux
 	log    zerolog.Logger
 }
 
 // NewServer creates a new server that will start on the specified port,
-// and responds to only the `/metrics` endpoint
+// and responds to the `/metrics` endpoint
 func NewServer(log zerolog.Logger, port uint, enableProfilerEndpoint bool) *Server {
 	addr := ":" + strconv.Itoa(int(port))
 
@@ -31,12 +32,18 @@ func NewServer(log zerolog.Logger, port uint, enableProfilerEndpoint bool) *Serv
 
 	m := &Server{
 		server: &http.Server{Addr: addr, Handler: mux},
+		mux:    mux,
 		log:    log,
 	}
 
 	return m
 }
 
+// Handle registers a node specific endpoint, e.g. `/slowest-transactions` on execution nodes.
+func (m *Server) Handle(pattern string, handler http.Handler) {
+	m.mux.Handle(pattern, handler)
+}
+
 // Ready returns a channel that will close when the network stack is ready.
 func (m *Server) Ready() <-chan struct{} {
 	ready := make(chan struct{})
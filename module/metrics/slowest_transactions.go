@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultSlowestTransactionsSize is the default number of transactions in the slowest transactions report
+	DefaultSlowestTransactionsSize = 20
+	// DefaultSlowestTransactionsWindow is the default period covered by the slowest transactions report
+	DefaultSlowestTransactionsWindow = time.Hour
+)
+
+// SlowTransaction is an entry of the slowest transactions report.
+type SlowTransaction struct {
+	TransactionID flow.Identifier `json:"transaction_id"`
+	Contracts     []string        `json:"contracts"`
+	Duration      time.Duration   `json:"duration"`
+	ExecutedAt    time.Time       `json:"executed_at"`
+}
+
+// SlowestTransactions is a rolling report of the slowest transactions executed within a window of time.
+//
+// Only the slowest transactions are kept, so a transaction that was dropped for slower ones is not
+// reported again once those leave the window.
+type SlowestTransactions struct {
+	lock         sync.Mutex
+	size         int
+	window       time.Duration
+	transactions []SlowTransaction
+}
+
+// NewSlowestTransactions creates a report of the given number of slowest transactions
+// executed within the window.
+func NewSlowestTransactions(size int, window time.Duration) *SlowestTransactions {
+	return &SlowestTransactions{
+		size:         size,
+		window:       window,
+		transactions: make([]SlowTransaction, 0, size+1),
+	}
+}
+
+// Add adds a transaction to the report, if it is one of the slowest.
+func (s *SlowestTransactions) Add(tx SlowTransaction) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.expire(tx.ExecutedAt)
+
+	s.transactions = append(s.transactions, tx)
+	sort.SliceStable(s.transactions, func(i, j int) bool {
+		return s.transactions[i].Duration > s.transactions[j].Duration
+	})
+
+	if len(s.transactions) > s.size {
+		s.transactions = s.transactions[:s.size]
+	}
+}
+
+// Report returns the slowest transactions executed within the window, slowest first.
+func (s *SlowestTransactions) Report() []SlowTransaction {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.expire(time.Now())
+
+	report := make([]SlowTransaction, len(s.transactions))
+	copy(report, s.transactions)
+	return report
+}
+
+// expire removes the transactions executed before the window ending now.
+func (s *SlowestTransactions) expire(now time.Time) {
+	start := now.Add(-s.window)
+
+	transactions := s.transactions[:0]
+	for _, tx := range s.transactions {
+		if !tx.ExecutedAt.Before(start) {
+			transactions = append(transactions, tx)
+		}
+	}
+	s.transactions = transactions
+}
+
+// ServeHTTP responds with the report encoded as JSON.
+func (s *SlowestTransactions) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(s.Report())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
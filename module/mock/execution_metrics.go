@@ -34,6 +34,21 @@ func (_m *ExecutionMetrics) ExecutionCollectionRequestSent() {
 	_m.Called()
 }
 
+// ExecutionComputationUsedPerTransaction provides a mock function with given fields: computation
+func (_m *ExecutionMetrics) ExecutionComputationUsedPerTransaction(computation uint64) {
+	_m.Called(computation)
+}
+
+// ExecutionContractChecked provides a mock function with given fields: contract, dur
+func (_m *ExecutionMetrics) ExecutionContractChecked(contract string, dur time.Duration) {
+	_m.Called(contract, dur)
+}
+
+// ExecutionContractParsed provides a mock function with given fields: contract, dur
+func (_m *ExecutionMetrics) ExecutionContractParsed(contract string, dur time.Duration) {
+	_m.Called(contract, dur)
+}
+
 // ExecutionGasUsedPerBlock provides a mock function with given fields: gas
 func (_m *ExecutionMetrics) ExecutionGasUsedPerBlock(gas uint64) {
 	_m.Called(gas)
@@ -64,6 +79,11 @@ func (_m *ExecutionMetrics) ExecutionTotalExecutedTransactions(numExecuted int)
 	_m.Called(numExecuted)
 }
 
+// ExecutionTransactionExecuted provides a mock function with given fields: txID, contracts, dur
+func (_m *ExecutionMetrics) ExecutionTransactionExecuted(txID flow.Identifier, contracts []string, dur time.Duration) {
+	_m.Called(txID, contracts, dur)
+}
+
 // FinishBlockReceivedToExecuted provides a mock function with given fields: blockID
 func (_m *ExecutionMetrics) FinishBlockReceivedToExecuted(blockID flow.Identifier) {
 	_m.Called(blockID)
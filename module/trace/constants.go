@@ -86,6 +86,7 @@ const (
 	EXESaveTransactionEvents  SpanName = "exe.ingestion.saveTransactionEvents"
 
 	EXEBroadcastExecutionReceipt SpanName = "exe.provider.broadcastExecutionReceipt"
+	EXEBroadcastRegisterDelta    SpanName = "exe.provider.broadcastRegisterDelta"
 
 	EXEComputeBlock            SpanName = "exe.computer.computeBlock"
 	EXEComputeCollection       SpanName = "exe.computer.computeCollection"
@@ -129,9 +130,14 @@ const (
 
 // Tag names
 const (
-	EXEParseDurationTag         = "runtime.parseTransactionDuration"
-	EXECheckDurationTag         = "runtime.checkTransactionDuration"
-	EXEInterpretDurationTag     = "runtime.interpretTransactionDuration"
-	EXEValueEncodingDurationTag = "runtime.encodingValueDuration"
-	EXEValueDecodingDurationTag = "runtime.decodingValueDuration"
+	EXEParseDurationTag            = "runtime.parseTransactionDuration"
+	EXECheckDurationTag            = "runtime.checkTransactionDuration"
+	EXEInterpretDurationTag        = "runtime.interpretTransactionDuration"
+	EXEValueEncodingDurationTag    = "runtime.encodingValueDuration"
+	EXEValueDecodingDurationTag    = "runtime.decodingValueDuration"
+	EXEImportsTag                  = "runtime.imports"
+	EXEProgramLocationTag          = "runtime.programLocation"
+	EXEProgramParseDurationTag     = "runtime.parseProgramDuration"
+	EXEProgramCheckDurationTag     = "runtime.checkProgramDuration"
+	EXEProgramInterpretDurationTag = "runtime.interpretProgramDuration"
 )
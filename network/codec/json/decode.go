@@ -65,6 +65,10 @@ func decode(env Envelope) (interface{}, error) {
 		v = &messages.ExecutionStateSyncRequest{}
 	case CodeExecutionStateDelta:
 		v = &messages.ExecutionStateDelta{}
+	case CodeExecutionRegisterDelta:
+		v = &messages.ExecutionRegisterDelta{}
+	case CodeExecutionRegisterDeltaRequest:
+		v = &messages.ExecutionRegisterDeltaRequest{}
 
 	// data exchange for execution of blocks
 	case CodeChunkDataRequest:
@@ -64,6 +64,10 @@ func encode(v interface{}) (*Envelope, error) {
 		code = CodeExecutionStateSyncRequest
 	case *messages.ExecutionStateDelta:
 		code = CodeExecutionStateDelta
+	case *messages.ExecutionRegisterDelta:
+		code = CodeExecutionRegisterDelta
+	case *messages.ExecutionRegisterDeltaRequest:
+		code = CodeExecutionRegisterDeltaRequest
 
 	// data exchange for execution of blocks
 	case *messages.ChunkDataRequest:
@@ -36,6 +36,8 @@ const (
 	// execution state synchronization
 	CodeExecutionStateSyncRequest
 	CodeExecutionStateDelta
+	CodeExecutionRegisterDelta
+	CodeExecutionRegisterDeltaRequest
 
 	// data exchange for execution of blocks
 	CodeChunkDataRequest
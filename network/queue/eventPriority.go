@@ -84,6 +84,10 @@ func getPriorityByType(message interface{}) Priority {
 		return MediumPriority
 	case *messages.ExecutionStateDelta:
 		return HighPriority
+	case *messages.ExecutionRegisterDelta:
+		return MediumPriority
+	case *messages.ExecutionRegisterDeltaRequest:
+		return MediumPriority
 
 	// data exchange for execution of blocks
 	case *messages.ChunkDataRequest:
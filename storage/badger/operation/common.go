@@ -349,6 +349,41 @@ func traverse(prefix []byte, iteration iterationFunc) func(*badger.Txn) error {
 	}
 }
 
+// findHighestAtOrBelow finds the key with the given prefix and the highest
+// height at or below the given height, and decodes its value into the given
+// entity. The keys must consist of the prefix followed by the height.
+func findHighestAtOrBelow(prefix []byte, height uint64, entity interface{}) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		if len(prefix) == 0 {
+			return fmt.Errorf("prefix must not be empty")
+		}
+
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		opts.Reverse = true
+
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		// in reverse order, seek goes to the highest key lower or equal to the
+		// given key
+		key := append(append([]byte{}, prefix...), b(height)...)
+		it.Seek(key)
+		if !it.ValidForPrefix(prefix) {
+			return storage.ErrNotFound
+		}
+
+		err := it.Item().Value(func(val []byte) error {
+			return msgpack.Unmarshal(val, entity)
+		})
+		if err != nil {
+			return fmt.Errorf("could not decode entity: %w", err)
+		}
+
+		return nil
+	}
+}
+
 // Fail returns a DB operation function that always fails with the given error.
 func Fail(err error) func(*badger.Txn) error {
 	return func(_ *badger.Txn) error {
@@ -25,6 +25,8 @@ const (
 	codeExecutedBlock           = 23 // latest executed block with max height
 	codeRootHeight              = 24 // the height of the first loaded block
 	codeLastCompleteBlockHeight = 25 // the height of the last block for which all collections were received
+	codeRegisterFirstHeight     = 26 // the height the register index was bootstrapped at
+	codeRegisterLatestHeight    = 27 // latest height indexed in the register index
 
 	// codes for single entity storage
 	// 31 was used for identities before epochs
@@ -58,6 +60,9 @@ const (
 	codeEpochSetup  = 60 // EpochSetup service event, keyed by ID
 	codeEpochCommit = 61 // EpochCommit service event, keyed by ID
 
+	// codes related to the register index
+	codeRegister = 70 // register values, keyed by register ID and height
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                = 100
 	codeCommit                       = 101
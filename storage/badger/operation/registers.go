@@ -0,0 +1,39 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InsertRegister inserts the value of a register at the height it was updated at. The register is identified by
+// the hash of its ID, so the keys of all its values have the same length.
+func InsertRegister(registerID flow.Identifier, height uint64, value flow.RegisterValue) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegister, registerID, height), value)
+}
+
+// LookupRegister retrieves the value of a register at the given height, which is the value of its latest update at
+// or below the height.
+func LookupRegister(registerID flow.Identifier, height uint64, value *flow.RegisterValue) func(*badger.Txn) error {
+	return findHighestAtOrBelow(makePrefix(codeRegister, registerID), height, value)
+}
+
+func InsertRegisterFirstHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegisterFirstHeight), height)
+}
+
+func RetrieveRegisterFirstHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeRegisterFirstHeight), height)
+}
+
+func InsertRegisterLatestHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegisterLatestHeight), height)
+}
+
+func UpdateRegisterLatestHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeRegisterLatestHeight), height)
+}
+
+func RetrieveRegisterLatestHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeRegisterLatestHeight), height)
+}
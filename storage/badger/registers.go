@@ -0,0 +1,169 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// registersBatchSize is the number of registers stored per transaction, as the registers of a full execution state,
+// or of a block updating many registers, don't fit in a single transaction.
+const registersBatchSize = 1000
+
+// Registers implements the register index, storing the value of each register at every height it was updated at.
+type Registers struct {
+	db *badger.DB
+}
+
+func NewRegisters(db *badger.DB) *Registers {
+	return &Registers{
+		db: db,
+	}
+}
+
+// Bootstrap stores the registers of the full execution state at the given height. The heights are only stored once
+// all the registers are, so bootstrapping again after a failure skips the registers already stored.
+func (r *Registers) Bootstrap(height uint64, entries flow.RegisterEntries) error {
+	_, err := r.FirstHeight()
+	if err == nil {
+		return storage.ErrAlreadyExists
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not check first height: %w", err)
+	}
+
+	for start := 0; start < len(entries); start += registersBatchSize {
+		end := start + registersBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		err := operation.RetryOnConflict(r.db.Update, func(tx *badger.Txn) error {
+			for _, entry := range entries[start:end] {
+				err := operation.SkipDuplicates(operation.InsertRegister(flow.MakeID(entry.Key), height, entry.Value))(tx)
+				if err != nil {
+					return fmt.Errorf("could not insert register %s: %w", entry.Key.String(), err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("could not store registers: %w", err)
+		}
+	}
+
+	err = operation.RetryOnConflict(r.db.Update, func(tx *badger.Txn) error {
+		err := operation.InsertRegisterFirstHeight(height)(tx)
+		if err != nil {
+			return fmt.Errorf("could not insert first height: %w", err)
+		}
+		err = operation.InsertRegisterLatestHeight(height)(tx)
+		if err != nil {
+			return fmt.Errorf("could not insert latest height: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not store heights: %w", err)
+	}
+
+	return nil
+}
+
+// Store stores the registers updated at the given height, along with the height as the latest indexed height. The
+// height is only stored once all the registers are, so storing it again after a failure skips the registers already
+// stored, and the registers are not readable at the height before.
+func (r *Registers) Store(height uint64, entries flow.RegisterEntries) error {
+	latest, err := r.LatestHeight()
+	if err != nil {
+		return fmt.Errorf("could not retrieve latest height: %w", err)
+	}
+	if height != latest+1 {
+		return fmt.Errorf("height %d is not the next height after the latest height %d", height, latest)
+	}
+
+	for start := 0; start < len(entries); start += registersBatchSize {
+		end := start + registersBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		err := operation.RetryOnConflict(r.db.Update, func(tx *badger.Txn) error {
+			for _, entry := range entries[start:end] {
+				err := operation.SkipDuplicates(operation.InsertRegister(flow.MakeID(entry.Key), height, entry.Value))(tx)
+				if err != nil {
+					return fmt.Errorf("could not insert register %s: %w", entry.Key.String(), err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("could not store registers at height %d: %w", height, err)
+		}
+	}
+
+	err = operation.RetryOnConflict(r.db.Update, func(tx *badger.Txn) error {
+		var latest uint64
+		err := operation.RetrieveRegisterLatestHeight(&latest)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve latest height: %w", err)
+		}
+		if height != latest+1 {
+			return fmt.Errorf("height %d is not the next height after the latest height %d", height, latest)
+		}
+		return operation.UpdateRegisterLatestHeight(height)(tx)
+	})
+	if err != nil {
+		return fmt.Errorf("could not update latest height to %d: %w", height, err)
+	}
+
+	return nil
+}
+
+// Get returns the value of the register at the given height, which is the value of its latest update at or below
+// the height. A deleted register has an empty value.
+func (r *Registers) Get(id flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	tx := r.db.NewTransaction(false)
+	defer tx.Discard()
+
+	var first, latest uint64
+	err := operation.RetrieveRegisterFirstHeight(&first)(tx)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("register index is not bootstrapped: %w", storage.ErrHeightNotIndexed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve first height: %w", err)
+	}
+	err = operation.RetrieveRegisterLatestHeight(&latest)(tx)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve latest height: %w", err)
+	}
+	if height < first || height > latest {
+		return nil, fmt.Errorf("height %d is outside of the indexed heights [%d, %d]: %w", height, first, latest, storage.ErrHeightNotIndexed)
+	}
+
+	var value flow.RegisterValue
+	err = operation.LookupRegister(flow.MakeID(id), height, &value)(tx)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up register %s: %w", id.String(), err)
+	}
+
+	return value, nil
+}
+
+func (r *Registers) FirstHeight() (uint64, error) {
+	var height uint64
+	err := r.db.View(operation.RetrieveRegisterFirstHeight(&height))
+	return height, err
+}
+
+func (r *Registers) LatestHeight() (uint64, error) {
+	var height uint64
+	err := r.db.View(operation.RetrieveRegisterLatestHeight(&height))
+	return height, err
+}
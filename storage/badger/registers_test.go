@@ -0,0 +1,116 @@
+package badger_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+func TestRegistersStoreAndGet(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewRegisters(db)
+
+		a := flow.NewRegisterID("a", "", "key")
+		b := flow.NewRegisterID("b", "", "key")
+		c := flow.NewRegisterID("c", "", "key")
+
+		// not bootstrapped yet
+		_, err := store.Get(a, 10)
+		assert.True(t, errors.Is(err, storage.ErrHeightNotIndexed))
+		err = store.Store(11, nil)
+		assert.Error(t, err)
+
+		err = store.Bootstrap(10, flow.RegisterEntries{
+			{Key: a, Value: []byte("a10")},
+			{Key: b, Value: []byte("b10")},
+		})
+		require.NoError(t, err)
+
+		err = store.Bootstrap(10, nil)
+		assert.True(t, errors.Is(err, storage.ErrAlreadyExists))
+
+		err = store.Store(11, flow.RegisterEntries{{Key: a, Value: []byte("a11")}})
+		require.NoError(t, err)
+		err = store.Store(12, flow.RegisterEntries{{Key: c, Value: []byte("c12")}})
+		require.NoError(t, err)
+
+		// heights must be stored in order
+		err = store.Store(14, flow.RegisterEntries{{Key: a, Value: []byte("a14")}})
+		assert.Error(t, err)
+
+		first, err := store.FirstHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), first)
+		latest, err := store.LatestHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(12), latest)
+
+		expected := []struct {
+			id     flow.RegisterID
+			height uint64
+			value  string
+		}{
+			{a, 10, "a10"},
+			{a, 11, "a11"},
+			{a, 12, "a11"},
+			{b, 12, "b10"},
+			{c, 12, "c12"},
+		}
+		for _, e := range expected {
+			value, err := store.Get(e.id, e.height)
+			require.NoError(t, err)
+			assert.Equal(t, e.value, string(value))
+		}
+
+		// not set yet
+		_, err = store.Get(c, 11)
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+		// outside of the indexed heights
+		_, err = store.Get(a, 9)
+		assert.True(t, errors.Is(err, storage.ErrHeightNotIndexed))
+		_, err = store.Get(a, 13)
+		assert.True(t, errors.Is(err, storage.ErrHeightNotIndexed))
+	})
+}
+
+// TestRegistersStoreInBatches checks that heights updating more registers than fit in a single transaction are stored.
+func TestRegistersStoreInBatches(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewRegisters(db)
+
+		err := store.Bootstrap(10, nil)
+		require.NoError(t, err)
+
+		entries := make(flow.RegisterEntries, 0, 2500)
+		for i := 0; i < cap(entries); i++ {
+			entries = append(entries, flow.RegisterEntry{
+				Key:   flow.NewRegisterID(fmt.Sprintf("owner-%d", i), "", "key"),
+				Value: []byte(fmt.Sprintf("value-%d", i)),
+			})
+		}
+
+		err = store.Store(11, entries)
+		require.NoError(t, err)
+
+		latest, err := store.LatestHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(11), latest)
+
+		for _, entry := range entries {
+			value, err := store.Get(entry.Key, 11)
+			require.NoError(t, err)
+			assert.Equal(t, entry.Value, value)
+		}
+	})
+}
@@ -3,7 +3,8 @@ package storage
 import "errors"
 
 var (
-	ErrNotFound      = errors.New("key not found")
-	ErrAlreadyExists = errors.New("key already exists")
-	ErrDataMismatch  = errors.New("data for key is different")
+	ErrNotFound         = errors.New("key not found")
+	ErrAlreadyExists    = errors.New("key already exists")
+	ErrDataMismatch     = errors.New("data for key is different")
+	ErrHeightNotIndexed = errors.New("height not indexed")
 )
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Registers represents persistent storage for the registers of the execution state, indexed by block height. The
+// value of a register can be read at any height between the first and the latest indexed heights.
+type Registers interface {
+
+	// Bootstrap stores the registers of the full execution state at the given height, which becomes the first and
+	// latest indexed height. It returns ErrAlreadyExists if the index was already bootstrapped.
+	Bootstrap(height uint64, entries flow.RegisterEntries) error
+
+	// Store stores the registers updated at the given height, which must be the height after the latest indexed
+	// height.
+	Store(height uint64, entries flow.RegisterEntries) error
+
+	// Get returns the value of a register at the given height. It returns ErrHeightNotIndexed if the height is not
+	// indexed, and ErrNotFound if the register was never set.
+	Get(id flow.RegisterID, height uint64) (flow.RegisterValue, error)
+
+	// FirstHeight returns the height the index was bootstrapped at.
+	FirstHeight() (uint64, error)
+
+	// LatestHeight returns the latest indexed height.
+	LatestHeight() (uint64, error)
+}